@@ -6,7 +6,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"syscall/js"
 	"time"
 	"unicode"
@@ -14,21 +16,31 @@ import (
 
 	"github.com/kittclouds/gokitt/internal/store"
 	"github.com/kittclouds/gokitt/pkg/agent"
+	"github.com/kittclouds/gokitt/pkg/analysis"
 	"github.com/kittclouds/gokitt/pkg/batch"
 	"github.com/kittclouds/gokitt/pkg/chat"
+	"github.com/kittclouds/gokitt/pkg/coref"
 	"github.com/kittclouds/gokitt/pkg/docstore"
 	"github.com/kittclouds/gokitt/pkg/extraction"
 	"github.com/kittclouds/gokitt/pkg/graph"
 	"github.com/kittclouds/gokitt/pkg/hierarchy"
 	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/memory"
+	"github.com/kittclouds/gokitt/pkg/om"
+	"github.com/kittclouds/gokitt/pkg/ontology"
 	"github.com/kittclouds/gokitt/pkg/qgram"
 	"github.com/kittclouds/gokitt/pkg/reality/builder"
+	"github.com/kittclouds/gokitt/pkg/reality/consistency"
+	"github.com/kittclouds/gokitt/pkg/reality/grounding"
 	"github.com/kittclouds/gokitt/pkg/reality/merger"
 	"github.com/kittclouds/gokitt/pkg/reality/pcst"
+	"github.com/kittclouds/gokitt/pkg/reality/persist"
 	"github.com/kittclouds/gokitt/pkg/reality/projection"
 	"github.com/kittclouds/gokitt/pkg/reality/validator"
+	"github.com/kittclouds/gokitt/pkg/review"
 	"github.com/kittclouds/gokitt/pkg/sab"
 	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+	"github.com/kittclouds/gokitt/pkg/scanner/discovery"
 )
 
 // Version info
@@ -45,6 +57,11 @@ var batchSvc *batch.Service           // Phase 6: LLM Batch Service
 var extractionSvc *extraction.Service // Phase 6: Unified Extraction
 var agentSvc *agent.Service           // Phase 6: Agent (tool-calling)
 var chatSvc *chat.ChatService         // Phase 7: Chat Service
+var omSvc *om.Service                 // Phase 7: Observer/Reflector
+var memoryExtractor *memory.Extractor // Phase 7: Structured memories
+var corefSvc *coref.Service           // Phase 8: World-level coreference
+var reviewSvc *review.Service         // Phase 9: Discovery review queue
+var relOntology = ontology.Default()  // Phase 11: Relation ontology
 
 func main() {
 	var err error
@@ -91,6 +108,7 @@ func main() {
 		"storeDeleteEntity":     js.FuncOf(storeDeleteEntity),
 		"storeListEntities":     js.FuncOf(storeListEntities),
 		"storeUpsertEdge":       js.FuncOf(storeUpsertEdge),
+		"storeMergeEdge":        js.FuncOf(storeMergeEdge),
 		"storeGetEdge":          js.FuncOf(storeGetEdge),
 		"storeDeleteEdge":       js.FuncOf(storeDeleteEdge),
 		"storeListEdges":        js.FuncOf(storeListEdges),
@@ -107,20 +125,32 @@ func main() {
 		"mergerAddScanner": js.FuncOf(mergerAddScanner),
 		"mergerAddLLM":     js.FuncOf(mergerAddLLM),
 		"mergerAddManual":  js.FuncOf(mergerAddManual),
+		"mergerRetractLLM": js.FuncOf(mergerRetractLLM),
 		"mergerGetGraph":   js.FuncOf(mergerGetGraph),
 		"mergerGetStats":   js.FuncOf(mergerGetStats),
+		"mergerSync":       js.FuncOf(mergerSync),
+		"mergerLoad":       js.FuncOf(mergerLoad),
+		"mergerGraphAt":    js.FuncOf(mergerGraphAt),
+		"storeEdgesAt":     js.FuncOf(storeEdgesAt),
 		// Phase 4: PCST Coherence Filter
-		"mergerRunPCST": js.FuncOf(mergerRunPCST),
+		"mergerRunPCST":       js.FuncOf(mergerRunPCST),
+		"mergerSummarize":     js.FuncOf(mergerSummarize),
+		"mergerQuerySubgraph": js.FuncOf(mergerQuerySubgraph),
 		// Phase 5: SharedArrayBuffer Zero-Copy
 		"sabInit":            js.FuncOf(sabInit),
 		"sabScanToBuffer":    js.FuncOf(sabScanToBuffer),
 		"sabGetBufferStatus": js.FuncOf(sabGetBufferStatus),
 		// Phase 6: LLM Batch + Extraction + Agent
 		"batchInit":          js.FuncOf(jsBatchInit),
+		"batchListModels":    js.FuncOf(jsBatchListModels),
+		"batchUsage":         js.FuncOf(jsBatchUsage),
 		"extractFromNote":    js.FuncOf(jsExtractFromNote),
+		"extractionCache":    js.FuncOf(jsExtractionCache),
+		"extractAndMerge":    js.FuncOf(jsExtractAndMerge),
 		"extractEntities":    js.FuncOf(jsExtractEntities),
 		"extractRelations":   js.FuncOf(jsExtractRelations),
 		"agentChatWithTools": js.FuncOf(jsAgentChatWithTools),
+		"agentRun":           js.FuncOf(jsAgentRun),
 		// Phase 7: Observational Memory + Chat Service
 		"chatInit":           js.FuncOf(jsChatInit),
 		"chatCreateThread":   js.FuncOf(jsChatCreateThread),
@@ -136,6 +166,37 @@ func main() {
 		"chatGetContext":     js.FuncOf(jsChatGetContext),
 		"chatClearThread":    js.FuncOf(jsChatClearThread),
 		"chatExportThread":   js.FuncOf(jsChatExportThread),
+		"omInit":             js.FuncOf(jsOMInit),
+		"omProcess":          js.FuncOf(jsOMProcess),
+		"omGetContext":       js.FuncOf(jsOMGetContext),
+		"omGetRecord":        js.FuncOf(jsOMGetRecord),
+		"memoryInit":         js.FuncOf(jsMemoryInit),
+		"memoryExtract":      js.FuncOf(jsMemoryExtract),
+		"memoryRecall":       js.FuncOf(jsMemoryRecall),
+		// Phase 8: World Coreference
+		"corefInit":    js.FuncOf(jsCorefInit),
+		"corefConfirm": js.FuncOf(jsCorefConfirm),
+		"corefReject":  js.FuncOf(jsCorefReject),
+		// Phase 9: Discovery Review Queue
+		"discoveryInit":   js.FuncOf(jsDiscoveryInit),
+		"discoveryQueue":  js.FuncOf(jsDiscoveryQueue),
+		"discoveryAccept": js.FuncOf(jsDiscoveryAccept),
+		"discoveryReject": js.FuncOf(jsDiscoveryReject),
+		"discoverySnooze": js.FuncOf(jsDiscoverySnooze),
+		// Phase 10: Graph Algorithms
+		"graphShortestPath": js.FuncOf(jsGraphShortestPath),
+		"graphEgoNetwork":   js.FuncOf(jsGraphEgoNetwork),
+		"graphCentrality":   js.FuncOf(jsGraphCentrality),
+		"graphCommunities":  js.FuncOf(jsGraphCommunities),
+		// Phase 11: Relation Ontology
+		"ontologyGet":   js.FuncOf(jsOntologyGet),
+		"ontologySet":   js.FuncOf(jsOntologySet),
+		"ontologyCheck": js.FuncOf(jsOntologyCheck),
+		// Phase 12: Consistency Checker
+		"checkConsistency": js.FuncOf(jsCheckConsistency),
+		// Phase 13: Narrative Analytics
+		"analyzeNote":  js.FuncOf(jsAnalyzeNote),
+		"analyzeWorld": js.FuncOf(jsAnalyzeWorld),
 	}))
 
 	select {}
@@ -456,17 +517,21 @@ func scan(this js.Value, args []js.Value) interface{} {
 	var prov *hierarchy.ProvenanceContext
 	if len(args) > 1 && args[1].String() != "" && args[1].String() != "null" {
 		var provInput struct {
-			VaultID    string `json:"vaultId"`
-			WorldID    string `json:"worldId"`
-			ParentPath string `json:"parentPath"`
-			FolderType string `json:"folderType"`
+			VaultID    string   `json:"vaultId"`
+			WorldID    string   `json:"worldId"`
+			ParentPath string   `json:"parentPath"`
+			FolderType string   `json:"folderType"`
+			StoryTime  *float64 `json:"storyTime"`
 		}
 		if err := json.Unmarshal([]byte(args[1].String()), &provInput); err == nil {
 			prov = &hierarchy.ProvenanceContext{
-				VaultID:    provInput.VaultID,
-				WorldID:    provInput.WorldID,
-				ParentPath: provInput.ParentPath,
-				FolderType: provInput.FolderType,
+				VaultID:     provInput.VaultID,
+				WorldID:     provInput.WorldID,
+				ParentPath:  provInput.ParentPath,
+				FolderType:  provInput.FolderType,
+				ResolveLink: noteLinkResolver(),
+				StoryTime:   noteStoryTime(provInput.WorldID, provInput.StoryTime),
+				ResolveTime: noteTimeResolver(),
 			}
 		}
 	}
@@ -509,12 +574,7 @@ func scan(this js.Value, args []js.Value) interface{} {
 
 	slimEdges := make([]interface{}, 0, len(conceptGraph.Edges))
 	for _, edge := range conceptGraph.Edges {
-		slimEdges = append(slimEdges, map[string]interface{}{
-			"source":     edge.Source,
-			"target":     edge.Target,
-			"type":       edge.Relation,
-			"confidence": edge.Weight,
-		})
+		slimEdges = append(slimEdges, slimEdge(edge))
 	}
 
 	response := map[string]interface{}{
@@ -534,6 +594,7 @@ func scan(this js.Value, args []js.Value) interface{} {
 }
 
 // scanDiscovery performs unsupervised NER ("The Virus")
+// Optional args worldID, noteID record the candidates in the review queue.
 // Args: [text string]
 func scanDiscovery(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
@@ -547,6 +608,13 @@ func scanDiscovery(this js.Value, args []js.Value) interface{} {
 	// Scan the text with Discovery Engine (heuristic)
 	pipeline.ScanDiscovery(text)
 
+	if reviewSvc != nil && len(args) >= 3 {
+		worldID, noteID := args[1].String(), args[2].String()
+		if _, err := reviewSvc.Record(worldID, noteID, text, pipeline.DiscoveryRegistry().GetCandidates()); err != nil {
+			return errorResult(err.Error())
+		}
+	}
+
 	candidates := pipeline.GetCandidates()
 	jsonBytes, _ := json.Marshal(candidates)
 	return string(jsonBytes)
@@ -662,17 +730,21 @@ func scanNote(this js.Value, args []js.Value) interface{} {
 	var prov *hierarchy.ProvenanceContext
 	if len(args) > 1 && args[1].String() != "" && args[1].String() != "null" {
 		var provInput struct {
-			VaultID    string `json:"vaultId"`
-			WorldID    string `json:"worldId"`
-			ParentPath string `json:"parentPath"`
-			FolderType string `json:"folderType"`
+			VaultID    string   `json:"vaultId"`
+			WorldID    string   `json:"worldId"`
+			ParentPath string   `json:"parentPath"`
+			FolderType string   `json:"folderType"`
+			StoryTime  *float64 `json:"storyTime"`
 		}
 		if err := json.Unmarshal([]byte(args[1].String()), &provInput); err == nil {
 			prov = &hierarchy.ProvenanceContext{
-				VaultID:    provInput.VaultID,
-				WorldID:    provInput.WorldID,
-				ParentPath: provInput.ParentPath,
-				FolderType: provInput.FolderType,
+				VaultID:     provInput.VaultID,
+				WorldID:     provInput.WorldID,
+				ParentPath:  provInput.ParentPath,
+				FolderType:  provInput.FolderType,
+				ResolveLink: noteLinkResolver(),
+				StoryTime:   noteStoryTime(provInput.WorldID, provInput.StoryTime),
+				ResolveTime: noteTimeResolver(),
 			}
 		}
 	}
@@ -686,20 +758,27 @@ func scanNote(this js.Value, args []js.Value) interface{} {
 
 	// 3. Graph (The World)
 	entityMap := make(projection.EntityMap)
+	mentions := make(map[string]int)
 	for _, ref := range result.ResolvedRefs {
 		entityMap[ref.Range.Start] = ref.EntityID
+		mentions[ref.EntityID]++
 	}
 
 	conceptGraph := projection.Project(cstRoot, pipeline.GetMatcher(), entityMap, text, prov)
 	conceptGraph.ToSerializable()
 
-	// 4. PCST (The Summary)
-	prizes := make(map[string]float64)
-	for id := range conceptGraph.Nodes {
-		prizes[id] = 1.0
-	}
+	// 4. PCST (The Summary): entities prized by mentions in this note and by
+	// centrality, edges priced by inverse confidence
+	prizes := pcst.Combine(
+		pcst.Weighted{Prizes: pcst.Mentions(mentions), Weight: 1},
+		pcst.Weighted{Prizes: pcst.Centrality(conceptGraph), Weight: 1},
+	)
+	summary := []string{}
 	solver := pcst.NewIpcstSolver(pcst.DefaultConfig())
-	_, _ = solver.Solve(conceptGraph, prizes, "")
+	if solution, err := solver.Solve(pcst.CostGraph(conceptGraph), prizes, ""); err == nil {
+		summary = solution.Nodes
+		sort.Strings(summary)
+	}
 
 	duration := time.Since(start).Microseconds()
 
@@ -714,12 +793,7 @@ func scanNote(this js.Value, args []js.Value) interface{} {
 
 	slimEdges := make([]interface{}, 0, len(conceptGraph.Edges))
 	for _, edge := range conceptGraph.Edges {
-		slimEdges = append(slimEdges, map[string]interface{}{
-			"source":     edge.Source,
-			"target":     edge.Target,
-			"type":       edge.Relation,
-			"confidence": edge.Weight,
-		})
+		slimEdges = append(slimEdges, slimEdge(edge))
 	}
 
 	response := map[string]interface{}{
@@ -728,6 +802,7 @@ func scanNote(this js.Value, args []js.Value) interface{} {
 			"nodes": slimNodes,
 			"edges": slimEdges,
 		},
+		"summary":   summary,
 		"timing_us": duration,
 	}
 
@@ -774,6 +849,7 @@ func validateRelations(this js.Value, args []js.Value) interface{} {
 
 	// Create validator and validate
 	v := validator.New(cstRoot, note.Text)
+	v.SetOntology(relOntology)
 	validated := v.Validate(llmRelations)
 
 	// Convert to JSON-friendly format
@@ -810,6 +886,9 @@ func storeInit(this js.Value, args []js.Value) interface{} {
 	if err != nil {
 		return errorResult("failed to initialize SQLite store: " + err.Error())
 	}
+	if extractionSvc != nil {
+		extractionSvc.SetCache(sqlStore)
+	}
 	fmt.Println("[GoKitt] âœ… SQLite Store initialized")
 	return successResult("store initialized")
 }
@@ -916,6 +995,9 @@ func storeUpsertEntity(this js.Value, args []js.Value) interface{} {
 	if err := sqlStore.UpsertEntity(&entity); err != nil {
 		return errorResult("upsert failed: " + err.Error())
 	}
+	if corefSvc != nil {
+		corefSvc.Add(&entity)
+	}
 
 	return successResult("upserted " + entity.ID)
 }
@@ -979,6 +1061,9 @@ func storeDeleteEntity(this js.Value, args []js.Value) interface{} {
 	if err := sqlStore.DeleteEntity(args[0].String()); err != nil {
 		return errorResult("delete failed: " + err.Error())
 	}
+	if corefSvc != nil {
+		corefSvc.Remove(args[0].String())
+	}
 
 	return successResult("deleted")
 }
@@ -1027,6 +1112,36 @@ func storeUpsertEdge(this js.Value, args []js.Value) interface{} {
 	return successResult("upserted " + edge.ID)
 }
 
+// storeMergeEdge records a user-asserted edge, merging it into the stored
+// edge for the same relation so that edge's evidence and support are kept.
+// The relation is normalised with the ontology first.
+// Args: [edgeJSON string] ({sourceId, targetId, relType, attributes?})
+// Returns: merged Edge JSON
+func storeMergeEdge(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("storeMergeEdge requires 1 arg: edgeJSON")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	var in merger.ManualEdgeInput
+	if err := json.Unmarshal([]byte(args[0].String()), &in); err != nil {
+		return errorResult("invalid edge json: " + err.Error())
+	}
+	in.SourceID, in.RelType, in.TargetID = relOntology.Normalize(in.SourceID, in.RelType, in.TargetID)
+	if in.SourceID == "" || in.TargetID == "" || in.RelType == "" {
+		return errorResult("storeMergeEdge requires sourceId, targetId and relType")
+	}
+
+	edge, err := persist.NewService(sqlStore).MergeManualEdge(in)
+	if err != nil {
+		return errorResult("merge failed: " + err.Error())
+	}
+	bytes, _ := json.Marshal(edge)
+	return string(bytes)
+}
+
 // storeGetEdge retrieves an edge by ID.
 // Args: [id string]
 // Returns: Edge JSON or null
@@ -1223,14 +1338,102 @@ func storeListFolders(this js.Value, args []js.Value) interface{} {
 	return string(bytes)
 }
 
+// slimEdge converts a serialized edge into the compact shape the UI consumes.
+// Factuality fields are only included for negated/modal/tensed edges.
+func slimEdge(edge *graph.SerializableEdge) map[string]interface{} {
+	out := map[string]interface{}{
+		"source":     edge.Source,
+		"target":     edge.Target,
+		"type":       edge.Relation,
+		"confidence": edge.Weight,
+	}
+	if edge.Negated {
+		out["negated"] = true
+	}
+	if edge.Modality != "" {
+		out["modality"] = edge.Modality
+	}
+	if edge.Tense != "" {
+		out["tense"] = edge.Tense
+	}
+	if edge.Authored {
+		out["authored"] = true
+	}
+	if edge.Valid != nil {
+		out["valid"] = edge.Valid
+	}
+	if edge.SourceSpan != [2]int{} {
+		out["sourceDoc"] = edge.SourceDoc
+		out["sentence"] = edge.SourceSpan
+		out["verb"] = edge.VerbSpan
+	}
+	return out
+}
+
+// noteLinkResolver maps wikilink targets (note titles, case-insensitive)
+// to note IDs from the store. The title index is built on first use.
+func noteLinkResolver() func(target string) string {
+	var byTitle map[string]string
+	return func(target string) string {
+		if sqlStore == nil {
+			return ""
+		}
+		if byTitle == nil {
+			byTitle = make(map[string]string)
+			notes, _ := sqlStore.ListNotes("")
+			for _, n := range notes {
+				byTitle[strings.ToLower(n.Title)] = n.ID
+			}
+		}
+		return byTitle[strings.ToLower(target)]
+	}
+}
+
+// noteStoryTime returns the note's story position: the explicit value if
+// given, otherwise the note's Order from the store
+func noteStoryTime(noteID string, explicit *float64) *float64 {
+	if explicit != nil || sqlStore == nil || noteID == "" {
+		return explicit
+	}
+	if note, err := sqlStore.GetNote(noteID); err == nil && note != nil {
+		return graph.StoryPoint(note.Order)
+	}
+	return nil
+}
+
+// noteTimeResolver maps time expressions to story points by note title:
+// "until the war" ends at the Order of the note titled "The War" (or "War").
+// The title index is built on first use.
+func noteTimeResolver() func(expr string) (float64, bool) {
+	var byTitle map[string]float64
+	return func(expr string) (float64, bool) {
+		if sqlStore == nil {
+			return 0, false
+		}
+		if byTitle == nil {
+			byTitle = make(map[string]float64)
+			notes, _ := sqlStore.ListNotes("")
+			for _, n := range notes {
+				byTitle[strings.ToLower(n.Title)] = n.Order
+			}
+		}
+		t, ok := byTitle[strings.ToLower(expr)]
+		return t, ok
+	}
+}
+
 // =============================================================================
 // Phase 3: Graph Merger API
 // =============================================================================
 
 // mergerInit creates a new merger instance
-// Args: []
+// Args: [factualityPolicy string (optional: "keep" | "downweight" | "filter")]
 func mergerInit(this js.Value, args []js.Value) interface{} {
 	graphMerger = merger.New()
+	graphMerger.SetOntology(relOntology)
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		graphMerger.SetFactualityPolicy(merger.FactualityPolicy(args[0].String()))
+	}
 	return successResult("Merger initialized")
 }
 
@@ -1247,20 +1450,9 @@ func mergerAddScanner(this js.Value, args []js.Value) interface{} {
 	noteID := args[0].String()
 	graphJSON := args[1].String()
 
-	// Parse graph from scan result
+	// Parse graph from scan result, in the scanner's own serialized form
 	var scanResult struct {
-		Graph struct {
-			Nodes map[string]struct {
-				Label string `json:"Label"`
-				Kind  string `json:"Kind"`
-			} `json:"nodes"`
-			Edges []struct {
-				Source     string  `json:"Source"`
-				Target     string  `json:"Target"`
-				Type       string  `json:"Type"`
-				Confidence float64 `json:"Confidence"`
-			} `json:"edges"`
-		} `json:"graph"`
+		Graph graph.ConceptGraph `json:"graph"`
 	}
 
 	if err := json.Unmarshal([]byte(graphJSON), &scanResult); err != nil {
@@ -1272,19 +1464,46 @@ func mergerAddScanner(this js.Value, args []js.Value) interface{} {
 
 	// Add nodes
 	for id, n := range scanResult.Graph.Nodes {
-		g.EnsureNode(id, n.Label, n.Kind)
+		if n != nil {
+			g.EnsureNode(id, n.Label, n.Kind)
+		}
 	}
 
 	// Add edges
 	for _, e := range scanResult.Graph.Edges {
-		g.AddLabeledEdge(e.Source, e.Target, e.Type, e.Confidence)
+		if e == nil {
+			continue
+		}
+		source, target := g.GetNode(e.Source), g.GetNode(e.Target)
+		if source == nil || target == nil {
+			continue
+		}
+		g.AddEdge(source, target, &graph.ConceptEdge{
+			Relation:   strings.ToUpper(e.Relation),
+			Weight:     e.Weight,
+			Manner:     e.Manner,
+			Location:   e.Location,
+			Time:       e.Time,
+			Recipient:  e.Recipient,
+			Negated:    e.Negated,
+			Modality:   e.Modality,
+			Tense:      e.Tense,
+			Authored:   e.Authored,
+			SourceDoc:  e.SourceDoc,
+			SourceSpan: e.SourceSpan,
+			VerbSpan:   e.VerbSpan,
+			Valid:      e.Valid,
+		})
 	}
 
+	// A rescan replaces the note's previous scanner evidence
+	retracted := graphMerger.RetractNote(noteID)
 	added := graphMerger.AddScannerGraph(g, noteID)
 
 	return map[string]interface{}{
-		"success": true,
-		"added":   added,
+		"success":   true,
+		"added":     added,
+		"retracted": len(retracted),
 	}
 }
 
@@ -1311,6 +1530,25 @@ func mergerAddLLM(this js.Value, args []js.Value) interface{} {
 	}
 }
 
+// mergerRetractLLM removes the LLM support a note contributed, e.g. before
+// its relations are re-extracted
+// Args: [noteId string]
+func mergerRetractLLM(this js.Value, args []js.Value) interface{} {
+	if graphMerger == nil {
+		return errorResult("Merger not initialized - call mergerInit first")
+	}
+	if len(args) < 1 {
+		return errorResult("mergerRetractLLM requires [noteId]")
+	}
+
+	retracted := graphMerger.RetractLLM(args[0].String())
+
+	return map[string]interface{}{
+		"success":   true,
+		"retracted": len(retracted),
+	}
+}
+
 // mergerAddManual adds manually created edges
 // Args: [edgesJSON string]
 func mergerAddManual(this js.Value, args []js.Value) interface{} {
@@ -1366,6 +1604,82 @@ func mergerGetStats(this js.Value, args []js.Value) interface{} {
 	return string(bytes)
 }
 
+// mergerGraphAt returns the relationship state at a story point
+// Args: [storyTime number]
+// Returns: MergedGraph JSON of the edges valid at that point
+func mergerGraphAt(this js.Value, args []js.Value) interface{} {
+	if graphMerger == nil {
+		return errorResult("Merger not initialized - call mergerInit first")
+	}
+	if len(args) < 1 {
+		return errorResult("mergerGraphAt requires 1 arg: storyTime")
+	}
+
+	bytes, err := json.Marshal(graphMerger.GraphAt(args[0].Float()))
+	if err != nil {
+		return errorResult("Failed to serialize graph: " + err.Error())
+	}
+	return string(bytes)
+}
+
+// storeEdgesAt returns the stored edges valid at a story point
+// Args: [storyTime number]
+// Returns: JSON array of edges
+func storeEdgesAt(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("storeEdgesAt requires 1 arg: storyTime")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	edges, err := sqlStore.ListEdgesAt(args[0].Float())
+	if err != nil {
+		return errorResult("list failed: " + err.Error())
+	}
+
+	bytes, _ := json.Marshal(edges)
+	return string(bytes)
+}
+
+// mergerSync persists the merged graph into the store's entities/edges,
+// retracting scanner edges whose evidence disappeared
+// Returns: SyncResult JSON
+func mergerSync(this js.Value, args []js.Value) interface{} {
+	if graphMerger == nil {
+		return errorResult("Merger not initialized - call mergerInit first")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	result, err := persist.NewService(sqlStore).Sync(graphMerger.GetMergedGraph())
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	jsonBytes, _ := json.Marshal(result)
+	return string(jsonBytes)
+}
+
+// mergerLoad rebuilds the merger from the store (call on startup)
+// Args: [factualityPolicy string (optional)]
+func mergerLoad(this js.Value, args []js.Value) interface{} {
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	graphMerger = merger.New()
+	graphMerger.SetOntology(relOntology)
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		graphMerger.SetFactualityPolicy(merger.FactualityPolicy(args[0].String()))
+	}
+	n, err := persist.NewService(sqlStore).Load(graphMerger)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	return successResult(fmt.Sprintf("Merger loaded with %d edges", n))
+}
+
 // =============================================================================
 // Phase 4: PCST Coherence Filter
 // =============================================================================
@@ -1396,7 +1710,147 @@ func mergerRunPCST(this js.Value, args []js.Value) interface{} {
 	if err != nil {
 		return errorResult("PCST failed: " + err.Error())
 	}
+	return pcstResult(filtered)
+}
+
+// mergerSummarize runs PCST on the merged graph with prizes from a policy
+// instead of hand-written values.
+// Args: [optionsJSON string (optional)]
+// optionsJSON: {"rootId", "mentions", "centrality", "recency", "halfLife",
+// "pruning", "numTrees"}; mentions/centrality/recency are prize weights
+// (default mentions=1, centrality=1), halfLife is in note-order units
+// (default 5), pruning is none|simple|gw|strong (default gw) and numTrees
+// caps an unrooted forest
+// Returns: filtered graph JSON
+func mergerSummarize(this js.Value, args []js.Value) interface{} {
+	if graphMerger == nil {
+		return errorResult("Merger not initialized - call mergerInit first")
+	}
+
+	var opts struct {
+		RootID     string  `json:"rootId"`
+		Mentions   float64 `json:"mentions"`
+		Centrality float64 `json:"centrality"`
+		Recency    float64 `json:"recency"`
+		HalfLife   float64 `json:"halfLife"`
+		Pruning    string  `json:"pruning"`
+		NumTrees   int     `json:"numTrees"`
+	}
+	if len(args) > 0 && args[0].Type() == js.TypeString && args[0].String() != "" {
+		if err := json.Unmarshal([]byte(args[0].String()), &opts); err != nil {
+			return errorResult("Failed to parse options JSON: " + err.Error())
+		}
+	}
+	if opts.Mentions == 0 && opts.Centrality == 0 && opts.Recency == 0 {
+		opts.Mentions, opts.Centrality = 1, 1
+	}
+	if opts.HalfLife == 0 {
+		opts.HalfLife = 5
+	}
+
+	mg := graphMerger.GetMergedGraph()
+	sources := []pcst.Weighted{
+		{Prizes: pcst.Mentions(entityMentions(mg)), Weight: opts.Mentions},
+		{Prizes: pcst.Centrality(mg.Graph()), Weight: opts.Centrality},
+	}
+	if opts.Recency > 0 {
+		order, err := noteOrders()
+		if err != nil {
+			return errorResult(err.Error())
+		}
+		lastSeen := mg.LastSeen(order)
+		now := 0.0
+		for _, t := range lastSeen {
+			now = max(now, t)
+		}
+		sources = append(sources, pcst.Weighted{Prizes: pcst.Recency(lastSeen, now, opts.HalfLife), Weight: opts.Recency})
+	}
+
+	cfg := pcst.DefaultConfig()
+	switch p := pcst.Pruning(opts.Pruning); p {
+	case "":
+	case pcst.PruneNone, pcst.PruneSimple, pcst.PruneGW, pcst.PruneStrong:
+		cfg.Pruning = p
+	default:
+		return errorResult("unknown pruning: " + opts.Pruning)
+	}
+	cfg.NumTrees = opts.NumTrees
+
+	filtered, err := graphMerger.SummarizeWith(cfg, pcst.Combine(sources...), opts.RootID, nil)
+	if err != nil {
+		return errorResult("PCST failed: " + err.Error())
+	}
+	return pcstResult(filtered)
+}
+
+// mergerQuerySubgraph returns the PCST summary around a search query:
+// entities named by the query or found in the notes it ranks, connected
+// through the cheapest, most confident edges.
+// Args: [query string, limit int (optional, notes to search, default 20)]
+// Returns: filtered graph JSON
+func mergerQuerySubgraph(this js.Value, args []js.Value) interface{} {
+	if graphMerger == nil {
+		return errorResult("Merger not initialized - call mergerInit first")
+	}
+	if len(args) < 1 {
+		return errorResult("mergerQuerySubgraph requires [query, limit?]")
+	}
+	query := args[0].String()
+	limit := 20
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		limit = args[1].Int()
+	}
+
+	hits := make(map[string]float64)
+	if searcher != nil {
+		for _, r := range searcher.Search(query, qgram.DefaultSearchConfig(), limit) {
+			hits[r.DocID] = r.Score
+		}
+	}
+
+	filtered, err := graphMerger.QuerySubgraph(query, hits)
+	if err != nil {
+		return errorResult("PCST failed: " + err.Error())
+	}
+	return pcstResult(filtered)
+}
+
+// entityMentions counts entity mentions: store totals where known, else the
+// sentences supporting each node's edges
+func entityMentions(mg *merger.MergedGraph) map[string]int {
+	counts := mg.Mentions()
+	if sqlStore == nil {
+		return counts
+	}
+	entities, err := sqlStore.ListEntities("")
+	if err != nil {
+		return counts
+	}
+	for _, e := range entities {
+		if _, ok := counts[e.ID]; ok && e.TotalMentions > 0 {
+			counts[e.ID] = e.TotalMentions
+		}
+	}
+	return counts
+}
+
+// noteOrders maps note IDs to their story position (note Order)
+func noteOrders() (map[string]float64, error) {
+	order := make(map[string]float64)
+	if sqlStore == nil {
+		return order, nil
+	}
+	notes, err := sqlStore.ListNotes("")
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		order[n.ID] = n.Order
+	}
+	return order, nil
+}
 
+func pcstResult(filtered *merger.MergedGraph) interface{} {
 	bytes, err := json.Marshal(map[string]interface{}{
 		"success":   true,
 		"graph":     filtered,
@@ -1406,7 +1860,6 @@ func mergerRunPCST(this js.Value, args []js.Value) interface{} {
 	if err != nil {
 		return errorResult("Failed to serialize result: " + err.Error())
 	}
-
 	return string(bytes)
 }
 
@@ -1544,12 +1997,16 @@ func jsBatchInit(this js.Value, args []js.Value) interface{} {
 
 	if batchSvc == nil {
 		batchSvc = batch.NewService(config)
+		batchSvc.SetUsageRecorder(recordUsage)
 	} else {
 		batchSvc.UpdateConfig(config)
 	}
 
 	// Initialize extraction and agent services
 	extractionSvc = extraction.NewService(batchSvc)
+	if sqlStore != nil {
+		extractionSvc.SetCache(sqlStore)
+	}
 	agentSvc = agent.NewService(batchSvc)
 
 	result, _ := json.Marshal(map[string]interface{}{
@@ -1560,9 +2017,99 @@ func jsBatchInit(this js.Value, args []js.Value) interface{} {
 	return string(result)
 }
 
+// jsBatchListModels lists the models the configured provider offers.
+// Returns: Promise<JSON> with {provider, models: [{id, name}], providers}
+func jsBatchListModels(this js.Value, args []js.Value) interface{} {
+	promise, resolve, reject := makePromise()
+
+	go func() {
+		if batchSvc == nil {
+			reject.Invoke(js.Global().Get("Error").New("batchListModels: service not initialized (call batchInit first)"))
+			return
+		}
+
+		models, err := batchSvc.ListModels(context.Background())
+		if err != nil {
+			reject.Invoke(js.Global().Get("Error").New(fmt.Sprintf("batchListModels: %v", err)))
+			return
+		}
+
+		jsonBytes, _ := json.Marshal(map[string]interface{}{
+			"provider":  string(batchSvc.GetConfig().Provider),
+			"models":    models,
+			"providers": batch.Providers(),
+		})
+		resolve.Invoke(string(jsonBytes))
+	}()
+
+	return promise
+}
+
+// usageSeq disambiguates usage IDs recorded in the same nanosecond.
+var usageSeq atomic.Int64
+
+// recordUsage persists a batch call's usage for the usage dashboard.
+// Calls made before storeInit are not recorded.
+func recordUsage(u batch.Usage) {
+	if sqlStore == nil {
+		return
+	}
+	errMsg := ""
+	if u.Err != nil {
+		errMsg = u.Err.Error()
+	}
+	sqlStore.AddUsage(&store.LLMUsage{
+		ID:               fmt.Sprintf("usage-%d-%d", u.At.UnixNano(), usageSeq.Add(1)),
+		Provider:         string(u.Provider),
+		Model:            u.Model,
+		Operation:        u.Operation,
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		LatencyMs:        u.Latency.Milliseconds(),
+		Attempts:         u.Attempts,
+		Error:            errMsg,
+		CreatedAt:        u.At.UnixMilli(),
+	})
+}
+
+// jsBatchUsage reports recorded LLM usage.
+// Args: sinceMs (number, optional), limit (number, optional, default 100)
+// Returns: JSON {summary: [...], recent: [...]}
+func jsBatchUsage(this js.Value, args []js.Value) interface{} {
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	var since int64
+	if len(args) > 0 && args[0].Type() == js.TypeNumber {
+		since = int64(args[0].Float())
+	}
+	limit := 100
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		limit = args[1].Int()
+	}
+
+	summary, err := sqlStore.SummarizeUsage(since)
+	if err != nil {
+		return errorResult(fmt.Sprintf("batchUsage: %v", err))
+	}
+	recent, err := sqlStore.ListUsage(since, limit)
+	if err != nil {
+		return errorResult(fmt.Sprintf("batchUsage: %v", err))
+	}
+
+	result, _ := json.Marshal(map[string]interface{}{
+		"summary": summary,
+		"recent":  recent,
+	})
+	return string(result)
+}
+
 // jsExtractFromNote performs unified entity + relation extraction via LLM.
-// Args: text (string), knownEntitiesJSON (string, optional)
-// Returns: Promise<JSON> with {entities: [...], relations: [...]}
+// With a noteId and an initialized store, unchanged notes are served from
+// the extraction cache.
+// Args: text (string), knownEntitiesJSON (string, optional), noteId (string, optional)
+// Returns: Promise<JSON> with {entities: [...], relations: [...], cached}
 func jsExtractFromNote(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
 		return errorResult("extractFromNote: text required")
@@ -1573,6 +2120,10 @@ func jsExtractFromNote(this js.Value, args []js.Value) interface{} {
 	if len(args) > 1 && !args[1].IsUndefined() && !args[1].IsNull() {
 		json.Unmarshal([]byte(args[1].String()), &knownEntities)
 	}
+	noteID := ""
+	if len(args) > 2 && args[2].Type() == js.TypeString {
+		noteID = args[2].String()
+	}
 
 	promise, resolve, reject := makePromise()
 
@@ -1582,54 +2133,172 @@ func jsExtractFromNote(this js.Value, args []js.Value) interface{} {
 			return
 		}
 
-		result, err := extractionSvc.ExtractFromNote(context.Background(), text, knownEntities)
+		var result *extraction.ExtractionResult
+		var cached bool
+		var err error
+		if noteID != "" {
+			result, cached, err = extractionSvc.ExtractFromNoteCached(context.Background(), noteID, text, knownEntities)
+		} else {
+			result, err = extractionSvc.ExtractFromNote(context.Background(), text, knownEntities)
+		}
 		if err != nil {
 			reject.Invoke(js.Global().Get("Error").New(fmt.Sprintf("extractFromNote: %v", err)))
 			return
 		}
 
-		jsonBytes, _ := json.Marshal(result)
+		jsonBytes, _ := json.Marshal(struct {
+			*extraction.ExtractionResult
+			Cached bool `json:"cached"`
+		}{result, cached})
 		resolve.Invoke(string(jsonBytes))
 	}()
 
 	return promise
 }
 
-// jsExtractEntities extracts entities only from text.
-// Args: text (string)
-// Returns: Promise<JSON> with entity array
-func jsExtractEntities(this js.Value, args []js.Value) interface{} {
+// jsExtractAndMerge runs the grounding pipeline on a DocStore note: extract
+// (cached), validate each relation against the note's CST, merge grounded
+// relations into the merger and register confident entities in the store.
+// Args: noteId (string), knownEntitiesJSON (string, optional),
+// optionsJSON (string, optional) - {minEntityConfidence, minRelationConfidence}
+// Returns: Promise<JSON> report of accepted/rejected entities and relations
+func jsExtractAndMerge(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
-		return errorResult("extractEntities: text required")
+		return errorResult("extractAndMerge: noteId required")
 	}
 
-	text := args[0].String()
+	noteID := args[0].String()
+	var knownEntities []string
+	if len(args) > 1 && args[1].Type() == js.TypeString {
+		json.Unmarshal([]byte(args[1].String()), &knownEntities)
+	}
+	var cfg grounding.Config
+	if len(args) > 2 && args[2].Type() == js.TypeString {
+		if err := json.Unmarshal([]byte(args[2].String()), &cfg); err != nil {
+			return errorResult(fmt.Sprintf("extractAndMerge: invalid options: %v", err))
+		}
+	}
 
 	promise, resolve, reject := makePromise()
 
 	go func() {
-		if extractionSvc == nil {
-			reject.Invoke(js.Global().Get("Error").New("extractEntities: service not initialized"))
+		fail := func(msg string) {
+			reject.Invoke(js.Global().Get("Error").New("extractAndMerge: " + msg))
+		}
+		switch {
+		case extractionSvc == nil:
+			fail("service not initialized (call batchInit first)")
+			return
+		case sqlStore == nil:
+			fail("store not initialized")
+			return
+		case graphMerger == nil:
+			fail("merger not initialized (call mergerInit first)")
+			return
+		}
+		note := docs.Get(noteID)
+		if note == nil {
+			fail("note not found in DocStore: " + noteID)
 			return
 		}
 
-		entities, err := extractionSvc.ExtractEntitiesFromNote(context.Background(), text)
+		p := grounding.New(extractionSvc, sqlStore, graphMerger, cfg)
+		p.SetOntology(relOntology)
+		report, err := p.Run(context.Background(), noteID, note.Text, knownEntities)
 		if err != nil {
-			reject.Invoke(js.Global().Get("Error").New(fmt.Sprintf("extractEntities: %v", err)))
+			fail(err.Error())
 			return
 		}
 
-		jsonBytes, _ := json.Marshal(entities)
+		jsonBytes, _ := json.Marshal(report)
 		resolve.Invoke(string(jsonBytes))
 	}()
 
 	return promise
 }
 
-// jsExtractRelations extracts relations only from text.
-// Args: text (string), knownEntitiesJSON (string, optional)
-// Returns: Promise<JSON> with relation array
-func jsExtractRelations(this js.Value, args []js.Value) interface{} {
+// jsExtractionCache reports or invalidates the extraction result cache.
+// Args: optionsJSON (string, optional) - {noteId?, model?}; either one drops
+// that note's or model's entries first
+// Returns: JSON {removed, stats: {entries, hits, bytes, models}}
+func jsExtractionCache(this js.Value, args []js.Value) interface{} {
+	if extractionSvc == nil {
+		return errorResult("extractionCache: service not initialized (call batchInit first)")
+	}
+
+	var opts struct {
+		NoteID string `json:"noteId"`
+		Model  string `json:"model"`
+	}
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		if err := json.Unmarshal([]byte(args[0].String()), &opts); err != nil {
+			return errorResult(fmt.Sprintf("extractionCache: invalid options: %v", err))
+		}
+	}
+
+	removed := 0
+	if opts.NoteID != "" {
+		n, err := extractionSvc.InvalidateNote(opts.NoteID)
+		if err != nil {
+			return errorResult(fmt.Sprintf("extractionCache: %v", err))
+		}
+		removed += n
+	}
+	if opts.Model != "" {
+		n, err := extractionSvc.InvalidateModel(opts.Model)
+		if err != nil {
+			return errorResult(fmt.Sprintf("extractionCache: %v", err))
+		}
+		removed += n
+	}
+
+	stats, err := extractionSvc.CacheStats()
+	if err != nil {
+		return errorResult(fmt.Sprintf("extractionCache: %v", err))
+	}
+
+	result, _ := json.Marshal(map[string]interface{}{
+		"removed": removed,
+		"stats":   stats,
+	})
+	return string(result)
+}
+
+// jsExtractEntities extracts entities only from text.
+// Args: text (string)
+// Returns: Promise<JSON> with entity array
+func jsExtractEntities(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("extractEntities: text required")
+	}
+
+	text := args[0].String()
+
+	promise, resolve, reject := makePromise()
+
+	go func() {
+		if extractionSvc == nil {
+			reject.Invoke(js.Global().Get("Error").New("extractEntities: service not initialized"))
+			return
+		}
+
+		entities, err := extractionSvc.ExtractEntitiesFromNote(context.Background(), text)
+		if err != nil {
+			reject.Invoke(js.Global().Get("Error").New(fmt.Sprintf("extractEntities: %v", err)))
+			return
+		}
+
+		jsonBytes, _ := json.Marshal(entities)
+		resolve.Invoke(string(jsonBytes))
+	}()
+
+	return promise
+}
+
+// jsExtractRelations extracts relations only from text.
+// Args: text (string), knownEntitiesJSON (string, optional)
+// Returns: Promise<JSON> with relation array
+func jsExtractRelations(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
 		return errorResult("extractRelations: text required")
 	}
@@ -1711,6 +2380,68 @@ func jsAgentChatWithTools(this js.Value, args []js.Value) interface{} {
 	return promise
 }
 
+// jsAgentRun runs the Go agent loop with the built-in store tools, writing
+// the transcript to the thread when threadId is given.
+// Args: messagesJSON (string), systemPrompt (string), optionsJSON (string, optional)
+// with {threadId, maxIterations, tokenBudget}
+// Returns: Promise<JSON> with {content, messages, steps, iterations, usage, stopReason}
+func jsAgentRun(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("agentRun: messagesJSON required")
+	}
+
+	messagesJSON := args[0].String()
+	systemPrompt := ""
+	if len(args) > 1 && !args[1].IsUndefined() && !args[1].IsNull() {
+		systemPrompt = args[1].String()
+	}
+	var opts struct {
+		ThreadID      string `json:"threadId"`
+		MaxIterations int    `json:"maxIterations"`
+		TokenBudget   int    `json:"tokenBudget"`
+	}
+	if len(args) > 2 && !args[2].IsUndefined() && !args[2].IsNull() {
+		if err := json.Unmarshal([]byte(args[2].String()), &opts); err != nil {
+			return errorResult(fmt.Sprintf("agentRun: invalid options: %v", err))
+		}
+	}
+
+	promise, resolve, reject := makePromise()
+
+	go func() {
+		if agentSvc == nil {
+			reject.Invoke(js.Global().Get("Error").New("agentRun: service not initialized (call batchInit first)"))
+			return
+		}
+		if sqlStore == nil {
+			reject.Invoke(js.Global().Get("Error").New("agentRun: store not initialized"))
+			return
+		}
+
+		var messages []agent.Message
+		if err := json.Unmarshal([]byte(messagesJSON), &messages); err != nil {
+			reject.Invoke(js.Global().Get("Error").New(fmt.Sprintf("agentRun: invalid messages: %v", err)))
+			return
+		}
+
+		tools := agent.NewRegistry(agent.StoreTools(sqlStore, relOntology)...)
+		runner := agent.NewRunner(agentSvc, tools, sqlStore, agent.RunConfig{
+			MaxIterations: opts.MaxIterations,
+			TokenBudget:   opts.TokenBudget,
+		})
+		result, err := runner.Run(context.Background(), opts.ThreadID, messages, systemPrompt)
+		if err != nil {
+			reject.Invoke(js.Global().Get("Error").New(fmt.Sprintf("agentRun: %v", err)))
+			return
+		}
+
+		jsonBytes, _ := json.Marshal(result)
+		resolve.Invoke(string(jsonBytes))
+	}()
+
+	return promise
+}
+
 // =============================================================================
 // Phase 7: Observational Memory + Chat Service Bridge
 // =============================================================================
@@ -1831,6 +2562,8 @@ func jsChatAddMessage(this js.Value, args []js.Value) interface{} {
 		return errorResult(err.Error())
 	}
 
+	observeThread(msg.ThreadID)
+
 	jsonBytes, _ := json.Marshal(msg)
 	return string(jsonBytes)
 }
@@ -1868,9 +2601,22 @@ func jsChatUpdateMessage(this js.Value, args []js.Value) interface{} {
 		return errorResult(err.Error())
 	}
 
+	// Updating ends streaming: the finished reply can now be observed
+	if msg, err := sqlStore.GetMessage(args[0].String()); err == nil && msg != nil {
+		observeThread(msg.ThreadID)
+	}
+
 	return successResult("Message updated")
 }
 
+// observeThread lets the Observer catch up in the background; it no-ops
+// below threshold
+func observeThread(threadID string) {
+	if omSvc != nil {
+		go omSvc.Process(context.Background(), threadID)
+	}
+}
+
 // jsChatAppendMessage appends content to a message.
 // Args: messageID, chunk (strings)
 func jsChatAppendMessage(this js.Value, args []js.Value) interface{} {
@@ -1983,3 +2729,645 @@ func jsChatExportThread(this js.Value, args []js.Value) interface{} {
 
 	return jsonStr
 }
+
+// jsOMInit starts the Observer/Reflector pipeline on the batch LLM.
+// Args: configJSON (string, optional) - {observeThreshold, reflectThreshold,
+// maxRetries, enabled}; omitted fields keep their defaults
+func jsOMInit(this js.Value, args []js.Value) interface{} {
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+	if batchSvc == nil {
+		return errorResult("batch service not initialized (call batchInit first)")
+	}
+
+	cfg := om.DefaultConfig()
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		opts := struct {
+			ObserveThreshold *int  `json:"observeThreshold"`
+			ReflectThreshold *int  `json:"reflectThreshold"`
+			MaxRetries       *int  `json:"maxRetries"`
+			Enabled          *bool `json:"enabled"`
+		}{}
+		if err := json.Unmarshal([]byte(args[0].String()), &opts); err != nil {
+			return errorResult("invalid config JSON: " + err.Error())
+		}
+		if opts.ObserveThreshold != nil {
+			cfg.ObserveThreshold = *opts.ObserveThreshold
+		}
+		if opts.ReflectThreshold != nil {
+			cfg.ReflectThreshold = *opts.ReflectThreshold
+		}
+		if opts.MaxRetries != nil {
+			cfg.MaxRetries = *opts.MaxRetries
+		}
+		if opts.Enabled != nil {
+			cfg.Enabled = *opts.Enabled
+		}
+	}
+
+	omSvc = om.NewService(batchSvc, sqlStore, cfg)
+	return successResult("Observational memory initialized")
+}
+
+// jsOMProcess runs the Observer (and Reflector when due) for a thread.
+// Args: threadID (string)
+// Returns: Promise<JSON> {threadId, pendingTokens, observed, messagesObserved,
+// reflected, attempts, generation, obsTokenCount}
+func jsOMProcess(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("omProcess: threadId required")
+	}
+	threadID := args[0].String()
+
+	promise, resolve, reject := makePromise()
+
+	go func() {
+		if omSvc == nil {
+			reject.Invoke(js.Global().Get("Error").New("omProcess: service not initialized (call omInit first)"))
+			return
+		}
+
+		result, err := omSvc.Process(context.Background(), threadID)
+		if err != nil {
+			reject.Invoke(js.Global().Get("Error").New(fmt.Sprintf("omProcess: %v", err)))
+			return
+		}
+
+		jsonBytes, _ := json.Marshal(result)
+		resolve.Invoke(string(jsonBytes))
+	}()
+
+	return promise
+}
+
+// jsOMGetContext returns the observations block to inject into the next
+// prompt ("" when the thread has none).
+// Args: threadID (string)
+func jsOMGetContext(this js.Value, args []js.Value) interface{} {
+	if omSvc == nil {
+		return errorResult("observational memory not initialized")
+	}
+	if len(args) < 1 {
+		return errorResult("missing arguments")
+	}
+
+	block, err := omSvc.ContextBlock(args[0].String())
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	return block
+}
+
+// jsOMGetRecord returns a thread's OM record and reflection history.
+// Args: threadID (string)
+// Returns: JSON {record, generations, pendingTokens}
+func jsOMGetRecord(this js.Value, args []js.Value) interface{} {
+	if omSvc == nil {
+		return errorResult("observational memory not initialized")
+	}
+	if len(args) < 1 {
+		return errorResult("missing arguments")
+	}
+	threadID := args[0].String()
+
+	record, err := sqlStore.GetOMRecord(threadID)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	generations, err := sqlStore.GetOMGenerations(threadID)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	pending, err := omSvc.PendingTokens(threadID)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	jsonBytes, _ := json.Marshal(map[string]interface{}{
+		"record":        record,
+		"generations":   generations,
+		"pendingTokens": pending,
+	})
+	return string(jsonBytes)
+}
+
+// jsMemoryInit starts structured memory extraction on the batch LLM.
+// Args: optionsJSON (string, optional) - {minConfidence, dedupThreshold}
+func jsMemoryInit(this js.Value, args []js.Value) interface{} {
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+	if batchSvc == nil {
+		return errorResult("batch service not initialized (call batchInit first)")
+	}
+
+	cfg := memory.DefaultConfig()
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		opts := struct {
+			MinConfidence  float64 `json:"minConfidence"`
+			DedupThreshold float64 `json:"dedupThreshold"`
+		}{}
+		if err := json.Unmarshal([]byte(args[0].String()), &opts); err != nil {
+			return errorResult("invalid options JSON: " + err.Error())
+		}
+		cfg = memory.Config{MinConfidence: opts.MinConfidence, DedupThreshold: opts.DedupThreshold}
+	}
+
+	memoryExtractor = memory.NewExtractor(batchSvc, sqlStore, cfg)
+	return successResult("Memory extraction initialized")
+}
+
+// jsMemoryExtract extracts memories from a thread's new messages.
+// Args: threadID (string)
+// Returns: Promise<JSON> {threadId, messagesProcessed, created, merged, outcomes}
+func jsMemoryExtract(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("memoryExtract: threadId required")
+	}
+	threadID := args[0].String()
+
+	promise, resolve, reject := makePromise()
+
+	go func() {
+		if memoryExtractor == nil {
+			reject.Invoke(js.Global().Get("Error").New("memoryExtract: service not initialized (call memoryInit first)"))
+			return
+		}
+
+		result, err := memoryExtractor.ProcessThread(context.Background(), threadID)
+		if err != nil {
+			reject.Invoke(js.Global().Get("Error").New(fmt.Sprintf("memoryExtract: %v", err)))
+			return
+		}
+
+		jsonBytes, _ := json.Marshal(result)
+		resolve.Invoke(string(jsonBytes))
+	}()
+
+	return promise
+}
+
+// jsMemoryRecall recalls memories across threads by entity: either one
+// entity by ID, or every known entity mentioned in a text.
+// Args: optionsJSON (string) - {entityId?, text?, excludeThreadId?, limit?}
+// Returns: JSON array of memories with the threads they were seen in
+func jsMemoryRecall(this js.Value, args []js.Value) interface{} {
+	if memoryExtractor == nil {
+		return errorResult("memory extraction not initialized")
+	}
+	if len(args) < 1 {
+		return errorResult("missing arguments")
+	}
+
+	var opts struct {
+		EntityID        string `json:"entityId"`
+		Text            string `json:"text"`
+		ExcludeThreadID string `json:"excludeThreadId"`
+		Limit           int    `json:"limit"`
+	}
+	if err := json.Unmarshal([]byte(args[0].String()), &opts); err != nil {
+		return errorResult("invalid options JSON: " + err.Error())
+	}
+
+	var recalled []*memory.Recalled
+	var err error
+	switch {
+	case opts.EntityID != "":
+		recalled, err = memoryExtractor.RecallByEntity(opts.EntityID, opts.ExcludeThreadID)
+		if err == nil && opts.Limit > 0 && len(recalled) > opts.Limit {
+			recalled = recalled[:opts.Limit]
+		}
+	case opts.Text != "":
+		recalled, err = memoryExtractor.RecallForText(opts.Text, opts.ExcludeThreadID, opts.Limit)
+	default:
+		return errorResult("entityId or text required")
+	}
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	jsonBytes, _ := json.Marshal(recalled)
+	return string(jsonBytes)
+}
+
+// =============================================================================
+// Phase 8: World Coreference Bridge
+// =============================================================================
+
+// jsCorefInit seeds the world entity registry from the store and makes the
+// scanner resolve names, aliases, pronouns and descriptions against it.
+// Returns: {success, message} with the number of entities loaded
+func jsCorefInit(this js.Value, args []js.Value) interface{} {
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+
+	corefSvc = coref.NewService(sqlStore)
+	n, err := corefSvc.Load()
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	pipeline.SetRegistry(corefSvc.Registry())
+
+	return successResult(fmt.Sprintf("coreference initialized with %d entities", n))
+}
+
+// jsCorefConfirm confirms that a mention refers to an entity; new names and
+// descriptions become aliases.
+// Args: text, entityID (strings)
+// Returns: updated Entity JSON, or null when nothing changed
+func jsCorefConfirm(this js.Value, args []js.Value) interface{} {
+	return corefFeedback(args, corefSvc.Confirm)
+}
+
+// jsCorefReject rejects a resolution; a matching alias is removed.
+// Args: text, entityID (strings)
+// Returns: updated Entity JSON, or null when aliases did not change
+func jsCorefReject(this js.Value, args []js.Value) interface{} {
+	return corefFeedback(args, corefSvc.Reject)
+}
+
+func corefFeedback(args []js.Value, apply func(text, entityID string) (*store.Entity, error)) interface{} {
+	if corefSvc == nil {
+		return errorResult("coreference not initialized")
+	}
+	if len(args) < 2 {
+		return errorResult("missing arguments")
+	}
+
+	entity, err := apply(args[0].String(), args[1].String())
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	if entity == nil {
+		return "null"
+	}
+
+	jsonBytes, _ := json.Marshal(entity)
+	return string(jsonBytes)
+}
+
+// =============================================================================
+// Phase 9: Discovery Review Queue Bridge
+// =============================================================================
+
+// jsDiscoveryInit restores the scanner's discovery registry from the
+// persisted queue and stop list. Subsequent scanDiscovery calls with a
+// worldID and noteID are recorded.
+// Args: worldID (string)
+func jsDiscoveryInit(this js.Value, args []js.Value) interface{} {
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+	if len(args) < 1 {
+		return errorResult("discoveryInit requires 1 argument: worldID")
+	}
+
+	reviewSvc = review.NewService(sqlStore)
+	if err := reviewSvc.Hydrate(args[0].String(), pipeline.DiscoveryRegistry()); err != nil {
+		return errorResult(err.Error())
+	}
+	return successResult("discovery review queue initialized")
+}
+
+// jsDiscoveryQueue returns the candidates awaiting review.
+// Args: worldID (string)
+// Returns: DiscoveryCandidate[] JSON
+func jsDiscoveryQueue(this js.Value, args []js.Value) interface{} {
+	if reviewSvc == nil {
+		return errorResult("discovery review not initialized")
+	}
+	if len(args) < 1 {
+		return errorResult("discoveryQueue requires 1 argument: worldID")
+	}
+
+	queue, err := reviewSvc.Queue(args[0].String())
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	jsonBytes, _ := json.Marshal(queue)
+	return string(jsonBytes)
+}
+
+// jsDiscoveryAccept promotes a candidate to an entity.
+// Args: worldID, token, kind (optional; defaults to the inferred kind)
+// Returns: Entity JSON
+func jsDiscoveryAccept(this js.Value, args []js.Value) interface{} {
+	if reviewSvc == nil {
+		return errorResult("discovery review not initialized")
+	}
+	if len(args) < 2 {
+		return errorResult("discoveryAccept requires worldID and token")
+	}
+
+	kind := ""
+	if len(args) > 2 && !args[2].IsUndefined() && !args[2].IsNull() {
+		kind = args[2].String()
+	}
+	entity, err := reviewSvc.Accept(args[0].String(), args[1].String(), kind)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	k := implicitmatcher.ParseKind(entity.Kind)
+	pipeline.DiscoveryRegistry().Restore(entity.Label, entity.TotalMentions, discovery.StatusPromoted, &k)
+	if corefSvc != nil {
+		corefSvc.Add(entity)
+	}
+
+	jsonBytes, _ := json.Marshal(entity)
+	return string(jsonBytes)
+}
+
+// jsDiscoveryReject adds a candidate to the world's stop list.
+// Args: worldID, token (strings)
+func jsDiscoveryReject(this js.Value, args []js.Value) interface{} {
+	if reviewSvc == nil {
+		return errorResult("discovery review not initialized")
+	}
+	if len(args) < 2 {
+		return errorResult("discoveryReject requires worldID and token")
+	}
+
+	if err := reviewSvc.Reject(args[0].String(), args[1].String()); err != nil {
+		return errorResult(err.Error())
+	}
+	pipeline.DiscoveryRegistry().Ignore(args[1].String())
+	return successResult("candidate rejected")
+}
+
+// jsDiscoverySnooze hides a candidate from the queue for a while.
+// Args: worldID, token (strings), minutes (number)
+func jsDiscoverySnooze(this js.Value, args []js.Value) interface{} {
+	if reviewSvc == nil {
+		return errorResult("discovery review not initialized")
+	}
+	if len(args) < 3 {
+		return errorResult("discoverySnooze requires worldID, token and minutes")
+	}
+
+	d := time.Duration(args[2].Float() * float64(time.Minute))
+	if err := reviewSvc.Snooze(args[0].String(), args[1].String(), d); err != nil {
+		return errorResult(err.Error())
+	}
+	return successResult("candidate snoozed")
+}
+
+// =============================================================================
+// Phase 10: Graph Algorithms Bridge
+// =============================================================================
+
+// storyGraph returns the merged graph without hierarchy edges, so paths
+// and communities follow story relations rather than shared notes
+func storyGraph() (*graph.ConceptGraph, error) {
+	if graphMerger == nil {
+		return nil, fmt.Errorf("Merger not initialized - call mergerInit first")
+	}
+	g := graphMerger.GetMergedGraph().Graph()
+	return g.Filter(func(e *graph.ConceptEdge) bool { return !graph.IsHierarchy(e.Relation) }), nil
+}
+
+// jsGraphShortestPath finds the most confident path between two entities.
+// Args: fromID, toID (strings)
+// Returns: Path JSON, or null when not connected
+func jsGraphShortestPath(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return errorResult("graphShortestPath requires fromID and toID")
+	}
+	g, err := storyGraph()
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	jsonBytes, _ := json.Marshal(g.ShortestPath(args[0].String(), args[1].String()))
+	return string(jsonBytes)
+}
+
+// jsGraphEgoNetwork returns the k-hop neighborhood of an entity.
+// Args: id (string), k (number, default 1)
+// Returns: ConceptGraph JSON
+func jsGraphEgoNetwork(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("graphEgoNetwork requires an entity id")
+	}
+	g, err := storyGraph()
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	k := 1
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		k = args[1].Int()
+	}
+	ego := g.EgoNetwork(args[0].String(), k)
+	ego.ToSerializable()
+	jsonBytes, _ := json.Marshal(ego)
+	return string(jsonBytes)
+}
+
+// jsGraphCentrality computes degree, PageRank and betweenness per entity.
+// Args: []
+// Returns: {degree, pagerank, betweenness} maps of id -> score
+func jsGraphCentrality(this js.Value, args []js.Value) interface{} {
+	g, err := storyGraph()
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	jsonBytes, _ := json.Marshal(map[string]map[string]float64{
+		"degree":      g.DegreeCentrality(),
+		"pagerank":    g.PageRank(0.85, 100),
+		"betweenness": g.BetweennessCentrality(),
+	})
+	return string(jsonBytes)
+}
+
+// jsGraphCommunities detects communities with stable IDs.
+// Args: []
+// Returns: map of id -> community ID
+func jsGraphCommunities(this js.Value, args []js.Value) interface{} {
+	g, err := storyGraph()
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	jsonBytes, _ := json.Marshal(g.Communities())
+	return string(jsonBytes)
+}
+
+// =============================================================================
+// Phase 11: Relation Ontology Bridge
+// =============================================================================
+
+// jsOntologyGet returns the active relation ontology.
+// Returns: JSON array of relations
+func jsOntologyGet(this js.Value, args []js.Value) interface{} {
+	jsonBytes, _ := json.Marshal(relOntology.Relations())
+	return string(jsonBytes)
+}
+
+// jsOntologySet replaces the relation ontology (empty resets to the default).
+// The merger and validator use it from then on.
+// Args: relationsJSON (string, JSON array of relations)
+func jsOntologySet(this js.Value, args []js.Value) interface{} {
+	o := ontology.Default()
+	if len(args) > 0 && args[0].Type() == js.TypeString && args[0].String() != "" {
+		parsed, err := ontology.Parse([]byte(args[0].String()))
+		if err != nil {
+			return errorResult(err.Error())
+		}
+		o = parsed
+	}
+
+	relOntology = o
+	if graphMerger != nil {
+		graphMerger.SetOntology(o)
+	}
+	return successResult(fmt.Sprintf("ontology set with %d relations", len(o.Relations())))
+}
+
+// jsOntologyCheck normalises a relation and checks its entity kinds.
+// Args: sourceID, relType, targetID, sourceKind, targetKind (strings)
+// Returns: {source, relType, target, violations}
+func jsOntologyCheck(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return errorResult("ontologyCheck requires sourceID, relType and targetID")
+	}
+	var sourceKind, targetKind string
+	if len(args) > 4 {
+		sourceKind, targetKind = args[3].String(), args[4].String()
+	}
+
+	src, rel, tgt := relOntology.Normalize(args[0].String(), args[1].String(), args[2].String())
+	jsonBytes, _ := json.Marshal(map[string]interface{}{
+		"source":     src,
+		"relType":    rel,
+		"target":     tgt,
+		"violations": relOntology.Check(args[1].String(), sourceKind, targetKind),
+	})
+	return string(jsonBytes)
+}
+
+// =============================================================================
+// Phase 12: Consistency Checker Bridge
+// =============================================================================
+
+// jsCheckConsistency reports contradictions in the merged world graph.
+// Edges are placed in story time by their validity or by the Order of the
+// notes they came from.
+// Returns: JSON array of contradictions
+func jsCheckConsistency(this js.Value, args []js.Value) interface{} {
+	if graphMerger == nil {
+		return errorResult("Merger not initialized - call mergerInit first")
+	}
+
+	order, err := noteOrders()
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	found := consistency.NewChecker().Check(graphMerger.GetMergedGraph(), order)
+	jsonBytes, _ := json.Marshal(found)
+	return string(jsonBytes)
+}
+
+// =============================================================================
+// Phase 13: Narrative Analytics Bridge
+// =============================================================================
+
+// jsAnalyzeNote computes prose metrics (counts, reading time, flow) for a
+// note in DocStore. Flow uses the merged graph for indirect links.
+// Args: noteId (string)
+// Returns: JSON metrics
+func jsAnalyzeNote(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("analyzeNote requires noteId")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+	text := docs.GetText(args[0].String())
+	if text == "" {
+		return errorResult("note not found in DocStore: " + args[0].String())
+	}
+
+	g := graph.NewGraph()
+	if graphMerger != nil {
+		g = graphMerger.GetMergedGraph().Graph()
+	}
+	jsonBytes, _ := json.Marshal(analysis.NewAnalyzer(g).Analyze(pipeline.Scan(text)))
+	return string(jsonBytes)
+}
+
+// jsAnalyzeWorld computes world analytics across the stored notes in
+// narrative order: presence timelines, interaction matrix, event classes
+// per chapter, character arcs and dormant characters.
+// Entity notes are not chapters and are skipped.
+// Args: optionsJSON (string, optional): {narrativeId, kinds, dormantAfter};
+// kinds limits tracked entities (e.g. ["CHARACTER"]), default all
+// Returns: JSON world report
+func jsAnalyzeWorld(this js.Value, args []js.Value) interface{} {
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	var opts struct {
+		NarrativeID  string   `json:"narrativeId"`
+		Kinds        []string `json:"kinds"`
+		DormantAfter int      `json:"dormantAfter"`
+	}
+	if len(args) > 0 && args[0].Type() == js.TypeString && args[0].String() != "" {
+		if err := json.Unmarshal([]byte(args[0].String()), &opts); err != nil {
+			return errorResult("invalid options: " + err.Error())
+		}
+	}
+
+	notes, err := sqlStore.ListNotes("")
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	var chapters []analysis.Chapter
+	for _, n := range notes {
+		if n.IsEntity || (opts.NarrativeID != "" && n.NarrativeID != opts.NarrativeID) {
+			continue
+		}
+		chapters = append(chapters, analysis.Chapter{
+			NoteID: n.ID,
+			Title:  n.Title,
+			Order:  n.Order,
+			Scan:   pipeline.Scan(n.Content),
+		})
+	}
+
+	worldOpts := analysis.WorldOptions{DormantAfter: opts.DormantAfter}
+	if entities, err := sqlStore.ListEntities(""); err == nil && len(entities) > 0 {
+		worldOpts.Entities = make(map[string]string)
+		for _, e := range entities {
+			if len(opts.Kinds) == 0 || containsFold(opts.Kinds, e.Kind) {
+				worldOpts.Entities[e.ID] = e.Label
+			}
+		}
+	}
+
+	jsonBytes, _ := json.Marshal(analysis.AnalyzeWorld(chapters, worldOpts))
+	return string(jsonBytes)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
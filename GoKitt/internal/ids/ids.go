@@ -0,0 +1,14 @@
+// Package ids generates the random IDs given to stored records.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// New creates a random 16-character hex ID
+func New() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Discovery Review Queue Tests
+// =============================================================================
+
+func TestCandidate_UpsertAndGet(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UnixMilli()
+
+	c := &DiscoveryCandidate{
+		WorldID:      "world-1",
+		Token:        "eldoria",
+		Display:      "Eldoria",
+		Count:        3,
+		NoteCounts:   map[string]int{"note-1": 2, "note-2": 1},
+		InferredKind: "LOCATION",
+		FirstNoteID:  "note-1",
+		LastNoteID:   "note-2",
+		Contexts:     []string{"rode north to Eldoria before dawn"},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	require.NoError(t, s.UpsertCandidate(c))
+
+	got, err := s.GetCandidate("world-1", "eldoria")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "Eldoria", got.Display)
+	assert.Equal(t, 3, got.Count)
+	assert.Equal(t, c.NoteCounts, got.NoteCounts)
+	assert.Equal(t, c.Contexts, got.Contexts)
+	assert.Equal(t, CandidatePending, got.Status, "empty status defaults to pending")
+
+	// Scoped by world
+	other, err := s.GetCandidate("world-2", "eldoria")
+	require.NoError(t, err)
+	assert.Nil(t, other)
+}
+
+func TestCandidate_ListByStatus(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UnixMilli()
+
+	for _, c := range []*DiscoveryCandidate{
+		{WorldID: "w", Token: "arin", Display: "Arin", Count: 2, Status: CandidatePending},
+		{WorldID: "w", Token: "bram", Display: "Bram", Count: 7, Status: CandidatePending},
+		{WorldID: "w", Token: "tuesday", Display: "Tuesday", Count: 4, Status: CandidateRejected},
+		{WorldID: "x", Token: "lyra", Display: "Lyra", Count: 1, Status: CandidatePending},
+	} {
+		c.CreatedAt, c.UpdatedAt = now, now
+		require.NoError(t, s.UpsertCandidate(c))
+	}
+
+	pending, err := s.ListCandidates("w", CandidatePending)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "bram", pending[0].Token, "most frequent first")
+
+	all, err := s.ListCandidates("w", "")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	require.NoError(t, s.DeleteCandidate("w", "arin"))
+	pending, err = s.ListCandidates("w", CandidatePending)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
+
+func TestStopWords(t *testing.T) {
+	s := newTestStore(t)
+
+	require.NoError(t, s.AddStopWord("w", "tuesday"))
+	require.NoError(t, s.AddStopWord("w", "tuesday"), "adding twice is a no-op")
+	require.NoError(t, s.AddStopWord("w", "chapter"))
+	require.NoError(t, s.AddStopWord("x", "arin"))
+
+	words, err := s.ListStopWords("w")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chapter", "tuesday"}, words)
+
+	require.NoError(t, s.RemoveStopWord("w", "chapter"))
+	words, err = s.ListStopWords("w")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tuesday"}, words)
+}
+
+func TestCandidate_ExportImport(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UnixMilli()
+
+	require.NoError(t, s.UpsertCandidate(&DiscoveryCandidate{
+		WorldID: "w", Token: "eldoria", Display: "Eldoria", Count: 2,
+		Status: CandidateSnoozed, SnoozedUntil: now + 1000, CreatedAt: now, UpdatedAt: now,
+	}))
+	require.NoError(t, s.AddStopWord("w", "tuesday"))
+
+	data, err := s.Export()
+	require.NoError(t, err)
+
+	s2 := newTestStore(t)
+	require.NoError(t, s2.Import(data))
+
+	got, err := s2.GetCandidate("w", "eldoria")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, CandidateSnoozed, got.Status)
+	assert.Equal(t, now+1000, got.SnoozedUntil)
+
+	words, err := s2.ListStopWords("w")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tuesday"}, words)
+}
+
+func TestCorefRejections(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.UpsertEntity(&Entity{ID: "e1", Label: "Gandalf", Kind: "CHARACTER", Aliases: []string{}}))
+
+	require.NoError(t, s.AddCorefRejection("the wizard", "e1"))
+	require.NoError(t, s.AddCorefRejection("the wizard", "e1"), "adding twice is a no-op")
+	require.NoError(t, s.AddCorefRejection("the king", "e1"))
+
+	data, err := s.Export()
+	require.NoError(t, err)
+	s2 := newTestStore(t)
+	require.NoError(t, s2.Import(data))
+	got, err := s2.ListCorefRejections()
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	require.NoError(t, s.RemoveCorefRejection("the king", "e1"))
+	got, err = s.ListCorefRejections()
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "the wizard", got[0].Text)
+
+	// Deleting the entity drops its rejections
+	require.NoError(t, s.DeleteEntity("e1"))
+	got, err = s.ListCorefRejections()
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
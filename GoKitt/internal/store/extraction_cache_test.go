@@ -0,0 +1,107 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Extraction Cache Tests
+// =============================================================================
+
+func cacheEntry(content, model, note string) *ExtractionCacheEntry {
+	return &ExtractionCacheEntry{
+		ExtractionCacheKey: ExtractionCacheKey{ContentHash: content, Provider: "p", Model: model, PromptVersion: "1", EntitiesHash: "e"},
+		NoteIDs:            []string{note},
+		Result:             `{"entities":[],"relations":[]}`,
+		CreatedAt:          1000,
+		LastUsedAt:         1000,
+	}
+}
+
+func TestExtractionCache_PutGetTouch(t *testing.T) {
+	s := newTestStore(t)
+
+	entry := cacheEntry("h1", "gemini", "n1")
+	require.NoError(t, s.PutExtractionCache(entry))
+
+	got, err := s.GetExtractionCache(entry.ExtractionCacheKey)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, entry, got)
+
+	require.NoError(t, s.TouchExtractionCache(entry.ExtractionCacheKey, "n2", 2000))
+	require.NoError(t, s.TouchExtractionCache(entry.ExtractionCacheKey, "n1", 3000))
+	got, err = s.GetExtractionCache(entry.ExtractionCacheKey)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Hits)
+	assert.Equal(t, int64(3000), got.LastUsedAt)
+	assert.Equal(t, []string{"n1", "n2"}, got.NoteIDs)
+
+	// Any key component differing is a miss
+	for _, change := range []func(*ExtractionCacheKey){
+		func(k *ExtractionCacheKey) { k.PromptVersion = "2" },
+		func(k *ExtractionCacheKey) { k.Provider = "other" },
+	} {
+		other := entry.ExtractionCacheKey
+		change(&other)
+		missing, err := s.GetExtractionCache(other)
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+	}
+}
+
+func TestExtractionCache_InvalidateAndStats(t *testing.T) {
+	s := newTestStore(t)
+
+	require.NoError(t, s.PutExtractionCache(cacheEntry("h1", "gemini", "n1")))
+	require.NoError(t, s.PutExtractionCache(cacheEntry("h2", "gemini", "n2")))
+	require.NoError(t, s.PutExtractionCache(cacheEntry("h3", "claude", "n1")))
+	require.NoError(t, s.TouchExtractionCache(cacheEntry("h2", "gemini", "").ExtractionCacheKey, "", 2000))
+
+	stats, err := s.ExtractionCacheStats()
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Entries)
+	assert.Equal(t, 1, stats.Hits)
+	require.Len(t, stats.Models, 2)
+	assert.Equal(t, "gemini", stats.Models[0].Model)
+	assert.Equal(t, 2, stats.Models[0].Entries)
+
+	n, err := s.DeleteExtractionCacheByNote("n1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = s.DeleteExtractionCacheByModel("gemini")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	stats, err = s.ExtractionCacheStats()
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Entries)
+	assert.NotNil(t, stats.Models)
+	assert.Empty(t, stats.Models)
+}
+
+func TestExtractionCache_InvalidateSharedEntry(t *testing.T) {
+	s := newTestStore(t)
+
+	// Two notes with identical text share one entry
+	entry := cacheEntry("h1", "gemini", "n1")
+	require.NoError(t, s.PutExtractionCache(entry))
+	require.NoError(t, s.TouchExtractionCache(entry.ExtractionCacheKey, "n2", 2000))
+
+	n, err := s.DeleteExtractionCacheByNote("n1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	got, err := s.GetExtractionCache(entry.ExtractionCacheKey)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// Stale links are dropped with the entry
+	require.NoError(t, s.PutExtractionCache(cacheEntry("h1", "gemini", "n3")))
+	got, err = s.GetExtractionCache(entry.ExtractionCacheKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"n3"}, got.NoteIDs)
+}
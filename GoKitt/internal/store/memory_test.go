@@ -0,0 +1,76 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Memory Tests
+// =============================================================================
+
+func TestMemory_UpdateLinkAndRecallByEntity(t *testing.T) {
+	s := newTestStore(t)
+
+	m := &Memory{ID: "m1", Content: "Lyra hates the sea", MemoryType: MemoryTypeFact, Confidence: 0.6,
+		SourceRole: "user", EntityID: "e-lyra", CreatedAt: 1000, UpdatedAt: 1000}
+	require.NoError(t, s.CreateMemory(m, "t1", "msg-1"))
+	require.NoError(t, s.CreateMemory(&Memory{ID: "m2", Content: "Arin is tall", MemoryType: MemoryTypeFact,
+		Confidence: 0.9, EntityID: "e-arin", CreatedAt: 1000, UpdatedAt: 1000}, "t1", "msg-1"))
+
+	m.Confidence = 0.8
+	m.UpdatedAt = 2000
+	require.NoError(t, s.UpdateMemory(m))
+	got, err := s.GetMemory("m1")
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, got.Confidence)
+	assert.Equal(t, int64(2000), got.UpdatedAt)
+
+	// Linking to a second thread, twice, keeps one link per thread
+	require.NoError(t, s.LinkMemory("m1", "t2", "msg-9", 3000))
+	require.NoError(t, s.LinkMemory("m1", "t2", "msg-10", 4000))
+	links, err := s.GetMemoryThreads("m1")
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, "t1", links[0].ThreadID)
+	assert.Equal(t, "msg-1", links[0].MessageID)
+	assert.Equal(t, "msg-9", links[1].MessageID)
+
+	t2, err := s.GetMemoriesForThread("t2")
+	require.NoError(t, err)
+	require.Len(t, t2, 1)
+
+	byEntity, err := s.ListMemoriesByEntity("e-lyra")
+	require.NoError(t, err)
+	require.Len(t, byEntity, 1)
+	assert.Equal(t, "m1", byEntity[0].ID)
+
+	none, err := s.ListMemoriesByEntity("e-missing")
+	require.NoError(t, err)
+	assert.NotNil(t, none)
+	assert.Empty(t, none)
+}
+
+func TestMemoryCursor(t *testing.T) {
+	s := newTestStore(t)
+
+	id, at, err := s.GetMemoryCursor("t1")
+	require.NoError(t, err)
+	assert.Equal(t, "", id)
+	assert.Equal(t, int64(0), at)
+
+	require.NoError(t, s.SetMemoryCursor("t1", "msg-1", 100))
+	require.NoError(t, s.SetMemoryCursor("t1", "msg-2", 250))
+	id, at, err = s.GetMemoryCursor("t1")
+	require.NoError(t, err)
+	assert.Equal(t, "msg-2", id)
+	assert.Equal(t, int64(250), at)
+
+	require.NoError(t, s.CreateThread(&Thread{ID: "t1", CreatedAt: 1, UpdatedAt: 1}))
+	require.NoError(t, s.DeleteThread("t1"))
+	id, _, err = s.GetMemoryCursor("t1")
+	require.NoError(t, err)
+	assert.Equal(t, "", id)
+}
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Schema Migration Tests
+// =============================================================================
+
+// openLegacyStore creates a database file holding tables as an older build
+// created them, then opens it (twice, to check migrations are idempotent).
+func openLegacyStore(t *testing.T, ddl string) *SQLiteStore {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "legacy.db")
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec(ddl)
+	require.NoError(t, err, "create legacy tables")
+	require.NoError(t, db.Close())
+
+	s, err := NewSQLiteStoreWithDSN(path)
+	require.NoError(t, err, "open legacy database")
+	require.NoError(t, s.Close())
+
+	s, err = NewSQLiteStoreWithDSN(path)
+	require.NoError(t, err, "reopen migrated database")
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestMigrate_EntityGender(t *testing.T) {
+	s := openLegacyStore(t, `
+		CREATE TABLE entities (
+			id TEXT PRIMARY KEY,
+			label TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			subtype TEXT,
+			aliases TEXT,
+			first_note TEXT,
+			total_mentions INTEGER DEFAULT 0,
+			narrative_id TEXT,
+			created_by TEXT DEFAULT 'user',
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		);
+		INSERT INTO entities VALUES ('e1', 'Arin', 'CHARACTER', '', '[]', '', 1, '', 'user', 1, 1);
+	`)
+
+	old, err := s.GetEntity("e1")
+	require.NoError(t, err)
+	require.NotNil(t, old, "existing rows survive the migration")
+	assert.Empty(t, old.Gender)
+
+	require.NoError(t, s.UpsertEntity(&Entity{ID: "e2", Label: "Lyra", Kind: "CHARACTER", Gender: "female", CreatedAt: 2, UpdatedAt: 2}))
+	e, err := s.GetEntity("e2")
+	require.NoError(t, err)
+	assert.Equal(t, "female", e.Gender)
+}
+
+// legacyEdges is the edges table before merged-graph sync
+const legacyEdges = `
+	CREATE TABLE edges (
+		id TEXT PRIMARY KEY,
+		source_id TEXT NOT NULL,
+		target_id TEXT NOT NULL,
+		rel_type TEXT NOT NULL,
+		confidence REAL DEFAULT 1.0,
+		bidirectional INTEGER DEFAULT 0,
+		source_note TEXT,
+		created_at INTEGER NOT NULL
+	);
+`
+
+func TestMigrate_EdgeColumns(t *testing.T) {
+	s := openLegacyStore(t, legacyEdges+`
+		INSERT INTO edges VALUES ('old', 'a', 'b', 'KNOWS', 1.0, 0, 'n1', 1);
+	`)
+	for _, column := range []string{"evidence", "provenances", "source_notes", "attributes", "support", "story_from", "story_until"} {
+		ok, err := hasColumn(s.db, "edges", column)
+		require.NoError(t, err)
+		assert.True(t, ok, "edges.%s", column)
+	}
+
+	old, err := s.GetEdge("old")
+	require.NoError(t, err)
+	require.NotNil(t, old, "existing rows survive the migration")
+	assert.Nil(t, old.StoryFrom)
+
+	from := 2.0
+	require.NoError(t, s.UpsertEdge(&Edge{
+		ID: "new", SourceID: "a", TargetID: "b", RelType: "ALLIES", Confidence: 1, CreatedAt: 2,
+		Provenances: []string{"scanner"}, SourceNotes: []string{"n2"},
+		Evidence:  []EdgeEvidence{{NoteID: "n2"}},
+		StoryFrom: &from,
+	}))
+	e, err := s.GetEdge("new")
+	require.NoError(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, []string{"n2"}, e.SourceNotes)
+	assert.Len(t, e.Evidence, 1)
+	require.NotNil(t, e.StoryFrom)
+	assert.Equal(t, from, *e.StoryFrom)
+}
+
+func TestMigrate_ThreadMessageToolCalls(t *testing.T) {
+	s := openLegacyStore(t, `
+		CREATE TABLE thread_messages (
+			id TEXT PRIMARY KEY,
+			thread_id TEXT NOT NULL,
+			role TEXT NOT NULL,
+			content TEXT NOT NULL,
+			narrative_id TEXT,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER,
+			is_streaming INTEGER DEFAULT 0
+		);
+		INSERT INTO thread_messages VALUES ('m1', 't1', 'user', 'Hello', '', 1, 1, 0);
+	`)
+
+	require.NoError(t, s.AddMessage(&ThreadMessage{
+		ID: "m2", ThreadID: "t1", Role: "tool", Content: "ok", CreatedAt: 2, ToolCallID: "call_1",
+	}))
+	msgs, err := s.GetThreadMessages("t1")
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Empty(t, msgs[0].ToolCallID, "existing rows get the default")
+	assert.Equal(t, "call_1", msgs[1].ToolCallID)
+}
+
+func TestMigrate_OMRecordLastObservedID(t *testing.T) {
+	s := openLegacyStore(t, `
+		CREATE TABLE om_records (
+			thread_id TEXT PRIMARY KEY,
+			observations TEXT NOT NULL DEFAULT '',
+			current_task TEXT NOT NULL DEFAULT '',
+			last_observed_at INTEGER NOT NULL DEFAULT 0,
+			obs_token_count INTEGER NOT NULL DEFAULT 0,
+			generation_num INTEGER NOT NULL DEFAULT 0,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		);
+		INSERT INTO om_records (thread_id, observations, last_observed_at, created_at, updated_at)
+		VALUES ('t1', 'Likes dragons', 5, 1, 1);
+	`)
+
+	rec, err := s.GetOMRecord("t1")
+	require.NoError(t, err)
+	require.NotNil(t, rec, "existing rows survive the migration")
+	assert.Equal(t, int64(5), rec.LastObservedAt)
+	assert.Empty(t, rec.LastObservedID)
+
+	rec.LastObservedID = "m9"
+	require.NoError(t, s.UpsertOMRecord(rec))
+	rec, err = s.GetOMRecord("t1")
+	require.NoError(t, err)
+	assert.Equal(t, "m9", rec.LastObservedID)
+}
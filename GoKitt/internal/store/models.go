@@ -41,7 +41,8 @@ type Entity struct {
 	FirstNote     string   `json:"firstNote"`
 	TotalMentions int      `json:"totalMentions"`
 	NarrativeID   string   `json:"narrativeId,omitempty"`
-	CreatedBy     string   `json:"createdBy"` // "user" | "extraction" | "auto"
+	CreatedBy     string   `json:"createdBy"`        // "user" | "extraction" | "auto" | "agent"
+	Gender        string   `json:"gender,omitempty"` // "male" | "female" | "neutral" | "plural" (pronoun agreement)
 	CreatedAt     int64    `json:"createdAt"`
 	UpdatedAt     int64    `json:"updatedAt"`
 }
@@ -57,6 +58,45 @@ type Edge struct {
 	Bidirectional bool    `json:"bidirectional"`
 	SourceNote    string  `json:"sourceNote,omitempty"`
 	CreatedAt     int64   `json:"createdAt"`
+
+	// Merged-graph provenance: "scanner" | "llm" | "manual"
+	Provenances []string       `json:"provenances,omitempty"`
+	SourceNotes []string       `json:"sourceNotes,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"` // negated, modality, tense, ...
+
+	// Evidence locates the sentences that justified the edge
+	Evidence []EdgeEvidence `json:"evidence,omitempty"`
+
+	// Support records the LLM and manual backing the merger combined into
+	// Confidence; nil for edges created directly
+	Support *EdgeSupport `json:"support,omitempty"`
+
+	// Story-time validity [StoryFrom, StoryUntil) in narrative order; nil = open
+	StoryFrom  *float64 `json:"storyFrom,omitempty"`
+	StoryUntil *float64 `json:"storyUntil,omitempty"`
+}
+
+// EdgeEvidence is a sentence (and verb) span in a note supporting an edge.
+// Ranges are byte offsets [start, end).
+type EdgeEvidence struct {
+	NoteID   string   `json:"noteId"`
+	Sentence [2]int   `json:"sentence"`
+	Verb     [2]int   `json:"verb"`
+	Weight   float64  `json:"weight,omitempty"`
+	Authored bool     `json:"authored,omitempty"` // Explicit triple/wikilink
+	LLM      bool     `json:"llm,omitempty"`      // Grounds an LLM-extracted relation
+	From     *float64 `json:"from,omitempty"`     // Story-time validity the sentence states
+	Until    *float64 `json:"until,omitempty"`
+	Negated  bool     `json:"negated,omitempty"` // Factuality frame of the sentence
+	Modality string   `json:"modality,omitempty"`
+	Tense    string   `json:"tense,omitempty"`
+}
+
+// EdgeSupport is an edge's non-scanner support: LLM confidence per source
+// note, and whether it was added manually.
+type EdgeSupport struct {
+	LLM    map[string]float64 `json:"llm,omitempty"`
+	Manual bool               `json:"manual,omitempty"`
 }
 
 // Folder represents a folder in the document hierarchy.
@@ -114,12 +154,14 @@ type Thread struct {
 type ThreadMessage struct {
 	ID          string `json:"id"`
 	ThreadID    string `json:"threadId"`
-	Role        string `json:"role"`        // "user", "assistant", "system"
+	Role        string `json:"role"`        // "user", "assistant", "system", "tool"
 	Content     string `json:"content"`     // Message text (or accumulated streaming text)
 	NarrativeID string `json:"narrativeId"` // Scope to narrative (from TypeScript scope)
 	CreatedAt   int64  `json:"createdAt"`
 	UpdatedAt   int64  `json:"updatedAt,omitempty"` // For streaming updates
 	IsStreaming bool   `json:"isStreaming,omitempty"`
+	ToolCalls   string `json:"toolCalls,omitempty"`  // JSON array of tool calls made by an assistant message
+	ToolCallID  string `json:"toolCallId,omitempty"` // Call a "tool" message answers
 }
 
 // MemoryThread links memories to threads (many-to-many relationship).
@@ -141,6 +183,7 @@ type OMRecord struct {
 	Observations   string `json:"observations"`   // LLM-extracted observations (prose)
 	CurrentTask    string `json:"currentTask"`    // What the user is currently doing
 	LastObservedAt int64  `json:"lastObservedAt"` // Timestamp cursor — messages before this are "observed"
+	LastObservedID string `json:"lastObservedId"` // Last observed message; wins over LastObservedAt
 	ObsTokenCount  int    `json:"obsTokenCount"`  // Cached token count of observations
 	GenerationNum  int    `json:"generationNum"`  // Reflection generation counter
 	CreatedAt      int64  `json:"createdAt"`
@@ -268,6 +311,117 @@ type PinnedPayload struct {
 	Payload string
 }
 
+// =============================================================================
+// Discovery Review Queue Types
+// =============================================================================
+
+// CandidateStatus is the review state of a discovery candidate.
+type CandidateStatus string
+
+const (
+	CandidatePending  CandidateStatus = "pending"  // Awaiting review
+	CandidateAccepted CandidateStatus = "accepted" // Promoted to an Entity
+	CandidateRejected CandidateStatus = "rejected" // Added to the world stop list
+	CandidateSnoozed  CandidateStatus = "snoozed"  // Hidden until SnoozedUntil
+)
+
+// DiscoveryCandidate is a potential entity found by the discovery scanner,
+// persisted per world so the review queue survives reloads.
+type DiscoveryCandidate struct {
+	WorldID      string          `json:"worldId"`
+	Token        string          `json:"token"`   // Canonical (lowercase) key
+	Display      string          `json:"display"` // Best surface form seen
+	Count        int             `json:"count"`   // Total occurrences across notes
+	NoteCounts   map[string]int  `json:"noteCounts"`
+	InferredKind string          `json:"inferredKind,omitempty"`
+	FirstNoteID  string          `json:"firstNoteId,omitempty"`
+	LastNoteID   string          `json:"lastNoteId,omitempty"`
+	Contexts     []string        `json:"contexts"` // Example snippets around mentions
+	Status       CandidateStatus `json:"status"`
+	SnoozedUntil int64           `json:"snoozedUntil,omitempty"`
+	EntityID     string          `json:"entityId,omitempty"` // Set once accepted
+	CreatedAt    int64           `json:"createdAt"`
+	UpdatedAt    int64           `json:"updatedAt"`
+}
+
+// CorefRejection records that a piece of text does not refer to an
+// entity, so coreference resolution keeps skipping it after a reload.
+type CorefRejection struct {
+	Text      string `json:"text"` // Normalised (lowercase, single-spaced)
+	EntityID  string `json:"entityId"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// =============================================================================
+// LLM Usage Types
+// =============================================================================
+
+// LLMUsage records one batch LLM call for the usage dashboard.
+type LLMUsage struct {
+	ID               string `json:"id"`
+	Provider         string `json:"provider"`
+	Model            string `json:"model"`
+	Operation        string `json:"operation"` // "complete" | "tools"
+	PromptTokens     int    `json:"promptTokens"`
+	CompletionTokens int    `json:"completionTokens"`
+	LatencyMs        int64  `json:"latencyMs"` // Wall time including retries
+	Attempts         int    `json:"attempts"`
+	Error            string `json:"error,omitempty"` // Empty on success
+	CreatedAt        int64  `json:"createdAt"`
+}
+
+// UsageSummary aggregates LLM usage for one provider and model.
+type UsageSummary struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Calls            int     `json:"calls"`
+	Errors           int     `json:"errors"`
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	AvgLatencyMs     float64 `json:"avgLatencyMs"`
+}
+
+// =============================================================================
+// Extraction Cache Types
+// =============================================================================
+
+// ExtractionCacheKey identifies a cached extraction: the same text,
+// provider, model, prompt version and known-entity set yield the same result.
+type ExtractionCacheKey struct {
+	ContentHash   string `json:"contentHash"`
+	Provider      string `json:"provider"`
+	Model         string `json:"model"`
+	PromptVersion string `json:"promptVersion"`
+	EntitiesHash  string `json:"entitiesHash"`
+}
+
+// ExtractionCacheEntry is a cached LLM extraction result.
+type ExtractionCacheEntry struct {
+	ExtractionCacheKey
+	NoteIDs    []string `json:"noteIds,omitempty"` // Every note extracted with this key
+	Result     string   `json:"result"`            // JSON-encoded extraction result
+	Hits       int      `json:"hits"`
+	CreatedAt  int64    `json:"createdAt"`
+	LastUsedAt int64    `json:"lastUsedAt"`
+}
+
+// ExtractionCacheStats summarises the extraction cache.
+type ExtractionCacheStats struct {
+	Entries int                          `json:"entries"`
+	Hits    int                          `json:"hits"`
+	Bytes   int                          `json:"bytes"` // Size of the cached results
+	Models  []*ExtractionCacheModelStats `json:"models"`
+}
+
+// ExtractionCacheModelStats summarises the cache entries of one provider model.
+type ExtractionCacheModelStats struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Entries  int    `json:"entries"`
+	Hits     int    `json:"hits"`
+	Bytes    int    `json:"bytes"`
+}
+
 // Storer defines the interface for data persistence.
 // SQLiteStore is the sole implementation, using in-memory SQLite for WASM.
 type Storer interface {
@@ -299,6 +453,8 @@ type Storer interface {
 	GetEdge(id string) (*Edge, error)
 	DeleteEdge(id string) error
 	ListEdgesForEntity(entityID string) ([]*Edge, error)
+	ListEdges() ([]*Edge, error)
+	ListEdgesAt(storyTime float64) ([]*Edge, error)
 	CountEdges() (int, error)
 
 	// Folders
@@ -316,6 +472,7 @@ type Storer interface {
 	// ThreadMessages - Conversation history
 	AddMessage(msg *ThreadMessage) error
 	GetThreadMessages(threadID string) ([]*ThreadMessage, error)
+	GetThreadMessagesAfter(threadID, afterID string, afterAt int64) ([]*ThreadMessage, error)
 	GetMessage(id string) (*ThreadMessage, error)
 	UpdateMessage(msg *ThreadMessage) error
 	AppendMessageContent(messageID string, chunk string) error
@@ -327,6 +484,12 @@ type Storer interface {
 	DeleteMemory(id string) error
 	GetMemoriesForThread(threadID string) ([]*Memory, error)
 	ListMemoriesByType(memoryType MemoryType) ([]*Memory, error)
+	UpdateMemory(memory *Memory) error
+	LinkMemory(memoryID, threadID, messageID string, createdAt int64) error
+	ListMemoriesByEntity(entityID string) ([]*Memory, error)
+	GetMemoryThreads(memoryID string) ([]*MemoryThread, error)
+	GetMemoryCursor(threadID string) (messageID string, lastExtractedAt int64, err error)
+	SetMemoryCursor(threadID, messageID string, lastExtractedAt int64) error
 
 	// Observational Memory — Three-agent pipeline state (Phase 8)
 	UpsertOMRecord(record *OMRecord) error
@@ -355,6 +518,33 @@ type Storer interface {
 	ListArtifacts(scope *ScopeKey) ([]*WorkspaceArtifact, error)
 	SearchNotes(scope *ScopeKey, query string, limit int) ([]*Note, error)
 
+	// Discovery review queue and per-world stop list
+	UpsertCandidate(c *DiscoveryCandidate) error
+	GetCandidate(worldID, token string) (*DiscoveryCandidate, error)
+	ListCandidates(worldID string, status CandidateStatus) ([]*DiscoveryCandidate, error)
+	DeleteCandidate(worldID, token string) error
+	AddStopWord(worldID, token string) error
+	RemoveStopWord(worldID, token string) error
+	ListStopWords(worldID string) ([]string, error)
+
+	// Coreference rejections
+	AddCorefRejection(text, entityID string) error
+	RemoveCorefRejection(text, entityID string) error
+	ListCorefRejections() ([]*CorefRejection, error)
+
+	// LLM usage
+	AddUsage(usage *LLMUsage) error
+	ListUsage(since int64, limit int) ([]*LLMUsage, error)
+	SummarizeUsage(since int64) ([]*UsageSummary, error)
+
+	// Extraction result cache
+	PutExtractionCache(e *ExtractionCacheEntry) error
+	GetExtractionCache(key ExtractionCacheKey) (*ExtractionCacheEntry, error)
+	TouchExtractionCache(key ExtractionCacheKey, noteID string, at int64) error
+	DeleteExtractionCacheByNote(noteID string) (int, error)
+	DeleteExtractionCacheByModel(model string) (int, error)
+	ExtractionCacheStats() (*ExtractionCacheStats, error)
+
 	// Lifecycle
 	Close() error
 }
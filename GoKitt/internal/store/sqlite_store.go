@@ -70,6 +70,7 @@ CREATE TABLE IF NOT EXISTS entities (
     total_mentions INTEGER DEFAULT 0,
     narrative_id TEXT,
     created_by TEXT DEFAULT 'user',
+    gender TEXT,
     created_at INTEGER NOT NULL,
     updated_at INTEGER NOT NULL
 );
@@ -87,6 +88,13 @@ CREATE TABLE IF NOT EXISTS edges (
     confidence REAL DEFAULT 1.0,
     bidirectional INTEGER DEFAULT 0,
     source_note TEXT,
+    provenances TEXT,  -- JSON array: scanner | llm | manual
+    source_notes TEXT, -- JSON array of note IDs
+    attributes TEXT,   -- JSON object
+    evidence TEXT,     -- JSON array of EdgeEvidence
+    support TEXT,      -- JSON EdgeSupport: per-note LLM confidence, manual flag
+    story_from REAL,   -- Story-time validity (NULL = open)
+    story_until REAL,
     created_at INTEGER NOT NULL
 );
 
@@ -134,7 +142,9 @@ CREATE TABLE IF NOT EXISTS thread_messages (
     narrative_id TEXT,
     created_at INTEGER NOT NULL,
     updated_at INTEGER,
-    is_streaming INTEGER DEFAULT 0
+    is_streaming INTEGER DEFAULT 0,
+    tool_calls TEXT NOT NULL DEFAULT '',
+    tool_call_id TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX IF NOT EXISTS idx_thread_messages_thread ON thread_messages(thread_id);
@@ -167,6 +177,14 @@ CREATE TABLE IF NOT EXISTS memory_threads (
 CREATE INDEX IF NOT EXISTS idx_memory_threads_thread ON memory_threads(thread_id);
 CREATE INDEX IF NOT EXISTS idx_memory_threads_message ON memory_threads(message_id);
 
+-- MemoryCursors: Per-thread extraction cursor; messages up to last_message_id are processed
+CREATE TABLE IF NOT EXISTS memory_cursors (
+    thread_id TEXT PRIMARY KEY,
+    last_message_id TEXT NOT NULL DEFAULT '',
+    last_extracted_at INTEGER NOT NULL DEFAULT 0,
+    updated_at INTEGER NOT NULL
+);
+
 -- =============================================================================
 -- Observational Memory Tables (Phase 8) — Three-agent pipeline
 -- =============================================================================
@@ -177,6 +195,7 @@ CREATE TABLE IF NOT EXISTS om_records (
     observations TEXT NOT NULL DEFAULT '',
     current_task TEXT NOT NULL DEFAULT '',
     last_observed_at INTEGER NOT NULL DEFAULT 0,
+    last_observed_id TEXT NOT NULL DEFAULT '',
     obs_token_count INTEGER NOT NULL DEFAULT 0,
     generation_num INTEGER NOT NULL DEFAULT 0,
     created_at INTEGER NOT NULL,
@@ -217,6 +236,97 @@ CREATE TABLE IF NOT EXISTS workspace_artifacts (
 
 CREATE INDEX IF NOT EXISTS idx_ws_scope
     ON workspace_artifacts(thread_id, narrative_id, folder_id);
+
+-- =============================================================================
+-- Discovery Review Queue
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS discovery_candidates (
+    world_id TEXT NOT NULL,
+    token TEXT NOT NULL,
+    display TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    note_counts TEXT NOT NULL DEFAULT '{}',
+    inferred_kind TEXT NOT NULL DEFAULT '',
+    first_note_id TEXT NOT NULL DEFAULT '',
+    last_note_id TEXT NOT NULL DEFAULT '',
+    contexts TEXT NOT NULL DEFAULT '[]',
+    status TEXT NOT NULL DEFAULT 'pending',
+    snoozed_until INTEGER NOT NULL DEFAULT 0,
+    entity_id TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    PRIMARY KEY (world_id, token)
+);
+
+-- Coreference rejections: text the user said does not refer to an entity
+CREATE TABLE IF NOT EXISTS coref_rejections (
+    text TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (text, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidates_status ON discovery_candidates(world_id, status);
+
+-- Per-world stop list: rejected candidates are never proposed again
+CREATE TABLE IF NOT EXISTS discovery_stopwords (
+    world_id TEXT NOT NULL,
+    token TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (world_id, token)
+);
+
+-- =============================================================================
+-- LLM Usage
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS llm_usage (
+    id TEXT PRIMARY KEY,
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL DEFAULT '',
+    operation TEXT NOT NULL DEFAULT '',
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    error TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_usage_created ON llm_usage(created_at);
+
+-- =============================================================================
+-- Extraction Cache
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS extraction_cache (
+    content_hash TEXT NOT NULL,
+    provider TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    entities_hash TEXT NOT NULL,
+    result TEXT NOT NULL,
+    hits INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL,
+    last_used_at INTEGER NOT NULL,
+    PRIMARY KEY (content_hash, provider, model, prompt_version, entities_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_extraction_cache_model ON extraction_cache(model);
+
+-- Notes sharing a cache entry (identical text) each get a link
+CREATE TABLE IF NOT EXISTS extraction_cache_notes (
+    content_hash TEXT NOT NULL,
+    provider TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    entities_hash TEXT NOT NULL,
+    note_id TEXT NOT NULL,
+    PRIMARY KEY (content_hash, provider, model, prompt_version, entities_hash, note_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_extraction_cache_notes_note ON extraction_cache_notes(note_id);
 `
 
 // NewSQLiteStore creates a new in-memory SQLite store.
@@ -237,6 +347,10 @@ func NewSQLiteStoreWithDSN(dsn string) (*SQLiteStore, error) {
 		db.Close()
 		return nil, fmt.Errorf("failed to create schema: %w", err)
 	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
 
 	return &SQLiteStore{
 		db:   db,
@@ -244,6 +358,53 @@ func NewSQLiteStoreWithDSN(dsn string) (*SQLiteStore, error) {
 	}, nil
 }
 
+// columnMigrations lists columns added to tables after they first shipped.
+// CREATE TABLE IF NOT EXISTS leaves an existing table untouched, so a
+// database file created by an older build is missing them.
+var columnMigrations = []struct {
+	table, column, decl string
+}{
+	{"entities", "gender", "TEXT"},
+	{"edges", "evidence", "TEXT"},
+	{"edges", "provenances", "TEXT"},
+	{"edges", "source_notes", "TEXT"},
+	{"edges", "attributes", "TEXT"},
+	{"edges", "support", "TEXT"},
+	{"edges", "story_from", "REAL"},
+	{"edges", "story_until", "REAL"},
+	{"thread_messages", "tool_calls", "TEXT NOT NULL DEFAULT ''"},
+	{"thread_messages", "tool_call_id", "TEXT NOT NULL DEFAULT ''"},
+	{"om_records", "last_observed_id", "TEXT NOT NULL DEFAULT ''"},
+}
+
+// migrate adds any missing columns from columnMigrations. It is idempotent
+// and runs on every open.
+func migrate(db *sql.DB) error {
+	for _, m := range columnMigrations {
+		ok, err := hasColumn(db, m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if ok {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.decl)); err != nil {
+			return fmt.Errorf("add %s.%s: %w", m.table, m.column, err)
+		}
+	}
+	return nil
+}
+
+// hasColumn reports whether table has the named column.
+func hasColumn(db *sql.DB, table, column string) (bool, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("inspect %s: %w", table, err)
+	}
+	return n > 0, nil
+}
+
 // Close closes the database connection.
 func (s *SQLiteStore) Close() error {
 	s.mu.Lock()
@@ -901,8 +1062,8 @@ func (s *SQLiteStore) UpsertEntity(entity *Entity) error {
 
 	_, err = s.db.Exec(`
 		INSERT INTO entities (id, label, kind, subtype, aliases, first_note, 
-			total_mentions, narrative_id, created_by, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			total_mentions, narrative_id, created_by, gender, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			label = excluded.label,
 			kind = excluded.kind,
@@ -911,10 +1072,11 @@ func (s *SQLiteStore) UpsertEntity(entity *Entity) error {
 			first_note = excluded.first_note,
 			total_mentions = excluded.total_mentions,
 			narrative_id = excluded.narrative_id,
+			gender = excluded.gender,
 			updated_at = excluded.updated_at
 	`, entity.ID, entity.Label, entity.Kind, entity.Subtype, string(aliasesJSON),
 		entity.FirstNote, entity.TotalMentions, entity.NarrativeID,
-		entity.CreatedBy, entity.CreatedAt, entity.UpdatedAt)
+		entity.CreatedBy, entity.Gender, entity.CreatedAt, entity.UpdatedAt)
 
 	return err
 }
@@ -929,12 +1091,12 @@ func (s *SQLiteStore) GetEntity(id string) (*Entity, error) {
 
 	err := s.db.QueryRow(`
 		SELECT id, label, kind, subtype, aliases, first_note, total_mentions,
-			narrative_id, created_by, created_at, updated_at
+			narrative_id, created_by, COALESCE(gender, ''), created_at, updated_at
 		FROM entities WHERE id = ?
 	`, id).Scan(
 		&entity.ID, &entity.Label, &entity.Kind, &entity.Subtype, &aliasesJSON,
 		&entity.FirstNote, &entity.TotalMentions, &entity.NarrativeID,
-		&entity.CreatedBy, &entity.CreatedAt, &entity.UpdatedAt,
+		&entity.CreatedBy, &entity.Gender, &entity.CreatedAt, &entity.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -966,12 +1128,12 @@ func (s *SQLiteStore) GetEntityByLabel(label string) (*Entity, error) {
 
 	err := s.db.QueryRow(`
 		SELECT id, label, kind, subtype, aliases, first_note, total_mentions,
-			narrative_id, created_by, created_at, updated_at
+			narrative_id, created_by, COALESCE(gender, ''), created_at, updated_at
 		FROM entities WHERE LOWER(label) = LOWER(?)
 	`, label).Scan(
 		&entity.ID, &entity.Label, &entity.Kind, &entity.Subtype, &aliasesJSON,
 		&entity.FirstNote, &entity.TotalMentions, &entity.NarrativeID,
-		&entity.CreatedBy, &entity.CreatedAt, &entity.UpdatedAt,
+		&entity.CreatedBy, &entity.Gender, &entity.CreatedAt, &entity.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -997,6 +1159,9 @@ func (s *SQLiteStore) DeleteEntity(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if _, err := s.db.Exec("DELETE FROM coref_rejections WHERE entity_id = ?", id); err != nil {
+		return err
+	}
 	_, err := s.db.Exec("DELETE FROM entities WHERE id = ?", id)
 	return err
 }
@@ -1012,13 +1177,13 @@ func (s *SQLiteStore) ListEntities(kind string) ([]*Entity, error) {
 	if kind != "" {
 		rows, err = s.db.Query(`
 			SELECT id, label, kind, subtype, aliases, first_note, total_mentions,
-				narrative_id, created_by, created_at, updated_at
+				narrative_id, created_by, COALESCE(gender, ''), created_at, updated_at
 			FROM entities WHERE kind = ? ORDER BY label
 		`, kind)
 	} else {
 		rows, err = s.db.Query(`
 			SELECT id, label, kind, subtype, aliases, first_note, total_mentions,
-				narrative_id, created_by, created_at, updated_at
+				narrative_id, created_by, COALESCE(gender, ''), created_at, updated_at
 			FROM entities ORDER BY label
 		`)
 	}
@@ -1037,7 +1202,7 @@ func (s *SQLiteStore) ListEntities(kind string) ([]*Entity, error) {
 		if err := rows.Scan(
 			&entity.ID, &entity.Label, &entity.Kind, &entity.Subtype, &aliasesJSON,
 			&entity.FirstNote, &entity.TotalMentions, &entity.NarrativeID,
-			&entity.CreatedBy, &entity.CreatedAt, &entity.UpdatedAt,
+			&entity.CreatedBy, &entity.Gender, &entity.CreatedAt, &entity.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1070,54 +1235,110 @@ func (s *SQLiteStore) CountEntities() (int, error) {
 // Edge CRUD
 // =============================================================================
 
+const edgeColumns = `id, source_id, target_id, rel_type, confidence, bidirectional,
+	source_note, COALESCE(provenances, 'null'), COALESCE(source_notes, 'null'),
+	COALESCE(attributes, 'null'), COALESCE(evidence, 'null'), COALESCE(support, 'null'),
+	story_from, story_until, created_at`
+
 // UpsertEdge inserts or updates an edge.
 func (s *SQLiteStore) UpsertEdge(edge *Edge) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, err := s.db.Exec(`
+	return s.insertEdgeLocked(edge, true)
+}
+
+// insertEdgeLocked writes an edge; the caller holds the write lock
+func (s *SQLiteStore) insertEdgeLocked(edge *Edge, upsert bool) error {
+	provenancesJSON, _ := json.Marshal(edge.Provenances)
+	sourceNotesJSON, _ := json.Marshal(edge.SourceNotes)
+	attributesJSON, err := json.Marshal(edge.Attributes)
+	if err != nil {
+		return fmt.Errorf("marshal attributes: %w", err)
+	}
+	evidenceJSON, err := json.Marshal(edge.Evidence)
+	if err != nil {
+		return fmt.Errorf("marshal evidence: %w", err)
+	}
+	supportJSON, err := json.Marshal(edge.Support)
+	if err != nil {
+		return fmt.Errorf("marshal support: %w", err)
+	}
+
+	query := `
 		INSERT INTO edges (id, source_id, target_id, rel_type, confidence, 
-			bidirectional, source_note, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			bidirectional, source_note, provenances, source_notes, attributes, evidence, support,
+			story_from, story_until, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if upsert {
+		query += `
 		ON CONFLICT(id) DO UPDATE SET
 			source_id = excluded.source_id,
 			target_id = excluded.target_id,
 			rel_type = excluded.rel_type,
 			confidence = excluded.confidence,
 			bidirectional = excluded.bidirectional,
-			source_note = excluded.source_note
-	`, edge.ID, edge.SourceID, edge.TargetID, edge.RelType, edge.Confidence,
-		boolToInt(edge.Bidirectional), edge.SourceNote, edge.CreatedAt)
-
+			source_note = excluded.source_note,
+			provenances = excluded.provenances,
+			source_notes = excluded.source_notes,
+			attributes = excluded.attributes,
+			evidence = excluded.evidence,
+			support = excluded.support,
+			story_from = excluded.story_from,
+			story_until = excluded.story_until`
+	}
+
+	_, err = s.db.Exec(query, edge.ID, edge.SourceID, edge.TargetID, edge.RelType, edge.Confidence,
+		boolToInt(edge.Bidirectional), edge.SourceNote, string(provenancesJSON), string(sourceNotesJSON),
+		string(attributesJSON), string(evidenceJSON), string(supportJSON), edge.StoryFrom, edge.StoryUntil, edge.CreatedAt)
 	return err
 }
 
-// GetEdge retrieves an edge by ID.
-func (s *SQLiteStore) GetEdge(id string) (*Edge, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
+// scanEdge reads one edge row selected with edgeColumns
+func scanEdge(row interface{ Scan(...any) error }) (*Edge, error) {
 	var edge Edge
 	var bidirectional int
+	var sourceNote sql.NullString
+	var storyFrom, storyUntil sql.NullFloat64
+	var provenancesJSON, sourceNotesJSON, attributesJSON, evidenceJSON, supportJSON string
 
-	err := s.db.QueryRow(`
-		SELECT id, source_id, target_id, rel_type, confidence, bidirectional, 
-			source_note, created_at
-		FROM edges WHERE id = ?
-	`, id).Scan(
+	if err := row.Scan(
 		&edge.ID, &edge.SourceID, &edge.TargetID, &edge.RelType, &edge.Confidence,
-		&bidirectional, &edge.SourceNote, &edge.CreatedAt,
-	)
+		&bidirectional, &sourceNote, &provenancesJSON, &sourceNotesJSON, &attributesJSON, &evidenceJSON, &supportJSON,
+		&storyFrom, &storyUntil, &edge.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	edge.Bidirectional = bidirectional != 0
+	edge.SourceNote = sourceNote.String
+	if storyFrom.Valid {
+		edge.StoryFrom = &storyFrom.Float64
+	}
+	if storyUntil.Valid {
+		edge.StoryUntil = &storyUntil.Float64
+	}
+	json.Unmarshal([]byte(provenancesJSON), &edge.Provenances)
+	json.Unmarshal([]byte(sourceNotesJSON), &edge.SourceNotes)
+	json.Unmarshal([]byte(attributesJSON), &edge.Attributes)
+	json.Unmarshal([]byte(evidenceJSON), &edge.Evidence)
+	json.Unmarshal([]byte(supportJSON), &edge.Support)
+	return &edge, nil
+}
 
+// GetEdge retrieves an edge by ID.
+func (s *SQLiteStore) GetEdge(id string) (*Edge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	edge, err := scanEdge(s.db.QueryRow(`SELECT `+edgeColumns+` FROM edges WHERE id = ?`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-
-	edge.Bidirectional = bidirectional != 0
-	return &edge, nil
+	return edge, nil
 }
 
 // DeleteEdge removes an edge by ID.
@@ -1134,12 +1355,30 @@ func (s *SQLiteStore) ListEdgesForEntity(entityID string) ([]*Edge, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	rows, err := s.db.Query(`
-		SELECT id, source_id, target_id, rel_type, confidence, bidirectional, 
-			source_note, created_at
-		FROM edges WHERE source_id = ? OR target_id = ?
-	`, entityID, entityID)
+	return s.queryEdges(`SELECT `+edgeColumns+` FROM edges WHERE source_id = ? OR target_id = ?`, entityID, entityID)
+}
+
+// ListEdges returns every edge.
+func (s *SQLiteStore) ListEdges() ([]*Edge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.queryEdges(`SELECT ` + edgeColumns + ` FROM edges ORDER BY id`)
+}
+
+// ListEdgesAt returns the edges valid at a story point: those whose
+// [story_from, story_until) interval contains it, open bounds included.
+func (s *SQLiteStore) ListEdgesAt(storyTime float64) ([]*Edge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.queryEdges(`SELECT `+edgeColumns+` FROM edges
+		WHERE (story_from IS NULL OR story_from <= ?) AND (story_until IS NULL OR story_until > ?)
+		ORDER BY id`, storyTime, storyTime)
+}
 
+func (s *SQLiteStore) queryEdges(query string, args ...any) ([]*Edge, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1148,18 +1387,11 @@ func (s *SQLiteStore) ListEdgesForEntity(entityID string) ([]*Edge, error) {
 	// Initialize as empty slice to ensure JSON marshaling returns [] instead of null
 	edges := make([]*Edge, 0)
 	for rows.Next() {
-		var edge Edge
-		var bidirectional int
-
-		if err := rows.Scan(
-			&edge.ID, &edge.SourceID, &edge.TargetID, &edge.RelType, &edge.Confidence,
-			&bidirectional, &edge.SourceNote, &edge.CreatedAt,
-		); err != nil {
+		edge, err := scanEdge(rows)
+		if err != nil {
 			return nil, err
 		}
-
-		edge.Bidirectional = bidirectional != 0
-		edges = append(edges, &edge)
+		edges = append(edges, edge)
 	}
 
 	return edges, rows.Err()
@@ -1333,6 +1565,9 @@ func (s *SQLiteStore) DeleteThread(id string) error {
 	if _, err := s.db.Exec("DELETE FROM memory_threads WHERE thread_id = ?", id); err != nil {
 		return err
 	}
+	if _, err := s.db.Exec("DELETE FROM memory_cursors WHERE thread_id = ?", id); err != nil {
+		return err
+	}
 
 	// Delete messages
 	if _, err := s.db.Exec("DELETE FROM thread_messages WHERE thread_id = ?", id); err != nil {
@@ -1392,9 +1627,11 @@ func (s *SQLiteStore) AddMessage(msg *ThreadMessage) error {
 	defer s.mu.Unlock()
 
 	_, err := s.db.Exec(`
-		INSERT INTO thread_messages (id, thread_id, role, content, narrative_id, created_at, updated_at, is_streaming)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, msg.ID, msg.ThreadID, msg.Role, msg.Content, msg.NarrativeID, msg.CreatedAt, msg.UpdatedAt, boolToInt(msg.IsStreaming))
+		INSERT INTO thread_messages (id, thread_id, role, content, narrative_id, created_at, updated_at, is_streaming,
+			tool_calls, tool_call_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, msg.ID, msg.ThreadID, msg.Role, msg.Content, msg.NarrativeID, msg.CreatedAt, msg.UpdatedAt, boolToInt(msg.IsStreaming),
+		msg.ToolCalls, msg.ToolCallID)
 
 	if err != nil {
 		return err
@@ -1411,8 +1648,9 @@ func (s *SQLiteStore) GetThreadMessages(threadID string) ([]*ThreadMessage, erro
 	defer s.mu.RUnlock()
 
 	rows, err := s.db.Query(`
-		SELECT id, thread_id, role, content, narrative_id, created_at, updated_at, is_streaming
-		FROM thread_messages WHERE thread_id = ? ORDER BY created_at ASC
+		SELECT id, thread_id, role, content, narrative_id, created_at, updated_at, is_streaming,
+			tool_calls, tool_call_id
+		FROM thread_messages WHERE thread_id = ? ORDER BY created_at ASC, rowid ASC
 	`, threadID)
 	if err != nil {
 		return nil, err
@@ -1426,7 +1664,7 @@ func (s *SQLiteStore) GetThreadMessages(threadID string) ([]*ThreadMessage, erro
 		var isStreaming int
 		var updatedAt sql.NullInt64
 		if err := rows.Scan(&m.ID, &m.ThreadID, &m.Role, &m.Content, &m.NarrativeID,
-			&m.CreatedAt, &updatedAt, &isStreaming); err != nil {
+			&m.CreatedAt, &updatedAt, &isStreaming, &m.ToolCalls, &m.ToolCallID); err != nil {
 			return nil, err
 		}
 		m.IsStreaming = isStreaming != 0
@@ -1439,6 +1677,32 @@ func (s *SQLiteStore) GetThreadMessages(threadID string) ([]*ThreadMessage, erro
 	return messages, rows.Err()
 }
 
+// GetThreadMessagesAfter returns the messages that follow the message
+// afterID in chronological order. Messages created in the same
+// millisecond keep their insertion order, so none are skipped. When
+// afterID is empty or no longer exists, messages created after afterAt are
+// returned instead.
+func (s *SQLiteStore) GetThreadMessagesAfter(threadID, afterID string, afterAt int64) ([]*ThreadMessage, error) {
+	messages, err := s.GetThreadMessages(threadID)
+	if err != nil {
+		return nil, err
+	}
+	if afterID != "" {
+		for i, m := range messages {
+			if m.ID == afterID {
+				return messages[i+1:], nil
+			}
+		}
+	}
+	after := make([]*ThreadMessage, 0, len(messages))
+	for _, m := range messages {
+		if m.CreatedAt > afterAt {
+			after = append(after, m)
+		}
+	}
+	return after, nil
+}
+
 // DeleteThreadMessages removes all messages from a thread.
 func (s *SQLiteStore) DeleteThreadMessages(threadID string) error {
 	s.mu.Lock()
@@ -1458,10 +1722,11 @@ func (s *SQLiteStore) GetMessage(id string) (*ThreadMessage, error) {
 	var updatedAt sql.NullInt64
 
 	err := s.db.QueryRow(`
-		SELECT id, thread_id, role, content, narrative_id, created_at, updated_at, is_streaming
+		SELECT id, thread_id, role, content, narrative_id, created_at, updated_at, is_streaming,
+			tool_calls, tool_call_id
 		FROM thread_messages WHERE id = ?
 	`, id).Scan(&m.ID, &m.ThreadID, &m.Role, &m.Content, &m.NarrativeID,
-		&m.CreatedAt, &updatedAt, &isStreaming)
+		&m.CreatedAt, &updatedAt, &isStreaming, &m.ToolCalls, &m.ToolCallID)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -1655,6 +1920,131 @@ func (s *SQLiteStore) ListMemoriesByType(memoryType MemoryType) ([]*Memory, erro
 	return memories, rows.Err()
 }
 
+// UpdateMemory updates a memory's content, type, confidence and entity link.
+func (s *SQLiteStore) UpdateMemory(memory *Memory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		UPDATE memories SET content = ?, memory_type = ?, confidence = ?, source_role = ?,
+			entity_id = ?, updated_at = ?
+		WHERE id = ?
+	`, memory.Content, string(memory.MemoryType), memory.Confidence, memory.SourceRole,
+		memory.EntityID, memory.UpdatedAt, memory.ID)
+	return err
+}
+
+// LinkMemory associates an existing memory with another thread.
+// Linking a memory to a thread it already belongs to is a no-op.
+func (s *SQLiteStore) LinkMemory(memoryID, threadID, messageID string, createdAt int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO memory_threads (memory_id, thread_id, message_id, created_at)
+		VALUES (?, ?, ?, ?)
+	`, memoryID, threadID, messageID, createdAt)
+	return err
+}
+
+// ListMemoriesByEntity returns all memories linked to an entity, across threads.
+func (s *SQLiteStore) ListMemoriesByEntity(entityID string) ([]*Memory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT id, content, memory_type, confidence, source_role, entity_id, created_at, updated_at
+		FROM memories WHERE entity_id = ?
+		ORDER BY confidence DESC, created_at DESC
+	`, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Initialize as empty slice to ensure JSON marshaling returns [] instead of null
+	memories := make([]*Memory, 0)
+	for rows.Next() {
+		var m Memory
+		var mt string
+		var entityID sql.NullString
+
+		if err := rows.Scan(&m.ID, &m.Content, &mt, &m.Confidence, &m.SourceRole,
+			&entityID, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		m.MemoryType = MemoryType(mt)
+		if entityID.Valid {
+			m.EntityID = entityID.String
+		}
+		memories = append(memories, &m)
+	}
+
+	return memories, rows.Err()
+}
+
+// GetMemoryThreads returns the thread links of a memory, oldest first.
+func (s *SQLiteStore) GetMemoryThreads(memoryID string) ([]*MemoryThread, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT memory_id, thread_id, message_id, created_at
+		FROM memory_threads WHERE memory_id = ?
+		ORDER BY created_at ASC
+	`, memoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]*MemoryThread, 0)
+	for rows.Next() {
+		var l MemoryThread
+		var messageID sql.NullString
+		if err := rows.Scan(&l.MemoryID, &l.ThreadID, &messageID, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		l.MessageID = messageID.String
+		links = append(links, &l)
+	}
+
+	return links, rows.Err()
+}
+
+// GetMemoryCursor returns the last message memories were extracted from
+// in a thread and its timestamp ("", 0 if none).
+func (s *SQLiteStore) GetMemoryCursor(threadID string) (string, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var messageID string
+	var at int64
+	err := s.db.QueryRow("SELECT last_message_id, last_extracted_at FROM memory_cursors WHERE thread_id = ?",
+		threadID).Scan(&messageID, &at)
+	if err == sql.ErrNoRows {
+		return "", 0, nil
+	}
+	return messageID, at, err
+}
+
+// SetMemoryCursor records the last message memories were extracted from.
+func (s *SQLiteStore) SetMemoryCursor(threadID, messageID string, lastExtractedAt int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		INSERT INTO memory_cursors (thread_id, last_message_id, last_extracted_at, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(thread_id) DO UPDATE SET
+			last_message_id = excluded.last_message_id,
+			last_extracted_at = excluded.last_extracted_at,
+			updated_at = excluded.updated_at
+	`, threadID, messageID, lastExtractedAt, time.Now().UnixMilli())
+	return err
+}
+
 // Export serializes all database tables to JSON bytes.
 // This is a portable export that doesn't depend on sqlite3 serialization APIs.
 func (s *SQLiteStore) Export() ([]byte, error) {
@@ -1662,10 +2052,15 @@ func (s *SQLiteStore) Export() ([]byte, error) {
 	defer s.mu.RUnlock()
 
 	type ExportData struct {
-		Notes    []*Note   `json:"notes"`
-		Entities []*Entity `json:"entities"`
-		Edges    []*Edge   `json:"edges"`
-		Folders  []*Folder `json:"folders"`
+		Notes      []*Note                 `json:"notes"`
+		Entities   []*Entity               `json:"entities"`
+		Edges      []*Edge                 `json:"edges"`
+		Folders    []*Folder               `json:"folders"`
+		Candidates []*DiscoveryCandidate   `json:"candidates,omitempty"`
+		StopWords  []exportStopWord        `json:"stopWords,omitempty"`
+		Rejections []*CorefRejection       `json:"corefRejections,omitempty"`
+		Cache      []*ExtractionCacheEntry `json:"extractionCache,omitempty"`
+		Usage      []*LLMUsage             `json:"llmUsage,omitempty"`
 	}
 
 	var data ExportData
@@ -1702,7 +2097,7 @@ func (s *SQLiteStore) Export() ([]byte, error) {
 	// Export entities
 	entityRows, err := s.db.Query(`
 		SELECT id, label, kind, subtype, aliases, first_note, total_mentions,
-			   created_at, updated_at, created_by, narrative_id
+			   created_at, updated_at, created_by, narrative_id, COALESCE(gender, '')
 		FROM entities
 	`)
 	if err != nil {
@@ -1715,7 +2110,7 @@ func (s *SQLiteStore) Export() ([]byte, error) {
 		if err := entityRows.Scan(
 			&e.ID, &e.Label, &e.Kind, &e.Subtype, &aliasesJSON,
 			&e.FirstNote, &e.TotalMentions, &e.CreatedAt, &e.UpdatedAt,
-			&e.CreatedBy, &e.NarrativeID,
+			&e.CreatedBy, &e.NarrativeID, &e.Gender,
 		); err != nil {
 			return nil, fmt.Errorf("scan entity: %w", err)
 		}
@@ -1724,25 +2119,17 @@ func (s *SQLiteStore) Export() ([]byte, error) {
 	}
 
 	// Export edges
-	edgeRows, err := s.db.Query(`
-		SELECT id, source_id, target_id, rel_type, confidence, bidirectional, source_note, created_at
-		FROM edges
-	`)
+	edgeRows, err := s.db.Query(`SELECT ` + edgeColumns + ` FROM edges`)
 	if err != nil {
 		return nil, fmt.Errorf("export edges: %w", err)
 	}
 	defer edgeRows.Close()
 	for edgeRows.Next() {
-		var e Edge
-		var bidir int
-		if err := edgeRows.Scan(
-			&e.ID, &e.SourceID, &e.TargetID, &e.RelType, &e.Confidence,
-			&bidir, &e.SourceNote, &e.CreatedAt,
-		); err != nil {
+		e, err := scanEdge(edgeRows)
+		if err != nil {
 			return nil, fmt.Errorf("scan edge: %w", err)
 		}
-		e.Bidirectional = bidir == 1
-		data.Edges = append(data.Edges, &e)
+		data.Edges = append(data.Edges, e)
 	}
 
 	// Export folders
@@ -1765,9 +2152,117 @@ func (s *SQLiteStore) Export() ([]byte, error) {
 		data.Folders = append(data.Folders, &f)
 	}
 
+	// Export discovery review queue
+	candidateRows, err := s.db.Query(`SELECT ` + candidateColumns + ` FROM discovery_candidates`)
+	if err != nil {
+		return nil, fmt.Errorf("export candidates: %w", err)
+	}
+	defer candidateRows.Close()
+	for candidateRows.Next() {
+		c, err := scanCandidate(candidateRows)
+		if err != nil {
+			return nil, fmt.Errorf("scan candidate: %w", err)
+		}
+		data.Candidates = append(data.Candidates, c)
+	}
+
+	stopRows, err := s.db.Query(`SELECT world_id, token FROM discovery_stopwords`)
+	if err != nil {
+		return nil, fmt.Errorf("export stop words: %w", err)
+	}
+	defer stopRows.Close()
+	for stopRows.Next() {
+		var w exportStopWord
+		if err := stopRows.Scan(&w.WorldID, &w.Token); err != nil {
+			return nil, fmt.Errorf("scan stop word: %w", err)
+		}
+		data.StopWords = append(data.StopWords, w)
+	}
+
+	rejectionRows, err := s.db.Query(`SELECT text, entity_id, created_at FROM coref_rejections`)
+	if err != nil {
+		return nil, fmt.Errorf("export coref rejections: %w", err)
+	}
+	defer rejectionRows.Close()
+	for rejectionRows.Next() {
+		var r CorefRejection
+		if err := rejectionRows.Scan(&r.Text, &r.EntityID, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan coref rejection: %w", err)
+		}
+		data.Rejections = append(data.Rejections, &r)
+	}
+
+	// Export the extraction cache with its note links
+	cacheRows, err := s.db.Query(`
+		SELECT content_hash, provider, model, prompt_version, entities_hash,
+			result, hits, created_at, last_used_at
+		FROM extraction_cache`)
+	if err != nil {
+		return nil, fmt.Errorf("export extraction cache: %w", err)
+	}
+	defer cacheRows.Close()
+	cache := make(map[ExtractionCacheKey]*ExtractionCacheEntry)
+	for cacheRows.Next() {
+		var e ExtractionCacheEntry
+		if err := cacheRows.Scan(
+			&e.ContentHash, &e.Provider, &e.Model, &e.PromptVersion, &e.EntitiesHash,
+			&e.Result, &e.Hits, &e.CreatedAt, &e.LastUsedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan extraction cache: %w", err)
+		}
+		cache[e.ExtractionCacheKey] = &e
+		data.Cache = append(data.Cache, &e)
+	}
+
+	linkRows, err := s.db.Query(`
+		SELECT content_hash, provider, model, prompt_version, entities_hash, note_id
+		FROM extraction_cache_notes ORDER BY note_id`)
+	if err != nil {
+		return nil, fmt.Errorf("export extraction cache notes: %w", err)
+	}
+	defer linkRows.Close()
+	for linkRows.Next() {
+		var key ExtractionCacheKey
+		var noteID string
+		if err := linkRows.Scan(
+			&key.ContentHash, &key.Provider, &key.Model, &key.PromptVersion, &key.EntitiesHash, &noteID,
+		); err != nil {
+			return nil, fmt.Errorf("scan extraction cache note: %w", err)
+		}
+		if e := cache[key]; e != nil {
+			e.NoteIDs = append(e.NoteIDs, noteID)
+		}
+	}
+
+	// Export the LLM usage log
+	usageRows, err := s.db.Query(`
+		SELECT id, provider, model, operation, prompt_tokens, completion_tokens,
+			latency_ms, attempts, error, created_at
+		FROM llm_usage ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("export usage: %w", err)
+	}
+	defer usageRows.Close()
+	for usageRows.Next() {
+		var u LLMUsage
+		if err := usageRows.Scan(
+			&u.ID, &u.Provider, &u.Model, &u.Operation, &u.PromptTokens, &u.CompletionTokens,
+			&u.LatencyMs, &u.Attempts, &u.Error, &u.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan usage: %w", err)
+		}
+		data.Usage = append(data.Usage, &u)
+	}
+
 	return json.Marshal(data)
 }
 
+// exportStopWord is a discovery stop list entry in an export
+type exportStopWord struct {
+	WorldID string `json:"worldId"`
+	Token   string `json:"token"`
+}
+
 // Import restores the database state from an exported JSON byte slice.
 // Clears all existing data and re-inserts from the export.
 func (s *SQLiteStore) Import(data []byte) error {
@@ -1779,10 +2274,15 @@ func (s *SQLiteStore) Import(data []byte) error {
 	}
 
 	type ExportData struct {
-		Notes    []*Note   `json:"notes"`
-		Entities []*Entity `json:"entities"`
-		Edges    []*Edge   `json:"edges"`
-		Folders  []*Folder `json:"folders"`
+		Notes      []*Note                 `json:"notes"`
+		Entities   []*Entity               `json:"entities"`
+		Edges      []*Edge                 `json:"edges"`
+		Folders    []*Folder               `json:"folders"`
+		Candidates []*DiscoveryCandidate   `json:"candidates,omitempty"`
+		StopWords  []exportStopWord        `json:"stopWords,omitempty"`
+		Rejections []*CorefRejection       `json:"corefRejections,omitempty"`
+		Cache      []*ExtractionCacheEntry `json:"extractionCache,omitempty"`
+		Usage      []*LLMUsage             `json:"llmUsage,omitempty"`
 	}
 
 	var importData ExportData
@@ -1791,7 +2291,8 @@ func (s *SQLiteStore) Import(data []byte) error {
 	}
 
 	// Clear all tables
-	for _, table := range []string{"edges", "entities", "folders", "notes"} {
+	for _, table := range []string{"edges", "entities", "folders", "notes", "discovery_candidates", "discovery_stopwords",
+		"coref_rejections", "extraction_cache", "extraction_cache_notes", "llm_usage"} {
 		if _, err := s.db.Exec("DELETE FROM " + table); err != nil {
 			return fmt.Errorf("clear %s: %w", table, err)
 		}
@@ -1825,10 +2326,10 @@ func (s *SQLiteStore) Import(data []byte) error {
 		aliasesJSON, _ := json.Marshal(e.Aliases)
 		_, err := s.db.Exec(`
 			INSERT INTO entities (id, label, kind, subtype, aliases, first_note, total_mentions,
-				created_at, updated_at, created_by, narrative_id)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				created_at, updated_at, created_by, narrative_id, gender)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`, e.ID, e.Label, e.Kind, e.Subtype, string(aliasesJSON),
-			e.FirstNote, e.TotalMentions, e.CreatedAt, e.UpdatedAt, e.CreatedBy, e.NarrativeID)
+			e.FirstNote, e.TotalMentions, e.CreatedAt, e.UpdatedAt, e.CreatedBy, e.NarrativeID, e.Gender)
 		if err != nil {
 			return fmt.Errorf("import entity %s: %w", e.ID, err)
 		}
@@ -1836,12 +2337,7 @@ func (s *SQLiteStore) Import(data []byte) error {
 
 	// Re-insert edges
 	for _, e := range importData.Edges {
-		_, err := s.db.Exec(`
-			INSERT INTO edges (id, source_id, target_id, rel_type, confidence, bidirectional, source_note, created_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-		`, e.ID, e.SourceID, e.TargetID, e.RelType, e.Confidence,
-			boolToInt(e.Bidirectional), e.SourceNote, e.CreatedAt)
-		if err != nil {
+		if err := s.insertEdgeLocked(e, false); err != nil {
 			return fmt.Errorf("import edge %s: %w", e.ID, err)
 		}
 	}
@@ -1858,14 +2354,55 @@ func (s *SQLiteStore) Import(data []byte) error {
 		}
 	}
 
-	return nil
-}
+	// Re-insert discovery review queue
+	for _, c := range importData.Candidates {
+		if err := s.upsertCandidateLocked(c); err != nil {
+			return fmt.Errorf("import candidate %s: %w", c.Token, err)
+		}
+	}
+	for _, w := range importData.StopWords {
+		_, err := s.db.Exec(`
+			INSERT OR IGNORE INTO discovery_stopwords (world_id, token, created_at)
+			VALUES (?, ?, ?)
+		`, w.WorldID, w.Token, time.Now().UnixMilli())
+		if err != nil {
+			return fmt.Errorf("import stop word %s: %w", w.Token, err)
+		}
+	}
 
-// =============================================================================
-// Episode Log CRUD (Stub implementations — temporal action stream)
-// =============================================================================
+	// Re-insert coreference rejections
+	for _, r := range importData.Rejections {
+		_, err := s.db.Exec(`
+			INSERT OR IGNORE INTO coref_rejections (text, entity_id, created_at)
+			VALUES (?, ?, ?)
+		`, r.Text, r.EntityID, r.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("import coref rejection %s: %w", r.Text, err)
+		}
+	}
 
-// LogEpisode records a temporal action log entry.
+	// Re-insert the extraction cache and its note links
+	for _, e := range importData.Cache {
+		if err := s.putExtractionCacheLocked(e); err != nil {
+			return fmt.Errorf("import extraction cache %s: %w", e.ContentHash, err)
+		}
+	}
+
+	// Re-insert the LLM usage log
+	for _, u := range importData.Usage {
+		if err := s.addUsageLocked(u); err != nil {
+			return fmt.Errorf("import usage %s: %w", u.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Episode Log CRUD (Stub implementations — temporal action stream)
+// =============================================================================
+
+// LogEpisode records a temporal action log entry.
 // TODO: Implement full episode tracking for "what did LLM know at time T?" queries.
 func (s *SQLiteStore) LogEpisode(episode *Episode) error {
 	s.mu.Lock()
@@ -1989,17 +2526,18 @@ func (s *SQLiteStore) UpsertOMRecord(record *OMRecord) error {
 	defer s.mu.Unlock()
 
 	_, err := s.db.Exec(`
-		INSERT INTO om_records (thread_id, observations, current_task, last_observed_at,
+		INSERT INTO om_records (thread_id, observations, current_task, last_observed_at, last_observed_id,
 			obs_token_count, generation_num, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(thread_id) DO UPDATE SET
 			observations = excluded.observations,
 			current_task = excluded.current_task,
 			last_observed_at = excluded.last_observed_at,
+			last_observed_id = excluded.last_observed_id,
 			obs_token_count = excluded.obs_token_count,
 			generation_num = excluded.generation_num,
 			updated_at = excluded.updated_at
-	`, record.ThreadID, record.Observations, record.CurrentTask, record.LastObservedAt,
+	`, record.ThreadID, record.Observations, record.CurrentTask, record.LastObservedAt, record.LastObservedID,
 		record.ObsTokenCount, record.GenerationNum, record.CreatedAt, record.UpdatedAt)
 
 	return err
@@ -2013,11 +2551,11 @@ func (s *SQLiteStore) GetOMRecord(threadID string) (*OMRecord, error) {
 
 	var record OMRecord
 	err := s.db.QueryRow(`
-		SELECT thread_id, observations, current_task, last_observed_at,
+		SELECT thread_id, observations, current_task, last_observed_at, last_observed_id,
 			obs_token_count, generation_num, created_at, updated_at
 		FROM om_records WHERE thread_id = ?
 	`, threadID).Scan(
-		&record.ThreadID, &record.Observations, &record.CurrentTask, &record.LastObservedAt,
+		&record.ThreadID, &record.Observations, &record.CurrentTask, &record.LastObservedAt, &record.LastObservedID,
 		&record.ObsTokenCount, &record.GenerationNum, &record.CreatedAt, &record.UpdatedAt,
 	)
 
@@ -2237,6 +2775,511 @@ func (s *SQLiteStore) SearchNotes(scope *ScopeKey, query string, limit int) ([]*
 	return notes, nil
 }
 
+// =============================================================================
+// Discovery Review Queue CRUD
+// =============================================================================
+
+// UpsertCandidate inserts or updates a discovery candidate.
+func (s *SQLiteStore) UpsertCandidate(c *DiscoveryCandidate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.upsertCandidateLocked(c)
+}
+
+func (s *SQLiteStore) upsertCandidateLocked(c *DiscoveryCandidate) error {
+	noteCountsJSON, err := json.Marshal(c.NoteCounts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal note counts: %w", err)
+	}
+	contextsJSON, err := json.Marshal(c.Contexts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contexts: %w", err)
+	}
+	status := c.Status
+	if status == "" {
+		status = CandidatePending
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO discovery_candidates (world_id, token, display, count, note_counts,
+			inferred_kind, first_note_id, last_note_id, contexts, status, snoozed_until,
+			entity_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(world_id, token) DO UPDATE SET
+			display = excluded.display,
+			count = excluded.count,
+			note_counts = excluded.note_counts,
+			inferred_kind = excluded.inferred_kind,
+			first_note_id = excluded.first_note_id,
+			last_note_id = excluded.last_note_id,
+			contexts = excluded.contexts,
+			status = excluded.status,
+			snoozed_until = excluded.snoozed_until,
+			entity_id = excluded.entity_id,
+			updated_at = excluded.updated_at
+	`, c.WorldID, c.Token, c.Display, c.Count, string(noteCountsJSON),
+		c.InferredKind, c.FirstNoteID, c.LastNoteID, string(contextsJSON), status,
+		c.SnoozedUntil, c.EntityID, c.CreatedAt, c.UpdatedAt)
+
+	return err
+}
+
+const candidateColumns = `world_id, token, display, count, note_counts, inferred_kind,
+	first_note_id, last_note_id, contexts, status, snoozed_until, entity_id, created_at, updated_at`
+
+// scanCandidate reads one candidate row selected with candidateColumns.
+func scanCandidate(row interface{ Scan(...any) error }) (*DiscoveryCandidate, error) {
+	var c DiscoveryCandidate
+	var noteCountsJSON, contextsJSON string
+	if err := row.Scan(
+		&c.WorldID, &c.Token, &c.Display, &c.Count, &noteCountsJSON, &c.InferredKind,
+		&c.FirstNoteID, &c.LastNoteID, &contextsJSON, &c.Status, &c.SnoozedUntil,
+		&c.EntityID, &c.CreatedAt, &c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(noteCountsJSON), &c.NoteCounts); err != nil || c.NoteCounts == nil {
+		c.NoteCounts = map[string]int{}
+	}
+	if err := json.Unmarshal([]byte(contextsJSON), &c.Contexts); err != nil || c.Contexts == nil {
+		c.Contexts = []string{}
+	}
+	return &c, nil
+}
+
+// GetCandidate retrieves a candidate by world and canonical token.
+func (s *SQLiteStore) GetCandidate(worldID, token string) (*DiscoveryCandidate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, err := scanCandidate(s.db.QueryRow(`
+		SELECT `+candidateColumns+`
+		FROM discovery_candidates WHERE world_id = ? AND token = ?
+	`, worldID, token))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+// ListCandidates returns a world's candidates, most frequent first,
+// optionally filtered by status.
+func (s *SQLiteStore) ListCandidates(worldID string, status CandidateStatus) ([]*DiscoveryCandidate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows *sql.Rows
+	var err error
+
+	if status != "" {
+		rows, err = s.db.Query(`
+			SELECT `+candidateColumns+`
+			FROM discovery_candidates WHERE world_id = ? AND status = ?
+			ORDER BY count DESC, token
+		`, worldID, status)
+	} else {
+		rows, err = s.db.Query(`
+			SELECT `+candidateColumns+`
+			FROM discovery_candidates WHERE world_id = ?
+			ORDER BY count DESC, token
+		`, worldID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Initialize as empty slice to ensure JSON marshaling returns [] instead of null
+	candidates := make([]*DiscoveryCandidate, 0)
+	for rows.Next() {
+		c, err := scanCandidate(rows)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
+
+// DeleteCandidate removes a candidate.
+func (s *SQLiteStore) DeleteCandidate(worldID, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec("DELETE FROM discovery_candidates WHERE world_id = ? AND token = ?", worldID, token)
+	return err
+}
+
+// AddStopWord adds a token to a world's discovery stop list.
+func (s *SQLiteStore) AddStopWord(worldID, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO discovery_stopwords (world_id, token, created_at)
+		VALUES (?, ?, ?)
+	`, worldID, token, time.Now().UnixMilli())
+	return err
+}
+
+// RemoveStopWord removes a token from a world's discovery stop list.
+func (s *SQLiteStore) RemoveStopWord(worldID, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec("DELETE FROM discovery_stopwords WHERE world_id = ? AND token = ?", worldID, token)
+	return err
+}
+
+// ListStopWords returns a world's discovery stop list.
+func (s *SQLiteStore) ListStopWords(worldID string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT token FROM discovery_stopwords WHERE world_id = ? ORDER BY token
+	`, worldID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	words := make([]string, 0)
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, err
+		}
+		words = append(words, token)
+	}
+
+	return words, rows.Err()
+}
+
+// =============================================================================
+// Coreference Rejections CRUD
+// =============================================================================
+
+// AddCorefRejection records that text does not refer to an entity.
+// Text is stored as given; callers normalise it.
+func (s *SQLiteStore) AddCorefRejection(text, entityID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO coref_rejections (text, entity_id, created_at)
+		VALUES (?, ?, ?)
+	`, text, entityID, time.Now().UnixMilli())
+	return err
+}
+
+// RemoveCorefRejection forgets a rejection, e.g. after the user confirms
+// the resolution after all.
+func (s *SQLiteStore) RemoveCorefRejection(text, entityID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec("DELETE FROM coref_rejections WHERE text = ? AND entity_id = ?", text, entityID)
+	return err
+}
+
+// ListCorefRejections returns every rejection, oldest first.
+func (s *SQLiteStore) ListCorefRejections() ([]*CorefRejection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT text, entity_id, created_at FROM coref_rejections ORDER BY created_at, text, entity_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rejections := make([]*CorefRejection, 0)
+	for rows.Next() {
+		var r CorefRejection
+		if err := rows.Scan(&r.Text, &r.EntityID, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		rejections = append(rejections, &r)
+	}
+
+	return rejections, rows.Err()
+}
+
+// =============================================================================
+// LLM Usage CRUD
+// =============================================================================
+
+// AddUsage records one LLM call.
+func (s *SQLiteStore) AddUsage(usage *LLMUsage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addUsageLocked(usage)
+}
+
+func (s *SQLiteStore) addUsageLocked(usage *LLMUsage) error {
+	_, err := s.db.Exec(`
+		INSERT INTO llm_usage (id, provider, model, operation, prompt_tokens,
+			completion_tokens, latency_ms, attempts, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, usage.ID, usage.Provider, usage.Model, usage.Operation, usage.PromptTokens,
+		usage.CompletionTokens, usage.LatencyMs, usage.Attempts, usage.Error, usage.CreatedAt)
+
+	return err
+}
+
+// ListUsage returns calls made at or after since, newest first.
+// A limit <= 0 returns all of them.
+func (s *SQLiteStore) ListUsage(since int64, limit int) ([]*LLMUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	rows, err := s.db.Query(`
+		SELECT id, provider, model, operation, prompt_tokens, completion_tokens,
+			latency_ms, attempts, error, created_at
+		FROM llm_usage
+		WHERE created_at >= ?
+		ORDER BY created_at DESC, id
+		LIMIT ?
+	`, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make([]*LLMUsage, 0)
+	for rows.Next() {
+		var u LLMUsage
+		if err := rows.Scan(
+			&u.ID, &u.Provider, &u.Model, &u.Operation, &u.PromptTokens, &u.CompletionTokens,
+			&u.LatencyMs, &u.Attempts, &u.Error, &u.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		usage = append(usage, &u)
+	}
+
+	return usage, rows.Err()
+}
+
+// SummarizeUsage aggregates calls made at or after since per provider and
+// model, most prompt tokens first.
+func (s *SQLiteStore) SummarizeUsage(since int64) ([]*UsageSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT provider, model, COUNT(*),
+			SUM(CASE WHEN error != '' THEN 1 ELSE 0 END),
+			SUM(prompt_tokens), SUM(completion_tokens), AVG(latency_ms)
+		FROM llm_usage
+		WHERE created_at >= ?
+		GROUP BY provider, model
+		ORDER BY SUM(prompt_tokens) DESC, provider, model
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := make([]*UsageSummary, 0)
+	for rows.Next() {
+		var u UsageSummary
+		if err := rows.Scan(
+			&u.Provider, &u.Model, &u.Calls, &u.Errors,
+			&u.PromptTokens, &u.CompletionTokens, &u.AvgLatencyMs,
+		); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, &u)
+	}
+
+	return summaries, rows.Err()
+}
+
+// =============================================================================
+// Extraction Cache CRUD
+// =============================================================================
+
+// extractionCacheKeyWhere matches one cache entry, or its note links
+const extractionCacheKeyWhere = `content_hash = ? AND provider = ? AND model = ? AND prompt_version = ? AND entities_hash = ?`
+
+func extractionCacheKeyArgs(key ExtractionCacheKey) []interface{} {
+	return []interface{}{key.ContentHash, key.Provider, key.Model, key.PromptVersion, key.EntitiesHash}
+}
+
+// PutExtractionCache stores an extraction result, replacing any entry with
+// the same key (and resetting its hit count), and links it to e.NoteIDs.
+func (s *SQLiteStore) PutExtractionCache(e *ExtractionCacheEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.putExtractionCacheLocked(e)
+}
+
+func (s *SQLiteStore) putExtractionCacheLocked(e *ExtractionCacheEntry) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO extraction_cache (content_hash, provider, model, prompt_version,
+			entities_hash, result, hits, created_at, last_used_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, e.ContentHash, e.Provider, e.Model, e.PromptVersion, e.EntitiesHash,
+		e.Result, e.Hits, e.CreatedAt, e.LastUsedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, noteID := range e.NoteIDs {
+		if err := s.linkExtractionCache(e.ExtractionCacheKey, noteID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetExtractionCache looks up a cached result. Returns nil if none exists.
+func (s *SQLiteStore) GetExtractionCache(key ExtractionCacheKey) (*ExtractionCacheEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var e ExtractionCacheEntry
+	err := s.db.QueryRow(`
+		SELECT content_hash, provider, model, prompt_version, entities_hash,
+			result, hits, created_at, last_used_at
+		FROM extraction_cache
+		WHERE `+extractionCacheKeyWhere, extractionCacheKeyArgs(key)...).Scan(
+		&e.ContentHash, &e.Provider, &e.Model, &e.PromptVersion, &e.EntitiesHash,
+		&e.Result, &e.Hits, &e.CreatedAt, &e.LastUsedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT note_id FROM extraction_cache_notes
+		WHERE `+extractionCacheKeyWhere+` ORDER BY note_id`, extractionCacheKeyArgs(key)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var noteID string
+		if err := rows.Scan(&noteID); err != nil {
+			return nil, err
+		}
+		e.NoteIDs = append(e.NoteIDs, noteID)
+	}
+	return &e, rows.Err()
+}
+
+// TouchExtractionCache records a cache hit on the entry with key and links
+// it to noteID, so invalidating that note drops the shared entry too.
+func (s *SQLiteStore) TouchExtractionCache(key ExtractionCacheKey, noteID string, at int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		UPDATE extraction_cache SET hits = hits + 1, last_used_at = ?
+		WHERE `+extractionCacheKeyWhere, append([]interface{}{at}, extractionCacheKeyArgs(key)...)...)
+	if err != nil {
+		return err
+	}
+	return s.linkExtractionCache(key, noteID)
+}
+
+func (s *SQLiteStore) linkExtractionCache(key ExtractionCacheKey, noteID string) error {
+	if noteID == "" {
+		return nil
+	}
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO extraction_cache_notes (content_hash, provider, model,
+			prompt_version, entities_hash, note_id)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, append(extractionCacheKeyArgs(key), noteID)...)
+	return err
+}
+
+// DeleteExtractionCacheByNote drops every entry linked to noteID, including
+// entries other notes share, and returns how many were removed.
+func (s *SQLiteStore) DeleteExtractionCacheByNote(noteID string) (int, error) {
+	return s.deleteExtractionCache(`EXISTS (
+		SELECT 1 FROM extraction_cache_notes l
+		WHERE l.note_id = ? AND l.content_hash = extraction_cache.content_hash
+			AND l.provider = extraction_cache.provider AND l.model = extraction_cache.model
+			AND l.prompt_version = extraction_cache.prompt_version
+			AND l.entities_hash = extraction_cache.entities_hash)`, noteID)
+}
+
+// DeleteExtractionCacheByModel drops the entries produced by model and
+// returns how many were removed.
+func (s *SQLiteStore) DeleteExtractionCacheByModel(model string) (int, error) {
+	return s.deleteExtractionCache("model = ?", model)
+}
+
+// deleteExtractionCache drops the matching entries and the note links left
+// without an entry
+func (s *SQLiteStore) deleteExtractionCache(where string, arg interface{}) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.db.Exec(`DELETE FROM extraction_cache WHERE `+where, arg)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = s.db.Exec(`
+		DELETE FROM extraction_cache_notes WHERE NOT EXISTS (
+			SELECT 1 FROM extraction_cache c
+			WHERE c.content_hash = extraction_cache_notes.content_hash
+				AND c.provider = extraction_cache_notes.provider AND c.model = extraction_cache_notes.model
+				AND c.prompt_version = extraction_cache_notes.prompt_version
+				AND c.entities_hash = extraction_cache_notes.entities_hash)`)
+	return int(n), err
+}
+
+// ExtractionCacheStats summarises the cache overall and per provider model.
+func (s *SQLiteStore) ExtractionCacheStats() (*ExtractionCacheStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT provider, model, COUNT(*), SUM(hits), SUM(LENGTH(result))
+		FROM extraction_cache
+		GROUP BY provider, model
+		ORDER BY COUNT(*) DESC, provider, model
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &ExtractionCacheStats{Models: make([]*ExtractionCacheModelStats, 0)}
+	for rows.Next() {
+		var m ExtractionCacheModelStats
+		if err := rows.Scan(&m.Provider, &m.Model, &m.Entries, &m.Hits, &m.Bytes); err != nil {
+			return nil, err
+		}
+		stats.Entries += m.Entries
+		stats.Hits += m.Hits
+		stats.Bytes += m.Bytes
+		stats.Models = append(stats.Models, &m)
+	}
+
+	return stats, rows.Err()
+}
+
 // getNoteByID retrieves a note by ID without locking (internal helper).
 func (s *SQLiteStore) getNoteByID(id string) (*Note, error) {
 	var note Note
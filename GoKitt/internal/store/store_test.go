@@ -205,6 +205,7 @@ func TestEntityUpsertAndGet(t *testing.T) {
 		TotalMentions: 5,
 		NarrativeID:   "narrative-1",
 		CreatedBy:     "user",
+		Gender:        "male",
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
@@ -220,6 +221,7 @@ func TestEntityUpsertAndGet(t *testing.T) {
 	assert.Equal(t, entity.Label, retrieved.Label)
 	assert.Equal(t, entity.Kind, retrieved.Kind)
 	assert.Equal(t, entity.Aliases, retrieved.Aliases)
+	assert.Equal(t, "male", retrieved.Gender)
 }
 
 func TestEntityGetByLabel(t *testing.T) {
@@ -339,6 +341,9 @@ func TestEdgeUpsertAndGet(t *testing.T) {
 		Bidirectional: true,
 		SourceNote:    "note-1",
 		CreatedAt:     now,
+		Evidence: []EdgeEvidence{
+			{NoteID: "note-1", Sentence: [2]int{0, 24}, Verb: [2]int{5, 10}},
+		},
 	}
 
 	err := store.UpsertEdge(edge)
@@ -353,6 +358,12 @@ func TestEdgeUpsertAndGet(t *testing.T) {
 	assert.Equal(t, edge.TargetID, retrieved.TargetID)
 	assert.Equal(t, edge.RelType, retrieved.RelType)
 	assert.Equal(t, edge.Confidence, retrieved.Confidence)
+	assert.Equal(t, edge.Evidence, retrieved.Evidence)
+
+	listed, err := store.ListEdgesForEntity("entity-2")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, edge.Evidence, listed[0].Evidence)
 }
 
 func TestEdgeDelete(t *testing.T) {
@@ -431,6 +442,76 @@ func TestEdgeCount(t *testing.T) {
 	assert.Equal(t, 4, count)
 }
 
+func TestEdgeProvenanceRoundTrip(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Now().UnixMilli()
+
+	edge := &Edge{
+		ID:          "arin-KILLS-troll",
+		SourceID:    "arin",
+		TargetID:    "troll",
+		RelType:     "KILLS",
+		Confidence:  0.9,
+		Provenances: []string{"scanner", "llm"},
+		SourceNotes: []string{"note-1", "note-2"},
+		Attributes:  map[string]any{"tense": "PAST"},
+		Evidence:    []EdgeEvidence{{NoteID: "note-1", Sentence: [2]int{0, 22}, Verb: [2]int{5, 11}, Weight: 1}},
+		CreatedAt:   now,
+	}
+	require.NoError(t, store.UpsertEdge(edge))
+	require.NoError(t, store.UpsertEdge(&Edge{ID: "plain", SourceID: "a", TargetID: "b", RelType: "KNOWS", CreatedAt: now}))
+
+	edges, err := store.ListEdges()
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+	assert.Equal(t, edge.Provenances, edges[0].Provenances)
+	assert.Equal(t, edge.SourceNotes, edges[0].SourceNotes)
+	assert.Equal(t, edge.Attributes, edges[0].Attributes)
+	assert.Nil(t, edges[1].Provenances)
+
+	// Survives export/import
+	data, err := store.Export()
+	require.NoError(t, err)
+	s2 := newTestStore(t)
+	require.NoError(t, s2.Import(data))
+	got, err := s2.GetEdge("arin-KILLS-troll")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, edge.Provenances, got.Provenances)
+	assert.Equal(t, edge.Evidence, got.Evidence)
+}
+
+func TestListEdgesAt(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Now().UnixMilli()
+	at := func(v float64) *float64 { return &v }
+
+	// Arin ruled Eldoria from chapter 1 until the war (chapter 5)
+	require.NoError(t, store.UpsertEdge(&Edge{ID: "rules", SourceID: "arin", TargetID: "eldoria", RelType: "RULES",
+		StoryFrom: at(1), StoryUntil: at(5), CreatedAt: now}))
+	require.NoError(t, store.UpsertEdge(&Edge{ID: "exiled", SourceID: "arin", TargetID: "eldoria", RelType: "EXILED_FROM",
+		StoryFrom: at(5), CreatedAt: now}))
+	require.NoError(t, store.UpsertEdge(&Edge{ID: "sibling", SourceID: "arin", TargetID: "lyra", RelType: "SIBLING_OF", CreatedAt: now}))
+
+	ids := func(t *testing.T, storyTime float64) []string {
+		edges, err := store.ListEdgesAt(storyTime)
+		require.NoError(t, err)
+		var out []string
+		for _, e := range edges {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"sibling"}, ids(t, 0))
+	assert.Equal(t, []string{"rules", "sibling"}, ids(t, 3))
+	assert.Equal(t, []string{"exiled", "sibling"}, ids(t, 5))
+
+	got, err := store.GetEdge("rules")
+	require.NoError(t, err)
+	require.NotNil(t, got.StoryUntil)
+	assert.Equal(t, 5.0, *got.StoryUntil)
+}
+
 // =============================================================================
 // Interface Compliance Test
 // =============================================================================
@@ -703,3 +784,61 @@ func TestNoteVersioning_CountsCurrentOnly(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1, count)
 }
+
+func TestThreadMessages_ToolCallsRoundTrip(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.CreateThread(&Thread{ID: "thread-1", CreatedAt: 1, UpdatedAt: 1}))
+
+	// Same timestamp: insertion order decides
+	msgs := []*ThreadMessage{
+		{ID: "m1", ThreadID: "thread-1", Role: "user", Content: "Who is Smaug?", CreatedAt: 10},
+		{ID: "m2", ThreadID: "thread-1", Role: "assistant", ToolCalls: `[{"id":"call_1","type":"function","function":{"name":"search_notes","arguments":"{}"}}]`, CreatedAt: 10},
+		{ID: "m3", ThreadID: "thread-1", Role: "tool", Content: `[{"id":"n1"}]`, ToolCallID: "call_1", CreatedAt: 10},
+	}
+	for _, m := range msgs {
+		require.NoError(t, store.AddMessage(m))
+	}
+
+	got, err := store.GetThreadMessages("thread-1")
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, []string{"m1", "m2", "m3"}, []string{got[0].ID, got[1].ID, got[2].ID})
+	assert.Equal(t, msgs[1].ToolCalls, got[1].ToolCalls)
+	assert.Equal(t, "call_1", got[2].ToolCallID)
+
+	one, err := store.GetMessage("m3")
+	require.NoError(t, err)
+	assert.Equal(t, "tool", one.Role)
+	assert.Equal(t, "call_1", one.ToolCallID)
+}
+
+func TestThreadMessagesAfter(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.CreateThread(&Thread{ID: "thread-1", CreatedAt: 1, UpdatedAt: 1}))
+	for i, at := range []int64{10, 10, 20} {
+		id := []string{"m1", "m2", "m3"}[i]
+		require.NoError(t, store.AddMessage(&ThreadMessage{ID: id, ThreadID: "thread-1", Role: "user", Content: id, CreatedAt: at}))
+	}
+
+	ids := func(msgs []*ThreadMessage) []string {
+		out := []string{}
+		for _, m := range msgs {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	// The message sharing m1's millisecond still follows it
+	got, err := store.GetThreadMessagesAfter("thread-1", "m1", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m2", "m3"}, ids(got))
+
+	got, err = store.GetThreadMessagesAfter("thread-1", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m1", "m2", "m3"}, ids(got))
+
+	// Unknown ID: falls back to the timestamp
+	got, err = store.GetThreadMessagesAfter("thread-1", "gone", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m3"}, ids(got))
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// LLM Usage Tests
+// =============================================================================
+
+func TestUsage_AddAndList(t *testing.T) {
+	s := newTestStore(t)
+
+	records := []*LLMUsage{
+		{ID: "u1", Provider: "google", Model: "gemini", Operation: "complete", PromptTokens: 100, CompletionTokens: 20, LatencyMs: 300, Attempts: 1, CreatedAt: 1000},
+		{ID: "u2", Provider: "google", Model: "gemini", Operation: "complete", PromptTokens: 50, CompletionTokens: 10, LatencyMs: 500, Attempts: 3, Error: "HTTP 503", CreatedAt: 2000},
+		{ID: "u3", Provider: "anthropic", Model: "claude", Operation: "tools", PromptTokens: 400, CompletionTokens: 80, LatencyMs: 900, Attempts: 1, CreatedAt: 3000},
+	}
+	for _, u := range records {
+		require.NoError(t, s.AddUsage(u))
+	}
+
+	all, err := s.ListUsage(0, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "u3", all[0].ID, "newest first")
+	assert.Equal(t, *records[1], *all[1])
+
+	recent, err := s.ListUsage(2000, 1)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Equal(t, "u3", recent[0].ID)
+
+	none, err := s.ListUsage(5000, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, none)
+	assert.Empty(t, none)
+}
+
+func TestUsage_Summarize(t *testing.T) {
+	s := newTestStore(t)
+
+	require.NoError(t, s.AddUsage(&LLMUsage{ID: "u1", Provider: "google", Model: "gemini", PromptTokens: 100, CompletionTokens: 20, LatencyMs: 300, Attempts: 1, CreatedAt: 1000}))
+	require.NoError(t, s.AddUsage(&LLMUsage{ID: "u2", Provider: "google", Model: "gemini", PromptTokens: 50, CompletionTokens: 10, LatencyMs: 500, Attempts: 3, Error: "HTTP 503", CreatedAt: 2000}))
+	require.NoError(t, s.AddUsage(&LLMUsage{ID: "u3", Provider: "anthropic", Model: "claude", PromptTokens: 400, CompletionTokens: 80, LatencyMs: 900, Attempts: 1, CreatedAt: 3000}))
+
+	summary, err := s.SummarizeUsage(0)
+	require.NoError(t, err)
+	require.Len(t, summary, 2)
+
+	assert.Equal(t, "anthropic", summary[0].Provider, "most prompt tokens first")
+	assert.Equal(t, UsageSummary{
+		Provider: "google", Model: "gemini", Calls: 2, Errors: 1,
+		PromptTokens: 150, CompletionTokens: 30, AvgLatencyMs: 400,
+	}, *summary[1])
+
+	later, err := s.SummarizeUsage(1500)
+	require.NoError(t, err)
+	require.Len(t, later, 2)
+	assert.Equal(t, 1, later[1].Calls)
+}
+
+func TestUsage_ExportImport(t *testing.T) {
+	s := newTestStore(t)
+
+	u := &LLMUsage{ID: "u1", Provider: "google", Model: "gemini", Operation: "complete", PromptTokens: 100, CompletionTokens: 20, LatencyMs: 300, Attempts: 2, Error: "HTTP 503", CreatedAt: 1000}
+	require.NoError(t, s.AddUsage(u))
+
+	data, err := s.Export()
+	require.NoError(t, err)
+
+	s2 := newTestStore(t)
+	require.NoError(t, s2.AddUsage(&LLMUsage{ID: "stale", Provider: "openrouter", CreatedAt: 500}))
+	require.NoError(t, s2.Import(data))
+
+	all, err := s2.ListUsage(0, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 1, "import replaces the usage log")
+	assert.Equal(t, *u, *all[0])
+}
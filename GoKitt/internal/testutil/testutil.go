@@ -0,0 +1,60 @@
+// Package testutil holds test scaffolding shared by packages that run an
+// LLM over stored chat threads.
+package testutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kittclouds/gokitt/internal/store"
+)
+
+// Scripted is a batch.Completer that answers each call with the next
+// reply (or error), recording the prompts it was sent.
+type Scripted struct {
+	Replies []string
+	Errs    []error
+	Prompts []string
+	Systems []string
+}
+
+// Complete returns the next scripted reply.
+func (c *Scripted) Complete(ctx context.Context, userPrompt, systemPrompt string) (string, error) {
+	i := len(c.Prompts)
+	c.Prompts = append(c.Prompts, userPrompt)
+	c.Systems = append(c.Systems, systemPrompt)
+	if i < len(c.Errs) && c.Errs[i] != nil {
+		return "", c.Errs[i]
+	}
+	if i >= len(c.Replies) {
+		return "", errors.New("no more replies")
+	}
+	return c.Replies[i], nil
+}
+
+// NewStore opens an in-memory store, closed when the test ends, with the
+// given threads created.
+func NewStore(t testing.TB, threadIDs ...string) store.Storer {
+	t.Helper()
+	st, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	for _, id := range threadIDs {
+		if err := st.CreateThread(&store.Thread{ID: id, CreatedAt: 1, UpdatedAt: 1}); err != nil {
+			t.Fatalf("create thread: %v", err)
+		}
+	}
+	return st
+}
+
+// AddMessage adds a finished message to a thread.
+func AddMessage(t testing.TB, st store.Storer, threadID, id, role, content string, at int64) {
+	t.Helper()
+	msg := &store.ThreadMessage{ID: id, ThreadID: threadID, Role: role, Content: content, CreatedAt: at, UpdatedAt: at}
+	if err := st.AddMessage(msg); err != nil {
+		t.Fatalf("add message: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Tool is a function the model can call, implemented in Go.
+type Tool struct {
+	Definition ToolDefinition
+	// Run receives the call's JSON arguments and returns the result the
+	// model sees, usually JSON.
+	Run func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+type narrativeKey struct{}
+
+// WithNarrative scopes tool calls made under ctx to a narrative
+func WithNarrative(ctx context.Context, narrativeID string) context.Context {
+	return context.WithValue(ctx, narrativeKey{}, narrativeID)
+}
+
+// NarrativeFrom returns the narrative set by WithNarrative, or ""
+func NarrativeFrom(ctx context.Context) string {
+	id, _ := ctx.Value(narrativeKey{}).(string)
+	return id
+}
+
+// NewTool builds a tool from its name, description and JSON Schema
+// parameters.
+func NewTool(name, description, parameters string, run func(ctx context.Context, args json.RawMessage) (string, error)) Tool {
+	return Tool{
+		Definition: ToolDefinition{
+			Type: "function",
+			Function: ToolFunctionSchema{
+				Name:        name,
+				Description: description,
+				Parameters:  json.RawMessage(parameters),
+			},
+		},
+		Run: run,
+	}
+}
+
+// Registry holds the tools available to a Runner, in registration order.
+type Registry struct {
+	tools map[string]Tool
+	order []string
+}
+
+// NewRegistry creates a registry with the given tools.
+func NewRegistry(tools ...Tool) *Registry {
+	r := &Registry{tools: make(map[string]Tool)}
+	for _, t := range tools {
+		r.Register(t)
+	}
+	return r
+}
+
+// Register adds a tool, replacing any tool with the same name.
+func (r *Registry) Register(t Tool) {
+	name := t.Definition.Function.Name
+	if _, ok := r.tools[name]; !ok {
+		r.order = append(r.order, name)
+	}
+	r.tools[name] = t
+}
+
+// Definitions returns the schemas sent to the model.
+func (r *Registry) Definitions() []ToolDefinition {
+	defs := make([]ToolDefinition, 0, len(r.order))
+	for _, name := range r.order {
+		defs = append(defs, r.tools[name].Definition)
+	}
+	return defs
+}
+
+// Execute runs the tool a call names.
+func (r *Registry) Execute(ctx context.Context, call ToolCall) (string, error) {
+	t, ok := r.tools[call.Function.Name]
+	if !ok {
+		return "", fmt.Errorf("agent: unknown tool %q", call.Function.Name)
+	}
+	args := json.RawMessage(call.Function.Arguments)
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	return t.Run(ctx, args)
+}
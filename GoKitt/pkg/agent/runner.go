@@ -0,0 +1,199 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kittclouds/gokitt/internal/ids"
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/batch"
+)
+
+// Completer makes one tool-calling completion. *Service implements it;
+// tests substitute a scripted model.
+type Completer interface {
+	ChatWithTools(ctx context.Context, messages []Message, tools []ToolDefinition, systemPrompt string) (*CompletionResult, error)
+}
+
+// Stop reasons reported in RunResult.
+const (
+	StopDone          = "done"           // The model answered without calling tools
+	StopMaxIterations = "max_iterations" // MaxIterations completions were made
+	StopTokenBudget   = "token_budget"   // TokenBudget was spent
+)
+
+// RunConfig bounds an agent run.
+type RunConfig struct {
+	MaxIterations int // Completions per run (default 8)
+	TokenBudget   int // Prompt + completion tokens per run, checked after each completion; 0 = unlimited
+}
+
+// DefaultRunConfig returns the default bounds.
+func DefaultRunConfig() RunConfig {
+	return RunConfig{MaxIterations: 8}
+}
+
+// Step is one tool call the runner executed.
+type Step struct {
+	Iteration int             `json:"iteration"`
+	CallID    string          `json:"callId"`
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments"`
+	Result    string          `json:"result"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// RunResult is the outcome of an agent run.
+type RunResult struct {
+	Content    string     `json:"content"`  // Final answer; empty if the run stopped early
+	Messages   []Message  `json:"messages"` // Messages the run added, in order
+	Steps      []Step     `json:"steps"`
+	Iterations int        `json:"iterations"`
+	Usage      TokenUsage `json:"usage"`
+	StopReason string     `json:"stopReason"`
+}
+
+// Runner executes the tool-calling loop in Go: it asks the model, runs the
+// tools it calls from the registry, feeds the results back and repeats
+// until the model answers or a bound is hit.
+type Runner struct {
+	llm   Completer
+	tools *Registry
+	store store.Storer // Optional transcript sink
+	cfg   RunConfig
+}
+
+// NewRunner creates a runner. When s is non-nil, every message a run adds
+// is persisted to the run's thread.
+func NewRunner(llm Completer, tools *Registry, s store.Storer, cfg RunConfig) *Runner {
+	if cfg.MaxIterations <= 0 {
+		cfg.MaxIterations = DefaultRunConfig().MaxIterations
+	}
+	if tools == nil {
+		tools = NewRegistry()
+	}
+	return &Runner{llm: llm, tools: tools, store: s, cfg: cfg}
+}
+
+// Run continues the conversation in messages. threadID names the thread
+// the transcript is written to; empty skips persistence. Tool failures are
+// reported to the model as {"error": ...} results rather than ending the run.
+func (r *Runner) Run(ctx context.Context, threadID string, messages []Message, systemPrompt string) (*RunResult, error) {
+	if r.llm == nil {
+		return nil, fmt.Errorf("agent: no model configured")
+	}
+
+	// Transcript messages and tool calls inherit the thread's narrative scope
+	narrativeID := ""
+	if r.store != nil && threadID != "" {
+		thread, err := r.store.GetThread(threadID)
+		if err != nil {
+			return nil, fmt.Errorf("agent: load thread: %w", err)
+		}
+		if thread == nil {
+			return nil, fmt.Errorf("agent: thread %q not found", threadID)
+		}
+		narrativeID = thread.NarrativeID
+	}
+	ctx = WithNarrative(ctx, narrativeID)
+
+	history := append([]Message(nil), messages...)
+	defs := r.tools.Definitions()
+	result := &RunResult{Messages: []Message{}, Steps: []Step{}}
+	record := func(msg Message) error {
+		history = append(history, msg)
+		result.Messages = append(result.Messages, msg)
+		return r.persist(threadID, narrativeID, msg)
+	}
+
+	for result.Iterations < r.cfg.MaxIterations {
+		result.Iterations++
+		reply, err := r.llm.ChatWithTools(ctx, history, defs, systemPrompt)
+		if err != nil {
+			return result, err
+		}
+		result.Usage.PromptTokens += reply.Usage.PromptTokens
+		result.Usage.CompletionTokens += reply.Usage.CompletionTokens
+
+		// Over budget: keep a final answer, but run no more tools. The
+		// unexecuted calls are left out of the transcript so the thread
+		// never holds calls without results.
+		if len(reply.ToolCalls) > 0 && r.cfg.TokenBudget > 0 && result.Usage.Total() >= r.cfg.TokenBudget {
+			result.StopReason = StopTokenBudget
+			return result, nil
+		}
+
+		assistant := Message{Role: "assistant", Content: reply.Content, ToolCalls: reply.ToolCalls}
+		if err := record(assistant); err != nil {
+			return result, err
+		}
+
+		if len(reply.ToolCalls) == 0 {
+			if reply.Content != nil {
+				result.Content = *reply.Content
+			}
+			result.StopReason = StopDone
+			return result, nil
+		}
+
+		for _, call := range reply.ToolCalls {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			step := Step{
+				Iteration: result.Iterations,
+				CallID:    call.ID,
+				Tool:      call.Function.Name,
+				Arguments: batch.ToolArguments(call.Function.Arguments),
+			}
+			out, err := r.tools.Execute(ctx, call)
+			if err != nil {
+				step.Error = err.Error()
+				out, _ = toJSON(map[string]string{"error": err.Error()})
+			}
+			step.Result = out
+			result.Steps = append(result.Steps, step)
+
+			toolMsg := Message{Role: "tool", Content: &out, ToolCallID: call.ID}
+			if err := record(toolMsg); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	result.StopReason = StopMaxIterations
+	return result, nil
+}
+
+// persist writes one transcript message to the thread.
+func (r *Runner) persist(threadID, narrativeID string, msg Message) error {
+	if r.store == nil || threadID == "" {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	tm := &store.ThreadMessage{
+		ID:          ids.New(),
+		ThreadID:    threadID,
+		Role:        msg.Role,
+		NarrativeID: narrativeID,
+		ToolCallID:  msg.ToolCallID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if msg.Content != nil {
+		tm.Content = *msg.Content
+	}
+	if len(msg.ToolCalls) > 0 {
+		calls, err := json.Marshal(msg.ToolCalls)
+		if err != nil {
+			return fmt.Errorf("agent: encode tool calls: %w", err)
+		}
+		tm.ToolCalls = string(calls)
+	}
+	if err := r.store.AddMessage(tm); err != nil {
+		return fmt.Errorf("agent: persist transcript: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/ontology"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+)
+
+// scriptedModel replays canned replies and records what it was sent
+type scriptedModel struct {
+	replies []*CompletionResult
+	seen    [][]Message
+	tools   []ToolDefinition
+}
+
+func (m *scriptedModel) ChatWithTools(ctx context.Context, messages []Message, tools []ToolDefinition, systemPrompt string) (*CompletionResult, error) {
+	m.seen = append(m.seen, append([]Message(nil), messages...))
+	m.tools = tools
+	if len(m.seen) > len(m.replies) {
+		return nil, errors.New("script exhausted")
+	}
+	return m.replies[len(m.seen)-1], nil
+}
+
+func text(s string) *string { return &s }
+
+func callReply(tokens int, calls ...ToolCall) *CompletionResult {
+	return &CompletionResult{ToolCalls: calls, Usage: TokenUsage{PromptTokens: tokens, CompletionTokens: tokens / 10}}
+}
+
+func call(id, name, args string) ToolCall {
+	return ToolCall{ID: id, Type: "function", Function: FunctionCall{Name: name, Arguments: args}}
+}
+
+func newWorldStore(t *testing.T) *store.SQLiteStore {
+	t.Helper()
+	s, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	if err := s.UpsertNote(&store.Note{ID: "n1", Title: "Smaug", Content: "Smaug the dragon sleeps under the Lonely Mountain."}); err != nil {
+		t.Fatalf("note: %v", err)
+	}
+	for _, e := range []*store.Entity{
+		{ID: "e-smaug", Label: "Smaug", Kind: "CHARACTER", Aliases: []string{}},
+		{ID: "e-mountain", Label: "Lonely Mountain", Kind: "LOCATION", Aliases: []string{}},
+	} {
+		if err := s.UpsertEntity(e); err != nil {
+			t.Fatalf("entity: %v", err)
+		}
+	}
+	if err := s.CreateThread(&store.Thread{ID: "t1", NarrativeID: "nar-1"}); err != nil {
+		t.Fatalf("thread: %v", err)
+	}
+	return s
+}
+
+func TestRunner_LoopAndTranscript(t *testing.T) {
+	s := newWorldStore(t)
+	model := &scriptedModel{replies: []*CompletionResult{
+		callReply(100, call("c1", "search_notes", `{"query":"Smaug"}`)),
+		callReply(200, call("c2", "get_note", `{"id":"n1"}`), call("c3", "get_neighbors", `{"entity":"Smaug"}`)),
+		{Content: text("Smaug sleeps under the Lonely Mountain."), Usage: TokenUsage{PromptTokens: 300, CompletionTokens: 12}},
+	}}
+
+	runner := NewRunner(model, NewRegistry(StoreTools(s, nil)...), s, DefaultRunConfig())
+	result, err := runner.Run(context.Background(), "t1", []Message{{Role: "user", Content: text("Where is Smaug?")}}, "Answer from the notes")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if result.StopReason != StopDone || result.Iterations != 3 {
+		t.Errorf("stop = %s after %d iterations", result.StopReason, result.Iterations)
+	}
+	if result.Content != "Smaug sleeps under the Lonely Mountain." {
+		t.Errorf("content = %q", result.Content)
+	}
+	if result.Usage.PromptTokens != 600 || result.Usage.CompletionTokens != 42 {
+		t.Errorf("usage = %+v", result.Usage)
+	}
+	if len(model.tools) != 6 {
+		t.Errorf("expected 6 tool definitions, got %d", len(model.tools))
+	}
+
+	if len(result.Steps) != 3 {
+		t.Fatalf("steps = %+v", result.Steps)
+	}
+	for _, step := range result.Steps {
+		if step.Error != "" {
+			t.Errorf("step %s failed: %s", step.Tool, step.Error)
+		}
+	}
+	if !strings.Contains(result.Steps[1].Result, "Lonely Mountain") {
+		t.Errorf("get_note result = %s", result.Steps[1].Result)
+	}
+
+	// The model saw each tool result before answering
+	last := model.seen[2]
+	if len(last) != 6 || last[5].Role != "tool" || last[5].ToolCallID != "c3" {
+		t.Errorf("final history = %+v", last)
+	}
+
+	msgs, err := s.GetThreadMessages("t1")
+	if err != nil {
+		t.Fatalf("transcript: %v", err)
+	}
+	roles := make([]string, len(msgs))
+	for i, m := range msgs {
+		roles[i] = m.Role
+	}
+	want := "assistant tool assistant tool tool assistant"
+	if got := strings.Join(roles, " "); got != want {
+		t.Errorf("transcript roles = %q, want %q", got, want)
+	}
+	if msgs[1].ToolCallID != "c1" || msgs[0].NarrativeID != "nar-1" {
+		t.Errorf("tool message = %+v", msgs[1])
+	}
+	var calls []ToolCall
+	if err := json.Unmarshal([]byte(msgs[2].ToolCalls), &calls); err != nil || len(calls) != 2 {
+		t.Errorf("persisted tool calls = %q (%v)", msgs[2].ToolCalls, err)
+	}
+}
+
+func TestRunner_Bounds(t *testing.T) {
+	loop := func() *scriptedModel {
+		m := &scriptedModel{}
+		for i := 0; i < 10; i++ {
+			m.replies = append(m.replies, callReply(400, call("c", "list_entities", `{}`)))
+		}
+		return m
+	}
+	s := newWorldStore(t)
+	tools := NewRegistry(StoreTools(s, nil)...)
+
+	result, err := NewRunner(loop(), tools, nil, RunConfig{MaxIterations: 3}).Run(context.Background(), "", nil, "")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.StopReason != StopMaxIterations || result.Iterations != 3 || len(result.Steps) != 3 {
+		t.Errorf("stop = %s, iterations = %d, steps = %d", result.StopReason, result.Iterations, len(result.Steps))
+	}
+
+	result, err = NewRunner(loop(), tools, nil, RunConfig{MaxIterations: 10, TokenBudget: 1000}).Run(context.Background(), "", nil, "")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// 440 tokens per completion: the third crosses 1000, and its tools never run
+	if result.StopReason != StopTokenBudget || result.Iterations != 3 || len(result.Steps) != 2 {
+		t.Errorf("stop = %s after %d iterations, %d steps", result.StopReason, result.Iterations, len(result.Steps))
+	}
+	if len(result.Messages) != 4 || result.Messages[3].Role != "tool" {
+		t.Errorf("expected the over-budget calls left out, got %+v", result.Messages)
+	}
+
+	// A final answer that crosses the budget is still returned
+	model := &scriptedModel{replies: []*CompletionResult{{Content: text("Done."), Usage: TokenUsage{PromptTokens: 2000}}}}
+	result, err = NewRunner(model, tools, nil, RunConfig{TokenBudget: 1000}).Run(context.Background(), "", nil, "")
+	if err != nil || result.StopReason != StopDone || result.Content != "Done." {
+		t.Errorf("stop = %s, content = %q (%v)", result.StopReason, result.Content, err)
+	}
+}
+
+func TestRunner_ToolErrorsGoBackToModel(t *testing.T) {
+	model := &scriptedModel{replies: []*CompletionResult{
+		callReply(10, call("c1", "no_such_tool", `{}`), call("c2", "get_note", `{"id":"missing"}`)),
+		{Content: text("Sorry, I could not find it.")},
+	}}
+	s := newWorldStore(t)
+
+	result, err := NewRunner(model, NewRegistry(StoreTools(s, nil)...), nil, DefaultRunConfig()).Run(context.Background(), "", nil, "")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.StopReason != StopDone {
+		t.Errorf("stop = %s", result.StopReason)
+	}
+	for _, step := range result.Steps {
+		if step.Error == "" || !strings.Contains(step.Result, `"error"`) {
+			t.Errorf("step %s should report an error, got %+v", step.Tool, step)
+		}
+	}
+
+	// Unknown thread is an error when persisting
+	if _, err := NewRunner(model, nil, s, DefaultRunConfig()).Run(context.Background(), "nope", nil, ""); err == nil {
+		t.Error("expected an error for an unknown thread")
+	}
+}
+
+func TestStoreTools_CreateEntityAndEdge(t *testing.T) {
+	s := newWorldStore(t)
+	tools := NewRegistry(StoreTools(s, ontology.Default())...)
+	ctx := WithNarrative(context.Background(), "saga")
+
+	out, err := tools.Execute(ctx, call("c1", "create_entity", `{"label":"Bard","kind":"character"}`))
+	if err != nil {
+		t.Fatalf("create_entity: %v", err)
+	}
+	var created map[string]string
+	json.Unmarshal([]byte(out), &created)
+	if created["kind"] != "CHARACTER" || created["id"] == "" {
+		t.Errorf("created = %v", created)
+	}
+	if e, _ := s.GetEntity(created["id"]); e == nil || e.NarrativeID != "saga" {
+		t.Errorf("expected the entity in the call's narrative, got %+v", e)
+	}
+	if _, err := tools.Execute(ctx, call("c2", "create_entity", `{"label":"Bard","kind":"CHARACTER"}`)); err == nil {
+		t.Error("duplicate label should fail")
+	}
+
+	// Labels resolve to IDs; "killed by" is the inverse of KILLS
+	if _, err := tools.Execute(ctx, call("c3", "create_edge", `{"source":"Smaug","relation":"killed by","target":"Bard"}`)); err != nil {
+		t.Fatalf("create_edge: %v", err)
+	}
+	edges, err := s.ListEdgesForEntity("e-smaug")
+	if err != nil || len(edges) != 1 {
+		t.Fatalf("edges = %+v (%v)", edges, err)
+	}
+	if edges[0].SourceID != created["id"] || edges[0].RelType != "KILLS" || edges[0].TargetID != "e-smaug" {
+		t.Errorf("edge = %+v", edges[0])
+	}
+
+	out, err = tools.Execute(ctx, call("c4", "get_neighbors", `{"entity":"e-smaug"}`))
+	if err != nil {
+		t.Fatalf("get_neighbors: %v", err)
+	}
+	if !strings.Contains(out, `"label":"Bard"`) || !strings.Contains(out, `"direction":"in"`) {
+		t.Errorf("neighbors = %s", out)
+	}
+
+	if _, err := tools.Execute(ctx, call("c5", "create_edge", `{"source":"Smaug","relation":"KILLS","target":"Nobody"}`)); err == nil {
+		t.Error("unknown target should fail")
+	}
+
+	// The same relation again merges into the stored edge
+	if _, err := tools.Execute(ctx, call("c6", "create_edge", `{"source":"Bard","relation":"KILLS","target":"Smaug","confidence":0.5}`)); err != nil {
+		t.Fatalf("create_edge: %v", err)
+	}
+	edges, _ = s.ListEdgesForEntity("e-smaug")
+	if len(edges) != 1 || edges[0].ID != merger.EdgeKey(created["id"], "e-smaug", "KILLS") || edges[0].Confidence != 0.8 {
+		t.Errorf("expected one merged edge, got %+v", edges)
+	}
+}
+
+func TestStoreTools_SearchNotesInNarrative(t *testing.T) {
+	s := newWorldStore(t)
+	s.UpsertNote(&store.Note{ID: "saga-1", Title: "Dragon hoard", Content: "The dragon guards its hoard.", NarrativeID: "saga", CreatedAt: 1, UpdatedAt: 1})
+	s.UpsertNote(&store.Note{ID: "other-1", Title: "Dragon egg", Content: "A dragon egg hatches.", NarrativeID: "other", CreatedAt: 1, UpdatedAt: 1})
+	tools := NewRegistry(StoreTools(s, nil)...)
+
+	out, err := tools.Execute(WithNarrative(context.Background(), "saga"), call("c1", "search_notes", `{"query":"dragon"}`))
+	if err != nil {
+		t.Fatalf("search_notes: %v", err)
+	}
+	if !strings.Contains(out, "saga-1") || strings.Contains(out, "other-1") {
+		t.Errorf("expected only the saga note, got %s", out)
+	}
+}
+
+func TestStoreTools_GetNoteTruncatesOnRuneBoundary(t *testing.T) {
+	s := newWorldStore(t)
+	content := strings.Repeat("a", maxNoteChars-1) + "é and more"
+	s.UpsertNote(&store.Note{ID: "long", Title: "Long", Content: content, CreatedAt: 1, UpdatedAt: 1})
+	tools := NewRegistry(StoreTools(s, nil)...)
+
+	out, err := tools.Execute(context.Background(), call("c1", "get_note", `{"id":"long"}`))
+	if err != nil {
+		t.Fatalf("get_note: %v", err)
+	}
+	var got struct {
+		Content   string `json:"content"`
+		Truncated bool   `json:"truncated"`
+	}
+	json.Unmarshal([]byte(out), &got)
+	if !got.Truncated || len(got.Content) != maxNoteChars-1 || !utf8.ValidString(got.Content) {
+		t.Errorf("expected truncation before the split rune, got %d bytes", len(got.Content))
+	}
+}
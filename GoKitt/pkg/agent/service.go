@@ -2,8 +2,9 @@
 // Used by the agentic chat loop for function-calling interactions.
 //
 // This is the Go-side "backend LLM logic" that replaces the chatWithTools
-// method from openrouter.service.ts. The tool schemas, tool executor, and
-// editor bridge remain in TypeScript since they touch the DOM.
+// method from openrouter.service.ts. Runner drives the whole loop in Go
+// with tools from a Registry (see StoreTools); editor tools remain in
+// TypeScript since they touch the DOM.
 package agent
 
 import (
@@ -14,7 +15,8 @@ import (
 	"github.com/kittclouds/gokitt/pkg/batch"
 )
 
-// Message represents a chat message in the OpenRouter/Google format.
+// Message represents a chat message in the OpenAI format, which batch
+// translates for providers that speak another one.
 type Message struct {
 	Role       string     `json:"role"`
 	Content    *string    `json:"content"` // Pointer to allow null
@@ -52,6 +54,19 @@ type ToolFunctionSchema struct {
 type CompletionResult struct {
 	Content   *string    `json:"content"`
 	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	Usage     TokenUsage `json:"usage"`
+}
+
+// TokenUsage counts the tokens a request consumed, when the provider
+// reports them.
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Total is prompt plus completion tokens.
+func (u TokenUsage) Total() int {
+	return u.PromptTokens + u.CompletionTokens
 }
 
 // Service wraps batch.Service to provide tool-calling completions.
@@ -67,7 +82,8 @@ func NewService(b *batch.Service) *Service {
 // ChatWithTools performs a non-streaming LLM call that may return tool_calls.
 // This replaces openrouter.service.ts chatWithTools().
 //
-// The actual HTTP call goes through batch.Service which uses syscall/js fetch.
+// The actual HTTP call goes through batch.Service, which translates to and
+// from the configured provider's tool-calling format.
 func (s *Service) ChatWithTools(
 	ctx context.Context,
 	messages []Message,
@@ -98,7 +114,7 @@ func (s *Service) ChatWithTools(
 		return nil, fmt.Errorf("agent: LLM call failed: %w", err)
 	}
 
-	// Parse the OpenRouter/Google response
+	// Parse the response, normalised to the OpenAI shape by batch
 	return parseCompletionResponse(raw)
 }
 
@@ -111,6 +127,7 @@ func parseCompletionResponse(raw string) (*CompletionResult, error) {
 				ToolCalls []ToolCall `json:"tool_calls"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage TokenUsage `json:"usage"`
 	}
 
 	if err := json.Unmarshal([]byte(raw), &response); err != nil {
@@ -125,5 +142,6 @@ func parseCompletionResponse(raw string) (*CompletionResult, error) {
 	return &CompletionResult{
 		Content:   choice.Content,
 		ToolCalls: choice.ToolCalls,
+		Usage:     response.Usage,
 	}, nil
 }
@@ -1,8 +1,15 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/batch"
 )
 
 func TestParseCompletionResponse_WithContent(t *testing.T) {
@@ -199,3 +206,46 @@ func TestMessageSerialization(t *testing.T) {
 		t.Errorf("expected nil content, got %v", *parsed2.Content)
 	}
 }
+
+func TestChatWithTools_Google(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		io.WriteString(w, `{"candidates":[{"content":{"role":"model","parts":[
+			{"functionCall":{"id":"fc_1","name":"search_notes","args":{"query":"dragon"}}}
+		]}}]}`)
+	}))
+	defer srv.Close()
+
+	b := batch.NewServiceWithTransport(batch.Config{
+		Provider:      batch.ProviderGoogle,
+		GoogleAPIKey:  "key",
+		GoogleModel:   "gemini-test",
+		GoogleBaseURL: srv.URL,
+	}, batch.NewHTTPTransport(srv.Client()))
+
+	content := "Find the dragon"
+	tools := []ToolDefinition{{
+		Type: "function",
+		Function: ToolFunctionSchema{
+			Name:        "search_notes",
+			Description: "Search notes",
+			Parameters:  json.RawMessage(`{"type":"object","properties":{"query":{"type":"string"}}}`),
+		},
+	}}
+
+	result, err := NewService(b).ChatWithTools(context.Background(), []Message{{Role: "user", Content: &content}}, tools, "Be helpful")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(body), `"functionDeclarations"`) {
+		t.Errorf("expected function declarations in request, got %s", body)
+	}
+	if len(result.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(result.ToolCalls))
+	}
+	tc := result.ToolCalls[0]
+	if tc.ID != "fc_1" || tc.Function.Name != "search_notes" || tc.Function.Arguments != `{"query":"dragon"}` {
+		t.Errorf("unexpected tool call %+v", tc)
+	}
+}
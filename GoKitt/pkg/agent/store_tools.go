@@ -0,0 +1,274 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kittclouds/gokitt/internal/ids"
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/ontology"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+	"github.com/kittclouds/gokitt/pkg/reality/persist"
+)
+
+// maxNoteChars caps how much of a note get_note returns to the model.
+const maxNoteChars = 6000
+
+// StoreTools returns tools that read and edit the world through the store:
+// search_notes, get_note, list_entities, get_neighbors, create_entity and
+// create_edge. New edges are normalised with onto when it is non-nil.
+// search_notes is limited to the call's narrative (see WithNarrative).
+func StoreTools(s store.Storer, onto *ontology.Ontology) []Tool {
+	return []Tool{
+		NewTool("search_notes", "Full-text search over notes. Returns matching note IDs and titles.",
+			`{"type":"object","properties":{
+				"query":{"type":"string","description":"Search text"},
+				"limit":{"type":"integer","description":"Maximum results (default 10)"}},
+			"required":["query"]}`,
+			func(ctx context.Context, raw json.RawMessage) (string, error) {
+				var args struct {
+					Query string `json:"query"`
+					Limit int    `json:"limit"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", fmt.Errorf("search_notes: %w", err)
+				}
+				if args.Limit <= 0 {
+					args.Limit = 10
+				}
+				scope := &store.ScopeKey{NarrativeID: NarrativeFrom(ctx)}
+				notes, err := s.SearchNotes(scope, args.Query, args.Limit)
+				if err != nil {
+					return "", fmt.Errorf("search_notes: %w", err)
+				}
+				type hit struct {
+					ID    string `json:"id"`
+					Title string `json:"title"`
+				}
+				hits := make([]hit, 0, len(notes))
+				for _, n := range notes {
+					hits = append(hits, hit{ID: n.ID, Title: n.Title})
+				}
+				return toJSON(hits)
+			}),
+
+		NewTool("get_note", "Read a note's title and content by ID.",
+			`{"type":"object","properties":{"id":{"type":"string","description":"Note ID"}},"required":["id"]}`,
+			func(ctx context.Context, raw json.RawMessage) (string, error) {
+				var args struct {
+					ID string `json:"id"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", fmt.Errorf("get_note: %w", err)
+				}
+				note, err := s.GetNote(args.ID)
+				if err != nil {
+					return "", fmt.Errorf("get_note: %w", err)
+				}
+				if note == nil {
+					return "", fmt.Errorf("get_note: note %q not found", args.ID)
+				}
+				content := note.MarkdownContent
+				if content == "" {
+					content = note.Content
+				}
+				truncated := len(content) > maxNoteChars
+				if truncated {
+					cut := maxNoteChars
+					for cut > 0 && !utf8.RuneStart(content[cut]) {
+						cut--
+					}
+					content = content[:cut]
+				}
+				return toJSON(map[string]interface{}{
+					"id":        note.ID,
+					"title":     note.Title,
+					"content":   content,
+					"truncated": truncated,
+				})
+			}),
+
+		NewTool("list_entities", "List known entities, optionally of one kind (CHARACTER, LOCATION, ...).",
+			`{"type":"object","properties":{"kind":{"type":"string","description":"Entity kind filter"}}}`,
+			func(ctx context.Context, raw json.RawMessage) (string, error) {
+				var args struct {
+					Kind string `json:"kind"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", fmt.Errorf("list_entities: %w", err)
+				}
+				entities, err := s.ListEntities(strings.ToUpper(args.Kind))
+				if err != nil {
+					return "", fmt.Errorf("list_entities: %w", err)
+				}
+				type entity struct {
+					ID      string   `json:"id"`
+					Label   string   `json:"label"`
+					Kind    string   `json:"kind"`
+					Aliases []string `json:"aliases,omitempty"`
+				}
+				out := make([]entity, 0, len(entities))
+				for _, e := range entities {
+					out = append(out, entity{ID: e.ID, Label: e.Label, Kind: e.Kind, Aliases: e.Aliases})
+				}
+				return toJSON(out)
+			}),
+
+		NewTool("get_neighbors", "List an entity's relationships in the world graph.",
+			`{"type":"object","properties":{"entity":{"type":"string","description":"Entity ID or label"}},"required":["entity"]}`,
+			func(ctx context.Context, raw json.RawMessage) (string, error) {
+				var args struct {
+					Entity string `json:"entity"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", fmt.Errorf("get_neighbors: %w", err)
+				}
+				e, err := findEntity(s, args.Entity)
+				if err != nil {
+					return "", fmt.Errorf("get_neighbors: %w", err)
+				}
+				edges, err := s.ListEdgesForEntity(e.ID)
+				if err != nil {
+					return "", fmt.Errorf("get_neighbors: %w", err)
+				}
+				type neighbor struct {
+					ID         string  `json:"id"`
+					Label      string  `json:"label"`
+					Relation   string  `json:"relation"`
+					Direction  string  `json:"direction"` // "out" | "in"
+					Confidence float64 `json:"confidence"`
+				}
+				out := make([]neighbor, 0, len(edges))
+				for _, edge := range edges {
+					n := neighbor{ID: edge.TargetID, Relation: edge.RelType, Direction: "out", Confidence: edge.Confidence}
+					if edge.TargetID == e.ID {
+						n.ID, n.Direction = edge.SourceID, "in"
+					}
+					n.Label = n.ID
+					if other, err := s.GetEntity(n.ID); err == nil && other != nil {
+						n.Label = other.Label
+					}
+					out = append(out, n)
+				}
+				return toJSON(map[string]interface{}{"entity": e.Label, "neighbors": out})
+			}),
+
+		NewTool("create_entity", "Register a new entity in the current narrative. Fails if the label is already taken.",
+			`{"type":"object","properties":{
+				"label":{"type":"string"},
+				"kind":{"type":"string","description":"CHARACTER, LOCATION, ORGANIZATION, ITEM, ..."},
+				"aliases":{"type":"array","items":{"type":"string"}}},
+			"required":["label","kind"]}`,
+			func(ctx context.Context, raw json.RawMessage) (string, error) {
+				var args struct {
+					Label   string   `json:"label"`
+					Kind    string   `json:"kind"`
+					Aliases []string `json:"aliases"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", fmt.Errorf("create_entity: %w", err)
+				}
+				if strings.TrimSpace(args.Label) == "" {
+					return "", fmt.Errorf("create_entity: label required")
+				}
+				if existing, err := s.GetEntityByLabel(args.Label); err == nil && existing != nil {
+					return "", fmt.Errorf("create_entity: %q already exists as %s", args.Label, existing.ID)
+				}
+				now := time.Now().UnixMilli()
+				e := &store.Entity{
+					ID:          ids.New(),
+					Label:       strings.TrimSpace(args.Label),
+					Kind:        strings.ToUpper(args.Kind),
+					Aliases:     args.Aliases,
+					NarrativeID: NarrativeFrom(ctx),
+					CreatedBy:   "agent",
+					CreatedAt:   now,
+					UpdatedAt:   now,
+				}
+				if e.Aliases == nil {
+					e.Aliases = []string{}
+				}
+				if err := s.UpsertEntity(e); err != nil {
+					return "", fmt.Errorf("create_entity: %w", err)
+				}
+				return toJSON(map[string]string{"id": e.ID, "label": e.Label, "kind": e.Kind})
+			}),
+
+		NewTool("create_edge", "Record a relationship between two existing entities.",
+			`{"type":"object","properties":{
+				"source":{"type":"string","description":"Subject entity ID or label"},
+				"relation":{"type":"string","description":"e.g. ALLIES, PARENT_OF, LOCATED_IN"},
+				"target":{"type":"string","description":"Object entity ID or label"},
+				"confidence":{"type":"number","description":"0-1 (default 0.8)"}},
+			"required":["source","relation","target"]}`,
+			func(ctx context.Context, raw json.RawMessage) (string, error) {
+				var args struct {
+					Source     string  `json:"source"`
+					Relation   string  `json:"relation"`
+					Target     string  `json:"target"`
+					Confidence float64 `json:"confidence"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", fmt.Errorf("create_edge: %w", err)
+				}
+				src, err := findEntity(s, args.Source)
+				if err != nil {
+					return "", fmt.Errorf("create_edge: %w", err)
+				}
+				dst, err := findEntity(s, args.Target)
+				if err != nil {
+					return "", fmt.Errorf("create_edge: %w", err)
+				}
+				if args.Confidence <= 0 || args.Confidence > 1 {
+					args.Confidence = 0.8
+				}
+
+				sourceID, relation, targetID := src.ID, ontology.Key(args.Relation), dst.ID
+				if onto != nil {
+					sourceID, relation, targetID = onto.Normalize(sourceID, args.Relation, targetID)
+				}
+				if relation == "" {
+					return "", fmt.Errorf("create_edge: relation required")
+				}
+
+				// Merge into the stored edge for the same relation, if any, so
+				// its evidence and other support are kept
+				edge, err := persist.NewService(s).MergeLLMEdge(merger.LLMEdgeInput{
+					SourceID: sourceID, TargetID: targetID, RelType: relation, Confidence: args.Confidence,
+				})
+				if err != nil {
+					return "", fmt.Errorf("create_edge: %w", err)
+				}
+				return toJSON(map[string]string{
+					"id": edge.ID, "source": edge.SourceID, "relation": edge.RelType, "target": edge.TargetID,
+				})
+			}),
+	}
+}
+
+// findEntity resolves an entity by ID, then by label.
+func findEntity(s store.Storer, ref string) (*store.Entity, error) {
+	if e, err := s.GetEntity(ref); err == nil && e != nil {
+		return e, nil
+	}
+	e, err := s.GetEntityByLabel(ref)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, fmt.Errorf("entity %q not found", ref)
+	}
+	return e, nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,257 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+)
+
+// Chapter is one note's scan, placed in the narrative
+type Chapter struct {
+	NoteID string
+	Title  string
+	Order  float64 // Story position; chapters are analysed in this order
+	Scan   conductor.ScanResult
+}
+
+// WorldOptions controls world-level analytics
+type WorldOptions struct {
+	Entities     map[string]string // ID -> label to track; nil tracks every resolved entity
+	DormantAfter int               // Chapters without a mention before an entity is dormant (default 3)
+}
+
+// WorldReport holds analytics across every chapter of a world
+type WorldReport struct {
+	Chapters     []ChapterInfo     `json:"chapters"`
+	Presence     []Presence        `json:"presence"`
+	Interactions InteractionMatrix `json:"interactions"`
+	EventClasses []ChapterEvents   `json:"eventClasses"`
+	Arcs         []Arc             `json:"arcs"`
+	Dormant      []LastSeen        `json:"dormant"`
+}
+
+// ChapterInfo identifies a chapter; other report fields index into Chapters
+type ChapterInfo struct {
+	NoteID string  `json:"noteId"`
+	Title  string  `json:"title"`
+	Order  float64 `json:"order"`
+}
+
+// Presence is an entity's mention timeline
+type Presence struct {
+	EntityID string `json:"entityId"`
+	Label    string `json:"label"`
+	Mentions []int  `json:"mentions"` // Per chapter
+	Total    int    `json:"total"`
+	First    int    `json:"first"` // Chapter index
+	Last     int    `json:"last"`  // Chapter index
+}
+
+// InteractionMatrix counts factual narrative events between pairs of
+// entities, in either direction. Counts is symmetric and indexed like Entities.
+type InteractionMatrix struct {
+	Entities []string `json:"entities"`
+	Counts   [][]int  `json:"counts"`
+}
+
+// ChapterEvents is the event-class distribution of one chapter
+type ChapterEvents struct {
+	NoteID string         `json:"noteId"`
+	Counts map[string]int `json:"counts"`
+}
+
+// Arc is the sequence of events an entity takes part in
+type Arc struct {
+	EntityID string `json:"entityId"`
+	Label    string `json:"label"`
+	Beats    []Beat `json:"beats"`
+}
+
+// Beat is one event in an arc
+type Beat struct {
+	Chapter int    `json:"chapter"`
+	Event   string `json:"event"`
+	Role    string `json:"role"`            // "subject" | "object"
+	Other   string `json:"other,omitempty"` // The other participant
+	Factual bool   `json:"factual"`
+}
+
+// LastSeen reports a dormant entity: not mentioned for several chapters
+type LastSeen struct {
+	EntityID      string `json:"entityId"`
+	Label         string `json:"label"`
+	LastNote      string `json:"lastNote"`
+	LastChapter   int    `json:"lastChapter"`
+	ChaptersSince int    `json:"chaptersSince"`
+}
+
+const unknownEntity = "Unknown"
+
+// AnalyzeWorld computes presence, interactions, event classes, arcs and
+// dormancy across chapters. Chapters are sorted by Order (then note ID).
+func AnalyzeWorld(chapters []Chapter, opts WorldOptions) WorldReport {
+	if opts.DormantAfter <= 0 {
+		opts.DormantAfter = 3
+	}
+	chapters = append([]Chapter(nil), chapters...)
+	sort.SliceStable(chapters, func(i, j int) bool {
+		if chapters[i].Order != chapters[j].Order {
+			return chapters[i].Order < chapters[j].Order
+		}
+		return chapters[i].NoteID < chapters[j].NoteID
+	})
+
+	tracked := func(id string) bool {
+		if id == "" || id == unknownEntity {
+			return false
+		}
+		if opts.Entities == nil {
+			return true
+		}
+		_, ok := opts.Entities[id]
+		return ok
+	}
+	label := func(id string) string {
+		if l := opts.Entities[id]; l != "" {
+			return l
+		}
+		return id
+	}
+
+	report := WorldReport{
+		Chapters:     make([]ChapterInfo, len(chapters)),
+		EventClasses: make([]ChapterEvents, len(chapters)),
+		Dormant:      []LastSeen{},
+	}
+	presence := make(map[string]*Presence)
+	arcs := make(map[string]*Arc)
+	pairs := make(map[[2]string]int)
+
+	for i, ch := range chapters {
+		report.Chapters[i] = ChapterInfo{NoteID: ch.NoteID, Title: ch.Title, Order: ch.Order}
+
+		for _, ref := range ch.Scan.ResolvedRefs {
+			if !tracked(ref.EntityID) {
+				continue
+			}
+			p, ok := presence[ref.EntityID]
+			if !ok {
+				p = &Presence{EntityID: ref.EntityID, Label: label(ref.EntityID), Mentions: make([]int, len(chapters)), First: i}
+				presence[ref.EntityID] = p
+			}
+			p.Mentions[i]++
+			p.Total++
+			p.Last = i
+		}
+
+		counts := make(map[string]int)
+		for _, evt := range ch.Scan.Narrative {
+			class := evt.Event.String()
+			counts[class]++
+
+			subj, obj := evt.Subject, evt.Object
+			for _, part := range []struct{ id, role, other string }{{subj, "subject", obj}, {obj, "object", subj}} {
+				if !tracked(part.id) {
+					continue
+				}
+				other := part.other
+				if other == unknownEntity {
+					other = ""
+				}
+				arc, ok := arcs[part.id]
+				if !ok {
+					arc = &Arc{EntityID: part.id, Label: label(part.id)}
+					arcs[part.id] = arc
+				}
+				arc.Beats = append(arc.Beats, Beat{Chapter: i, Event: class, Role: part.role, Other: other, Factual: evt.IsFactual()})
+			}
+
+			if evt.IsFactual() && tracked(subj) && tracked(obj) && subj != obj {
+				pairs[orderedIDs(subj, obj)]++
+			}
+		}
+		report.EventClasses[i] = ChapterEvents{NoteID: ch.NoteID, Counts: counts}
+	}
+
+	report.Presence = sortedPresence(presence)
+	report.Interactions = interactionMatrix(pairs)
+	report.Arcs = sortedArcs(arcs)
+
+	for _, p := range report.Presence {
+		since := len(chapters) - 1 - p.Last
+		if since >= opts.DormantAfter {
+			report.Dormant = append(report.Dormant, LastSeen{
+				EntityID:      p.EntityID,
+				Label:         p.Label,
+				LastNote:      chapters[p.Last].NoteID,
+				LastChapter:   p.Last,
+				ChaptersSince: since,
+			})
+		}
+	}
+	sort.SliceStable(report.Dormant, func(i, j int) bool {
+		return report.Dormant[i].ChaptersSince > report.Dormant[j].ChaptersSince
+	})
+	return report
+}
+
+// sortedPresence orders timelines by total mentions, then ID
+func sortedPresence(m map[string]*Presence) []Presence {
+	out := make([]Presence, 0, len(m))
+	for _, p := range m {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].EntityID < out[j].EntityID
+	})
+	return out
+}
+
+func sortedArcs(m map[string]*Arc) []Arc {
+	out := make([]Arc, 0, len(m))
+	for _, a := range m {
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].EntityID < out[j].EntityID })
+	return out
+}
+
+// interactionMatrix lays pair counts out as a symmetric matrix over the
+// entities that interact, sorted by ID
+func interactionMatrix(pairs map[[2]string]int) InteractionMatrix {
+	index := make(map[string]int)
+	ids := []string{}
+	for p := range pairs {
+		for _, id := range p {
+			if _, ok := index[id]; !ok {
+				index[id] = 0
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	counts := make([][]int, len(ids))
+	for i := range counts {
+		counts[i] = make([]int, len(ids))
+	}
+	for p, n := range pairs {
+		a, b := index[p[0]], index[p[1]]
+		counts[a][b] += n
+		counts[b][a] += n
+	}
+	return InteractionMatrix{Entities: ids, Counts: counts}
+}
+
+func orderedIDs(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
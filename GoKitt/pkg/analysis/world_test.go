@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+)
+
+// chapter builds a scan with one mention per ref ID and the given events
+func chapter(noteID string, order float64, refs []string, events ...conductor.NarrativeEvent) Chapter {
+	scan := conductor.ScanResult{Narrative: events}
+	for _, id := range refs {
+		scan.ResolvedRefs = append(scan.ResolvedRefs, conductor.ResolvedReference{EntityID: id})
+	}
+	return Chapter{NoteID: noteID, Order: order, Scan: scan}
+}
+
+func event(class narrative.EventClass, subj, obj string) conductor.NarrativeEvent {
+	return conductor.NarrativeEvent{Event: class, Subject: subj, Object: obj}
+}
+
+func TestAnalyzeWorld(t *testing.T) {
+	hypothetical := event(narrative.EventBattle, "arin", "bram")
+	hypothetical.Modality = narrative.ModalityHypothetical
+
+	chapters := []Chapter{
+		// Out of order on purpose: analysed by Order
+		chapter("ch3", 3, []string{"arin"}, event(narrative.EventTravel, "arin", "Unknown")),
+		chapter("ch1", 1, []string{"arin", "bram", "lyra", "arin"},
+			event(narrative.EventMeet, "arin", "bram"),
+			event(narrative.EventBattle, "bram", "arin"),
+			hypothetical),
+		chapter("ch2", 2, []string{"arin", "lyra"}, event(narrative.EventRescue, "lyra", "arin")),
+		chapter("ch4", 4, []string{"arin"}),
+		chapter("ch5", 5, []string{"arin"}),
+	}
+
+	r := AnalyzeWorld(chapters, WorldOptions{
+		Entities:     map[string]string{"arin": "Arin", "bram": "Bram", "lyra": "Lyra"},
+		DormantAfter: 3,
+	})
+
+	if len(r.Chapters) != 5 || r.Chapters[0].NoteID != "ch1" || r.Chapters[4].NoteID != "ch5" {
+		t.Fatalf("Expected chapters in story order, got %+v", r.Chapters)
+	}
+
+	// Presence: arin leads, with per-chapter counts
+	arin := r.Presence[0]
+	if arin.EntityID != "arin" || arin.Label != "Arin" || arin.Total != 6 || arin.Mentions[0] != 2 || arin.Last != 4 {
+		t.Errorf("Unexpected arin presence %+v", arin)
+	}
+
+	// Interactions: two factual events between arin and bram, one with lyra
+	m := r.Interactions
+	idx := map[string]int{}
+	for i, id := range m.Entities {
+		idx[id] = i
+	}
+	if got := m.Counts[idx["arin"]][idx["bram"]]; got != 2 {
+		t.Errorf("Expected 2 arin-bram interactions, got %d", got)
+	}
+	if m.Counts[idx["bram"]][idx["arin"]] != 2 || m.Counts[idx["arin"]][idx["lyra"]] != 1 {
+		t.Errorf("Expected a symmetric matrix, got %+v", m)
+	}
+
+	// Event classes per chapter
+	if c := r.EventClasses[0].Counts; c["BATTLE"] != 2 || c["MEET"] != 1 {
+		t.Errorf("Unexpected chapter 1 events %v", c)
+	}
+
+	// Arcs: bram meets, fights, and the hypothetical battle is marked
+	var bram Arc
+	for _, a := range r.Arcs {
+		if a.EntityID == "bram" {
+			bram = a
+		}
+	}
+	if len(bram.Beats) != 3 || bram.Beats[0].Role != "object" || bram.Beats[1].Role != "subject" || bram.Beats[2].Factual {
+		t.Errorf("Unexpected bram arc %+v", bram)
+	}
+	for _, a := range r.Arcs {
+		for _, b := range a.Beats {
+			if b.Other == "Unknown" {
+				t.Errorf("Expected unknown participants to be blank, got %+v", b)
+			}
+		}
+	}
+
+	// Dormant: bram last seen in ch1 (4 chapters ago), lyra in ch2 (3 ago)
+	if len(r.Dormant) != 2 || r.Dormant[0].EntityID != "bram" || r.Dormant[0].ChaptersSince != 4 ||
+		r.Dormant[1].EntityID != "lyra" || r.Dormant[1].LastNote != "ch2" {
+		t.Errorf("Unexpected dormant report %+v", r.Dormant)
+	}
+}
@@ -0,0 +1,259 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// defaultAnthropicBaseURL is the Anthropic API root.
+	defaultAnthropicBaseURL = "https://api.anthropic.com/v1"
+	anthropicVersion        = "2023-06-01"
+)
+
+// anthropicRequest represents the request body for the Messages API.
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string           `json:"role"` // "user" | "assistant"
+	Content []anthropicBlock `json:"content"`
+}
+
+// anthropicBlock is a content block: text, tool_use or tool_result.
+type anthropicBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// anthropicResponse represents a Messages API response or error.
+type anthropicResponse struct {
+	Content []anthropicBlock `json:"content"`
+	Usage   struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// anthropicAPI speaks the Anthropic Messages API.
+type anthropicAPI struct{}
+
+func (anthropicAPI) configured(cfg Config) bool {
+	return cfg.AnthropicAPIKey != ""
+}
+
+func (anthropicAPI) model(cfg Config) string {
+	return cfg.AnthropicModel
+}
+
+// complete makes a non-streaming Messages API request.
+func (a anthropicAPI) complete(ctx context.Context, s *call, userPrompt, systemPrompt string) (string, error) {
+	req := anthropicRequest{
+		Model:  s.config.AnthropicModel,
+		System: systemPrompt,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: []anthropicBlock{{Type: "text", Text: userPrompt}}},
+		},
+		MaxTokens:   4096,
+		Temperature: 0.3,
+	}
+
+	resp, err := a.send(ctx, s, req)
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("batch: empty response from Anthropic")
+	}
+	return text.String(), nil
+}
+
+// completeWithTools translates OpenAI-shaped messages and tools to the
+// Messages API and the reply back: system messages become the system
+// prompt, tool calls become tool_use blocks and tool messages become
+// tool_result blocks.
+func (a anthropicAPI) completeWithTools(ctx context.Context, s *call, messages, tools interface{}) (string, error) {
+	msgs, schemas, err := decodeToolInput(messages, tools)
+	if err != nil {
+		return "", err
+	}
+
+	req := anthropicRequest{
+		Model:       s.config.AnthropicModel,
+		MaxTokens:   2048,
+		Temperature: 0.7,
+	}
+	var system []string
+	for _, m := range msgs {
+		content := ""
+		if m.Content != nil {
+			content = *m.Content
+		}
+
+		var role string
+		var blocks []anthropicBlock
+		switch m.Role {
+		case "system":
+			system = append(system, content)
+			continue
+		case "tool":
+			role = "user"
+			blocks = []anthropicBlock{{Type: "tool_result", ToolUseID: m.ToolCallID, Content: content}}
+		case "assistant":
+			role = "assistant"
+			if content != "" {
+				blocks = append(blocks, anthropicBlock{Type: "text", Text: content})
+			}
+			for _, call := range m.ToolCalls {
+				blocks = append(blocks, anthropicBlock{
+					Type:  "tool_use",
+					ID:    call.ID,
+					Name:  call.Function.Name,
+					Input: ToolArguments(call.Function.Arguments),
+				})
+			}
+		default:
+			role = "user"
+			blocks = []anthropicBlock{{Type: "text", Text: content}}
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		// Roles must alternate: consecutive turns (e.g. several tool
+		// results) share one message
+		if n := len(req.Messages); n > 0 && req.Messages[n-1].Role == role {
+			req.Messages[n-1].Content = append(req.Messages[n-1].Content, blocks...)
+		} else {
+			req.Messages = append(req.Messages, anthropicMessage{Role: role, Content: blocks})
+		}
+	}
+	req.System = strings.Join(system, "\n\n")
+	for _, t := range schemas {
+		schema := t.Function.Parameters
+		if len(schema) == 0 {
+			schema = json.RawMessage(`{"type":"object"}`)
+		}
+		req.Tools = append(req.Tools, anthropicTool{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			InputSchema: schema,
+		})
+	}
+
+	resp, err := a.send(ctx, s, req)
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	var calls []toolCall
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			calls = append(calls, toolCall{
+				ID:       block.ID,
+				Type:     "function",
+				Function: toolCallFunction{Name: block.Name, Arguments: string(ToolArguments(string(block.Input)))},
+			})
+		}
+	}
+	return toolCompletion(text.String(), calls, resp.Usage.InputTokens, resp.Usage.OutputTokens)
+}
+
+// listModels reads GET /models.
+func (anthropicAPI) listModels(ctx context.Context, s *call) ([]Model, error) {
+	response, err := s.getJSON(ctx, baseURL(s.config.AnthropicBaseURL, defaultAnthropicBaseURL)+"/models", anthropicHeaders(s.config))
+	if err != nil {
+		return nil, fmt.Errorf("batch: Anthropic model list failed: %w", err)
+	}
+	if !response.OK() {
+		return nil, fmt.Errorf("batch: Anthropic model list failed: HTTP %d: %s", response.Status, response.Body)
+	}
+
+	var list struct {
+		Data []struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(response.Body, &list); err != nil {
+		return nil, fmt.Errorf("batch: failed to parse Anthropic model list: %w", err)
+	}
+	models := make([]Model, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, Model{ID: m.ID, Name: m.DisplayName})
+	}
+	return models, nil
+}
+
+// send posts a Messages API request and checks for errors.
+func (anthropicAPI) send(ctx context.Context, s *call, req anthropicRequest) (*anthropicResponse, error) {
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("batch: failed to marshal Anthropic request: %w", err)
+	}
+
+	url := baseURL(s.config.AnthropicBaseURL, defaultAnthropicBaseURL) + "/messages"
+	response, err := s.postJSON(ctx, url, anthropicHeaders(s.config), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("batch: Anthropic API request failed: %w", err)
+	}
+
+	// Error statuses carry {"type":"error","error":{...}}
+	var resp anthropicResponse
+	if err := json.Unmarshal(response.Body, &resp); err != nil {
+		if !response.OK() {
+			return nil, fmt.Errorf("batch: Anthropic API request failed: HTTP %d: %s", response.Status, response.Body)
+		}
+		return nil, fmt.Errorf("batch: failed to parse Anthropic response: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("batch: Anthropic API error %s: %s", resp.Error.Type, resp.Error.Message)
+	}
+	if !response.OK() {
+		return nil, fmt.Errorf("batch: Anthropic API request failed: HTTP %d: %s", response.Status, response.Body)
+	}
+	return &resp, nil
+}
+
+func anthropicHeaders(cfg Config) map[string]string {
+	return map[string]string{
+		"x-api-key":         cfg.AnthropicAPIKey,
+		"anthropic-version": anthropicVersion,
+		// Required for calls made straight from the browser
+		"anthropic-dangerous-direct-browser-access": "true",
+	}
+}
@@ -1,20 +1,21 @@
-//go:build js && wasm
-// +build js,wasm
-
 package batch
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"syscall/js"
+	"strings"
 )
 
+// defaultGoogleBaseURL is the Google GenAI endpoint root.
+const defaultGoogleBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
 // googleRequest represents the request body for Google GenAI API.
 type googleRequest struct {
 	Contents          []googleContent         `json:"contents"`
 	SystemInstruction *googleContent          `json:"systemInstruction,omitempty"`
 	GenerationConfig  *googleGenerationConfig `json:"generationConfig,omitempty"`
+	Tools             []googleTool            `json:"tools,omitempty"`
 }
 
 type googleContent struct {
@@ -22,8 +23,33 @@ type googleContent struct {
 	Parts []googlePart `json:"parts"`
 }
 
+// googlePart holds one of text, a function call or a function response.
 type googlePart struct {
-	Text string `json:"text"`
+	Text             string                  `json:"text,omitempty"`
+	FunctionCall     *googleFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *googleFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type googleFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type googleFunctionResponse struct {
+	ID       string          `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"` // Must be a JSON object
+}
+
+type googleTool struct {
+	FunctionDeclarations []googleFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type googleFunctionDeclaration struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
 }
 
 type googleGenerationConfig struct {
@@ -35,11 +61,13 @@ type googleGenerationConfig struct {
 type googleResponse struct {
 	Candidates []struct {
 		Content struct {
-			Parts []struct {
-				Text string `json:"text"`
-			} `json:"parts"`
+			Parts []googlePart `json:"parts"`
 		} `json:"content"`
 	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
 	Error *struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
@@ -47,14 +75,28 @@ type googleResponse struct {
 	} `json:"error,omitempty"`
 }
 
-// callGoogle makes a non-streaming request to Google GenAI API.
-func (s *Service) callGoogle(_ context.Context, userPrompt, systemPrompt string) (string, error) {
-	url := fmt.Sprintf(
-		"https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s",
-		s.config.GoogleModel,
-		s.config.GoogleAPIKey,
-	)
+// googleModelList is the GET /models response.
+type googleModelList struct {
+	Models []struct {
+		Name                       string   `json:"name"` // "models/<id>"
+		DisplayName                string   `json:"displayName"`
+		SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+	} `json:"models"`
+}
+
+// googleAPI speaks the Google GenAI generateContent API.
+type googleAPI struct{}
+
+func (googleAPI) configured(cfg Config) bool {
+	return cfg.GoogleAPIKey != ""
+}
+
+func (googleAPI) model(cfg Config) string {
+	return cfg.GoogleModel
+}
 
+// complete makes a non-streaming request to Google GenAI API.
+func (g googleAPI) complete(ctx context.Context, s *call, userPrompt, systemPrompt string) (string, error) {
 	// Build request body
 	req := googleRequest{
 		Contents: []googleContent{
@@ -76,99 +118,271 @@ func (s *Service) callGoogle(_ context.Context, userPrompt, systemPrompt string)
 		}
 	}
 
-	reqBody, err := json.Marshal(req)
+	resp, err := g.send(ctx, s, req)
 	if err != nil {
-		return "", fmt.Errorf("batch: failed to marshal Google request: %w", err)
+		return "", err
+	}
+
+	// Extract text from response
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("batch: empty response from Google")
 	}
 
-	// Use browser fetch via syscall/js
-	response, err := s.jsFetch(url, string(reqBody))
+	text := resp.Candidates[0].Content.Parts[0].Text
+	return text, nil
+}
+
+// completeWithTools maps OpenAI-shaped messages and tools onto Gemini
+// function calling and the reply back: system messages become the system
+// instruction, tool calls become functionCall parts, tool messages become
+// functionResponse parts and declarations carry the tool schemas.
+func (g googleAPI) completeWithTools(ctx context.Context, s *call, messages, tools interface{}) (string, error) {
+	msgs, schemas, err := decodeToolInput(messages, tools)
 	if err != nil {
-		return "", fmt.Errorf("batch: Google API request failed: %w", err)
+		return "", err
 	}
 
-	// Parse response
-	var resp googleResponse
-	if err := json.Unmarshal([]byte(response), &resp); err != nil {
-		return "", fmt.Errorf("batch: failed to parse Google response: %w", err)
+	req := googleRequest{
+		GenerationConfig: &googleGenerationConfig{
+			Temperature:     0.7,
+			MaxOutputTokens: 2048,
+		},
 	}
 
-	// Check for API error
-	if resp.Error != nil {
-		return "", fmt.Errorf("batch: Google API error %d: %s", resp.Error.Code, resp.Error.Message)
+	// Function responses name the function; tool messages only carry the call ID
+	callNames := make(map[string]string)
+	var system []string
+	for _, m := range msgs {
+		content := ""
+		if m.Content != nil {
+			content = *m.Content
+		}
+
+		var role string
+		var parts []googlePart
+		switch m.Role {
+		case "system":
+			system = append(system, content)
+			continue
+		case "tool":
+			role = "user"
+			parts = []googlePart{{FunctionResponse: &googleFunctionResponse{
+				ID:       m.ToolCallID,
+				Name:     callNames[m.ToolCallID],
+				Response: functionResponse(content),
+			}}}
+		case "assistant":
+			role = "model"
+			if content != "" {
+				parts = append(parts, googlePart{Text: content})
+			}
+			for _, call := range m.ToolCalls {
+				callNames[call.ID] = call.Function.Name
+				parts = append(parts, googlePart{FunctionCall: &googleFunctionCall{
+					ID:   call.ID,
+					Name: call.Function.Name,
+					Args: ToolArguments(call.Function.Arguments),
+				}})
+			}
+		default:
+			role = "user"
+			parts = []googlePart{{Text: content}}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+
+		// Consecutive turns (e.g. several function responses) share one content
+		if n := len(req.Contents); n > 0 && req.Contents[n-1].Role == role {
+			req.Contents[n-1].Parts = append(req.Contents[n-1].Parts, parts...)
+		} else {
+			req.Contents = append(req.Contents, googleContent{Role: role, Parts: parts})
+		}
+	}
+	if len(system) > 0 {
+		req.SystemInstruction = &googleContent{
+			Parts: []googlePart{{Text: strings.Join(system, "\n\n")}},
+		}
+	}
+	if len(schemas) > 0 {
+		decls := make([]googleFunctionDeclaration, 0, len(schemas))
+		for _, t := range schemas {
+			decls = append(decls, googleFunctionDeclaration{
+				Name:        t.Function.Name,
+				Description: t.Function.Description,
+				Parameters:  geminiSchema(t.Function.Parameters),
+			})
+		}
+		req.Tools = []googleTool{{FunctionDeclarations: decls}}
 	}
 
-	// Extract text from response
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+	resp, err := g.send(ctx, s, req)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Candidates) == 0 {
 		return "", fmt.Errorf("batch: empty response from Google")
 	}
 
-	text := resp.Candidates[0].Content.Parts[0].Text
-	return text, nil
+	var text strings.Builder
+	var calls []toolCall
+	for i, part := range resp.Candidates[0].Content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			// Older models leave the call ID out
+			id := part.FunctionCall.ID
+			if id == "" {
+				id = fmt.Sprintf("call_%d_%s", i, part.FunctionCall.Name)
+			}
+			calls = append(calls, toolCall{
+				ID:   id,
+				Type: "function",
+				Function: toolCallFunction{
+					Name:      part.FunctionCall.Name,
+					Arguments: string(ToolArguments(string(part.FunctionCall.Args))),
+				},
+			})
+		case part.Text != "":
+			text.WriteString(part.Text)
+		}
+	}
+	return toolCompletion(text.String(), calls, resp.UsageMetadata.PromptTokenCount, resp.UsageMetadata.CandidatesTokenCount)
 }
 
-// jsFetch performs a fetch request using the browser's fetch API.
-func (s *Service) jsFetch(url, body string) (string, error) {
-	// Get fetch function from global scope
-	fetch := js.Global().Get("fetch")
-	if fetch.IsUndefined() {
-		return "", fmt.Errorf("batch: fetch not available")
+// send posts a generateContent request and checks for errors.
+func (googleAPI) send(ctx context.Context, s *call, req googleRequest) (*googleResponse, error) {
+	url := fmt.Sprintf(
+		"%s/models/%s:generateContent",
+		baseURL(s.config.GoogleBaseURL, defaultGoogleBaseURL),
+		s.config.GoogleModel,
+	)
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("batch: failed to marshal Google request: %w", err)
 	}
 
-	// Create headers object
-	headers := js.Global().Get("Object").New()
-	headers.Set("Content-Type", "application/json")
+	response, err := s.postJSON(ctx, url, googleHeaders(s.config), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("batch: Google API request failed: %w", err)
+	}
 
-	// Create options object
-	options := js.Global().Get("Object").New()
-	options.Set("method", "POST")
-	options.Set("headers", headers)
-	options.Set("body", body)
+	// Parse response; error statuses carry an error object
+	var resp googleResponse
+	if err := json.Unmarshal(response.Body, &resp); err != nil {
+		if !response.OK() {
+			return nil, fmt.Errorf("batch: Google API request failed: HTTP %d: %s", response.Status, response.Body)
+		}
+		return nil, fmt.Errorf("batch: failed to parse Google response: %w", err)
+	}
 
-	// Call fetch
-	promise := fetch.Invoke(url, options)
+	// Check for API error
+	if resp.Error != nil {
+		return nil, fmt.Errorf("batch: Google API error %d: %s", resp.Error.Code, resp.Error.Message)
+	}
+	if !response.OK() {
+		return nil, fmt.Errorf("batch: Google API request failed: HTTP %d: %s", response.Status, response.Body)
+	}
+	return &resp, nil
+}
 
-	// Wait for response using a channel
-	resultCh := make(chan struct {
-		response string
-		err      error
-	})
+// functionResponse wraps a tool result as the JSON object Gemini expects:
+// objects pass through, anything else goes under "content".
+func functionResponse(content string) json.RawMessage {
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed)
+	}
+	wrapped, _ := json.Marshal(map[string]string{"content": content})
+	return wrapped
+}
 
-	// Set up promise handlers
-	then := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		response := args[0]
+// geminiSchema adapts a JSON Schema to the OpenAPI subset Gemini accepts:
+// keywords it rejects are dropped, and objects without properties are
+// left out entirely.
+func geminiSchema(schema json.RawMessage) json.RawMessage {
+	if len(schema) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(schema, &v); err != nil {
+		return nil
+	}
+	root, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if props, _ := root["properties"].(map[string]interface{}); len(props) == 0 {
+		return nil
+	}
+	out, err := json.Marshal(stripSchema(root))
+	if err != nil {
+		return nil
+	}
+	return out
+}
 
-		// Get response text
-		textPromise := response.Call("text")
+// unsupportedSchemaKeys are JSON Schema keywords Gemini rejects.
+var unsupportedSchemaKeys = []string{"$schema", "$id", "$ref", "$defs", "additionalProperties", "default", "examples"}
 
-		textThen := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-			text := args[0].String()
-			resultCh <- struct {
-				response string
-				err      error
-			}{response: text, err: nil}
-			return nil
-		})
-		defer textThen.Release()
+func stripSchema(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, k := range unsupportedSchemaKeys {
+			delete(t, k)
+		}
+		for k, child := range t {
+			// Property names are data, not keywords
+			if k == "properties" {
+				if props, ok := child.(map[string]interface{}); ok {
+					for name, p := range props {
+						props[name] = stripSchema(p)
+					}
+					continue
+				}
+			}
+			t[k] = stripSchema(child)
+		}
+		return t
+	case []interface{}:
+		for i := range t {
+			t[i] = stripSchema(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
 
-		textPromise.Call("then", textThen)
-		return nil
-	})
-	defer then.Release()
-
-	catch := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		errMsg := args[0].Get("message").String()
-		resultCh <- struct {
-			response string
-			err      error
-		}{response: "", err: fmt.Errorf("%s", errMsg)}
-		return nil
-	})
-	defer catch.Release()
+// googleHeaders carries the API key. A ?key= query parameter would end up
+// in transport error messages, and from there in logs and llm_usage.
+func googleHeaders(cfg Config) map[string]string {
+	return map[string]string{"x-goog-api-key": cfg.GoogleAPIKey}
+}
 
-	promise.Call("then", then).Call("catch", catch)
+// listModels reads GET /models, keeping models that support generateContent.
+func (googleAPI) listModels(ctx context.Context, s *call) ([]Model, error) {
+	url := baseURL(s.config.GoogleBaseURL, defaultGoogleBaseURL) + "/models"
+	response, err := s.getJSON(ctx, url, googleHeaders(s.config))
+	if err != nil {
+		return nil, fmt.Errorf("batch: Google model list failed: %w", err)
+	}
+	if !response.OK() {
+		return nil, fmt.Errorf("batch: Google model list failed: HTTP %d: %s", response.Status, response.Body)
+	}
 
-	// Wait for result
-	result := <-resultCh
-	return result.response, result.err
+	var list googleModelList
+	if err := json.Unmarshal(response.Body, &list); err != nil {
+		return nil, fmt.Errorf("batch: failed to parse Google model list: %w", err)
+	}
+	models := make([]Model, 0, len(list.Models))
+	for _, m := range list.Models {
+		for _, method := range m.SupportedGenerationMethods {
+			if method == "generateContent" {
+				models = append(models, Model{ID: strings.TrimPrefix(m.Name, "models/"), Name: m.DisplayName})
+				break
+			}
+		}
+	}
+	return models, nil
 }
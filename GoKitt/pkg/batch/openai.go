@@ -0,0 +1,234 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// defaultOpenRouterBaseURL is the OpenRouter API root.
+const defaultOpenRouterBaseURL = "https://openrouter.ai/api/v1"
+
+// chatRequest represents the request body for the OpenAI chat completions API.
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+	MaxTokens   int           `json:"max_tokens"`
+	Stream      bool          `json:"stream"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatResponse represents a chat completion response.
+type chatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error,omitempty"`
+}
+
+// modelList is the OpenAI GET /models response.
+type modelList struct {
+	Data []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"data"`
+}
+
+// chatEndpoint is where and how to reach a chat completions server.
+type chatEndpoint struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	Headers map[string]string
+}
+
+// openAICompat speaks the OpenAI chat completions API. OpenRouter and local
+// servers (Ollama, llama.cpp server, LM Studio) share it and differ only in
+// endpoint, credentials and extra headers.
+type openAICompat struct {
+	label    string // Provider name in errors
+	keyless  bool   // Local servers usually run without an API key
+	endpoint func(cfg Config) chatEndpoint
+}
+
+var openRouterAPI = openAICompat{
+	label: "OpenRouter",
+	endpoint: func(cfg Config) chatEndpoint {
+		// Attribution headers OpenRouter asks for
+		headers := map[string]string{"X-Title": "KittClouds"}
+		if origin := appOrigin(); origin != "" {
+			headers["HTTP-Referer"] = origin
+		}
+		return chatEndpoint{
+			BaseURL: baseURL(cfg.OpenRouterBaseURL, defaultOpenRouterBaseURL),
+			APIKey:  cfg.OpenRouterAPIKey,
+			Model:   cfg.OpenRouterModel,
+			Headers: headers,
+		}
+	},
+}
+
+var openAICompatAPI = openAICompat{
+	label:   "OpenAI-compatible",
+	keyless: true,
+	endpoint: func(cfg Config) chatEndpoint {
+		return chatEndpoint{
+			BaseURL: baseURL(cfg.OpenAIBaseURL, ""),
+			APIKey:  cfg.OpenAIAPIKey,
+			Model:   cfg.OpenAIModel,
+		}
+	},
+}
+
+func (p openAICompat) configured(cfg Config) bool {
+	ep := p.endpoint(cfg)
+	return ep.BaseURL != "" && (p.keyless || ep.APIKey != "")
+}
+
+func (p openAICompat) model(cfg Config) string {
+	return p.endpoint(cfg).Model
+}
+
+// complete makes a non-streaming chat completion request.
+func (p openAICompat) complete(ctx context.Context, s *call, userPrompt, systemPrompt string) (string, error) {
+	ep := p.endpoint(s.config)
+
+	// Build messages
+	messages := make([]chatMessage, 0, 2)
+	if systemPrompt != "" {
+		messages = append(messages, chatMessage{
+			Role:    "system",
+			Content: systemPrompt,
+		})
+	}
+	messages = append(messages, chatMessage{
+		Role:    "user",
+		Content: userPrompt,
+	})
+
+	// Build request body
+	req := chatRequest{
+		Model:       ep.Model,
+		Messages:    messages,
+		Temperature: 0.3,
+		MaxTokens:   4096,
+		Stream:      false, // EXPLICITLY NO STREAMING
+	}
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("batch: failed to marshal %s request: %w", p.label, err)
+	}
+
+	response, err := p.post(ctx, s, ep, reqBody)
+	if err != nil {
+		return "", fmt.Errorf("batch: %s API request failed: %w", p.label, err)
+	}
+
+	// Parse response
+	var resp chatResponse
+	if err := json.Unmarshal([]byte(response), &resp); err != nil {
+		return "", fmt.Errorf("batch: failed to parse %s response: %w", p.label, err)
+	}
+
+	// Check for API error
+	if resp.Error != nil {
+		return "", fmt.Errorf("batch: %s API error %d: %s", p.label, resp.Error.Code, resp.Error.Message)
+	}
+
+	// Extract text from response
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("batch: empty response from %s", p.label)
+	}
+
+	text := resp.Choices[0].Message.Content
+	if text == "" {
+		return "", fmt.Errorf("batch: empty content in %s response", p.label)
+	}
+
+	return text, nil
+}
+
+// completeWithTools passes messages and tools through unchanged; the raw
+// response is already in the shape callers expect.
+func (p openAICompat) completeWithTools(ctx context.Context, s *call, messages, tools interface{}) (string, error) {
+	ep := p.endpoint(s.config)
+
+	reqMap := map[string]interface{}{
+		"model":       ep.Model,
+		"messages":    messages,
+		"temperature": 0.7,
+		"max_tokens":  2048,
+		"stream":      false,
+	}
+	if tools != nil {
+		reqMap["tools"] = tools
+	}
+
+	reqBody, err := json.Marshal(reqMap)
+	if err != nil {
+		return "", fmt.Errorf("batch: failed to marshal tool request: %w", err)
+	}
+
+	raw, err := p.post(ctx, s, ep, reqBody)
+	if err != nil {
+		return "", fmt.Errorf("batch: %s tool API request failed: %w", p.label, err)
+	}
+	return raw, nil
+}
+
+// listModels reads GET /models.
+func (p openAICompat) listModels(ctx context.Context, s *call) ([]Model, error) {
+	ep := p.endpoint(s.config)
+	response, err := s.getJSON(ctx, ep.BaseURL+"/models", p.headers(ep))
+	if err != nil {
+		return nil, fmt.Errorf("batch: %s model list failed: %w", p.label, err)
+	}
+	if !response.OK() {
+		return nil, fmt.Errorf("batch: %s model list failed: HTTP %d: %s", p.label, response.Status, response.Body)
+	}
+
+	var list modelList
+	if err := json.Unmarshal(response.Body, &list); err != nil {
+		return nil, fmt.Errorf("batch: failed to parse %s model list: %w", p.label, err)
+	}
+	models := make([]Model, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, Model{ID: m.ID, Name: m.Name})
+	}
+	return models, nil
+}
+
+// post sends a chat completion request. Non-2xx statuses are errors.
+func (p openAICompat) post(ctx context.Context, s *call, ep chatEndpoint, body []byte) (string, error) {
+	response, err := s.postJSON(ctx, ep.BaseURL+"/chat/completions", p.headers(ep), body)
+	if err != nil {
+		return "", err
+	}
+	if !response.OK() {
+		return "", fmt.Errorf("HTTP %d: %s", response.Status, response.Body)
+	}
+	return string(response.Body), nil
+}
+
+// headers adds Bearer auth, when there is a key, to the endpoint's headers.
+func (p openAICompat) headers(ep chatEndpoint) map[string]string {
+	headers := make(map[string]string, len(ep.Headers)+1)
+	for k, v := range ep.Headers {
+		headers[k] = v
+	}
+	if ep.APIKey != "" {
+		headers["Authorization"] = fmt.Sprintf("Bearer %s", ep.APIKey)
+	}
+	return headers
+}
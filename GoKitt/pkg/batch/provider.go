@@ -0,0 +1,43 @@
+package batch
+
+import (
+	"context"
+	"sort"
+)
+
+// Model is one model a provider offers.
+type Model struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// providerAPI maps batch requests onto one provider's HTTP API.
+//
+// completeWithTools takes OpenAI-shaped messages and tool schemas and returns
+// an OpenAI-shaped chat completion, whatever the provider speaks natively,
+// so callers parse a single format.
+type providerAPI interface {
+	configured(cfg Config) bool
+	model(cfg Config) string
+	complete(ctx context.Context, s *call, userPrompt, systemPrompt string) (string, error)
+	completeWithTools(ctx context.Context, s *call, messages, tools interface{}) (string, error)
+	listModels(ctx context.Context, s *call) ([]Model, error)
+}
+
+// providers is the registry of supported providers.
+var providers = map[Provider]providerAPI{
+	ProviderGoogle:     googleAPI{},
+	ProviderOpenRouter: openRouterAPI,
+	ProviderOpenAI:     openAICompatAPI,
+	ProviderAnthropic:  anthropicAPI{},
+}
+
+// Providers lists the registered providers.
+func Providers() []Provider {
+	out := make([]Provider, 0, len(providers))
+	for p := range providers {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
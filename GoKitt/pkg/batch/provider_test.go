@@ -0,0 +1,372 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// recorder is a mock endpoint that returns a canned body and keeps the last
+// request
+type recorder struct {
+	req  *http.Request
+	body []byte
+}
+
+func serve(t *testing.T, routes map[string]string) (*httptest.Server, *recorder) {
+	t.Helper()
+	rec := &recorder{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		rec.req = r
+		rec.body, _ = io.ReadAll(r.Body)
+		io.WriteString(w, reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, rec
+}
+
+func TestProvidersRegistry(t *testing.T) {
+	got := Providers()
+	want := []Provider{ProviderAnthropic, ProviderGoogle, ProviderOpenAI, ProviderOpenRouter}
+	if len(got) != len(want) {
+		t.Fatalf("Providers() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Providers()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if NewService(Config{Provider: "nope"}).IsConfigured() {
+		t.Error("unknown provider reported as configured")
+	}
+	if _, err := NewService(Config{Provider: "nope"}).Complete(context.Background(), "x", ""); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
+
+func TestOpenAICompatibleLocalServer(t *testing.T) {
+	srv, rec := serve(t, map[string]string{
+		"POST /v1/chat/completions": `{"choices":[{"message":{"content":"local reply"}}]}`,
+		"GET /v1/models":            `{"object":"list","data":[{"id":"llama3.2"},{"id":"qwen2.5"}]}`,
+	})
+	svc := NewServiceWithTransport(Config{
+		Provider:      ProviderOpenAI,
+		OpenAIBaseURL: srv.URL + "/v1/",
+		OpenAIModel:   "llama3.2",
+	}, NewHTTPTransport(srv.Client()))
+
+	// No API key needed for a local server
+	if !svc.IsConfigured() {
+		t.Fatal("keyless OpenAI-compatible server should be configured")
+	}
+	if got := svc.GetCurrentModel(); got != "llama3.2" {
+		t.Errorf("model = %q", got)
+	}
+
+	text, err := svc.Complete(context.Background(), "hello", "")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if text != "local reply" {
+		t.Errorf("text = %q", text)
+	}
+	if got := rec.req.Header.Get("Authorization"); got != "" {
+		t.Errorf("unexpected Authorization %q without a key", got)
+	}
+
+	models, err := svc.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if len(models) != 2 || models[0].ID != "llama3.2" {
+		t.Errorf("models = %+v", models)
+	}
+
+	if (&Service{config: Config{Provider: ProviderOpenAI}}).IsConfigured() {
+		t.Error("OpenAI-compatible provider without a base URL should not be configured")
+	}
+}
+
+func TestAnthropicComplete(t *testing.T) {
+	srv, rec := serve(t, map[string]string{
+		"POST /v1/messages": `{"content":[{"type":"text","text":"Bonjour"}],"usage":{"input_tokens":5,"output_tokens":1}}`,
+		"GET /v1/models":    `{"data":[{"id":"claude-test","display_name":"Claude Test"}]}`,
+	})
+	svc := NewServiceWithTransport(Config{
+		Provider:         ProviderAnthropic,
+		AnthropicAPIKey:  "a-key",
+		AnthropicModel:   "claude-test",
+		AnthropicBaseURL: srv.URL + "/v1",
+	}, NewHTTPTransport(srv.Client()))
+
+	text, err := svc.Complete(context.Background(), "Say hello in French", "Be brief")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if text != "Bonjour" {
+		t.Errorf("text = %q", text)
+	}
+	if got := rec.req.Header.Get("x-api-key"); got != "a-key" {
+		t.Errorf("x-api-key = %q", got)
+	}
+	if got := rec.req.Header.Get("anthropic-version"); got != anthropicVersion {
+		t.Errorf("anthropic-version = %q", got)
+	}
+
+	var req anthropicRequest
+	if err := json.Unmarshal(rec.body, &req); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if req.System != "Be brief" || req.Model != "claude-test" || req.MaxTokens == 0 {
+		t.Errorf("request = %+v", req)
+	}
+
+	models, err := svc.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if len(models) != 1 || models[0].Name != "Claude Test" {
+		t.Errorf("models = %+v", models)
+	}
+}
+
+func TestAnthropicToolCalling(t *testing.T) {
+	srv, rec := serve(t, map[string]string{
+		"POST /v1/messages": `{"content":[
+			{"type":"text","text":"Let me look."},
+			{"type":"tool_use","id":"toolu_2","name":"get_note","input":{"id":"n1"}}
+		]}`,
+	})
+	svc := NewServiceWithTransport(Config{
+		Provider:         ProviderAnthropic,
+		AnthropicAPIKey:  "a-key",
+		AnthropicBaseURL: srv.URL + "/v1",
+	}, NewHTTPTransport(srv.Client()))
+
+	str := func(s string) *string { return &s }
+	messages := []toolMessage{
+		{Role: "system", Content: str("You are helpful")},
+		{Role: "user", Content: str("Find dragons")},
+		{Role: "assistant", ToolCalls: []toolCall{{ID: "toolu_1", Type: "function", Function: toolCallFunction{Name: "search", Arguments: `{"q":"dragon"}`}}}},
+		{Role: "tool", ToolCallID: "toolu_1", Content: str("n1: Smaug")},
+	}
+	tools := json.RawMessage(`[{"type":"function","function":{"name":"search","description":"Search notes","parameters":{"type":"object"}}}]`)
+
+	raw, err := svc.CompleteWithTools(context.Background(), messages, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools: %v", err)
+	}
+
+	// Request: system lifted out, tool call and result as blocks
+	var req anthropicRequest
+	if err := json.Unmarshal(rec.body, &req); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if req.System != "You are helpful" {
+		t.Errorf("system = %q", req.System)
+	}
+	if len(req.Messages) != 3 {
+		t.Fatalf("messages = %+v", req.Messages)
+	}
+	if b := req.Messages[1].Content[0]; b.Type != "tool_use" || b.Name != "search" || string(b.Input) != `{"q":"dragon"}` {
+		t.Errorf("tool_use block = %+v", b)
+	}
+	if b := req.Messages[2].Content[0]; req.Messages[2].Role != "user" || b.Type != "tool_result" || b.ToolUseID != "toolu_1" {
+		t.Errorf("tool_result block = %+v", b)
+	}
+	if len(req.Tools) != 1 || req.Tools[0].Name != "search" || string(req.Tools[0].InputSchema) != `{"type":"object"}` {
+		t.Errorf("tools = %+v", req.Tools)
+	}
+
+	// Response: OpenAI shape
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content   *string    `json:"content"`
+				ToolCalls []toolCall `json:"tool_calls"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatalf("response: %v", err)
+	}
+	msg := resp.Choices[0].Message
+	if msg.Content == nil || *msg.Content != "Let me look." {
+		t.Errorf("content = %v", msg.Content)
+	}
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Function.Name != "get_note" || msg.ToolCalls[0].Function.Arguments != `{"id":"n1"}` {
+		t.Errorf("tool_calls = %+v", msg.ToolCalls)
+	}
+	if resp.Choices[0].FinishReason != "tool_calls" {
+		t.Errorf("finish_reason = %q", resp.Choices[0].FinishReason)
+	}
+}
+
+func TestGoogleListModels(t *testing.T) {
+	srv, rec := serve(t, map[string]string{
+		"GET /models": `{"models":[
+			{"name":"models/gemini-test","displayName":"Gemini Test","supportedGenerationMethods":["generateContent"]},
+			{"name":"models/embed-test","supportedGenerationMethods":["embedContent"]}
+		]}`,
+	})
+	svc := NewServiceWithTransport(Config{
+		Provider:      ProviderGoogle,
+		GoogleAPIKey:  "g-key",
+		GoogleBaseURL: srv.URL,
+	}, NewHTTPTransport(srv.Client()))
+
+	models, err := svc.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != "gemini-test" {
+		t.Errorf("models = %+v", models)
+	}
+	if got := rec.req.Header.Get("x-goog-api-key"); got != "g-key" {
+		t.Errorf("key header = %q", got)
+	}
+	if rec.req.URL.RawQuery != "" {
+		t.Errorf("key leaked into the URL: %q", rec.req.URL.RawQuery)
+	}
+}
+
+func TestGoogleErrorsOmitKey(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close() // Connection refused: the transport error quotes the URL
+
+	svc := NewServiceWithTransport(Config{
+		Provider:      ProviderGoogle,
+		GoogleAPIKey:  "g-secret",
+		GoogleModel:   "gemini-test",
+		GoogleBaseURL: srv.URL,
+		MaxRetries:    -1,
+	}, NewHTTPTransport(http.DefaultClient))
+
+	_, err := svc.Complete(context.Background(), "hi", "")
+	if err == nil {
+		t.Fatal("expected a connection error")
+	}
+	if strings.Contains(err.Error(), "g-secret") {
+		t.Errorf("error leaks the API key: %v", err)
+	}
+}
+
+func TestGoogleToolCalling(t *testing.T) {
+	srv, rec := serve(t, map[string]string{
+		"POST /models/gemini-test:generateContent": `{"candidates":[{"content":{"role":"model","parts":[
+			{"functionCall":{"name":"get_note","args":{"id":"n1"}}},
+			{"functionCall":{"name":"list_entities","args":{}}}
+		]}}],"usageMetadata":{"promptTokenCount":40,"candidatesTokenCount":8}}`,
+	})
+	svc := NewServiceWithTransport(Config{
+		Provider:      ProviderGoogle,
+		GoogleAPIKey:  "g-key",
+		GoogleModel:   "gemini-test",
+		GoogleBaseURL: srv.URL,
+	}, NewHTTPTransport(srv.Client()))
+
+	str := func(s string) *string { return &s }
+	messages := []toolMessage{
+		{Role: "system", Content: str("You are helpful")},
+		{Role: "user", Content: str("Find dragons")},
+		{Role: "assistant", ToolCalls: []toolCall{
+			{ID: "call_a", Type: "function", Function: toolCallFunction{Name: "search", Arguments: `{"q":"dragon"}`}},
+			{ID: "call_b", Type: "function", Function: toolCallFunction{Name: "count", Arguments: ``}},
+		}},
+		{Role: "tool", ToolCallID: "call_a", Content: str("n1: Smaug")},
+		{Role: "tool", ToolCallID: "call_b", Content: str(`{"count":1}`)},
+	}
+	tools := json.RawMessage(`[
+		{"type":"function","function":{"name":"search","description":"Search notes","parameters":{
+			"$schema":"http://json-schema.org/draft-07/schema#","type":"object","additionalProperties":false,
+			"properties":{"q":{"type":"string","default":"x"},"default":{"type":"string"}},"required":["q"]}}},
+		{"type":"function","function":{"name":"count","description":"Count notes","parameters":{"type":"object","properties":{}}}}
+	]`)
+
+	raw, err := svc.CompleteWithTools(context.Background(), messages, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools: %v", err)
+	}
+
+	// Request
+	var req googleRequest
+	if err := json.Unmarshal(rec.body, &req); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if req.SystemInstruction == nil || req.SystemInstruction.Parts[0].Text != "You are helpful" {
+		t.Errorf("systemInstruction = %+v", req.SystemInstruction)
+	}
+	if len(req.Contents) != 3 {
+		t.Fatalf("contents = %+v", req.Contents)
+	}
+	model := req.Contents[1]
+	if model.Role != "model" || len(model.Parts) != 2 || model.Parts[0].FunctionCall.Name != "search" || string(model.Parts[1].FunctionCall.Args) != "{}" {
+		t.Errorf("model turn = %+v", model)
+	}
+	results := req.Contents[2]
+	if results.Role != "user" || len(results.Parts) != 2 {
+		t.Fatalf("function responses = %+v", results)
+	}
+	if fr := results.Parts[0].FunctionResponse; fr.Name != "search" || string(fr.Response) != `{"content":"n1: Smaug"}` {
+		t.Errorf("first response = %+v", fr)
+	}
+	if fr := results.Parts[1].FunctionResponse; fr.Name != "count" || string(fr.Response) != `{"count":1}` {
+		t.Errorf("second response = %+v", fr)
+	}
+
+	decls := req.Tools[0].FunctionDeclarations
+	if len(decls) != 2 {
+		t.Fatalf("declarations = %+v", decls)
+	}
+	var params map[string]interface{}
+	if err := json.Unmarshal(decls[0].Parameters, &params); err != nil {
+		t.Fatalf("parameters: %v", err)
+	}
+	if _, ok := params["$schema"]; ok {
+		t.Error("$schema not stripped")
+	}
+	if _, ok := params["additionalProperties"]; ok {
+		t.Error("additionalProperties not stripped")
+	}
+	props := params["properties"].(map[string]interface{})
+	if _, ok := props["default"]; !ok {
+		t.Error("property named default was dropped")
+	}
+	if _, ok := props["q"].(map[string]interface{})["default"]; ok {
+		t.Error("nested default not stripped")
+	}
+	if decls[1].Parameters != nil {
+		t.Errorf("empty object schema should be omitted, got %s", decls[1].Parameters)
+	}
+
+	// Response: OpenAI shape with generated IDs
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content   *string    `json:"content"`
+				ToolCalls []toolCall `json:"tool_calls"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatalf("response: %v", err)
+	}
+	calls := resp.Choices[0].Message.ToolCalls
+	if resp.Choices[0].Message.Content != nil || len(calls) != 2 {
+		t.Fatalf("message = %+v", resp.Choices[0].Message)
+	}
+	if calls[0].Function.Name != "get_note" || calls[0].Function.Arguments != `{"id":"n1"}` || calls[0].ID == "" || calls[0].ID == calls[1].ID {
+		t.Errorf("tool_calls = %+v", calls)
+	}
+}
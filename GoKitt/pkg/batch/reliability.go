@@ -0,0 +1,207 @@
+package batch
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for the reliability settings in Config.
+const (
+	DefaultMaxRetries = 2
+	DefaultRetryBase  = 500 * time.Millisecond
+	DefaultTimeout    = 60 * time.Second
+	maxBackoff        = 30 * time.Second
+)
+
+// tokenBucket is a rate limiter: tokens refill at rate per second up to
+// burst, and each request takes one. Requests may reserve ahead, driving
+// the balance negative; the deficit is how long they wait.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perMinute float64, burst int, now time.Time) *tokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &tokenBucket{rate: perMinute / 60, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// refund returns a reserved token that was never used.
+func (b *tokenBucket) refund() {
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+// waitTurn blocks until the current provider's bucket grants a request.
+func (s *call) waitTurn(ctx context.Context) error {
+	if s.config.RequestsPerMinute <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	b, ok := s.limiters[s.config.Provider]
+	if !ok {
+		b = newTokenBucket(s.config.RequestsPerMinute, s.config.Burst, s.now())
+		s.limiters[s.config.Provider] = b
+	}
+	wait := b.reserve(s.now())
+	s.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := s.sleep(ctx, wait); err != nil {
+		s.mu.Lock()
+		b.refund()
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// acquire takes one of MaxConcurrent request slots; release gives it back.
+func (s *call) acquire(ctx context.Context) (release func(), err error) {
+	s.mu.Lock()
+	if s.slots == nil && s.config.MaxConcurrent > 0 {
+		s.slots = make(chan struct{}, s.config.MaxConcurrent)
+	}
+	slots := s.slots
+	s.mu.Unlock()
+
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// do sends a request with rate limiting, per-attempt timeouts and retries
+// with exponential backoff on 429, 5xx and network errors. A Retry-After
+// header on 429 or 503 replaces the backoff. After the last retry the
+// final response is returned for the caller to report.
+func (s *call) do(ctx context.Context, req *Request) (*Response, error) {
+	retries := s.config.MaxRetries
+	if retries == 0 {
+		retries = DefaultMaxRetries
+	}
+	stats, _ := ctx.Value(callStatsKey{}).(*callStats)
+
+	for attempt := 0; ; attempt++ {
+		if err := s.waitTurn(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := s.attempt(ctx, req)
+		if stats != nil {
+			stats.attempts++
+			if resp != nil {
+				stats.body = resp.Body
+			}
+		}
+
+		if attempt >= retries || !retryable(ctx, resp, err) {
+			return resp, err
+		}
+		wait, ok := s.retryAfter(resp)
+		if !ok {
+			wait = s.backoff(attempt)
+		}
+		if err := s.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends one request within a concurrency slot and the timeout.
+func (s *call) attempt(ctx context.Context, req *Request) (*Response, error) {
+	release, err := s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	timeout := DefaultTimeout
+	switch {
+	case s.config.TimeoutMs < 0:
+		timeout = 0
+	case s.config.TimeoutMs > 0:
+		timeout = time.Duration(s.config.TimeoutMs) * time.Millisecond
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return s.transport.Do(ctx, req)
+}
+
+// retryable reports whether a failed attempt is worth repeating. Errors
+// are retried unless the caller's context is done (an attempt timing out
+// on its own is retried).
+func retryable(ctx context.Context, resp *Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return resp.Status == http.StatusTooManyRequests || resp.Status >= 500
+}
+
+// backoff doubles RetryBaseMs per attempt, capped, with jitter over the
+// upper half so concurrent callers spread out.
+func (s *call) backoff(attempt int) time.Duration {
+	base := DefaultRetryBase
+	if s.config.RetryBaseMs > 0 {
+		base = time.Duration(s.config.RetryBaseMs) * time.Millisecond
+	}
+	d := min(base<<attempt, maxBackoff)
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryAfter reads the wait a 429 or 503 response asks for, given in
+// seconds or as an HTTP date, capped at maxBackoff so a misbehaving server
+// cannot park the caller for hours.
+func (s *call) retryAfter(resp *Response) (time.Duration, bool) {
+	if resp == nil || resp.RetryAfter == "" ||
+		(resp.Status != http.StatusTooManyRequests && resp.Status != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(resp.RetryAfter)); err == nil {
+		return min(time.Duration(max(secs, 0))*time.Second, maxBackoff), true
+	}
+	if at, err := http.ParseTime(resp.RetryAfter); err == nil {
+		return min(max(at.Sub(s.now()), 0), maxBackoff), true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
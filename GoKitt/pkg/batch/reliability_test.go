@@ -0,0 +1,299 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first n requests with status, then succeeds
+func flakyServer(t *testing.T, n int32, status int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= n {
+			w.WriteHeader(status)
+			io.WriteString(w, `{"error":{"message":"try later","code":`+strconv.Itoa(status)+`}}`)
+			return
+		}
+		io.WriteString(w, `{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":12,"completion_tokens":3}}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func newReliabilityService(srv *httptest.Server, cfg Config) (*Service, *[]time.Duration) {
+	cfg.Provider = ProviderOpenAI
+	cfg.OpenAIBaseURL = srv.URL
+	cfg.OpenAIModel = "local"
+	svc := NewServiceWithTransport(cfg, NewHTTPTransport(srv.Client()))
+
+	var slept []time.Duration
+	svc.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return ctx.Err()
+	}
+	return svc, &slept
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	srv, calls := flakyServer(t, 2, http.StatusServiceUnavailable)
+	svc, slept := newReliabilityService(srv, Config{RetryBaseMs: 100})
+
+	var usage []Usage
+	svc.SetUsageRecorder(func(u Usage) { usage = append(usage, u) })
+
+	text, err := svc.Complete(context.Background(), "hi", "")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if text != "ok" || *calls != 3 {
+		t.Errorf("text = %q after %d calls", text, *calls)
+	}
+
+	// Jittered over the upper half of 100ms, then 200ms
+	if len(*slept) != 2 {
+		t.Fatalf("slept = %v", *slept)
+	}
+	if d := (*slept)[0]; d < 50*time.Millisecond || d > 100*time.Millisecond {
+		t.Errorf("first backoff = %v", d)
+	}
+	if d := (*slept)[1]; d < 100*time.Millisecond || d > 200*time.Millisecond {
+		t.Errorf("second backoff = %v", d)
+	}
+
+	if len(usage) != 1 {
+		t.Fatalf("usage = %+v", usage)
+	}
+	u := usage[0]
+	if u.Attempts != 3 || u.PromptTokens != 12 || u.CompletionTokens != 3 || u.Err != nil {
+		t.Errorf("usage = %+v", u)
+	}
+	if u.Provider != ProviderOpenAI || u.Model != "local" || u.Operation != "complete" {
+		t.Errorf("usage = %+v", u)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	srv, calls := flakyServer(t, 10, http.StatusTooManyRequests)
+	svc, _ := newReliabilityService(srv, Config{MaxRetries: 3})
+
+	var usage []Usage
+	svc.SetUsageRecorder(func(u Usage) { usage = append(usage, u) })
+
+	_, err := svc.Complete(context.Background(), "hi", "")
+	if err == nil || !strings.Contains(err.Error(), "HTTP 429") {
+		t.Fatalf("err = %v", err)
+	}
+	if *calls != 4 {
+		t.Errorf("calls = %d, want 4", *calls)
+	}
+	if len(usage) != 1 || usage[0].Err == nil || usage[0].Attempts != 4 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("Retry-After", time.Unix(10, 0).UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			io.WriteString(w, `{"choices":[{"message":{"content":"ok"}}]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	svc, slept := newReliabilityService(srv, Config{MaxRetries: 3, RetryBaseMs: 100})
+	svc.now = func() time.Time { return time.Unix(5, 0) }
+	if _, err := svc.Complete(context.Background(), "hi", ""); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	want := []time.Duration{3 * time.Second, 5 * time.Second}
+	if len(*slept) != 2 || (*slept)[0] != want[0] || (*slept)[1] != want[1] {
+		t.Errorf("slept = %v, want %v", *slept, want)
+	}
+}
+
+func TestRetryAfterCapped(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("Retry-After", time.Unix(5+3600, 0).UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			io.WriteString(w, `{"choices":[{"message":{"content":"ok"}}]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	svc, slept := newReliabilityService(srv, Config{MaxRetries: 3})
+	svc.now = func() time.Time { return time.Unix(5, 0) }
+	if _, err := svc.Complete(context.Background(), "hi", ""); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if len(*slept) != 2 || (*slept)[0] != maxBackoff || (*slept)[1] != maxBackoff {
+		t.Errorf("slept = %v, want two waits of %v", *slept, maxBackoff)
+	}
+}
+
+func TestUpdateConfigDuringCalls(t *testing.T) {
+	srv, _ := flakyServer(t, 0, http.StatusOK)
+	svc, _ := newReliabilityService(srv, Config{RequestsPerMinute: 6000, Burst: 100})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			cfg := svc.GetConfig()
+			cfg.MaxConcurrent = i%3 + 1
+			svc.UpdateConfig(cfg)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		if _, err := svc.Complete(context.Background(), "hi", ""); err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+	}
+	<-done
+}
+
+func TestNoRetry(t *testing.T) {
+	// Client errors are not retried
+	srv, calls := flakyServer(t, 1, http.StatusBadRequest)
+	svc, _ := newReliabilityService(srv, Config{})
+	if _, err := svc.Complete(context.Background(), "hi", ""); err == nil {
+		t.Fatal("expected an error")
+	}
+	if *calls != 1 {
+		t.Errorf("calls = %d, want 1", *calls)
+	}
+
+	// MaxRetries -1 disables retries
+	srv, calls = flakyServer(t, 1, http.StatusBadGateway)
+	svc, _ = newReliabilityService(srv, Config{MaxRetries: -1})
+	if _, err := svc.Complete(context.Background(), "hi", ""); err == nil {
+		t.Fatal("expected an error")
+	}
+	if *calls != 1 {
+		t.Errorf("calls = %d, want 1", *calls)
+	}
+}
+
+func TestAttemptTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		srv.Close()
+	})
+
+	svc, slept := newReliabilityService(srv, Config{TimeoutMs: 20, MaxRetries: 1})
+	start := time.Now()
+	_, err := svc.Complete(context.Background(), "hi", "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if len(*slept) != 1 {
+		t.Errorf("timed-out attempt should be retried once, slept = %v", *slept)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %v", elapsed)
+	}
+
+	// A cancelled caller is not retried
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	*slept = nil
+	if _, err := svc.Complete(ctx, "hi", ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want canceled", err)
+	}
+	if len(*slept) != 0 {
+		t.Errorf("slept = %v", *slept)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	t0 := time.Unix(0, 0)
+	b := newTokenBucket(60, 2, t0)
+
+	for i, want := range []time.Duration{0, 0, time.Second, 2 * time.Second} {
+		if got := b.reserve(t0); got != want {
+			t.Errorf("reserve %d = %v, want %v", i, got, want)
+		}
+	}
+
+	// Refills at one token per second, never above burst
+	b = newTokenBucket(60, 2, t0)
+	b.reserve(t0)
+	b.reserve(t0)
+	if got := b.reserve(t0.Add(time.Second)); got != 0 {
+		t.Errorf("after refill = %v, want 0", got)
+	}
+	b = newTokenBucket(60, 2, t0)
+	b.reserve(t0.Add(time.Hour))
+	b.reserve(t0.Add(time.Hour))
+	if got := b.reserve(t0.Add(time.Hour)); got != time.Second {
+		t.Errorf("burst cap = %v, want 1s", got)
+	}
+}
+
+func TestRateLimitedCalls(t *testing.T) {
+	srv, calls := flakyServer(t, 0, http.StatusOK)
+	svc, slept := newReliabilityService(srv, Config{RequestsPerMinute: 30})
+	now := time.Unix(0, 0)
+	svc.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Complete(context.Background(), "hi", ""); err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d", *calls)
+	}
+	want := []time.Duration{2 * time.Second, 4 * time.Second}
+	if len(*slept) != 2 || (*slept)[0] != want[0] || (*slept)[1] != want[1] {
+		t.Errorf("slept = %v, want %v", *slept, want)
+	}
+}
+
+func TestParseUsage(t *testing.T) {
+	cases := []struct {
+		body             string
+		prompt, complete int
+	}{
+		{`{"usage":{"prompt_tokens":10,"completion_tokens":2}}`, 10, 2},
+		{`{"usage":{"input_tokens":7,"output_tokens":4}}`, 7, 4},
+		{`{"usageMetadata":{"promptTokenCount":5,"candidatesTokenCount":1}}`, 5, 1},
+		{`{"choices":[]}`, 0, 0},
+		{`not json`, 0, 0},
+	}
+	for _, c := range cases {
+		p, comp := parseUsage([]byte(c.body))
+		if p != c.prompt || comp != c.complete {
+			t.Errorf("parseUsage(%s) = %d, %d", c.body, p, comp)
+		}
+	}
+}
@@ -1,19 +1,22 @@
 // Package batch provides non-streaming LLM completion services.
 // Used for entity extraction, relation extraction, and other batch operations.
 //
-// Supports two providers:
+// Providers are looked up in a registry (see Providers):
 //   - Google GenAI (generativelanguage.googleapis.com)
 //   - OpenRouter (openrouter.ai)
+//   - Any OpenAI-compatible server (Ollama, llama.cpp server, LM Studio)
+//   - Anthropic Messages API (api.anthropic.com)
 //
-// All HTTP calls use syscall/js to leverage the browser's fetch API,
-// avoiding CORS issues in WASM environment.
+// HTTP calls go through a Transport: the browser's fetch API in WASM
+// (avoiding CORS issues), net/http elsewhere.
 package batch
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Provider type for LLM providers.
@@ -22,6 +25,8 @@ type Provider string
 const (
 	ProviderGoogle     Provider = "google"
 	ProviderOpenRouter Provider = "openrouter"
+	ProviderOpenAI     Provider = "openai"
+	ProviderAnthropic  Provider = "anthropic"
 )
 
 // Config holds batch LLM settings passed from TypeScript.
@@ -31,109 +36,190 @@ type Config struct {
 	GoogleModel      string   `json:"googleModel"`
 	OpenRouterAPIKey string   `json:"openRouterApiKey"`
 	OpenRouterModel  string   `json:"openRouterModel"`
+
+	// OpenAI-compatible server, e.g. http://localhost:11434/v1 for Ollama.
+	// The API key is optional for local servers.
+	OpenAIBaseURL string `json:"openAiBaseUrl,omitempty"`
+	OpenAIAPIKey  string `json:"openAiApiKey,omitempty"`
+	OpenAIModel   string `json:"openAiModel,omitempty"`
+
+	AnthropicAPIKey string `json:"anthropicApiKey,omitempty"`
+	AnthropicModel  string `json:"anthropicModel,omitempty"`
+
+	// Endpoint overrides (proxies, local mock servers); empty = provider default
+	GoogleBaseURL     string `json:"googleBaseUrl,omitempty"`
+	OpenRouterBaseURL string `json:"openRouterBaseUrl,omitempty"`
+	AnthropicBaseURL  string `json:"anthropicBaseUrl,omitempty"`
+
+	// Reliability; zero values pick the defaults
+	MaxRetries        int     `json:"maxRetries,omitempty"`        // Retries on 429/5xx/network errors (default 2, -1 disables)
+	RetryBaseMs       int     `json:"retryBaseMs,omitempty"`       // First backoff, doubled per retry (default 500)
+	TimeoutMs         int     `json:"timeoutMs,omitempty"`         // Per-attempt timeout (default 60000, -1 disables)
+	RequestsPerMinute float64 `json:"requestsPerMinute,omitempty"` // Token-bucket rate per provider (0 = unlimited)
+	Burst             int     `json:"burst,omitempty"`             // Requests allowed back to back (default 1)
+	MaxConcurrent     int     `json:"maxConcurrent,omitempty"`     // Requests in flight at once (0 = unlimited)
+}
+
+// Completer makes one plain completion. *Service implements it; packages
+// that only need text back take a Completer so tests can substitute a
+// scripted model.
+type Completer interface {
+	Complete(ctx context.Context, userPrompt, systemPrompt string) (string, error)
 }
 
 // Service handles non-streaming LLM completions.
 type Service struct {
-	config Config
+	config    Config
+	transport Transport
+
+	mu       sync.Mutex
+	usage    UsageRecorder
+	limiters map[Provider]*tokenBucket
+	slots    chan struct{} // MaxConcurrent semaphore; nil = unlimited
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
 }
 
-// NewService creates a batch service with config from TypeScript.
+// NewService creates a batch service with config from TypeScript,
+// using the build's default transport.
 func NewService(config Config) *Service {
-	return &Service{config: config}
+	return NewServiceWithTransport(config, defaultTransport())
+}
+
+// NewServiceWithTransport creates a batch service that sends requests
+// through t.
+func NewServiceWithTransport(config Config, t Transport) *Service {
+	return &Service{
+		config:    config,
+		transport: t,
+		limiters:  make(map[Provider]*tokenBucket),
+		now:       time.Now,
+		sleep:     sleepContext,
+	}
 }
 
-// UpdateConfig updates the service configuration.
+// SetTransport replaces the transport used for requests.
+func (s *Service) SetTransport(t Transport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transport = t
+}
+
+// SetUsageRecorder sets the callback that receives per-call usage;
+// nil stops recording.
+func (s *Service) SetUsageRecorder(r UsageRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = r
+}
+
+// UpdateConfig updates the service configuration. Rate limiters and the
+// concurrency limit restart from the new settings.
 func (s *Service) UpdateConfig(config Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.config = config
+	s.limiters = make(map[Provider]*tokenBucket)
+	s.slots = nil
 }
 
 // GetConfig returns the current configuration.
 func (s *Service) GetConfig() Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.config
 }
 
 // IsConfigured checks if the current provider has valid credentials.
 func (s *Service) IsConfigured() bool {
-	switch s.config.Provider {
-	case ProviderGoogle:
-		return s.config.GoogleAPIKey != ""
-	case ProviderOpenRouter:
-		return s.config.OpenRouterAPIKey != ""
-	default:
-		return false
-	}
+	cfg := s.GetConfig()
+	p, ok := providers[cfg.Provider]
+	return ok && p.configured(cfg)
 }
 
 // GetCurrentModel returns the model for the current provider.
 func (s *Service) GetCurrentModel() string {
-	switch s.config.Provider {
-	case ProviderGoogle:
-		return s.config.GoogleModel
-	case ProviderOpenRouter:
-		return s.config.OpenRouterModel
-	default:
-		return ""
+	cfg := s.GetConfig()
+	if p, ok := providers[cfg.Provider]; ok {
+		return p.model(cfg)
+	}
+	return ""
+}
+
+// GetCurrentProviderModel returns the current provider and its model, read
+// from one configuration snapshot.
+func (s *Service) GetCurrentProviderModel() (Provider, string) {
+	cfg := s.GetConfig()
+	if p, ok := providers[cfg.Provider]; ok {
+		return cfg.Provider, p.model(cfg)
 	}
+	return cfg.Provider, ""
 }
 
 // Complete makes a non-streaming LLM completion request.
 // Returns the full response text.
 func (s *Service) Complete(ctx context.Context, userPrompt, systemPrompt string) (string, error) {
-	if !s.IsConfigured() {
-		return "", errors.New("batch: provider not configured")
-	}
-
-	switch s.config.Provider {
-	case ProviderGoogle:
-		return s.callGoogle(ctx, userPrompt, systemPrompt)
-	case ProviderOpenRouter:
-		return s.callOpenRouter(ctx, userPrompt, systemPrompt)
-	default:
-		return "", errors.New("batch: unknown provider")
+	c, p, err := s.begin()
+	if err != nil {
+		return "", err
 	}
+	return c.track(ctx, "complete", p, func(ctx context.Context) (string, error) {
+		return p.complete(ctx, c, userPrompt, systemPrompt)
+	})
 }
 
 // CompleteWithTools makes a non-streaming LLM request with tool schemas.
-// Accepts any messages/tools structure and returns the raw JSON response
-// for the caller to parse (preserves tool_calls in response).
-//
-// Only OpenRouter is supported for tool calling.
+// Accepts OpenAI-shaped messages/tools and returns the raw JSON response
+// in the OpenAI chat completion shape, translated for providers that
+// speak another format (preserves tool_calls in response).
 func (s *Service) CompleteWithTools(ctx context.Context, messages interface{}, tools interface{}) (string, error) {
-	if !s.IsConfigured() {
-		return "", errors.New("batch: provider not configured")
+	c, p, err := s.begin()
+	if err != nil {
+		return "", err
 	}
+	return c.track(ctx, "tools", p, func(ctx context.Context) (string, error) {
+		return p.completeWithTools(ctx, c, messages, tools)
+	})
+}
 
-	if s.config.Provider != ProviderOpenRouter {
-		return "", errors.New("batch: tool calling only supported via OpenRouter")
+// ListModels returns the models the current provider offers.
+func (s *Service) ListModels(ctx context.Context) ([]Model, error) {
+	c, p, err := s.begin()
+	if err != nil {
+		return nil, err
 	}
+	return p.listModels(ctx, c)
+}
 
-	// Build full request body
-	reqMap := map[string]interface{}{
-		"model":       s.config.OpenRouterModel,
-		"messages":    messages,
-		"temperature": 0.7,
-		"max_tokens":  2048,
-		"stream":      false,
-	}
-	if tools != nil {
-		reqMap["tools"] = tools
-	}
+// call is one request's view of the service. It holds the config and
+// transport as they were when the request started, so UpdateConfig and
+// SetTransport never race with a request in flight.
+type call struct {
+	*Service
+	config    Config
+	transport Transport
+}
 
-	reqBody, err := json.Marshal(reqMap)
-	if err != nil {
-		return "", fmt.Errorf("batch: failed to marshal tool request: %w", err)
+// begin snapshots the settings and returns the configured provider's API.
+func (s *Service) begin() (*call, providerAPI, error) {
+	s.mu.Lock()
+	c := &call{Service: s, config: s.config, transport: s.transport}
+	s.mu.Unlock()
+	p, ok := providers[c.config.Provider]
+	if !ok {
+		return nil, nil, errors.New("batch: unknown provider")
 	}
-
-	// Use the same jsFetchWithAuth that callOpenRouter uses
-	raw, err := s.jsFetchWithAuth(
-		"https://openrouter.ai/api/v1/chat/completions",
-		string(reqBody),
-		s.config.OpenRouterAPIKey,
-	)
-	if err != nil {
-		return "", fmt.Errorf("batch: OpenRouter tool API request failed: %w", err)
+	if !p.configured(c.config) {
+		return nil, nil, errors.New("batch: provider not configured")
 	}
+	return c, p, nil
+}
 
-	return raw, nil
+// baseURL returns the override without a trailing slash, or the default.
+func baseURL(override, def string) string {
+	if override == "" {
+		return def
+	}
+	return strings.TrimRight(override, "/")
 }
@@ -0,0 +1,180 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockProviders serves the Google and OpenRouter endpoints and records the
+// last request each received
+type mockProviders struct {
+	google     *http.Request
+	googleBody []byte
+	router     *http.Request
+	routerBody []byte
+	fail       bool
+}
+
+func (m *mockProviders) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/google/models/"):
+		m.google, m.googleBody = r, body
+		if m.fail {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":{"code":400,"message":"API key not valid","status":"INVALID_ARGUMENT"}}`)
+			return
+		}
+		io.WriteString(w, `{"candidates":[{"content":{"parts":[{"text":"Hello world"}]}}]}`)
+	case r.URL.Path == "/router/chat/completions":
+		m.router, m.routerBody = r, body
+		if m.fail {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `rate limited`)
+			return
+		}
+		io.WriteString(w, `{"choices":[{"message":{"content":"Hi there","tool_calls":[{"id":"call_1"}]}}]}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newMockService(t *testing.T, provider Provider) (*Service, *mockProviders) {
+	t.Helper()
+	mock := &mockProviders{}
+	srv := httptest.NewServer(mock)
+	t.Cleanup(srv.Close)
+
+	svc := NewServiceWithTransport(Config{
+		Provider:          provider,
+		GoogleAPIKey:      "g-key",
+		GoogleModel:       "gemini-test",
+		OpenRouterAPIKey:  "or-key",
+		OpenRouterModel:   "router/test",
+		GoogleBaseURL:     srv.URL + "/google/",
+		OpenRouterBaseURL: srv.URL + "/router",
+	}, NewHTTPTransport(srv.Client()))
+	svc.sleep = func(context.Context, time.Duration) error { return nil }
+	return svc, mock
+}
+
+func TestGoogleComplete(t *testing.T) {
+	svc, mock := newMockService(t, ProviderGoogle)
+
+	text, err := svc.Complete(context.Background(), "Say hello", "Be brief")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if text != "Hello world" {
+		t.Errorf("text = %q, want %q", text, "Hello world")
+	}
+
+	if got := mock.google.URL.Path; got != "/google/models/gemini-test:generateContent" {
+		t.Errorf("path = %q", got)
+	}
+	if got := mock.google.Header.Get("x-goog-api-key"); got != "g-key" {
+		t.Errorf("key header = %q", got)
+	}
+	if got := mock.google.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var req googleRequest
+	if err := json.Unmarshal(mock.googleBody, &req); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if len(req.Contents) != 1 || req.Contents[0].Parts[0].Text != "Say hello" {
+		t.Errorf("contents = %+v", req.Contents)
+	}
+	if req.SystemInstruction == nil || req.SystemInstruction.Parts[0].Text != "Be brief" {
+		t.Errorf("systemInstruction = %+v", req.SystemInstruction)
+	}
+}
+
+func TestGoogleAPIError(t *testing.T) {
+	svc, mock := newMockService(t, ProviderGoogle)
+	mock.fail = true
+
+	_, err := svc.Complete(context.Background(), "Say hello", "")
+	if err == nil || !strings.Contains(err.Error(), "Google API error 400: API key not valid") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestOpenRouterComplete(t *testing.T) {
+	svc, mock := newMockService(t, ProviderOpenRouter)
+
+	text, err := svc.Complete(context.Background(), "Say hi", "Be brief")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if text != "Hi there" {
+		t.Errorf("text = %q, want %q", text, "Hi there")
+	}
+
+	if got := mock.router.Header.Get("Authorization"); got != "Bearer or-key" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := mock.router.Header.Get("X-Title"); got != "KittClouds" {
+		t.Errorf("X-Title = %q", got)
+	}
+
+	var req chatRequest
+	if err := json.Unmarshal(mock.routerBody, &req); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if req.Model != "router/test" || req.Stream {
+		t.Errorf("model = %q, stream = %v", req.Model, req.Stream)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Role != "system" || req.Messages[1].Content != "Say hi" {
+		t.Errorf("messages = %+v", req.Messages)
+	}
+}
+
+func TestOpenRouterHTTPError(t *testing.T) {
+	svc, mock := newMockService(t, ProviderOpenRouter)
+	mock.fail = true
+
+	_, err := svc.Complete(context.Background(), "Say hi", "")
+	if err == nil || !strings.Contains(err.Error(), "HTTP 429: rate limited") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestCompleteWithToolsReturnsRawResponse(t *testing.T) {
+	svc, mock := newMockService(t, ProviderOpenRouter)
+
+	messages := []map[string]string{{"role": "user", "content": "Look it up"}}
+	tools := []map[string]interface{}{{"type": "function", "function": map[string]string{"name": "search"}}}
+	raw, err := svc.CompleteWithTools(context.Background(), messages, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools: %v", err)
+	}
+	if !strings.Contains(raw, `"tool_calls"`) {
+		t.Errorf("raw response lost tool_calls: %s", raw)
+	}
+
+	var req map[string]interface{}
+	if err := json.Unmarshal(mock.routerBody, &req); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if _, ok := req["tools"]; !ok {
+		t.Error("tools missing from request")
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	svc, _ := newMockService(t, ProviderOpenRouter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := svc.Complete(ctx, "Say hi", ""); err == nil {
+		t.Fatal("expected an error for a cancelled context")
+	}
+}
@@ -0,0 +1,110 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// toolMessage is an OpenAI-shaped chat message, as CompleteWithTools
+// receives them.
+type toolMessage struct {
+	Role       string     `json:"role"`
+	Content    *string    `json:"content"`
+	ToolCalls  []toolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+// toolCall is an OpenAI-shaped function call.
+type toolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function toolCallFunction `json:"function"`
+}
+
+type toolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON object, encoded as a string
+}
+
+// toolSchema is an OpenAI-shaped tool definition.
+type toolSchema struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Parameters  json.RawMessage `json:"parameters"`
+	} `json:"function"`
+}
+
+// decodeToolInput re-reads CompleteWithTools' untyped arguments for
+// providers that need to translate them.
+func decodeToolInput(messages, tools interface{}) ([]toolMessage, []toolSchema, error) {
+	var msgs []toolMessage
+	if err := roundTrip(messages, &msgs); err != nil {
+		return nil, nil, fmt.Errorf("batch: invalid tool messages: %w", err)
+	}
+	var schemas []toolSchema
+	if tools != nil {
+		if err := roundTrip(tools, &schemas); err != nil {
+			return nil, nil, fmt.Errorf("batch: invalid tool schemas: %w", err)
+		}
+	}
+	return msgs, schemas, nil
+}
+
+func roundTrip(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// toolCompletion encodes a reply and its token usage as an OpenAI chat
+// completion, the shape CompleteWithTools returns for every provider.
+func toolCompletion(text string, calls []toolCall, promptTokens, completionTokens int) (string, error) {
+	type message struct {
+		Role      string     `json:"role"`
+		Content   *string    `json:"content"`
+		ToolCalls []toolCall `json:"tool_calls,omitempty"`
+	}
+	type choice struct {
+		Message      message `json:"message"`
+		FinishReason string  `json:"finish_reason"`
+	}
+
+	msg := message{Role: "assistant", ToolCalls: calls}
+	if text != "" {
+		msg.Content = &text
+	}
+	finish := "stop"
+	if len(calls) > 0 {
+		finish = "tool_calls"
+	}
+
+	type usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	}
+
+	out, err := json.Marshal(struct {
+		Choices []choice `json:"choices"`
+		Usage   usage    `json:"usage"`
+	}{
+		Choices: []choice{{Message: msg, FinishReason: finish}},
+		Usage:   usage{PromptTokens: promptTokens, CompletionTokens: completionTokens},
+	})
+	if err != nil {
+		return "", fmt.Errorf("batch: failed to encode tool response: %w", err)
+	}
+	return string(out), nil
+}
+
+// ToolArguments returns a call's arguments as a JSON object; empty or
+// malformed arguments become {}.
+func ToolArguments(args string) json.RawMessage {
+	if args == "" || !json.Valid([]byte(args)) {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(args)
+}
@@ -0,0 +1,92 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Request is an HTTP request sent through a Transport.
+type Request struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// Response is the status and body of an HTTP response.
+type Response struct {
+	Status     int
+	Body       []byte
+	RetryAfter string // Retry-After header, if any
+}
+
+// OK reports whether the status is 2xx.
+func (r *Response) OK() bool {
+	return r.Status >= 200 && r.Status < 300
+}
+
+// Transport sends HTTP requests for the service. WASM builds default to the
+// browser's fetch (FetchTransport); other builds use net/http (HTTPTransport).
+// Tests can point either at a local mock server.
+type Transport interface {
+	Do(ctx context.Context, req *Request) (*Response, error)
+}
+
+// HTTPTransport sends requests with net/http.
+type HTTPTransport struct {
+	Client *http.Client
+}
+
+// NewHTTPTransport creates a net/http transport; a nil client uses
+// http.DefaultClient.
+func NewHTTPTransport(client *http.Client) *HTTPTransport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPTransport{Client: client}
+}
+
+// Do sends the request and reads the whole response body.
+func (t *HTTPTransport) Do(ctx context.Context, req *Request) (*Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("batch: build request: %w", err)
+	}
+	for k, v := range req.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := t.Client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("batch: read response: %w", err)
+	}
+	return &Response{Status: resp.StatusCode, Body: body, RetryAfter: resp.Header.Get("Retry-After")}, nil
+}
+
+// postJSON sends a JSON body with POST, with the service's rate limits and
+// retries.
+func (s *call) postJSON(ctx context.Context, url string, headers map[string]string, body []byte) (*Response, error) {
+	all := map[string]string{"Content-Type": "application/json"}
+	for k, v := range headers {
+		all[k] = v
+	}
+	return s.do(ctx, &Request{Method: http.MethodPost, URL: url, Headers: all, Body: body})
+}
+
+// getJSON sends a GET expecting a JSON response.
+func (s *call) getJSON(ctx context.Context, url string, headers map[string]string) (*Response, error) {
+	all := map[string]string{"Accept": "application/json"}
+	for k, v := range headers {
+		all[k] = v
+	}
+	return s.do(ctx, &Request{Method: http.MethodGet, URL: url, Headers: all})
+}
@@ -0,0 +1,101 @@
+//go:build js && wasm
+// +build js,wasm
+
+package batch
+
+import (
+	"context"
+	"fmt"
+	"syscall/js"
+)
+
+// defaultTransport uses the browser's fetch, avoiding CORS issues that
+// net/http's wasm round-tripper runs into with some providers.
+func defaultTransport() Transport {
+	return FetchTransport{}
+}
+
+// appOrigin returns window.location.origin (sent as HTTP-Referer).
+func appOrigin() string {
+	origin := js.Global().Get("window").Get("location").Get("origin")
+	if origin.Type() != js.TypeString {
+		return ""
+	}
+	return origin.String()
+}
+
+// FetchTransport sends requests with the browser's fetch API.
+type FetchTransport struct{}
+
+type fetchResult struct {
+	resp *Response
+	err  error
+}
+
+// Do invokes fetch and waits for the response text. Cancelling ctx aborts
+// the request.
+func (FetchTransport) Do(ctx context.Context, req *Request) (*Response, error) {
+	fetch := js.Global().Get("fetch")
+	if fetch.IsUndefined() {
+		return nil, fmt.Errorf("batch: fetch not available")
+	}
+
+	headers := js.Global().Get("Object").New()
+	for k, v := range req.Headers {
+		headers.Set(k, v)
+	}
+	options := js.Global().Get("Object").New()
+	options.Set("method", req.Method)
+	options.Set("headers", headers)
+	if len(req.Body) > 0 {
+		options.Set("body", string(req.Body))
+	}
+
+	var controller js.Value
+	if ac := js.Global().Get("AbortController"); !ac.IsUndefined() {
+		controller = ac.New()
+		options.Set("signal", controller.Get("signal"))
+	}
+
+	// Buffered so late callbacks never block after a cancelled wait
+	resultCh := make(chan fetchResult, 1)
+	var status int
+	var retryAfter string
+
+	textThen := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		resultCh <- fetchResult{resp: &Response{Status: status, Body: []byte(args[0].String()), RetryAfter: retryAfter}}
+		return nil
+	})
+	catch := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		msg := "fetch failed"
+		if len(args) > 0 && args[0].Type() == js.TypeObject {
+			msg = args[0].Get("message").String()
+		}
+		resultCh <- fetchResult{err: fmt.Errorf("%s", msg)}
+		return nil
+	})
+	then := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		status = args[0].Get("status").Int()
+		if v := args[0].Get("headers").Call("get", "Retry-After"); v.Type() == js.TypeString {
+			retryAfter = v.String()
+		}
+		args[0].Call("text").Call("then", textThen).Call("catch", catch)
+		return nil
+	})
+
+	fetch.Invoke(req.URL, options).Call("then", then).Call("catch", catch)
+
+	select {
+	case r := <-resultCh:
+		then.Release()
+		textThen.Release()
+		catch.Release()
+		return r.resp, r.err
+	case <-ctx.Done():
+		if !controller.IsUndefined() {
+			controller.Call("abort")
+		}
+		// The callbacks stay registered: the aborted promise still settles
+		return nil, ctx.Err()
+	}
+}
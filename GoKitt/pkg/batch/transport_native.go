@@ -0,0 +1,14 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package batch
+
+// defaultTransport uses net/http outside the browser.
+func defaultTransport() Transport {
+	return NewHTTPTransport(nil)
+}
+
+// appOrigin has no page origin outside the browser.
+func appOrigin() string {
+	return ""
+}
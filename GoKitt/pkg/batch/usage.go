@@ -0,0 +1,86 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Usage describes one Complete or CompleteWithTools call.
+type Usage struct {
+	Provider         Provider
+	Model            string
+	Operation        string // "complete" | "tools"
+	PromptTokens     int
+	CompletionTokens int
+	Latency          time.Duration // Wall time including retries and waits
+	Attempts         int
+	Err              error
+	At               time.Time
+}
+
+// UsageRecorder receives a Usage record after every call, successful or
+// not. It runs on the caller's goroutine.
+type UsageRecorder func(Usage)
+
+// callStats collects what the request path saw during one call.
+type callStats struct {
+	attempts int
+	body     []byte // Last response body
+}
+
+type callStatsKey struct{}
+
+// track runs one provider call and reports its usage.
+func (s *call) track(ctx context.Context, op string, p providerAPI, fn func(context.Context) (string, error)) (string, error) {
+	stats := &callStats{}
+	start := s.now()
+	out, err := fn(context.WithValue(ctx, callStatsKey{}, stats))
+
+	s.mu.Lock()
+	record := s.usage
+	s.mu.Unlock()
+	if record != nil {
+		prompt, completion := parseUsage(stats.body)
+		record(Usage{
+			Provider:         s.config.Provider,
+			Model:            p.model(s.config),
+			Operation:        op,
+			PromptTokens:     prompt,
+			CompletionTokens: completion,
+			Latency:          s.now().Sub(start),
+			Attempts:         stats.attempts,
+			Err:              err,
+			At:               start,
+		})
+	}
+	return out, err
+}
+
+// parseUsage reads token counts from any supported provider's response:
+// OpenAI usage.prompt_tokens, Anthropic usage.input_tokens or Google
+// usageMetadata.promptTokenCount.
+func parseUsage(body []byte) (prompt, completion int) {
+	var resp struct {
+		Usage *struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			InputTokens      int `json:"input_tokens"`
+			OutputTokens     int `json:"output_tokens"`
+		} `json:"usage"`
+		UsageMetadata *struct {
+			PromptTokenCount     int `json:"promptTokenCount"`
+			CandidatesTokenCount int `json:"candidatesTokenCount"`
+		} `json:"usageMetadata"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &resp) != nil {
+		return 0, 0
+	}
+	if u := resp.Usage; u != nil {
+		return u.PromptTokens + u.InputTokens, u.CompletionTokens + u.OutputTokens
+	}
+	if u := resp.UsageMetadata; u != nil {
+		return u.PromptTokenCount, u.CandidatesTokenCount
+	}
+	return 0, 0
+}
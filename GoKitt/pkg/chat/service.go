@@ -3,11 +3,10 @@
 package chat
 
 import (
-	"crypto/rand"
-	"encoding/hex"
 	"fmt"
 	"time"
 
+	"github.com/kittclouds/gokitt/internal/ids"
 	"github.com/kittclouds/gokitt/internal/store"
 )
 
@@ -31,7 +30,7 @@ func NewChatService(s store.Storer) *ChatService {
 func (s *ChatService) CreateThread(worldID, narrativeID string) (*store.Thread, error) {
 	now := time.Now().UnixMilli()
 	thread := &store.Thread{
-		ID:          generateID(),
+		ID:          ids.New(),
 		WorldID:     worldID,
 		NarrativeID: narrativeID,
 		CreatedAt:   now,
@@ -68,7 +67,7 @@ func (s *ChatService) DeleteThread(id string) error {
 func (s *ChatService) AddMessage(threadID, role, content, narrativeID string) (*store.ThreadMessage, error) {
 	now := time.Now().UnixMilli()
 	msg := &store.ThreadMessage{
-		ID:          generateID(),
+		ID:          ids.New(),
 		ThreadID:    threadID,
 		Role:        role,
 		Content:     content,
@@ -130,7 +129,7 @@ func (s *ChatService) AppendMessageContent(messageID, chunk string) error {
 func (s *ChatService) StartStreamingMessage(threadID, narrativeID string) (*store.ThreadMessage, error) {
 	now := time.Now().UnixMilli()
 	msg := &store.ThreadMessage{
-		ID:          generateID(),
+		ID:          ids.New(),
 		ThreadID:    threadID,
 		Role:        "assistant",
 		Content:     "",
@@ -189,12 +188,6 @@ func (s *ChatService) ExportThread(threadID string) (string, error) {
 // Helpers
 // =============================================================================
 
-func generateID() string {
-	b := make([]byte, 8)
-	rand.Read(b)
-	return hex.EncodeToString(b)
-}
-
 func escapeJSON(s string) string {
 	// Basic JSON escaping
 	result := ""
@@ -0,0 +1,150 @@
+// Package coref provides world-level coreference resolution.
+// It seeds a shared resolver.Registry from stored entities (labels, aliases,
+// kind, gender) so every note of a world resolves against the same registry,
+// and persists user feedback on resolutions: confirmed names become entity
+// aliases, rejections are kept in the store.
+package coref
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
+)
+
+// Service keeps the world registry in sync with the store.
+type Service struct {
+	store    store.Storer
+	registry *resolver.Registry
+}
+
+// NewService creates a coreference service backed by the given store.
+func NewService(s store.Storer) *Service {
+	return &Service{
+		store:    s,
+		registry: resolver.NewRegistry(),
+	}
+}
+
+// Registry returns the shared registry to hand to conductors/resolvers.
+func (s *Service) Registry() *resolver.Registry {
+	return s.registry
+}
+
+// Load seeds the registry with every stored entity and rejection.
+// Returns the number of entities registered.
+func (s *Service) Load() (int, error) {
+	entities, err := s.store.ListEntities("")
+	if err != nil {
+		return 0, fmt.Errorf("coref: list entities: %w", err)
+	}
+	for _, e := range entities {
+		s.registry.Register(ToMetadata(e))
+	}
+
+	rejections, err := s.store.ListCorefRejections()
+	if err != nil {
+		return 0, fmt.Errorf("coref: list rejections: %w", err)
+	}
+	for _, r := range rejections {
+		s.registry.Reject(r.Text, r.EntityID)
+	}
+	return len(entities), nil
+}
+
+// Add registers (or refreshes) a single entity.
+func (s *Service) Add(e *store.Entity) {
+	if e != nil {
+		s.registry.Register(ToMetadata(e))
+	}
+}
+
+// Remove drops an entity from the registry.
+func (s *Service) Remove(id string) {
+	s.registry.Remove(id)
+}
+
+// Confirm records that text refers to entityID. New names and descriptions
+// become aliases and are written back to the stored entity.
+// Returns the updated entity, or nil when nothing changed.
+func (s *Service) Confirm(text, entityID string) (*store.Entity, error) {
+	if key := normalize(text); key != "" {
+		if err := s.store.RemoveCorefRejection(key, entityID); err != nil {
+			return nil, fmt.Errorf("coref: remove rejection: %w", err)
+		}
+	}
+	if !s.registry.Confirm(text, entityID) {
+		return nil, nil
+	}
+	return s.persistAliases(entityID)
+}
+
+// Reject records that text does not refer to entityID, in the registry and
+// the store. If text was an alias it is removed from the stored entity as
+// well. Returns the updated entity, or nil when its aliases did not change.
+func (s *Service) Reject(text, entityID string) (*store.Entity, error) {
+	changed := s.registry.Reject(text, entityID)
+	if s.registry.IsRejected(text, entityID) {
+		if err := s.store.AddCorefRejection(normalize(text), entityID); err != nil {
+			return nil, fmt.Errorf("coref: save rejection: %w", err)
+		}
+	}
+	if !changed {
+		return nil, nil
+	}
+	return s.persistAliases(entityID)
+}
+
+// persistAliases copies the registry's aliases for id onto the stored entity
+func (s *Service) persistAliases(id string) (*store.Entity, error) {
+	meta, ok := s.registry.Get(id)
+	if !ok {
+		return nil, nil
+	}
+	entity, err := s.store.GetEntity(id)
+	if err != nil {
+		return nil, fmt.Errorf("coref: get entity %s: %w", id, err)
+	}
+	if entity == nil {
+		return nil, nil // Registered from a tag only; nothing to persist
+	}
+
+	entity.Aliases = meta.Aliases
+	if entity.Aliases == nil {
+		entity.Aliases = []string{}
+	}
+	entity.UpdatedAt = time.Now().UnixMilli()
+	if err := s.store.UpsertEntity(entity); err != nil {
+		return nil, fmt.Errorf("coref: update aliases of %s: %w", id, err)
+	}
+	return entity, nil
+}
+
+// normalize keys rejections the way the registry does
+func normalize(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// ToMetadata converts a stored entity into resolver metadata.
+// Entities without a stored gender default to neutral for places and things.
+func ToMetadata(e *store.Entity) resolver.EntityMetadata {
+	gender := resolver.ParseGender(e.Gender)
+	if gender == resolver.GenderUnknown {
+		switch strings.ToUpper(e.Kind) {
+		case "LOCATION", "PLACE", "OBJECT", "ITEM", "MONSTER":
+			gender = resolver.GenderNeutral
+		case "FACTION", "ORGANIZATION":
+			gender = resolver.GenderPlural
+		}
+	}
+	return resolver.EntityMetadata{
+		ID:      e.ID,
+		Name:    e.Label,
+		Gender:  gender,
+		Aliases: e.Aliases,
+		Kind:    e.Kind,
+		Subtype: e.Subtype,
+	}
+}
@@ -0,0 +1,125 @@
+package coref
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
+)
+
+func newTestService(t *testing.T) (*Service, *store.SQLiteStore) {
+	s, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	entities := []*store.Entity{
+		{ID: "e-gandalf", Label: "Gandalf", Kind: "CHARACTER", Subtype: "wizard",
+			Aliases: []string{"Mithrandir"}, Gender: "male", CreatedBy: "user"},
+		{ID: "e-shire", Label: "The Shire", Kind: "LOCATION", Aliases: []string{}, CreatedBy: "user"},
+	}
+	for _, e := range entities {
+		if err := s.UpsertEntity(e); err != nil {
+			t.Fatalf("UpsertEntity: %v", err)
+		}
+	}
+	return NewService(s), s
+}
+
+func TestLoadSeedsRegistry(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	n, err := svc.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if n != 2 || svc.Registry().Len() != 2 {
+		t.Fatalf("Expected 2 entities, got %d (registry %d)", n, svc.Registry().Len())
+	}
+
+	r := resolver.NewWithRegistry(svc.Registry())
+	if id := r.Resolve("Mithrandir", nil); id != "e-gandalf" {
+		t.Errorf("Expected stored alias to resolve, got %s", id)
+	}
+	if id := r.Resolve("the wizard", nil); id != "e-gandalf" {
+		t.Errorf("Expected subtype to act as a descriptor, got %s", id)
+	}
+
+	// Stored gender and kind defaults drive pronoun agreement
+	r.ObserveMention("e-gandalf")
+	r.ObserveMention("e-shire")
+	if id := r.Resolve("he", nil); id != "e-gandalf" {
+		t.Errorf("Expected he -> e-gandalf, got %s", id)
+	}
+	if id := r.Resolve("it", nil); id != "e-shire" {
+		t.Errorf("Expected it -> e-shire, got %s", id)
+	}
+}
+
+func TestConfirmRejectPersistAliases(t *testing.T) {
+	svc, s := newTestService(t)
+	if _, err := svc.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	updated, err := svc.Confirm("the grey pilgrim", "e-gandalf")
+	if err != nil || updated == nil {
+		t.Fatalf("Confirm: %v (updated=%v)", err, updated)
+	}
+	stored, _ := s.GetEntity("e-gandalf")
+	if len(stored.Aliases) != 2 || stored.Aliases[1] != "the grey pilgrim" {
+		t.Errorf("Expected confirmed alias to be persisted, got %v", stored.Aliases)
+	}
+
+	// Pronouns and known aliases change nothing
+	if updated, _ := svc.Confirm("he", "e-gandalf"); updated != nil {
+		t.Error("Expected pronoun confirmation to be ignored")
+	}
+
+	updated, err = svc.Reject("Mithrandir", "e-gandalf")
+	if err != nil || updated == nil {
+		t.Fatalf("Reject: %v (updated=%v)", err, updated)
+	}
+	stored, _ = s.GetEntity("e-gandalf")
+	if len(stored.Aliases) != 1 || stored.Aliases[0] != "the grey pilgrim" {
+		t.Errorf("Expected rejected alias to be removed, got %v", stored.Aliases)
+	}
+
+	// Rejecting a non-alias is remembered but leaves the store alone
+	if updated, _ := svc.Reject("the wizard", "e-gandalf"); updated != nil {
+		t.Error("Expected no store update for a non-alias rejection")
+	}
+	r := resolver.NewWithRegistry(svc.Registry())
+	if id := r.Resolve("the wizard", nil); id == "e-gandalf" {
+		t.Error("Expected rejected description to be skipped")
+	}
+}
+
+func TestRejectSurvivesReload(t *testing.T) {
+	svc, s := newTestService(t)
+	svc.Load()
+
+	if _, err := svc.Reject("the  Wizard", "e-gandalf"); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if rs, _ := s.ListCorefRejections(); len(rs) != 1 || rs[0].Text != "the wizard" {
+		t.Fatalf("Expected one stored rejection, got %v", rs)
+	}
+
+	reloaded := NewService(s)
+	if _, err := reloaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reloaded.Registry().IsRejected("the wizard", "e-gandalf") {
+		t.Error("Expected rejection to survive a reload")
+	}
+
+	// Confirming the same text lifts the stored rejection
+	if _, err := reloaded.Confirm("the wizard", "e-gandalf"); err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+	if rs, _ := s.ListCorefRejections(); len(rs) != 0 {
+		t.Errorf("Expected rejection removed on confirm, got %v", rs)
+	}
+}
@@ -0,0 +1,125 @@
+package extraction
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kittclouds/gokitt/internal/store"
+)
+
+// PromptVersion identifies the prompt, parser and chunking revision. Bump it
+// whenever any of them changes what a note extracts to, so cached results
+// from the old revision are not reused.
+const PromptVersion = "2"
+
+// SetCache enables the extraction result cache backed by st; nil disables it.
+func (s *Service) SetCache(st store.Storer) {
+	s.cache = st
+}
+
+// ExtractFromNoteCached is ExtractFromNote with the result cache: the same
+// text extracted with the same provider, model, prompt version and known
+// entities is served from the store instead of calling the LLM. noteID is
+// linked to the entry, hit or miss, so invalidating any note that shares
+// it drops it. The second return value reports a
+// cache hit. Without a cache this is ExtractFromNote.
+func (s *Service) ExtractFromNoteCached(
+	ctx context.Context,
+	noteID string,
+	text string,
+	knownEntities []string,
+) (*ExtractionResult, bool, error) {
+	if s.cache == nil || s.batch == nil || text == "" {
+		result, err := s.ExtractFromNote(ctx, text, knownEntities)
+		return result, false, err
+	}
+
+	key := s.cacheKey(text, knownEntities)
+	// Cache failures fall through to a fresh extraction
+	if entry, err := s.cache.GetExtractionCache(key); err == nil && entry != nil {
+		var result ExtractionResult
+		if json.Unmarshal([]byte(entry.Result), &result) == nil {
+			s.cache.TouchExtractionCache(key, noteID, time.Now().UnixMilli())
+			return &result, true, nil
+		}
+	}
+
+	result, err := s.ExtractFromNote(ctx, text, knownEntities)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		now := time.Now().UnixMilli()
+		s.cache.PutExtractionCache(&store.ExtractionCacheEntry{
+			ExtractionCacheKey: key,
+			NoteIDs:            []string{noteID},
+			Result:             string(data),
+			CreatedAt:          now,
+			LastUsedAt:         now,
+		})
+	}
+	return result, false, nil
+}
+
+// CacheStats reports the extraction cache's size and hits.
+func (s *Service) CacheStats() (*store.ExtractionCacheStats, error) {
+	if s.cache == nil {
+		return nil, fmt.Errorf("extraction: cache not enabled")
+	}
+	return s.cache.ExtractionCacheStats()
+}
+
+// InvalidateNote drops the cached results linked to noteID, including
+// results shared with other notes of the same text.
+func (s *Service) InvalidateNote(noteID string) (int, error) {
+	if s.cache == nil {
+		return 0, fmt.Errorf("extraction: cache not enabled")
+	}
+	return s.cache.DeleteExtractionCacheByNote(noteID)
+}
+
+// InvalidateModel drops the cached results produced by model.
+func (s *Service) InvalidateModel(model string) (int, error) {
+	if s.cache == nil {
+		return 0, fmt.Errorf("extraction: cache not enabled")
+	}
+	return s.cache.DeleteExtractionCacheByModel(model)
+}
+
+// cacheKey derives the cache key for text under the current provider and
+// model. The
+// known-entity set is hashed order- and case-insensitively, since the
+// prompt treats it as a set.
+func (s *Service) cacheKey(text string, knownEntities []string) store.ExtractionCacheKey {
+	names := make([]string, 0, len(knownEntities))
+	seen := make(map[string]bool, len(knownEntities))
+	for _, k := range knownEntities {
+		if key := normName(k); key != "" && !seen[key] {
+			seen[key] = true
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+
+	entities := sha256.New()
+	for _, n := range names {
+		entities.Write([]byte(n))
+		entities.Write([]byte{0})
+	}
+	content := sha256.Sum256([]byte(text))
+	provider, model := s.batch.GetCurrentProviderModel()
+
+	return store.ExtractionCacheKey{
+		ContentHash:   hex.EncodeToString(content[:]),
+		Provider:      string(provider),
+		Model:         model,
+		PromptVersion: PromptVersion,
+		EntitiesHash:  hex.EncodeToString(entities.Sum(nil)),
+	}
+}
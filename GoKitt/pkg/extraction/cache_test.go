@@ -0,0 +1,166 @@
+package extraction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kittclouds/gokitt/internal/store"
+)
+
+// ---------------------------------------------------------------------------
+// Result cache tests
+// ---------------------------------------------------------------------------
+
+func TestExtractFromNoteCached_ReusesUnchangedNotes(t *testing.T) {
+	calls := 0
+	b := newMockBatch(t, func(string) string {
+		calls++
+		return `{"entities":[{"label":"Arin","kind":"CHARACTER","confidence":0.9}],"relations":[]}`
+	})
+	st, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	defer st.Close()
+
+	svc := NewService(b)
+	svc.SetCache(st)
+	ctx := context.Background()
+
+	first, hit, err := svc.ExtractFromNoteCached(ctx, "n1", "Arin rode north.", []string{"Lyra", "Eldoria"})
+	if err != nil || hit {
+		t.Fatalf("expected a fresh extraction, hit=%v err=%v", hit, err)
+	}
+
+	// Known entities are a set: order and case do not matter
+	second, hit, err := svc.ExtractFromNoteCached(ctx, "n1", "Arin rode north.", []string{"eldoria", "Lyra"})
+	if err != nil || !hit {
+		t.Fatalf("expected a cache hit, hit=%v err=%v", hit, err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 LLM call, got %d", calls)
+	}
+	if len(second.Entities) != 1 || second.Entities[0].Label != first.Entities[0].Label {
+		t.Errorf("cached result differs: %+v", second)
+	}
+
+	// Changed text or known entities miss
+	svc.ExtractFromNoteCached(ctx, "n1", "Arin rode south.", []string{"Lyra", "Eldoria"})
+	svc.ExtractFromNoteCached(ctx, "n1", "Arin rode north.", []string{"Lyra"})
+	if calls != 3 {
+		t.Errorf("expected 3 LLM calls, got %d", calls)
+	}
+
+	stats, err := svc.CacheStats()
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.Entries != 3 || stats.Hits != 1 {
+		t.Errorf("expected 3 entries and 1 hit, got %+v", stats)
+	}
+	if len(stats.Models) != 1 || stats.Models[0].Model != "test-model" || stats.Models[0].Provider == "" {
+		t.Errorf("expected entries under test-model, got %+v", stats.Models)
+	}
+}
+
+func TestExtractFromNoteCached_Invalidate(t *testing.T) {
+	calls := 0
+	b := newMockBatch(t, func(string) string {
+		calls++
+		return `{"entities":[],"relations":[]}`
+	})
+	st, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	defer st.Close()
+
+	svc := NewService(b)
+	svc.SetCache(st)
+	ctx := context.Background()
+
+	svc.ExtractFromNoteCached(ctx, "n1", "One.", nil)
+	svc.ExtractFromNoteCached(ctx, "n2", "Two.", nil)
+
+	if n, err := svc.InvalidateNote("n1"); err != nil || n != 1 {
+		t.Fatalf("expected 1 entry dropped for n1, got %d (%v)", n, err)
+	}
+	if _, hit, _ := svc.ExtractFromNoteCached(ctx, "n1", "One.", nil); hit {
+		t.Error("expected a miss after invalidating the note")
+	}
+	if _, hit, _ := svc.ExtractFromNoteCached(ctx, "n2", "Two.", nil); !hit {
+		t.Error("expected other notes to stay cached")
+	}
+
+	// A note with the same text shares n2's entry; invalidating it drops both
+	if _, hit, _ := svc.ExtractFromNoteCached(ctx, "n3", "Two.", nil); !hit {
+		t.Error("expected identical text to hit the shared entry")
+	}
+	if n, err := svc.InvalidateNote("n3"); err != nil || n != 1 {
+		t.Fatalf("expected the shared entry dropped for n3, got %d (%v)", n, err)
+	}
+	if _, hit, _ := svc.ExtractFromNoteCached(ctx, "n2", "Two.", nil); hit {
+		t.Error("expected n2 to miss after invalidating a note sharing its entry")
+	}
+
+	if n, err := svc.InvalidateModel("test-model"); err != nil || n != 2 {
+		t.Fatalf("expected 2 entries dropped for the model, got %d (%v)", n, err)
+	}
+	if calls != 4 {
+		t.Errorf("expected 4 LLM calls, got %d", calls)
+	}
+}
+
+func TestExtractFromNoteCached_NoCache(t *testing.T) {
+	svc := NewService(newMockBatch(t, func(string) string { return `{"entities":[],"relations":[]}` }))
+	if _, hit, err := svc.ExtractFromNoteCached(context.Background(), "n1", "Text.", nil); err != nil || hit {
+		t.Errorf("expected a plain extraction, hit=%v err=%v", hit, err)
+	}
+	if _, err := svc.CacheStats(); err == nil {
+		t.Error("expected an error without a cache")
+	}
+}
+
+func TestExtractFromNoteCached_SurvivesExportImport(t *testing.T) {
+	calls := 0
+	b := newMockBatch(t, func(string) string {
+		calls++
+		return `{"entities":[{"label":"Arin","kind":"CHARACTER","confidence":0.9}],"relations":[]}`
+	})
+	src, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	defer src.Close()
+
+	svc := NewService(b)
+	svc.SetCache(src)
+	ctx := context.Background()
+	svc.ExtractFromNoteCached(ctx, "n1", "Arin rode north.", nil)
+
+	data, err := src.Export()
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	dst, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	defer dst.Close()
+	if err := dst.Import(data); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	svc.SetCache(dst)
+	if _, hit, err := svc.ExtractFromNoteCached(ctx, "n2", "Arin rode north.", nil); err != nil || !hit {
+		t.Fatalf("expected a cache hit after import, hit=%v err=%v", hit, err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 LLM call, got %d", calls)
+	}
+
+	// The imported entry keeps its note links, so invalidation still works
+	if n, err := svc.InvalidateNote("n1"); err != nil || n != 1 {
+		t.Errorf("expected the imported entry dropped for n1, got %d (%v)", n, err)
+	}
+}
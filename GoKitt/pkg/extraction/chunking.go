@@ -0,0 +1,106 @@
+package extraction
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+)
+
+// ChunkOverlap is how much trailing text (in chars) of one chunk is repeated
+// at the start of the next, so a relation spanning a chunk boundary is seen
+// whole by at least one LLM call.
+const ChunkOverlap = 800
+
+// splitText splits text into windows of at most size bytes that end on
+// sentence or paragraph boundaries. Each window after the first starts with
+// up to overlap bytes of whole sentences from the end of the previous one.
+func splitText(text string, size, overlap int) []chunker.TextRange {
+	if len(text) <= size {
+		return []chunker.TextRange{chunker.NewRange(0, len(text))}
+	}
+
+	segs := segments(text, size)
+	var out []chunker.TextRange
+	first := 0 // first segment of the current window
+	for i := range segs {
+		if segs[i].End-segs[first].Start <= size {
+			continue
+		}
+		out = append(out, chunker.NewRange(segs[first].Start, segs[i-1].End))
+
+		// Back up over whole trailing segments for context, keeping the
+		// next window within size and always moving forward
+		next := i
+		for next > first+1 &&
+			segs[i-1].End-segs[next-1].Start <= overlap &&
+			segs[i].End-segs[next-1].Start <= size {
+			next--
+		}
+		first = next
+	}
+	return append(out, chunker.NewRange(segs[first].Start, segs[len(segs)-1].End))
+}
+
+// segments cuts text at sentence and paragraph boundaries; pieces still
+// longer than size are split at the last whitespace that fits.
+func segments(text string, size int) []chunker.TextRange {
+	var segs []chunker.TextRange
+	start := 0
+	for _, cut := range boundaries(text) {
+		if cut <= start {
+			continue
+		}
+		for cut-start > size {
+			end := hardCut(text, start, start+size)
+			segs = append(segs, chunker.NewRange(start, end))
+			start = end
+		}
+		segs = append(segs, chunker.NewRange(start, cut))
+		start = cut
+	}
+	return segs
+}
+
+// boundaries returns the sorted offsets where a segment may end: after
+// sentence-final punctuation found by the chunker's tokenizer, after blank
+// lines, and at the end of the text.
+func boundaries(text string) []int {
+	var cuts []int
+	for _, tok := range chunker.New().Chunk(text).Tokens {
+		if tok.POS == chunker.Punctuation && isSentenceEnd(tok.Text) {
+			cuts = append(cuts, tok.Range.End)
+		}
+	}
+	for i := 0; ; {
+		j := strings.Index(text[i:], "\n\n")
+		if j < 0 {
+			break
+		}
+		i += j + 2
+		cuts = append(cuts, i)
+	}
+	cuts = append(cuts, len(text))
+	sort.Ints(cuts)
+	return cuts
+}
+
+func isSentenceEnd(s string) bool {
+	return s == "." || s == "!" || s == "?"
+}
+
+// hardCut picks an end offset in (start, limit] for an over-long sentence,
+// preferring the last whitespace and never splitting a UTF-8 sequence.
+func hardCut(text string, start, limit int) int {
+	for i := limit - 1; i > start; i-- {
+		switch text[i] {
+		case ' ', '\n', '\t', '\r':
+			return i + 1
+		}
+	}
+	for limit > start+1 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return limit
+}
@@ -0,0 +1,232 @@
+package extraction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/batch"
+)
+
+// ---------------------------------------------------------------------------
+// splitText tests
+// ---------------------------------------------------------------------------
+
+func TestSplitText_ShortTextSingleChunk(t *testing.T) {
+	text := "Arin drew his sword. Lyra watched."
+	chunks := splitText(text, 100, 20)
+	if len(chunks) != 1 || chunks[0].Slice(text) != text {
+		t.Fatalf("expected the whole text as one chunk, got %v", chunks)
+	}
+}
+
+func TestSplitText_SentenceBoundariesAndOverlap(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&sb, "Sentence number %02d is here. ", i)
+	}
+	text := sb.String()
+
+	chunks := splitText(text, 200, 60)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if c.Len() > 200 {
+			t.Errorf("chunk %d is %d bytes, limit 200", i, c.Len())
+		}
+		if got := strings.TrimSpace(c.Slice(text)); !strings.HasSuffix(got, ".") {
+			t.Errorf("chunk %d does not end on a sentence: %q", i, got)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := chunks[i-1]
+		if c.Start >= prev.End {
+			t.Errorf("chunk %d does not overlap the previous one", i)
+		}
+		if c.Start <= prev.Start {
+			t.Errorf("chunk %d does not move forward", i)
+		}
+	}
+	if chunks[0].Start != 0 || chunks[len(chunks)-1].End != len(text) {
+		t.Error("chunks do not cover the whole text")
+	}
+}
+
+func TestSplitText_ParagraphBreaks(t *testing.T) {
+	text := strings.Repeat("a", 50) + "\n\n" + strings.Repeat("b", 50) + "\n\n" + strings.Repeat("c", 50)
+	chunks := splitText(text, 110, 0)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if !strings.HasSuffix(chunks[0].Slice(text), "b\n\n") {
+		t.Errorf("expected first chunk to end at a paragraph break, got %q", chunks[0].Slice(text))
+	}
+}
+
+func TestSplitText_LongSentenceHardSplit(t *testing.T) {
+	text := strings.Repeat("word ", 100) // no sentence punctuation
+	chunks := splitText(text, 64, 16)
+	for i, c := range chunks {
+		if c.Len() > 64 {
+			t.Errorf("chunk %d is %d bytes, limit 64", i, c.Len())
+		}
+		if s := c.Slice(text); !strings.HasSuffix(s, " ") {
+			t.Errorf("chunk %d splits a word: %q", i, s)
+		}
+	}
+	if chunks[len(chunks)-1].End != len(text) {
+		t.Error("chunks do not cover the whole text")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// reconcile tests
+// ---------------------------------------------------------------------------
+
+func TestReconcile_MergesAliasesAndKeepsMaxConfidence(t *testing.T) {
+	results := []*ExtractionResult{
+		{
+			Entities: []ExtractedEntity{
+				{Label: "Lyra Vance", Kind: KindCharacter, Aliases: []string{"Lyra"}, Confidence: 0.7},
+				{Label: "Eldoria", Kind: KindLocation, Confidence: 0.9},
+			},
+			Relations: []ExtractedRelation{
+				{Subject: "Lyra", Object: "Eldoria", RelationType: RelTraveledTo, Verb: "went to", Confidence: 0.6},
+			},
+		},
+		{
+			Entities: []ExtractedEntity{
+				{Label: "lyra", Kind: KindNPC, Confidence: 0.95},
+				{Label: "The Vance Girl", Kind: KindCharacter, Aliases: []string{"Lyra Vance"}, Confidence: 0.5},
+			},
+			Relations: []ExtractedRelation{
+				{Subject: "Lyra Vance", Object: "eldoria", RelationType: RelTraveledTo, Verb: "traveled to", Confidence: 0.9, Time: "at dawn"},
+			},
+		},
+	}
+
+	out := reconcile(results)
+	if len(out.Entities) != 2 {
+		t.Fatalf("expected 2 entities, got %d: %+v", len(out.Entities), out.Entities)
+	}
+
+	lyra := out.Entities[0]
+	if lyra.Label != "Lyra Vance" {
+		t.Errorf("expected canonical label 'Lyra Vance', got %q", lyra.Label)
+	}
+	if lyra.Kind != KindCharacter {
+		t.Errorf("expected kind CHARACTER (most confidence), got %q", lyra.Kind)
+	}
+	if lyra.Confidence != 0.95 {
+		t.Errorf("expected max confidence 0.95, got %v", lyra.Confidence)
+	}
+	if len(lyra.Aliases) != 2 || lyra.Aliases[0] != "Lyra" || lyra.Aliases[1] != "The Vance Girl" {
+		t.Errorf("unexpected aliases: %v", lyra.Aliases)
+	}
+
+	if len(out.Relations) != 1 {
+		t.Fatalf("expected duplicate relations to merge, got %d", len(out.Relations))
+	}
+	rel := out.Relations[0]
+	if rel.Subject != "Lyra Vance" || rel.Object != "Eldoria" {
+		t.Errorf("expected relation on canonical labels, got %q -> %q", rel.Subject, rel.Object)
+	}
+	if rel.Verb != "traveled to" || rel.Confidence != 0.9 || rel.Time != "at dawn" {
+		t.Errorf("expected the more confident relation to win, got %+v", rel)
+	}
+}
+
+func TestReconcile_FillsMissingModifiers(t *testing.T) {
+	results := []*ExtractionResult{
+		{Relations: []ExtractedRelation{{Subject: "A", Object: "B", RelationType: RelBattles, Confidence: 0.9}}},
+		{Relations: []ExtractedRelation{{Subject: "A", Object: "B", RelationType: RelBattles, Confidence: 0.5, Location: "the bridge"}}},
+	}
+	out := reconcile(results)
+	if len(out.Relations) != 1 || out.Relations[0].Location != "the bridge" {
+		t.Errorf("expected location filled from the duplicate, got %+v", out.Relations)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Chunked ExtractFromNote
+// ---------------------------------------------------------------------------
+
+// newMockBatch returns a batch service backed by a local OpenAI-compatible
+// server that answers each user prompt with reply(prompt).
+func newMockBatch(t *testing.T, reply func(prompt string) string) *batch.Service {
+	t.Helper()
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &req)
+
+		mu.Lock()
+		content := reply(req.Messages[len(req.Messages)-1].Content)
+		mu.Unlock()
+
+		out, _ := json.Marshal(map[string]interface{}{
+			"choices": []interface{}{map[string]interface{}{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+		w.Write(out)
+	}))
+	t.Cleanup(srv.Close)
+
+	return batch.NewServiceWithTransport(batch.Config{
+		Provider:      batch.ProviderOpenAI,
+		OpenAIBaseURL: srv.URL,
+		OpenAIModel:   "test-model",
+	}, batch.NewHTTPTransport(srv.Client()))
+}
+
+func TestExtractFromNote_ChunksLongNotes(t *testing.T) {
+	var prompts []string
+	b := newMockBatch(t, func(prompt string) string {
+		prompts = append(prompts, prompt)
+
+		// Each chunk reports the dragon, and the last also reports the king
+		if strings.Contains(prompt, "The king fell.") {
+			return `{"entities":[{"label":"vyrax","kind":"CHARACTER","confidence":0.9},{"label":"King Aldric","kind":"CHARACTER","confidence":0.9}],` +
+				`"relations":[{"subject":"Vyrax","object":"King Aldric","verb":"killed","relationType":"DEFEATS","confidence":0.9}]}`
+		}
+		return `{"entities":[{"label":"Vyrax","kind":"CHARACTER","confidence":0.8}],"relations":[]}`
+	})
+
+	text := strings.Repeat("Vyrax circled the valley. ", MaxTextLength/20) + "The king fell."
+	result, err := NewService(b).ExtractFromNote(context.Background(), text, []string{"Eldoria"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(prompts) < 2 {
+		t.Fatalf("expected several LLM calls, got %d", len(prompts))
+	}
+	if !strings.HasSuffix(prompts[len(prompts)-1], "The king fell.") {
+		t.Error("expected the tail of the note to be extracted")
+	}
+	if !strings.Contains(prompts[1], "Eldoria, Vyrax") {
+		t.Error("expected later chunks to be primed with entities found so far")
+	}
+
+	if len(result.Entities) != 2 {
+		t.Fatalf("expected entities reconciled to 2, got %+v", result.Entities)
+	}
+	if result.Entities[0].Label != "Vyrax" || result.Entities[0].Confidence != 0.9 {
+		t.Errorf("unexpected merged entity: %+v", result.Entities[0])
+	}
+	if len(result.Relations) != 1 {
+		t.Errorf("expected 1 relation, got %d", len(result.Relations))
+	}
+}
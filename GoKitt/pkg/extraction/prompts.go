@@ -5,8 +5,9 @@ import (
 	"strings"
 )
 
-// MaxTextLength is the maximum number of characters sent to the LLM.
-// Matches the TypeScript relation extractor's 8000-char limit.
+// MaxTextLength is the maximum number of characters sent to the LLM in one
+// call. Matches the TypeScript relation extractor's 8000-char limit; the
+// service splits longer notes into chunks (see ExtractFromNote).
 const MaxTextLength = 8000
 
 // SystemPrompt instructs the LLM to return structured JSON only.
@@ -18,7 +19,7 @@ No markdown, no explanation. Start with { and end with }.`
 // BuildUserPrompt constructs the combined extraction prompt.
 // knownEntities primes the LLM with entity labels already in the registry.
 func BuildUserPrompt(text string, knownEntities []string) string {
-	// Truncate text to avoid token limits
+	// Truncate text to avoid token limits; the service chunks before this
 	truncated := text
 	if len(truncated) > MaxTextLength {
 		truncated = truncated[:MaxTextLength]
@@ -0,0 +1,169 @@
+package extraction
+
+import "strings"
+
+// reconcile merges per-chunk results into one. Entities sharing a label or
+// alias (case-insensitively) collapse into one entity; relations are
+// rewritten onto the merged labels and deduplicated by
+// (subject, relationType, object).
+//
+// Confidence is the maximum over duplicates rather than a combination:
+// overlapping chunks see the same mention twice, which is not independent
+// evidence.
+func reconcile(results []*ExtractionResult) *ExtractionResult {
+	var all []ExtractedEntity
+	for _, r := range results {
+		all = append(all, r.Entities...)
+	}
+	entities, canonical := mergeEntities(all)
+
+	out := &ExtractionResult{
+		Entities:  entities,
+		Relations: make([]ExtractedRelation, 0),
+	}
+	index := make(map[string]int)
+	for _, r := range results {
+		for _, rel := range r.Relations {
+			if c, ok := canonical[normName(rel.Subject)]; ok {
+				rel.Subject = c
+			}
+			if c, ok := canonical[normName(rel.Object)]; ok {
+				rel.Object = c
+			}
+			key := normName(rel.Subject) + "|" + rel.RelationType + "|" + normName(rel.Object)
+			if i, ok := index[key]; ok {
+				out.Relations[i] = mergeRelation(out.Relations[i], rel)
+				continue
+			}
+			index[key] = len(out.Relations)
+			out.Relations = append(out.Relations, rel)
+		}
+	}
+	return out
+}
+
+// mergeEntities groups entities whose names overlap and folds each group
+// into one entity. It returns the merged entities in first-seen order and
+// a map from every normalised name to its group's canonical label.
+func mergeEntities(entities []ExtractedEntity) ([]ExtractedEntity, map[string]string) {
+	// Union-find over entity indices, joined through shared names
+	parent := make([]int, len(entities))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	owner := make(map[string]int)
+	for i, e := range entities {
+		for _, name := range append([]string{e.Label}, e.Aliases...) {
+			key := normName(name)
+			if key == "" {
+				continue
+			}
+			if j, ok := owner[key]; ok {
+				a, b := find(i), find(j)
+				// The earlier entity stays the root so output order is stable
+				if a < b {
+					parent[b] = a
+				} else {
+					parent[a] = b
+				}
+				continue
+			}
+			owner[key] = i
+		}
+	}
+
+	groups := make(map[int][]ExtractedEntity)
+	var roots []int
+	for i, e := range entities {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], e)
+	}
+
+	merged := make([]ExtractedEntity, 0, len(roots))
+	canonical := make(map[string]string)
+	for _, root := range roots {
+		e := foldEntities(groups[root])
+		merged = append(merged, e)
+		canonical[normName(e.Label)] = e.Label
+		for _, a := range e.Aliases {
+			canonical[normName(a)] = e.Label
+		}
+	}
+	return merged, canonical
+}
+
+// foldEntities combines mentions of one entity. The label named most often,
+// as a label or alias, wins (ties go to the longer, more specific name);
+// the kind with the most total confidence wins; every other name becomes
+// an alias.
+func foldEntities(group []ExtractedEntity) ExtractedEntity {
+	labelVotes := make(map[string]int)
+	kindVotes := make(map[EntityKind]float64)
+	out := ExtractedEntity{Label: group[0].Label, Kind: group[0].Kind}
+	for _, e := range group {
+		for _, name := range append([]string{e.Label}, e.Aliases...) {
+			labelVotes[normName(name)]++
+		}
+		kindVotes[e.Kind] += e.Confidence
+		out.Confidence = max(out.Confidence, e.Confidence)
+	}
+	for _, e := range group {
+		best, cur := labelVotes[normName(e.Label)], labelVotes[normName(out.Label)]
+		if best > cur || (best == cur && len(e.Label) > len(out.Label)) {
+			out.Label = e.Label
+		}
+		if kindVotes[e.Kind] > kindVotes[out.Kind] {
+			out.Kind = e.Kind
+		}
+	}
+
+	seen := map[string]bool{normName(out.Label): true}
+	for _, e := range group {
+		for _, name := range append([]string{e.Label}, e.Aliases...) {
+			key := normName(name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			out.Aliases = append(out.Aliases, strings.TrimSpace(name))
+		}
+	}
+	return out
+}
+
+// mergeRelation keeps the more confident of two duplicate relations and
+// fills its empty optional fields from the other.
+func mergeRelation(a, b ExtractedRelation) ExtractedRelation {
+	if b.Confidence > a.Confidence {
+		a, b = b, a
+	}
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&a.SubjectKind, b.SubjectKind)
+	fill(&a.ObjectKind, b.ObjectKind)
+	fill(&a.Manner, b.Manner)
+	fill(&a.Location, b.Location)
+	fill(&a.Time, b.Time)
+	fill(&a.Recipient, b.Recipient)
+	fill(&a.SourceSentence, b.SourceSentence)
+	return a
+}
+
+// normName folds a name for comparison: trimmed, lowercased, single-spaced.
+func normName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/kittclouds/gokitt/internal/store"
 	"github.com/kittclouds/gokitt/pkg/batch"
 )
 
@@ -11,6 +12,7 @@ import (
 // It composes with batch.Service for the actual LLM completion call.
 type Service struct {
 	batch *batch.Service
+	cache store.Storer // Optional result cache (see SetCache)
 }
 
 // NewService creates an extraction service backed by the given batch service.
@@ -18,9 +20,14 @@ func NewService(b *batch.Service) *Service {
 	return &Service{batch: b}
 }
 
-// ExtractFromNote performs a single LLM call to extract both entities and
-// relations from the given text. knownEntities primes the LLM with existing
-// entity labels for better relation extraction.
+// ExtractFromNote extracts both entities and relations from the given text.
+// knownEntities primes the LLM with existing entity labels for better
+// relation extraction.
+//
+// Text up to MaxTextLength takes a single LLM call. Longer notes are split
+// on sentence and paragraph boundaries into overlapping chunks, extracted in
+// order (each chunk primed with the entities found so far), and reconciled
+// into one result.
 func (s *Service) ExtractFromNote(
 	ctx context.Context,
 	text string,
@@ -33,21 +40,55 @@ func (s *Service) ExtractFromNote(
 		return nil, fmt.Errorf("extraction: LLM provider not configured")
 	}
 
-	text = truncateText(text)
 	if text == "" {
 		return &ExtractionResult{}, nil
 	}
 
+	chunks := splitText(text, MaxTextLength, ChunkOverlap)
+	if len(chunks) == 1 {
+		result, err := s.extractChunk(ctx, text, knownEntities)
+		if err != nil {
+			return nil, fmt.Errorf("extraction: %w", err)
+		}
+		return result, nil
+	}
+
+	known := append([]string(nil), knownEntities...)
+	seen := make(map[string]bool, len(known))
+	for _, k := range known {
+		seen[normName(k)] = true
+	}
+
+	results := make([]*ExtractionResult, 0, len(chunks))
+	for i, r := range chunks {
+		result, err := s.extractChunk(ctx, r.Slice(text), known)
+		if err != nil {
+			return nil, fmt.Errorf("extraction: chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		results = append(results, result)
+
+		for _, e := range result.Entities {
+			if key := normName(e.Label); !seen[key] {
+				seen[key] = true
+				known = append(known, e.Label)
+			}
+		}
+	}
+	return reconcile(results), nil
+}
+
+// extractChunk runs one LLM call over text that fits in MaxTextLength.
+func (s *Service) extractChunk(ctx context.Context, text string, knownEntities []string) (*ExtractionResult, error) {
 	userPrompt := BuildUserPrompt(text, knownEntities)
 
 	raw, err := s.batch.Complete(ctx, userPrompt, SystemPrompt)
 	if err != nil {
-		return nil, fmt.Errorf("extraction: LLM call failed: %w", err)
+		return nil, fmt.Errorf("LLM call failed: %w", err)
 	}
 
 	result, err := ParseResponse(raw)
 	if err != nil {
-		return nil, fmt.Errorf("extraction: parse failed: %w", err)
+		return nil, fmt.Errorf("parse failed: %w", err)
 	}
 
 	return result, nil
@@ -79,11 +120,3 @@ func (s *Service) ExtractRelationsFromNote(
 	}
 	return result.Relations, nil
 }
-
-// truncateText limits text length to MaxTextLength.
-func truncateText(text string) string {
-	if len(text) > MaxTextLength {
-		return text[:MaxTextLength]
-	}
-	return text
-}
@@ -0,0 +1,440 @@
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// Path is a route between two nodes found by ShortestPath
+type Path struct {
+	Nodes      []string `json:"nodes"`
+	Relations  []string `json:"relations"`  // Relations[i] joins Nodes[i] and Nodes[i+1]
+	Confidence float64  `json:"confidence"` // Product of edge weights along the path
+}
+
+// IsHierarchy reports whether a relation belongs to the folder/note
+// hierarchy rather than the story itself
+func IsHierarchy(relation string) bool {
+	switch relation {
+	case RelContains, RelWormhole, RelContainsWorld, RelWorldContains, RelLinksTo:
+		return true
+	}
+	return false
+}
+
+// Filter returns a copy of the graph keeping every node and only the edges
+// accepted by keep. Edges are copied, the original graph is not modified.
+func (g *ConceptGraph) Filter(keep func(*ConceptEdge) bool) *ConceptGraph {
+	out := NewGraph()
+	for id, node := range g.Nodes {
+		out.EnsureNode(id, node.Label, node.Kind)
+	}
+	for _, node := range g.Nodes {
+		for _, edge := range node.Outbound {
+			if keep(edge) {
+				copied := *edge
+				out.AddEdge(out.Nodes[edge.Source.ID], out.Nodes[edge.Target.ID], &copied)
+			}
+		}
+	}
+	return out
+}
+
+// link is an undirected neighbor entry used by the algorithms below
+type link struct {
+	to       int
+	weight   float64 // Strongest edge weight between the pair
+	relation string  // Relation of that edge
+}
+
+// adjacency is an undirected, index-based view of a graph.
+// Node indices follow sorted IDs so results are deterministic.
+type adjacency struct {
+	ids   []string
+	index map[string]int
+	links [][]link
+}
+
+// undirected collapses parallel and opposite edges into one link per pair,
+// keeping the strongest. Self-loops are dropped.
+func (g *ConceptGraph) undirected() *adjacency {
+	a := &adjacency{
+		ids:   make([]string, 0, len(g.Nodes)),
+		index: make(map[string]int, len(g.Nodes)),
+	}
+	for id := range g.Nodes {
+		a.ids = append(a.ids, id)
+	}
+	sort.Strings(a.ids)
+	for i, id := range a.ids {
+		a.index[id] = i
+	}
+
+	best := make([]map[int]link, len(a.ids))
+	for i := range best {
+		best[i] = make(map[int]link)
+	}
+	for _, node := range g.Nodes {
+		for _, edge := range node.Outbound {
+			u, v := a.index[edge.Source.ID], a.index[edge.Target.ID]
+			if u == v {
+				continue
+			}
+			w := clampWeight(edge.Weight)
+			if cur, ok := best[u][v]; !ok || w > cur.weight ||
+				(w == cur.weight && edge.Relation < cur.relation) {
+				best[u][v] = link{to: v, weight: w, relation: edge.Relation}
+				best[v][u] = link{to: u, weight: w, relation: edge.Relation}
+			}
+		}
+	}
+
+	a.links = make([][]link, len(a.ids))
+	for i, m := range best {
+		for _, l := range m {
+			a.links[i] = append(a.links[i], l)
+		}
+		sort.Slice(a.links[i], func(x, y int) bool { return a.links[i][x].to < a.links[i][y].to })
+	}
+	return a
+}
+
+// clampWeight maps an edge weight into (0, 1] so it can be read as a
+// confidence. Unset weights count as certain.
+func clampWeight(w float64) float64 {
+	switch {
+	case w == 0 || w > 1:
+		return 1.0
+	case w < 0:
+		return 1e-6
+	}
+	return w
+}
+
+// ShortestPath finds the most confident undirected path between two nodes.
+// Edge cost is -ln(weight), so the path maximizes the product of weights.
+// Returns nil when either node is missing or they are not connected.
+func (g *ConceptGraph) ShortestPath(fromID, toID string) *Path {
+	a := g.undirected()
+	from, okFrom := a.index[fromID]
+	to, okTo := a.index[toID]
+	if !okFrom || !okTo {
+		return nil
+	}
+
+	dist := make([]float64, len(a.ids))
+	prev := make([]int, len(a.ids))
+	via := make([]string, len(a.ids))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[from] = 0
+
+	pq := &pathHeap{{node: from}}
+	for pq.Len() > 0 {
+		cur := heap.Pop(pq).(pathItem)
+		if cur.cost > dist[cur.node] {
+			continue
+		}
+		if cur.node == to {
+			break
+		}
+		for _, l := range a.links[cur.node] {
+			cost := cur.cost - math.Log(l.weight)
+			if cost < dist[l.to] {
+				dist[l.to] = cost
+				prev[l.to] = cur.node
+				via[l.to] = l.relation
+				heap.Push(pq, pathItem{node: l.to, cost: cost})
+			}
+		}
+	}
+	if math.IsInf(dist[to], 1) {
+		return nil
+	}
+
+	path := &Path{Confidence: math.Exp(-dist[to])}
+	for n := to; n != -1; n = prev[n] {
+		path.Nodes = append(path.Nodes, a.ids[n])
+		if prev[n] != -1 {
+			path.Relations = append(path.Relations, via[n])
+		}
+	}
+	reverseStrings(path.Nodes)
+	reverseStrings(path.Relations)
+	return path
+}
+
+// EgoNetwork returns the subgraph induced by the nodes within k undirected
+// hops of the given node (the node itself included). Edges are copied.
+func (g *ConceptGraph) EgoNetwork(id string, k int) *ConceptGraph {
+	out := NewGraph()
+	center := g.Nodes[id]
+	if center == nil {
+		return out
+	}
+
+	depth := map[string]int{id: 0}
+	frontier := []*ConceptNode{center}
+	for hop := 1; hop <= k && len(frontier) > 0; hop++ {
+		var next []*ConceptNode
+		for _, node := range frontier {
+			for _, n := range g.Neighbors(node.ID) {
+				if _, seen := depth[n.ID]; !seen {
+					depth[n.ID] = hop
+					next = append(next, n)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	for nid := range depth {
+		node := g.Nodes[nid]
+		out.EnsureNode(nid, node.Label, node.Kind)
+	}
+	for nid := range depth {
+		for _, edge := range g.Nodes[nid].Outbound {
+			if _, ok := depth[edge.Target.ID]; ok {
+				copied := *edge
+				out.AddEdge(out.Nodes[nid], out.Nodes[edge.Target.ID], &copied)
+			}
+		}
+	}
+	return out
+}
+
+// PageRank computes weighted PageRank over directed edges.
+// damping is typically 0.85; iteration stops after maxIter rounds or once
+// the total change drops below 1e-6. Dangling nodes spread rank uniformly.
+func (g *ConceptGraph) PageRank(damping float64, maxIter int) map[string]float64 {
+	n := len(g.Nodes)
+	result := make(map[string]float64, n)
+	if n == 0 {
+		return result
+	}
+
+	ids := g.sortedIDs()
+	index := make(map[string]int, n)
+	for i, id := range ids {
+		index[id] = i
+	}
+	outWeight := make([]float64, n)
+	for i, id := range ids {
+		for _, edge := range g.Nodes[id].Outbound {
+			outWeight[i] += clampWeight(edge.Weight)
+		}
+	}
+
+	rank := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1.0 / float64(n)
+	}
+	for iter := 0; iter < maxIter; iter++ {
+		dangling := 0.0
+		for i := range ids {
+			if outWeight[i] == 0 {
+				dangling += rank[i]
+			}
+		}
+
+		base := (1-damping)/float64(n) + damping*dangling/float64(n)
+		next := make([]float64, n)
+		for i := range next {
+			next[i] = base
+		}
+		for i, id := range ids {
+			if outWeight[i] == 0 {
+				continue
+			}
+			for _, edge := range g.Nodes[id].Outbound {
+				next[index[edge.Target.ID]] += damping * rank[i] * clampWeight(edge.Weight) / outWeight[i]
+			}
+		}
+
+		delta := 0.0
+		for i := range rank {
+			delta += math.Abs(next[i] - rank[i])
+		}
+		rank = next
+		if delta < 1e-6 {
+			break
+		}
+	}
+
+	for i, id := range ids {
+		result[id] = rank[i]
+	}
+	return result
+}
+
+// BetweennessCentrality computes normalized betweenness (Brandes) on the
+// undirected, unweighted graph: the share of shortest paths through a node
+func (g *ConceptGraph) BetweennessCentrality() map[string]float64 {
+	a := g.undirected()
+	n := len(a.ids)
+	cb := make([]float64, n)
+
+	for s := 0; s < n; s++ {
+		stack := make([]int, 0, n)
+		preds := make([][]int, n)
+		sigma := make([]float64, n)
+		dist := make([]int, n)
+		for i := range dist {
+			dist[i] = -1
+		}
+		sigma[s], dist[s] = 1, 0
+
+		queue := []int{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, l := range a.links[v] {
+				w := l.to
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+
+		delta := make([]float64, n)
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				cb[w] += delta[w]
+			}
+		}
+	}
+
+	result := make(map[string]float64, n)
+	// Each undirected pair was counted from both ends
+	norm := 0.0
+	if n > 2 {
+		norm = float64((n-1)*(n-2)) / 2
+	}
+	for i, id := range a.ids {
+		if norm > 0 {
+			result[id] = cb[i] / 2 / norm
+		} else {
+			result[id] = 0
+		}
+	}
+	return result
+}
+
+// Communities detects communities by weighted label propagation on the
+// undirected graph. IDs are stable: the same graph always yields the same
+// assignment, numbered from 0 by descending size, then by smallest member ID.
+func (g *ConceptGraph) Communities() map[string]int {
+	a := g.undirected()
+	n := len(a.ids)
+
+	label := make([]int, n)
+	for i := range label {
+		label[i] = i
+	}
+
+	// Nodes are visited in ID order; ties keep the current label, then
+	// prefer the smallest, so propagation is deterministic and converges
+	for iter := 0; iter < 100; iter++ {
+		changed := false
+		for v := 0; v < n; v++ {
+			if len(a.links[v]) == 0 {
+				continue
+			}
+			score := make(map[int]float64)
+			for _, l := range a.links[v] {
+				score[label[l.to]] += l.weight
+			}
+			best, bestScore := label[v], score[label[v]]
+			for lbl, sc := range score {
+				if sc > bestScore || (sc == bestScore && lbl < best && best != label[v]) {
+					best, bestScore = lbl, sc
+				}
+			}
+			if best != label[v] {
+				label[v] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	// Renumber: bigger communities first, ties by smallest member (ids are sorted)
+	members := make(map[int][]int)
+	for v, lbl := range label {
+		members[lbl] = append(members[lbl], v)
+	}
+	labels := make([]int, 0, len(members))
+	for lbl := range members {
+		labels = append(labels, lbl)
+	}
+	sort.Slice(labels, func(x, y int) bool {
+		mx, my := members[labels[x]], members[labels[y]]
+		if len(mx) != len(my) {
+			return len(mx) > len(my)
+		}
+		return mx[0] < my[0]
+	})
+
+	result := make(map[string]int, n)
+	for id, lbl := range labels {
+		for _, v := range members[lbl] {
+			result[a.ids[v]] = id
+		}
+	}
+	return result
+}
+
+// sortedIDs returns node IDs in sorted order
+func (g *ConceptGraph) sortedIDs() []string {
+	ids := make([]string, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func reverseStrings(s []string) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+type pathItem struct {
+	node int
+	cost float64
+}
+
+type pathHeap []pathItem
+
+func (h pathHeap) Len() int { return len(h) }
+func (h pathHeap) Less(i, j int) bool {
+	if h[i].cost != h[j].cost {
+		return h[i].cost < h[j].cost
+	}
+	return h[i].node < h[j].node
+}
+func (h pathHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *pathHeap) Push(x interface{}) { *h = append(*h, x.(pathItem)) }
+func (h *pathHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
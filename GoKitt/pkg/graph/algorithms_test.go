@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"math"
+	"testing"
+)
+
+// twoCliques builds two triangles (a,b,c) and (x,y,z) joined by c -> x
+func twoCliques() *ConceptGraph {
+	g := NewGraph()
+	for _, id := range []string{"a", "b", "c", "x", "y", "z"} {
+		g.EnsureNode(id, id, "CHARACTER")
+	}
+	link := func(s, t string, w float64) {
+		g.AddLabeledEdge(s, t, "KNOWS", w)
+	}
+	link("a", "b", 1.0)
+	link("b", "c", 1.0)
+	link("c", "a", 1.0)
+	link("x", "y", 1.0)
+	link("y", "z", 1.0)
+	link("z", "x", 1.0)
+	link("c", "x", 0.5)
+	return g
+}
+
+func TestShortestPath(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"arin", "lyra", "bram", "guild"} {
+		g.EnsureNode(id, id, "CHARACTER")
+	}
+	// Direct but weak vs. two confident hops
+	g.AddLabeledEdge("arin", "guild", "RUMORED_IN", 0.2)
+	g.AddLabeledEdge("arin", "lyra", "LOVES", 0.9)
+	g.AddLabeledEdge("guild", "lyra", "EMPLOYS", 0.9) // Traversed against direction
+
+	path := g.ShortestPath("arin", "guild")
+	if path == nil {
+		t.Fatal("Expected a path")
+	}
+	if len(path.Nodes) != 3 || path.Nodes[1] != "lyra" {
+		t.Errorf("Expected the confident route via lyra, got %v", path.Nodes)
+	}
+	if path.Relations[0] != "LOVES" || path.Relations[1] != "EMPLOYS" {
+		t.Errorf("Unexpected relations %v", path.Relations)
+	}
+	if math.Abs(path.Confidence-0.81) > 1e-9 {
+		t.Errorf("Confidence = %f, want 0.81", path.Confidence)
+	}
+
+	if g.ShortestPath("arin", "bram") != nil {
+		t.Error("Expected no path to a disconnected node")
+	}
+	if p := g.ShortestPath("arin", "arin"); p == nil || len(p.Nodes) != 1 || p.Confidence != 1 {
+		t.Errorf("Expected trivial path to self, got %+v", p)
+	}
+}
+
+func TestEgoNetwork(t *testing.T) {
+	g := twoCliques()
+
+	ego := g.EgoNetwork("a", 1)
+	if ego.NodeCount() != 3 || ego.EdgeCount() != 3 {
+		t.Errorf("1-hop ego of a: %d nodes / %d edges, want 3 / 3", ego.NodeCount(), ego.EdgeCount())
+	}
+	if ego := g.EgoNetwork("a", 2); ego.NodeCount() != 4 || ego.GetNode("x") == nil {
+		t.Errorf("Expected 2-hop ego to reach x, got %d nodes", ego.NodeCount())
+	}
+	if ego.GetNode("a").Outbound[0] == g.GetNode("a").Outbound[0] {
+		t.Error("Expected ego edges to be copies")
+	}
+	if g.EgoNetwork("missing", 2).NodeCount() != 0 {
+		t.Error("Expected empty ego network for a missing node")
+	}
+}
+
+func TestCentrality(t *testing.T) {
+	g := twoCliques()
+
+	bc := g.BetweennessCentrality()
+	if bc["c"] <= bc["a"] || bc["x"] <= bc["y"] {
+		t.Errorf("Expected bridge nodes to have higher betweenness, got %v", bc)
+	}
+	// c lies on the 6 a/b-to-x/y/z paths out of the 10 pairs excluding it
+	if math.Abs(bc["c"]-0.6) > 1e-9 {
+		t.Errorf("Betweenness(c) = %f, want 0.6", bc["c"])
+	}
+
+	// Everything flows into the sink
+	star := NewGraph()
+	for _, id := range []string{"sink", "p", "q", "r"} {
+		star.EnsureNode(id, id, "TEST")
+	}
+	for _, id := range []string{"p", "q", "r"} {
+		star.AddLabeledEdge(id, "sink", "LINKS", 1.0)
+	}
+	pr := star.PageRank(0.85, 100)
+	total := 0.0
+	for _, r := range pr {
+		total += r
+	}
+	if math.Abs(total-1) > 1e-6 {
+		t.Errorf("PageRank sums to %f, want 1", total)
+	}
+	if pr["sink"] <= pr["p"] {
+		t.Errorf("Expected sink to rank highest, got %v", pr)
+	}
+}
+
+func TestCommunities(t *testing.T) {
+	g := twoCliques()
+	g.EnsureNode("loner", "loner", "CHARACTER")
+
+	comm := g.Communities()
+	if comm["a"] != comm["b"] || comm["b"] != comm["c"] {
+		t.Errorf("Expected a, b, c together, got %v", comm)
+	}
+	if comm["x"] != comm["y"] || comm["y"] != comm["z"] {
+		t.Errorf("Expected x, y, z together, got %v", comm)
+	}
+	if comm["a"] == comm["x"] {
+		t.Errorf("Expected the cliques apart, got %v", comm)
+	}
+	// Stable IDs: largest first, ties by smallest member, singletons last
+	if comm["a"] != 0 || comm["x"] != 1 || comm["loner"] != 2 {
+		t.Errorf("Unexpected community IDs %v", comm)
+	}
+
+	for i := 0; i < 5; i++ {
+		again := g.Communities()
+		for id, c := range comm {
+			if again[id] != c {
+				t.Fatalf("Community IDs changed between runs: %v vs %v", comm, again)
+			}
+		}
+	}
+}
+
+func TestFilterHierarchy(t *testing.T) {
+	g := twoCliques()
+	world := g.EnsureNode("world:note-1", "Note", KindWorld)
+	g.AddEdge(world, g.GetNode("a"), &ConceptEdge{Relation: RelWorldContains, Weight: 1})
+	g.AddEdge(world, g.GetNode("z"), &ConceptEdge{Relation: RelWorldContains, Weight: 1})
+
+	story := g.Filter(func(e *ConceptEdge) bool { return !IsHierarchy(e.Relation) })
+	if story.EdgeCount() != 7 || g.EdgeCount() != 9 {
+		t.Errorf("Expected hierarchy edges filtered from a copy, got %d / %d", story.EdgeCount(), g.EdgeCount())
+	}
+	if p := story.ShortestPath("a", "z"); p == nil || len(p.Nodes) != 4 {
+		t.Errorf("Expected the story path a-c-x-z, got %+v", p)
+	}
+}
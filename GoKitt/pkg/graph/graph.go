@@ -19,6 +19,7 @@ const (
 	RelWormhole      = "WORMHOLE"
 	RelContainsWorld = "CONTAINS_WORLD" // Folder -> World
 	RelWorldContains = "WORLD_CONTAINS" // World -> Entity
+	RelLinksTo       = "LINKS_TO"       // World -> World (wikilink)
 )
 
 // ConceptNode represents an entity in the graph
@@ -36,8 +37,9 @@ type ConceptNode struct {
 type ConceptEdge struct {
 	Relation   string  `json:"relation"`
 	Weight     float64 `json:"weight"`
-	SourceDoc  string  `json:"sourceDoc"`
-	SourceSpan [2]int  `json:"sourceSpan"`
+	SourceDoc  string  `json:"sourceDoc"`  // Note ID the edge was read from
+	SourceSpan [2]int  `json:"sourceSpan"` // Sentence byte range [start, end)
+	VerbSpan   [2]int  `json:"verbSpan"`   // Verb (or predicate) byte range [start, end)
 
 	// QuadPlus modifiers
 	Manner    string `json:"manner,omitempty"`
@@ -45,6 +47,18 @@ type ConceptEdge struct {
 	Time      string `json:"time,omitempty"`
 	Recipient string `json:"recipient,omitempty"`
 
+	// Factuality frame (empty Modality means factual)
+	Negated  bool   `json:"negated,omitempty"`
+	Modality string `json:"modality,omitempty"`
+	Tense    string `json:"tense,omitempty"`
+
+	// Authored edges were written explicitly by the user (triples, wikilinks)
+	// rather than derived from prose
+	Authored bool `json:"authored,omitempty"`
+
+	// Story-time validity (nil = holds throughout the narrative)
+	Valid *Interval `json:"valid,omitempty"`
+
 	// Pointers to nodes
 	Source *ConceptNode `json:"-"`
 	Target *ConceptNode `json:"-"`
@@ -60,14 +74,23 @@ type ConceptGraph struct {
 
 // SerializableEdge is a JSON-friendly edge representation
 type SerializableEdge struct {
-	Source    string  `json:"source"`
-	Target    string  `json:"target"`
-	Relation  string  `json:"relation"`
-	Weight    float64 `json:"weight"`
-	Manner    string  `json:"manner,omitempty"`
-	Location  string  `json:"location,omitempty"`
-	Time      string  `json:"time,omitempty"`
-	Recipient string  `json:"recipient,omitempty"`
+	Source    string    `json:"source"`
+	Target    string    `json:"target"`
+	Relation  string    `json:"relation"`
+	Weight    float64   `json:"weight"`
+	Manner    string    `json:"manner,omitempty"`
+	Location  string    `json:"location,omitempty"`
+	Time      string    `json:"time,omitempty"`
+	Recipient string    `json:"recipient,omitempty"`
+	Negated   bool      `json:"negated,omitempty"`
+	Modality  string    `json:"modality,omitempty"`
+	Tense     string    `json:"tense,omitempty"`
+	Authored  bool      `json:"authored,omitempty"`
+	Valid     *Interval `json:"valid,omitempty"`
+
+	SourceDoc  string `json:"sourceDoc,omitempty"`
+	SourceSpan [2]int `json:"sourceSpan"`
+	VerbSpan   [2]int `json:"verbSpan"`
 }
 
 // NewGraph creates an empty graph
@@ -92,6 +115,15 @@ func (g *ConceptGraph) ToSerializable() {
 				Location:  edge.Location,
 				Time:      edge.Time,
 				Recipient: edge.Recipient,
+				Negated:   edge.Negated,
+				Modality:  edge.Modality,
+				Tense:     edge.Tense,
+				Authored:  edge.Authored,
+				Valid:     edge.Valid,
+
+				SourceDoc:  edge.SourceDoc,
+				SourceSpan: edge.SourceSpan,
+				VerbSpan:   edge.VerbSpan,
 			})
 		}
 	}
@@ -144,14 +176,15 @@ func (g *ConceptGraph) AddQuad(
 	g.AddQuadPlus(sourceID, sourceLabel, sourceKind, targetID, targetLabel, targetKind, relation, weight, manner, location, time, "")
 }
 
-// AddQuadPlus adds an edge with all modifiers including Recipient
+// AddQuadPlus adds an edge with all modifiers including Recipient.
+// The created edge is returned so callers can attach extra annotations.
 func (g *ConceptGraph) AddQuadPlus(
 	sourceID, sourceLabel, sourceKind string,
 	targetID, targetLabel, targetKind string,
 	relation string,
 	weight float64,
 	manner, location, time, recipient string,
-) {
+) *ConceptEdge {
 	source := g.EnsureNode(sourceID, sourceLabel, sourceKind)
 	target := g.EnsureNode(targetID, targetLabel, targetKind)
 
@@ -164,6 +197,7 @@ func (g *ConceptGraph) AddQuadPlus(
 		Recipient: recipient,
 	}
 	g.AddEdge(source, target, edge)
+	return edge
 }
 
 // AddLabeledEdge adds an edge between existing nodes by ID with a relation and weight
@@ -100,3 +100,32 @@ func TestDegreeCentrality(t *testing.T) {
 		t.Error("Hub should have higher centrality than leaf nodes")
 	}
 }
+
+func TestInterval(t *testing.T) {
+	// "Arin ruled Eldoria (note 1) until the war (note 5)"
+	ruled := NewInterval(StoryPoint(1), StoryPoint(5))
+	for at, want := range map[float64]bool{0: false, 1: true, 4.5: true, 5: false, 9: false} {
+		if got := ruled.Contains(at); got != want {
+			t.Errorf("Contains(%v) = %v, want %v", at, got, want)
+		}
+	}
+
+	var always *Interval
+	if !always.Contains(-100) || NewInterval(nil, nil) != nil {
+		t.Error("Expected a nil interval to hold everywhere")
+	}
+
+	hull := ruled.Hull(NewInterval(StoryPoint(3), StoryPoint(8)))
+	if !hull.Equal(NewInterval(StoryPoint(1), StoryPoint(8))) {
+		t.Errorf("Hull = %+v, want [1, 8)", hull)
+	}
+	if open := ruled.Hull(NewInterval(StoryPoint(3), nil)); open.Until != nil || *open.From != 1 {
+		t.Errorf("Expected an open end to win, got %+v", open)
+	}
+	if !ruled.Overlaps(NewInterval(StoryPoint(4), nil)) || ruled.Overlaps(NewInterval(StoryPoint(5), nil)) {
+		t.Error("Expected [1, 5) to overlap [4, ...) but not [5, ...)")
+	}
+	if ruled.Hull(nil) != nil {
+		t.Error("Expected hull with an unbounded interval to be unbounded")
+	}
+}
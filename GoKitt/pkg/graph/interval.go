@@ -0,0 +1,82 @@
+package graph
+
+// Interval is a story-time validity range [From, Until).
+// Story time is narrative order: note Order, or the position of the note a
+// time expression ("until the war") resolves to. A nil bound is open.
+type Interval struct {
+	From  *float64 `json:"from,omitempty"`
+	Until *float64 `json:"until,omitempty"`
+}
+
+// NewInterval creates an interval from optional bounds
+func NewInterval(from, until *float64) *Interval {
+	if from == nil && until == nil {
+		return nil
+	}
+	return &Interval{From: from, Until: until}
+}
+
+// StoryPoint returns a pointer to t, for building interval bounds
+func StoryPoint(t float64) *float64 {
+	return &t
+}
+
+// Contains reports whether the interval holds at story point t.
+// A nil interval holds everywhere.
+func (iv *Interval) Contains(t float64) bool {
+	if iv == nil {
+		return true
+	}
+	if iv.From != nil && t < *iv.From {
+		return false
+	}
+	if iv.Until != nil && t >= *iv.Until {
+		return false
+	}
+	return true
+}
+
+// Overlaps reports whether two intervals share any story point.
+// A nil interval overlaps everything.
+func (iv *Interval) Overlaps(o *Interval) bool {
+	if iv == nil || o == nil {
+		return true
+	}
+	if iv.From != nil && o.Until != nil && *iv.From >= *o.Until {
+		return false
+	}
+	if o.From != nil && iv.Until != nil && *o.From >= *iv.Until {
+		return false
+	}
+	return true
+}
+
+// Hull returns the smallest interval covering both; open bounds win
+func (iv *Interval) Hull(o *Interval) *Interval {
+	if iv == nil || o == nil {
+		return nil
+	}
+	var from, until *float64
+	if iv.From != nil && o.From != nil {
+		from = StoryPoint(min(*iv.From, *o.From))
+	}
+	if iv.Until != nil && o.Until != nil {
+		until = StoryPoint(max(*iv.Until, *o.Until))
+	}
+	return NewInterval(from, until)
+}
+
+// Equal reports whether two intervals have the same bounds
+func (iv *Interval) Equal(o *Interval) bool {
+	if iv == nil || o == nil {
+		return iv == o
+	}
+	return sameBound(iv.From, o.From) && sameBound(iv.Until, o.Until)
+}
+
+func sameBound(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -8,6 +8,18 @@ type ProvenanceContext struct {
 	WorldID    string // Note ID (World)
 	ParentPath string // For debugging / label generation
 	FolderType string // Galaxy type (empty = SolarSystem)
+
+	// ResolveLink maps a wikilink target (note title) to its note ID.
+	// Optional; unresolved targets keep the title as their ID.
+	ResolveLink func(target string) string
+
+	// StoryTime is the note's position in the narrative (note Order).
+	// Optional; nil leaves projected edges unanchored in story time.
+	StoryTime *float64
+
+	// ResolveTime maps a time expression ("the war") to a story point.
+	// Optional; unresolved expressions leave that bound open.
+	ResolveTime func(expr string) (float64, bool)
 }
 
 // WormholeSpec defines a cross-world link input
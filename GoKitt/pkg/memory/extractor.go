@@ -0,0 +1,417 @@
+// Package memory extracts typed long-term memories (facts, preferences,
+// entity mentions, relations) from chat threads into the memories table,
+// links them to their source message and to known entities, and recalls
+// them across threads by entity.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kittclouds/gokitt/internal/ids"
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/batch"
+	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+)
+
+// Outcome statuses
+const (
+	StatusCreated  = "created"  // Stored as a new memory
+	StatusMerged   = "merged"   // Matched an existing memory, now linked to this thread
+	StatusRejected = "rejected" // Dropped; see Reason
+)
+
+// Config tunes extraction.
+type Config struct {
+	MinConfidence  float64 // Candidates below this are rejected (default 0.5)
+	DedupThreshold float64 // Word overlap at which two memories are the same (default 0.8)
+}
+
+// DefaultConfig returns the default thresholds.
+func DefaultConfig() Config {
+	return Config{MinConfidence: 0.5, DedupThreshold: 0.8}
+}
+
+// Outcome reports what happened to one extracted memory.
+type Outcome struct {
+	Memory    *store.Memory `json:"memory"`
+	MessageID string        `json:"messageId,omitempty"`
+	Status    string        `json:"status"`
+	Reason    string        `json:"reason,omitempty"`
+}
+
+// Result reports an extraction pass over a thread.
+type Result struct {
+	ThreadID          string    `json:"threadId"`
+	MessagesProcessed int       `json:"messagesProcessed"`
+	Created           int       `json:"created"`
+	Merged            int       `json:"merged"`
+	Outcomes          []Outcome `json:"outcomes"`
+}
+
+// Recalled is a memory with the threads it was seen in.
+type Recalled struct {
+	*store.Memory
+	Threads []string `json:"threads"`
+}
+
+// Extractor turns thread messages into stored memories.
+type Extractor struct {
+	llm   batch.Completer
+	store store.Storer
+	cfg   Config
+}
+
+// NewExtractor creates an extractor. Zero thresholds fall back to DefaultConfig.
+func NewExtractor(llm batch.Completer, s store.Storer, cfg Config) *Extractor {
+	def := DefaultConfig()
+	if cfg.MinConfidence <= 0 {
+		cfg.MinConfidence = def.MinConfidence
+	}
+	if cfg.DedupThreshold <= 0 {
+		cfg.DedupThreshold = def.DedupThreshold
+	}
+	return &Extractor{llm: llm, store: s, cfg: cfg}
+}
+
+// ProcessThread extracts memories from the thread's messages newer than its
+// memory cursor, then advances the cursor past them. Each memory is linked
+// to its source message and, through the implicit dictionary, to the store
+// entity it is about. A memory matching an existing one of the same type is
+// not stored twice: the existing memory is linked to this thread instead,
+// keeping the higher confidence.
+func (e *Extractor) ProcessThread(ctx context.Context, threadID string) (*Result, error) {
+	result := &Result{ThreadID: threadID, Outcomes: make([]Outcome, 0)}
+	if e.llm == nil {
+		return nil, fmt.Errorf("memory: LLM not configured")
+	}
+
+	pending, err := e.pending(threadID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	raw, err := e.llm.Complete(ctx, BuildUserPrompt(pending), SystemPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("memory: LLM call failed: %w", err)
+	}
+	candidates, err := parseResponse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	dict, err := e.dictionary()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[store.MemoryType][]*store.Memory)
+	for _, c := range candidates {
+		outcome, err := e.apply(c, threadID, pending, dict, existing)
+		if err != nil {
+			return nil, err
+		}
+		switch outcome.Status {
+		case StatusCreated:
+			result.Created++
+		case StatusMerged:
+			result.Merged++
+		}
+		result.Outcomes = append(result.Outcomes, outcome)
+	}
+
+	last := pending[len(pending)-1]
+	if err := e.store.SetMemoryCursor(threadID, last.ID, last.CreatedAt); err != nil {
+		return nil, fmt.Errorf("memory: save cursor: %w", err)
+	}
+	result.MessagesProcessed = len(pending)
+	return result, nil
+}
+
+// RecallByEntity returns the memories about an entity from every thread
+// except excludeThreadID (empty keeps all), most confident first.
+func (e *Extractor) RecallByEntity(entityID, excludeThreadID string) ([]*Recalled, error) {
+	memories, err := e.store.ListMemoriesByEntity(entityID)
+	if err != nil {
+		return nil, fmt.Errorf("memory: list by entity: %w", err)
+	}
+
+	recalled := make([]*Recalled, 0, len(memories))
+	for _, m := range memories {
+		links, err := e.store.GetMemoryThreads(m.ID)
+		if err != nil {
+			return nil, fmt.Errorf("memory: get threads: %w", err)
+		}
+		threads := make([]string, 0, len(links))
+		other := excludeThreadID == ""
+		for _, l := range links {
+			threads = append(threads, l.ThreadID)
+			if l.ThreadID != excludeThreadID {
+				other = true
+			}
+		}
+		if other {
+			recalled = append(recalled, &Recalled{Memory: m, Threads: threads})
+		}
+	}
+	return recalled, nil
+}
+
+// RecallForText finds the known entities mentioned in text and recalls
+// their memories from other threads, in order of first mention, up to
+// limit memories (0 = no limit).
+func (e *Extractor) RecallForText(text, excludeThreadID string, limit int) ([]*Recalled, error) {
+	dict, err := e.dictionary()
+	if err != nil || dict == nil {
+		return make([]*Recalled, 0), err
+	}
+
+	recalled := make([]*Recalled, 0)
+	seenEntity := make(map[string]bool)
+	seenMemory := make(map[string]bool)
+	for _, m := range dict.ScanWithInfo(text) {
+		for _, info := range m.Entities {
+			if seenEntity[info.ID] {
+				continue
+			}
+			seenEntity[info.ID] = true
+
+			memories, err := e.RecallByEntity(info.ID, excludeThreadID)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range memories {
+				if seenMemory[r.ID] {
+					continue
+				}
+				seenMemory[r.ID] = true
+				recalled = append(recalled, r)
+				if limit > 0 && len(recalled) >= limit {
+					return recalled, nil
+				}
+			}
+		}
+	}
+	return recalled, nil
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// apply validates one candidate and stores or merges it
+func (e *Extractor) apply(
+	c candidate,
+	threadID string,
+	pending []*store.ThreadMessage,
+	dict *implicitmatcher.RuntimeDictionary,
+	existing map[store.MemoryType][]*store.Memory,
+) (Outcome, error) {
+	source := pending[len(pending)-1]
+	if c.Message >= 1 && c.Message <= len(pending) {
+		source = pending[c.Message-1]
+	}
+	now := time.Now().UnixMilli()
+	memory := &store.Memory{
+		Content:    strings.TrimSpace(c.Content),
+		Confidence: min(max(c.Confidence, 0), 1),
+		SourceRole: source.Role,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	outcome := Outcome{Memory: memory, MessageID: source.ID}
+
+	memoryType, ok := parseType(c.Type)
+	memory.MemoryType = memoryType
+	switch {
+	case len(wordSet(memory.Content)) == 0:
+		outcome.Status, outcome.Reason = StatusRejected, "no words"
+		return outcome, nil
+	case !ok:
+		outcome.Status, outcome.Reason = StatusRejected, fmt.Sprintf("unknown type %q", c.Type)
+		return outcome, nil
+	case memory.Confidence < e.cfg.MinConfidence:
+		outcome.Status = StatusRejected
+		outcome.Reason = fmt.Sprintf("confidence %.2f below %.2f", memory.Confidence, e.cfg.MinConfidence)
+		return outcome, nil
+	}
+	memory.EntityID = linkEntity(dict, c.Entity, memory.Content)
+
+	if _, loaded := existing[memoryType]; !loaded {
+		list, err := e.store.ListMemoriesByType(memoryType)
+		if err != nil {
+			return outcome, fmt.Errorf("memory: list %s: %w", memoryType, err)
+		}
+		existing[memoryType] = list
+	}
+
+	if dup := e.findDuplicate(existing[memoryType], memory); dup != nil {
+		if err := e.store.LinkMemory(dup.ID, threadID, source.ID, now); err != nil {
+			return outcome, fmt.Errorf("memory: link: %w", err)
+		}
+		changed := false
+		if memory.Confidence > dup.Confidence {
+			dup.Confidence, changed = memory.Confidence, true
+		}
+		if dup.EntityID == "" && memory.EntityID != "" {
+			dup.EntityID, changed = memory.EntityID, true
+		}
+		if changed {
+			dup.UpdatedAt = now
+			if err := e.store.UpdateMemory(dup); err != nil {
+				return outcome, fmt.Errorf("memory: update: %w", err)
+			}
+		}
+		outcome.Memory, outcome.Status = dup, StatusMerged
+		return outcome, nil
+	}
+
+	memory.ID = ids.New()
+	if err := e.store.CreateMemory(memory, threadID, source.ID); err != nil {
+		return outcome, fmt.Errorf("memory: create: %w", err)
+	}
+	existing[memoryType] = append(existing[memoryType], memory)
+	outcome.Status = StatusCreated
+	return outcome, nil
+}
+
+// pending returns the user and assistant messages past the thread's memory
+// cursor, stopping at the first message still streaming
+func (e *Extractor) pending(threadID string) ([]*store.ThreadMessage, error) {
+	lastID, lastAt, err := e.store.GetMemoryCursor(threadID)
+	if err != nil {
+		return nil, fmt.Errorf("memory: get cursor: %w", err)
+	}
+	messages, err := e.store.GetThreadMessagesAfter(threadID, lastID, lastAt)
+	if err != nil {
+		return nil, fmt.Errorf("memory: get messages: %w", err)
+	}
+
+	pending := make([]*store.ThreadMessage, 0)
+	for _, m := range messages {
+		if m.IsStreaming {
+			break
+		}
+		if (m.Role == "user" || m.Role == "assistant") && m.Content != "" {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// dictionary compiles the stored entities into an implicit dictionary,
+// or returns nil when there are none
+func (e *Extractor) dictionary() (*implicitmatcher.RuntimeDictionary, error) {
+	entities, err := e.store.ListEntities("")
+	if err != nil {
+		return nil, fmt.Errorf("memory: list entities: %w", err)
+	}
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	registered := make([]implicitmatcher.RegisteredEntity, 0, len(entities))
+	for _, en := range entities {
+		registered = append(registered, implicitmatcher.RegisteredEntity{
+			ID:          en.ID,
+			Label:       en.Label,
+			Aliases:     en.Aliases,
+			Kind:        en.Kind,
+			NarrativeID: en.NarrativeID,
+		})
+	}
+	dict, err := implicitmatcher.Compile(registered)
+	if err != nil {
+		return nil, fmt.Errorf("memory: dictionary: %w", err)
+	}
+	return dict, nil
+}
+
+// linkEntity resolves the entity a memory is about: the LLM's entity name
+// when the dictionary knows it, otherwise the first entity mentioned in
+// the content
+func linkEntity(dict *implicitmatcher.RuntimeDictionary, name, content string) string {
+	if dict == nil {
+		return ""
+	}
+	if name != "" {
+		if infos := dict.Lookup(name); len(infos) > 0 {
+			return best(dict, infos)
+		}
+	}
+
+	var first []*implicitmatcher.EntityInfo
+	start, length := -1, 0
+	for _, m := range dict.ScanWithInfo(content) {
+		if len(m.Entities) == 0 {
+			continue
+		}
+		if start < 0 || m.Start < start || (m.Start == start && m.End-m.Start > length) {
+			first, start, length = m.Entities, m.Start, m.End-m.Start
+		}
+	}
+	if first == nil {
+		return ""
+	}
+	return best(dict, first)
+}
+
+// best picks the highest-priority entity among infos
+func best(dict *implicitmatcher.RuntimeDictionary, infos []*implicitmatcher.EntityInfo) string {
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.ID)
+	}
+	if info := dict.SelectBest(ids); info != nil {
+		return info.ID
+	}
+	return ""
+}
+
+// findDuplicate returns the existing memory m restates: same words, or a
+// word overlap of at least DedupThreshold, about the same entity (or
+// without one on either side)
+func (e *Extractor) findDuplicate(candidates []*store.Memory, m *store.Memory) *store.Memory {
+	words := wordSet(m.Content)
+	for _, c := range candidates {
+		if c.EntityID != "" && m.EntityID != "" && c.EntityID != m.EntityID {
+			continue
+		}
+		if jaccard(words, wordSet(c.Content)) >= e.cfg.DedupThreshold {
+			return c
+		}
+	}
+	return nil
+}
+
+// wordSet lowercases text and splits it into its set of words
+func wordSet(text string) map[string]bool {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		set[f] = true
+	}
+	return set
+}
+
+// jaccard is the word overlap of two sets; empty sets overlap nothing
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/internal/testutil"
+)
+
+func newTestStore(t *testing.T) store.Storer {
+	t.Helper()
+	st := testutil.NewStore(t, "t1", "t2")
+	st.UpsertEntity(&store.Entity{ID: "e-lyra", Label: "Lyra Vance", Kind: "CHARACTER", Aliases: []string{"Lyra"}})
+	st.UpsertEntity(&store.Entity{ID: "e-eldoria", Label: "Eldoria", Kind: "LOCATION", Aliases: []string{}})
+	return st
+}
+
+func TestProcessThread_CreatesTypedLinkedMemories(t *testing.T) {
+	st := newTestStore(t)
+	llm := &testutil.Scripted{Replies: []string{"```json\n" + `{"memories": [
+		{"content": "Lyra is afraid of the sea", "type": "fact", "confidence": 0.9, "entity": "Lyra", "message": 1},
+		{"content": "The user prefers present tense", "type": "PREFERENCE", "confidence": 0.8, "message": 2},
+		{"content": "Eldoria is the capital", "type": "fact", "confidence": 0.85, "message": 3},
+		{"content": "Maybe it rains", "type": "fact", "confidence": 0.2, "message": 1},
+		{"content": "Something odd", "type": "gossip", "confidence": 0.9, "message": 1},
+		{"content": "...", "type": "fact", "confidence": 0.9, "message": 1}
+	]}` + "\n```"}}
+	ex := NewExtractor(llm, st, Config{})
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Lyra is afraid of the sea.", 10)
+	testutil.AddMessage(t, st, "t1", "m2", "user", "Please write in present tense.", 11)
+	testutil.AddMessage(t, st, "t1", "m3", "assistant", "Noted. Eldoria is the capital, right?", 12)
+
+	result, err := ex.ProcessThread(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("ProcessThread failed: %v", err)
+	}
+	if result.MessagesProcessed != 3 || result.Created != 3 || len(result.Outcomes) != 6 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if !strings.Contains(llm.Prompts[0], "[3] assistant: Noted.") {
+		t.Errorf("Expected numbered messages in the prompt, got %s", llm.Prompts[0])
+	}
+
+	lyra := result.Outcomes[0]
+	if lyra.MessageID != "m1" || lyra.Memory.EntityID != "e-lyra" || lyra.Memory.SourceRole != "user" {
+		t.Errorf("Expected the fact linked to m1 and Lyra, got %+v / %+v", lyra, lyra.Memory)
+	}
+	if pref := result.Outcomes[1].Memory; pref.MemoryType != store.MemoryTypePreference || pref.EntityID != "" {
+		t.Errorf("Expected an unlinked preference, got %+v", pref)
+	}
+	// No entity hint: linked from the content through the dictionary
+	if capital := result.Outcomes[2]; capital.Memory.EntityID != "e-eldoria" || capital.Memory.SourceRole != "assistant" {
+		t.Errorf("Expected the capital linked to Eldoria, got %+v", capital.Memory)
+	}
+	for _, o := range result.Outcomes[3:] {
+		if o.Status != StatusRejected || o.Reason == "" {
+			t.Errorf("Expected %q rejected with a reason, got %+v", o.Memory.Content, o)
+		}
+	}
+
+	memories, _ := st.GetMemoriesForThread("t1")
+	if len(memories) != 3 {
+		t.Errorf("Expected 3 stored memories, got %d", len(memories))
+	}
+	links, _ := st.GetMemoryThreads(lyra.Memory.ID)
+	if len(links) != 1 || links[0].MessageID != "m1" {
+		t.Errorf("Expected the memory linked to its source message, got %+v", links)
+	}
+
+	// Nothing new: no LLM call
+	if result, err := ex.ProcessThread(context.Background(), "t1"); err != nil || result.MessagesProcessed != 0 || len(llm.Prompts) != 1 {
+		t.Errorf("Expected no work without new messages, got %+v, %v", result, err)
+	}
+}
+
+func TestProcessThread_DeduplicatesAcrossThreads(t *testing.T) {
+	st := newTestStore(t)
+	llm := &testutil.Scripted{Replies: []string{
+		`{"memories": [{"content": "Lyra is afraid of the sea.", "type": "fact", "confidence": 0.6, "entity": "Lyra", "message": 1}]}`,
+		`{"memories": [
+			{"content": "lyra is afraid of the sea", "type": "fact", "confidence": 0.9, "message": 1},
+			{"content": "lyra is afraid of the sea", "type": "fact", "confidence": 0.7, "message": 1}
+		]}`,
+	}}
+	ex := NewExtractor(llm, st, DefaultConfig())
+	ctx := context.Background()
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Lyra is afraid of the sea.", 10)
+	if _, err := ex.ProcessThread(ctx, "t1"); err != nil {
+		t.Fatalf("ProcessThread failed: %v", err)
+	}
+
+	testutil.AddMessage(t, st, "t2", "m2", "user", "Remember, Lyra fears the sea.", 20)
+	result, err := ex.ProcessThread(ctx, "t2")
+	if err != nil {
+		t.Fatalf("ProcessThread failed: %v", err)
+	}
+	if result.Created != 0 || result.Merged != 2 {
+		t.Fatalf("Expected both candidates merged into the existing memory, got %+v", result)
+	}
+
+	facts, _ := st.ListMemoriesByType(store.MemoryTypeFact)
+	if len(facts) != 1 || facts[0].Confidence != 0.9 {
+		t.Fatalf("Expected one fact with the higher confidence, got %+v", facts)
+	}
+	links, _ := st.GetMemoryThreads(facts[0].ID)
+	if len(links) != 2 || links[1].ThreadID != "t2" || links[1].MessageID != "m2" {
+		t.Errorf("Expected the memory linked to both threads, got %+v", links)
+	}
+}
+
+func TestProcessThread_SameMillisecondMessageNotSkipped(t *testing.T) {
+	st := newTestStore(t)
+	llm := &testutil.Scripted{Replies: []string{`{"memories": []}`, `{"memories": []}`}}
+	ex := NewExtractor(llm, st, DefaultConfig())
+	ctx := context.Background()
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Lyra is afraid of the sea.", 10)
+	if _, err := ex.ProcessThread(ctx, "t1"); err != nil {
+		t.Fatalf("ProcessThread failed: %v", err)
+	}
+	testutil.AddMessage(t, st, "t1", "m2", "assistant", "Noted, she fears the sea.", 10)
+	result, err := ex.ProcessThread(ctx, "t1")
+	if err != nil || result.MessagesProcessed != 1 {
+		t.Fatalf("Expected the reply processed, got %+v, %v", result, err)
+	}
+	if !strings.Contains(llm.Prompts[1], "[1] assistant: Noted") {
+		t.Errorf("Expected only the new message in the prompt, got %s", llm.Prompts[1])
+	}
+}
+
+func TestProcessThread_LLMErrorKeepsCursor(t *testing.T) {
+	st := newTestStore(t)
+	ex := NewExtractor(&testutil.Scripted{}, st, DefaultConfig())
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Lyra is afraid of the sea.", 10)
+	if _, err := ex.ProcessThread(context.Background(), "t1"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if id, _, _ := st.GetMemoryCursor("t1"); id != "" {
+		t.Errorf("Expected the cursor unchanged, got %q", id)
+	}
+}
+
+func TestRecall_CrossThreadByEntity(t *testing.T) {
+	st := newTestStore(t)
+	st.CreateMemory(&store.Memory{ID: "a", Content: "Lyra is afraid of the sea", MemoryType: store.MemoryTypeFact,
+		Confidence: 0.9, EntityID: "e-lyra", CreatedAt: 1, UpdatedAt: 1}, "t1", "m1")
+	st.CreateMemory(&store.Memory{ID: "b", Content: "Lyra wants a ship", MemoryType: store.MemoryTypeFact,
+		Confidence: 0.7, EntityID: "e-lyra", CreatedAt: 2, UpdatedAt: 2}, "t2", "m2")
+	st.CreateMemory(&store.Memory{ID: "c", Content: "Eldoria is the capital", MemoryType: store.MemoryTypeFact,
+		Confidence: 0.8, EntityID: "e-eldoria", CreatedAt: 3, UpdatedAt: 3}, "t1", "m3")
+	ex := NewExtractor(&testutil.Scripted{}, st, DefaultConfig())
+
+	all, err := ex.RecallByEntity("e-lyra", "")
+	if err != nil || len(all) != 2 || all[0].ID != "a" {
+		t.Fatalf("Expected both Lyra memories by confidence, got %+v, %v", all, err)
+	}
+
+	other, _ := ex.RecallByEntity("e-lyra", "t2")
+	if len(other) != 1 || other[0].ID != "a" || other[0].Threads[0] != "t1" {
+		t.Errorf("Expected only the memory from another thread, got %+v", other)
+	}
+
+	recalled, err := ex.RecallForText("Should Lyra sail from Eldoria?", "t2", 0)
+	if err != nil {
+		t.Fatalf("RecallForText failed: %v", err)
+	}
+	if len(recalled) != 2 || recalled[0].ID != "a" || recalled[1].ID != "c" {
+		t.Errorf("Expected Lyra's then Eldoria's memories, got %+v", recalled)
+	}
+	if limited, _ := ex.RecallForText("Should Lyra sail from Eldoria?", "", 1); len(limited) != 1 {
+		t.Errorf("Expected the limit applied, got %d", len(limited))
+	}
+}
+
+func TestParseResponse_BareArray(t *testing.T) {
+	got, err := parseResponse(`Here you go: [{"content": "x", "type": "fact", "confidence": 1}]`)
+	if err != nil || len(got) != 1 || got[0].Content != "x" {
+		t.Errorf("Expected one candidate, got %+v, %v", got, err)
+	}
+	if _, err := parseResponse("no json here"); err == nil {
+		t.Error("Expected a parse error")
+	}
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kittclouds/gokitt/internal/store"
+)
+
+// SystemPrompt instructs the LLM to return structured JSON only.
+const SystemPrompt = `You extract long-term memories from a conversation between a writer and their assistant.
+Return ONLY a valid JSON object with a "memories" array.
+No markdown, no explanation. Start with { and end with }.`
+
+// BuildUserPrompt constructs the extraction prompt. Messages are numbered
+// from 1 so each memory can name the message it came from.
+func BuildUserPrompt(messages []*store.ThreadMessage) string {
+	var sb strings.Builder
+	sb.WriteString("Extract memories worth keeping across conversations from these messages.\n\n")
+
+	sb.WriteString("Each memory object:\n")
+	sb.WriteString("- \"content\": One self-contained statement (string)\n")
+	sb.WriteString("- \"type\": One of: fact, preference, entity_mention, relation\n")
+	sb.WriteString("- \"confidence\": 0.0-1.0 (number)\n")
+	sb.WriteString("- \"entity\": Main character, place or thing it is about, as written (string, optional)\n")
+	sb.WriteString("- \"message\": Number of the message it comes from (number)\n\n")
+
+	sb.WriteString("TYPE GUIDE:\n")
+	sb.WriteString("- fact: Something true about the story or world\n")
+	sb.WriteString("- preference: How the user likes to work or write\n")
+	sb.WriteString("- entity_mention: An entity the user is focusing on\n")
+	sb.WriteString("- relation: A relationship between two entities\n\n")
+
+	sb.WriteString("Skip small talk and anything only relevant to this moment. ")
+	sb.WriteString("Return {\"memories\": []} if there is nothing to keep.\n\n")
+
+	sb.WriteString("MESSAGES:\n")
+	for i, m := range messages {
+		fmt.Fprintf(&sb, "[%d] %s: %s\n", i+1, m.Role, m.Content)
+	}
+	return sb.String()
+}
+
+// candidate is one memory as returned by the LLM
+type candidate struct {
+	Content    string  `json:"content"`
+	Type       string  `json:"type"`
+	Confidence float64 `json:"confidence"`
+	Entity     string  `json:"entity"`
+	Message    int     `json:"message"`
+}
+
+// parseResponse parses the LLM reply into candidates. Accepts the object
+// form, a bare array, and either wrapped in code fences or prose.
+func parseResponse(raw string) ([]candidate, error) {
+	cleaned := strings.TrimSpace(raw)
+	if cleaned == "" {
+		return nil, nil
+	}
+
+	obj, arr := strings.Index(cleaned, "{"), strings.Index(cleaned, "[")
+	if end := strings.LastIndex(cleaned, "}"); obj >= 0 && end > obj && (arr < 0 || obj < arr) {
+		var wrapped struct {
+			Memories []candidate `json:"memories"`
+		}
+		if err := json.Unmarshal([]byte(cleaned[obj:end+1]), &wrapped); err == nil {
+			return wrapped.Memories, nil
+		}
+	}
+	if start, end := arr, strings.LastIndex(cleaned, "]"); start >= 0 && end > start {
+		var arr []candidate
+		if err := json.Unmarshal([]byte(cleaned[start:end+1]), &arr); err == nil {
+			return arr, nil
+		}
+	}
+	return nil, fmt.Errorf("memory: failed to parse LLM response")
+}
+
+// parseType maps an LLM type string to a MemoryType
+func parseType(s string) (store.MemoryType, bool) {
+	switch t := store.MemoryType(strings.ToLower(strings.TrimSpace(s))); t {
+	case store.MemoryTypeFact, store.MemoryTypePreference, store.MemoryTypeEntityMention, store.MemoryTypeRelation:
+		return t, true
+	}
+	return "", false
+}
@@ -0,0 +1,82 @@
+package om
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kittclouds/gokitt/internal/store"
+)
+
+// ObserverSystemPrompt instructs the Observer to write new observations only.
+const ObserverSystemPrompt = `You are the Observer for a writing assistant's long-term memory.
+Read the new conversation messages and record what is worth remembering: facts about the story,
+decisions the user made, their preferences, open questions and what they are working on.
+Write short dated bullet points. Do not repeat anything already in the existing observations.
+Reply with an <observations> block and a one-line <current-task> block, nothing else.`
+
+// ReflectorSystemPrompt instructs the Reflector to condense observations.
+const ReflectorSystemPrompt = `You are the Reflector for a writing assistant's long-term memory.
+Rewrite the observations into a shorter list that keeps every fact, decision and preference still
+relevant. Merge duplicates, drop superseded details and keep the bullet point format.
+Reply with an <observations> block only.`
+
+// BuildObserverPrompt constructs the Observer prompt for the unobserved messages.
+func BuildObserverPrompt(record *store.OMRecord, messages []*store.ThreadMessage) string {
+	var sb strings.Builder
+	if record.Observations != "" {
+		sb.WriteString("EXISTING OBSERVATIONS:\n")
+		sb.WriteString(record.Observations)
+		sb.WriteString("\n\n")
+	}
+	if record.CurrentTask != "" {
+		sb.WriteString("CURRENT TASK: ")
+		sb.WriteString(record.CurrentTask)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("NEW MESSAGES:\n")
+	for _, m := range messages {
+		fmt.Fprintf(&sb, "[%s] %s\n", m.Role, m.Content)
+	}
+	sb.WriteString("\nReply as:\n<observations>\n- ...\n</observations>\n<current-task>...</current-task>")
+	return sb.String()
+}
+
+// BuildReflectorPrompt constructs the Reflector prompt. attempt > 0 asks for
+// a harder compression after a previous reply came back too long.
+func BuildReflectorPrompt(observations string, target, attempt int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Condense these observations to under %d tokens (about %d words).\n", target, target*3/4)
+	if attempt > 0 {
+		sb.WriteString("Your previous rewrite was still too long: be more aggressive, keep only what matters for future replies.\n")
+	}
+	sb.WriteString("\nOBSERVATIONS:\n")
+	sb.WriteString(observations)
+	sb.WriteString("\n\nReply as:\n<observations>\n- ...\n</observations>")
+	return sb.String()
+}
+
+// parseReply extracts the <observations> and <current-task> blocks. A reply
+// without tags is taken as observations in full.
+func parseReply(raw string) (observations, task string) {
+	observations, ok := tagged(raw, "observations")
+	if !ok {
+		observations = raw
+	}
+	task, _ = tagged(raw, "current-task")
+	return strings.TrimSpace(observations), strings.TrimSpace(task)
+}
+
+// tagged returns the text between <tag> and </tag>
+func tagged(raw, tag string) (string, bool) {
+	open, close := "<"+tag+">", "</"+tag+">"
+	start := strings.Index(raw, open)
+	if start < 0 {
+		return "", false
+	}
+	rest := raw[start+len(open):]
+	if end := strings.Index(rest, close); end >= 0 {
+		rest = rest[:end]
+	}
+	return rest, true
+}
@@ -0,0 +1,318 @@
+// Package om runs the observational memory pipeline over chat threads: the
+// Observer condenses messages past the thread's cursor into observations,
+// the Reflector compresses observations that grow too long, and the result
+// is injected as a context block into the next prompt.
+package om
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kittclouds/gokitt/internal/ids"
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/batch"
+)
+
+// DefaultConfig returns the default thresholds with OM enabled.
+func DefaultConfig() store.OMConfig {
+	return store.OMConfig{
+		ObserveThreshold: 1000,
+		ReflectThreshold: 4000,
+		MaxRetries:       2,
+		Enabled:          true,
+	}
+}
+
+// Result reports what a Process call did.
+type Result struct {
+	ThreadID         string `json:"threadId"`
+	PendingTokens    int    `json:"pendingTokens"`    // Unobserved message tokens found
+	Observed         bool   `json:"observed"`         // The Observer ran
+	MessagesObserved int    `json:"messagesObserved"` // Messages moved behind the cursor
+	Reflected        bool   `json:"reflected"`        // The Reflector compressed observations
+	Attempts         int    `json:"attempts"`         // Reflector calls made
+	Generation       int    `json:"generation"`
+	ObsTokenCount    int    `json:"obsTokenCount"`
+}
+
+// Service runs the Observer and Reflector for chat threads.
+type Service struct {
+	llm   batch.Completer
+	store store.Storer
+	cfg   store.OMConfig
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex // Per-thread, so concurrent calls don't observe twice
+}
+
+// NewService creates an OM service. Zero thresholds fall back to DefaultConfig.
+func NewService(llm batch.Completer, s store.Storer, cfg store.OMConfig) *Service {
+	def := DefaultConfig()
+	if cfg.ObserveThreshold <= 0 {
+		cfg.ObserveThreshold = def.ObserveThreshold
+	}
+	if cfg.ReflectThreshold <= 0 {
+		cfg.ReflectThreshold = def.ReflectThreshold
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	return &Service{llm: llm, store: s, cfg: cfg, locks: make(map[string]*sync.Mutex)}
+}
+
+// Config returns the active thresholds.
+func (s *Service) Config() store.OMConfig {
+	return s.cfg
+}
+
+// EstimateTokens approximates the token count of text (4 chars per token).
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// Process runs the pipeline for a thread after messages were added or a
+// streamed reply finished. Messages after the record's cursor are counted; past ObserveThreshold
+// the Observer appends observations and advances the cursor, and once the
+// observations exceed ReflectThreshold the Reflector compresses them.
+//
+// The observation is saved before reflecting, so a reflection error comes
+// back with a non-nil Result describing what was kept.
+func (s *Service) Process(ctx context.Context, threadID string) (*Result, error) {
+	result := &Result{ThreadID: threadID}
+	if !s.cfg.Enabled {
+		return result, nil
+	}
+	if s.llm == nil {
+		return nil, fmt.Errorf("om: LLM not configured")
+	}
+
+	lock := s.threadLock(threadID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	record, err := s.record(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.unobserved(record)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range pending {
+		result.PendingTokens += EstimateTokens(m.Content)
+	}
+	result.Generation, result.ObsTokenCount = record.GenerationNum, record.ObsTokenCount
+
+	if len(pending) > 0 && result.PendingTokens >= s.cfg.ObserveThreshold {
+		if err := s.observe(ctx, record, pending); err != nil {
+			return nil, err
+		}
+		result.Observed = true
+		result.MessagesObserved = len(pending)
+	}
+
+	if record.ObsTokenCount > s.cfg.ReflectThreshold {
+		attempts, err := s.reflect(ctx, record)
+		result.Attempts = attempts
+		result.Reflected = err == nil
+		if err != nil {
+			result.ObsTokenCount = record.ObsTokenCount
+			return result, err
+		}
+	}
+
+	result.Generation, result.ObsTokenCount = record.GenerationNum, record.ObsTokenCount
+	return result, nil
+}
+
+// PendingTokens returns the estimated tokens of a thread's unobserved messages.
+func (s *Service) PendingTokens(threadID string) (int, error) {
+	record, err := s.record(threadID)
+	if err != nil {
+		return 0, err
+	}
+	pending, err := s.unobserved(record)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, m := range pending {
+		total += EstimateTokens(m.Content)
+	}
+	return total, nil
+}
+
+// ContextBlock returns the observations and current task formatted for
+// injection into the next prompt, or "" when the thread has none. Messages
+// past the cursor are not included: the caller sends them as history.
+func (s *Service) ContextBlock(threadID string) (string, error) {
+	record, err := s.store.GetOMRecord(threadID)
+	if err != nil {
+		return "", fmt.Errorf("om: get record: %w", err)
+	}
+	if record == nil || (record.Observations == "" && record.CurrentTask == "") {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("The following observations summarize earlier parts of this conversation.\n")
+	if record.Observations != "" {
+		sb.WriteString("<observations>\n")
+		sb.WriteString(record.Observations)
+		sb.WriteString("\n</observations>\n")
+	}
+	if record.CurrentTask != "" {
+		sb.WriteString("<current-task>")
+		sb.WriteString(record.CurrentTask)
+		sb.WriteString("</current-task>\n")
+	}
+	return sb.String(), nil
+}
+
+// =============================================================================
+// Observer / Reflector
+// =============================================================================
+
+// observe appends the Observer's notes on pending to the record and moves
+// the cursor past them
+func (s *Service) observe(ctx context.Context, record *store.OMRecord, pending []*store.ThreadMessage) error {
+	raw, err := s.llm.Complete(ctx, BuildObserverPrompt(record, pending), ObserverSystemPrompt)
+	if err != nil {
+		return fmt.Errorf("om: observer: %w", err)
+	}
+	observations, task := parseReply(raw)
+
+	if observations != "" {
+		if record.Observations != "" {
+			record.Observations += "\n"
+		}
+		record.Observations += observations
+	}
+	if task != "" {
+		record.CurrentTask = task
+	}
+	last := pending[len(pending)-1]
+	record.LastObservedAt, record.LastObservedID = last.CreatedAt, last.ID
+	record.ObsTokenCount = EstimateTokens(record.Observations)
+	record.UpdatedAt = time.Now().UnixMilli()
+
+	if err := s.store.UpsertOMRecord(record); err != nil {
+		return fmt.Errorf("om: save record: %w", err)
+	}
+	return nil
+}
+
+// reflect compresses the record's observations, retrying up to MaxRetries
+// times while the rewrite stays above ReflectThreshold. The shortest
+// rewrite that shrinks the observations is kept even if none gets under
+// the threshold. Returns the number of Reflector calls made.
+func (s *Service) reflect(ctx context.Context, record *store.OMRecord) (int, error) {
+	input := record.Observations
+	inputTokens := EstimateTokens(input)
+	target := s.cfg.ReflectThreshold / 2
+
+	best, bestTokens := "", inputTokens
+	attempts := 0
+	var lastErr error
+	for attempt := 0; attempt <= s.cfg.MaxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			lastErr = err
+			break
+		}
+		attempts++
+		raw, err := s.llm.Complete(ctx, BuildReflectorPrompt(input, target, attempt), ReflectorSystemPrompt)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		observations, _ := parseReply(raw)
+		tokens := EstimateTokens(observations)
+		if observations == "" || tokens >= bestTokens {
+			lastErr = fmt.Errorf("rewrite did not shrink the observations (%d tokens)", tokens)
+			continue
+		}
+		best, bestTokens = observations, tokens
+		if tokens <= s.cfg.ReflectThreshold {
+			break
+		}
+	}
+	if best == "" {
+		return attempts, fmt.Errorf("om: reflector failed after %d attempts: %w", attempts, lastErr)
+	}
+
+	now := time.Now().UnixMilli()
+	gen := &store.OMGeneration{
+		ID:           ids.New(),
+		ThreadID:     record.ThreadID,
+		Generation:   record.GenerationNum + 1,
+		InputTokens:  inputTokens,
+		OutputTokens: bestTokens,
+		InputText:    input,
+		OutputText:   best,
+		CreatedAt:    now,
+	}
+	if err := s.store.AddOMGeneration(gen); err != nil {
+		return attempts, fmt.Errorf("om: log generation: %w", err)
+	}
+
+	record.Observations = best
+	record.ObsTokenCount = bestTokens
+	record.GenerationNum = gen.Generation
+	record.UpdatedAt = now
+	if err := s.store.UpsertOMRecord(record); err != nil {
+		return attempts, fmt.Errorf("om: save record: %w", err)
+	}
+	return attempts, nil
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// record loads a thread's OM record, starting a fresh one if none exists
+func (s *Service) record(threadID string) (*store.OMRecord, error) {
+	record, err := s.store.GetOMRecord(threadID)
+	if err != nil {
+		return nil, fmt.Errorf("om: get record: %w", err)
+	}
+	if record == nil {
+		now := time.Now().UnixMilli()
+		record = &store.OMRecord{ThreadID: threadID, CreatedAt: now, UpdatedAt: now}
+	}
+	return record, nil
+}
+
+// unobserved returns the user and assistant messages past the record's
+// cursor, stopping at the first message still streaming so the cursor
+// never skips over it
+func (s *Service) unobserved(record *store.OMRecord) ([]*store.ThreadMessage, error) {
+	messages, err := s.store.GetThreadMessagesAfter(record.ThreadID, record.LastObservedID, record.LastObservedAt)
+	if err != nil {
+		return nil, fmt.Errorf("om: get messages: %w", err)
+	}
+	pending := make([]*store.ThreadMessage, 0)
+	for _, m := range messages {
+		if m.IsStreaming {
+			break
+		}
+		if (m.Role == "user" || m.Role == "assistant") && m.Content != "" {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+func (s *Service) threadLock(threadID string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, ok := s.locks[threadID]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[threadID] = lock
+	}
+	return lock
+}
@@ -0,0 +1,221 @@
+package om
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/internal/testutil"
+)
+
+func TestProcess_BelowThresholdDoesNothing(t *testing.T) {
+	st := testutil.NewStore(t, "t1")
+	llm := &testutil.Scripted{}
+	svc := NewService(llm, st, store.OMConfig{ObserveThreshold: 100, Enabled: true})
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Hello there.", 10)
+	result, err := svc.Process(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if result.Observed || result.PendingTokens == 0 || len(llm.Prompts) != 0 {
+		t.Errorf("Expected pending tokens counted without observing, got %+v (%d calls)", result, len(llm.Prompts))
+	}
+	if rec, _ := st.GetOMRecord("t1"); rec != nil {
+		t.Errorf("Expected no record saved, got %+v", rec)
+	}
+}
+
+func TestProcess_ObservesAndAdvancesCursor(t *testing.T) {
+	st := testutil.NewStore(t, "t1")
+	llm := &testutil.Scripted{Replies: []string{
+		"<observations>\n- User is writing chapter 3 about Lyra.\n</observations>\n<current-task>Drafting chapter 3</current-task>",
+		"<observations>\n- Lyra reaches Eldoria.\n</observations>",
+	}}
+	svc := NewService(llm, st, store.OMConfig{ObserveThreshold: 10, ReflectThreshold: 1000, Enabled: true})
+	ctx := context.Background()
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "I'm working on chapter 3, where Lyra leaves home.", 10)
+	testutil.AddMessage(t, st, "t1", "m2", "tool", strings.Repeat("tool output ", 50), 11)
+	testutil.AddMessage(t, st, "t1", "m3", "assistant", "Great, let's outline her journey.", 12)
+
+	result, err := svc.Process(ctx, "t1")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if !result.Observed || result.MessagesObserved != 2 {
+		t.Fatalf("Expected two messages observed, got %+v", result)
+	}
+	if strings.Contains(llm.Prompts[0], "tool output") || !strings.Contains(llm.Prompts[0], "[user] I'm working on chapter 3") {
+		t.Errorf("Unexpected observer prompt: %s", llm.Prompts[0])
+	}
+
+	rec, _ := st.GetOMRecord("t1")
+	if rec == nil || rec.LastObservedAt != 12 || rec.CurrentTask != "Drafting chapter 3" {
+		t.Fatalf("Unexpected record: %+v", rec)
+	}
+	if rec.ObsTokenCount != EstimateTokens(rec.Observations) {
+		t.Errorf("Expected cached token count, got %d", rec.ObsTokenCount)
+	}
+
+	// Nothing new: no call
+	if result, _ := svc.Process(ctx, "t1"); result.Observed || len(llm.Prompts) != 1 {
+		t.Errorf("Expected no observation without new messages, got %+v", result)
+	}
+
+	// New messages only: existing observations given as context, appended to
+	testutil.AddMessage(t, st, "t1", "m4", "user", "Now Lyra arrives in Eldoria at night.", 20)
+	if _, err := svc.Process(ctx, "t1"); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if p := llm.Prompts[1]; !strings.Contains(p, "EXISTING OBSERVATIONS") || strings.Contains(p, "leaves home") {
+		t.Errorf("Expected only new messages with existing observations, got %s", p)
+	}
+	rec, _ = st.GetOMRecord("t1")
+	if !strings.Contains(rec.Observations, "chapter 3") || !strings.Contains(rec.Observations, "Eldoria") {
+		t.Errorf("Expected observations appended, got %q", rec.Observations)
+	}
+	if rec.LastObservedAt != 20 || rec.CurrentTask != "Drafting chapter 3" {
+		t.Errorf("Expected cursor advanced and task kept, got %+v", rec)
+	}
+}
+
+func TestProcess_StopsAtStreamingMessage(t *testing.T) {
+	st := testutil.NewStore(t, "t1")
+	llm := &testutil.Scripted{Replies: []string{"- noted"}}
+	svc := NewService(llm, st, store.OMConfig{ObserveThreshold: 1, Enabled: true})
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Tell me about Arin.", 10)
+	st.AddMessage(&store.ThreadMessage{ID: "m2", ThreadID: "t1", Role: "assistant", Content: "Arin is", CreatedAt: 11, IsStreaming: true})
+	testutil.AddMessage(t, st, "t1", "m3", "user", "And Lyra?", 12)
+
+	if _, err := svc.Process(context.Background(), "t1"); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	rec, _ := st.GetOMRecord("t1")
+	if rec.LastObservedAt != 10 || rec.Observations != "- noted" {
+		t.Errorf("Expected cursor stopped before the streaming message, got %+v", rec)
+	}
+}
+
+func TestProcess_SameMillisecondMessageNotSkipped(t *testing.T) {
+	st := testutil.NewStore(t, "t1")
+	llm := &testutil.Scripted{Replies: []string{"- asked about Arin", "- Arin is a knight"}}
+	svc := NewService(llm, st, store.OMConfig{ObserveThreshold: 1, Enabled: true})
+	ctx := context.Background()
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Tell me about Arin.", 10)
+	if _, err := svc.Process(ctx, "t1"); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	// The reply lands in the same millisecond as the observed message
+	testutil.AddMessage(t, st, "t1", "m2", "assistant", "Arin is a knight.", 10)
+	result, err := svc.Process(ctx, "t1")
+	if err != nil || result.MessagesObserved != 1 {
+		t.Fatalf("Expected the reply observed, got %+v, %v", result, err)
+	}
+	if rec, _ := st.GetOMRecord("t1"); rec.LastObservedID != "m2" {
+		t.Errorf("Expected the cursor on m2, got %+v", rec)
+	}
+}
+
+func TestProcess_ReflectsWithRetryAndLogsGeneration(t *testing.T) {
+	st := testutil.NewStore(t, "t1")
+	long := "<observations>\n" + strings.Repeat("- Lyra likes tea.\n", 30) + "</observations>"
+	llm := &testutil.Scripted{Replies: []string{
+		long,
+		"<observations>\n" + strings.Repeat("- Lyra likes tea.\n", 20) + "</observations>", // still too long
+		"<observations>\n- Lyra likes tea.\n</observations>",
+	}}
+	svc := NewService(llm, st, store.OMConfig{ObserveThreshold: 1, ReflectThreshold: 50, MaxRetries: 2, Enabled: true})
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Lyra always drinks tea.", 10)
+	result, err := svc.Process(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if !result.Reflected || result.Attempts != 2 || result.Generation != 1 {
+		t.Fatalf("Expected a reflection on the second attempt, got %+v", result)
+	}
+	if llm.Systems[1] != ReflectorSystemPrompt || !strings.Contains(llm.Prompts[2], "still too long") {
+		t.Error("Expected the retry to ask for harder compression")
+	}
+
+	rec, _ := st.GetOMRecord("t1")
+	if rec.Observations != "- Lyra likes tea." || rec.GenerationNum != 1 || rec.ObsTokenCount != result.ObsTokenCount {
+		t.Errorf("Expected compressed observations saved, got %+v", rec)
+	}
+	gens, _ := st.GetOMGenerations("t1")
+	if len(gens) != 1 || gens[0].Generation != 1 || gens[0].InputTokens <= gens[0].OutputTokens {
+		t.Fatalf("Expected one logged generation, got %+v", gens)
+	}
+	if !strings.Contains(gens[0].InputText, "likes tea") || gens[0].OutputText != rec.Observations {
+		t.Errorf("Unexpected generation texts: %+v", gens[0])
+	}
+}
+
+func TestProcess_ReflectionFailureKeepsObservations(t *testing.T) {
+	st := testutil.NewStore(t, "t1")
+	long := "<observations>\n" + strings.Repeat("- Arin guards the gate.\n", 30) + "</observations>"
+	llm := &testutil.Scripted{
+		Replies: []string{long, "", long},
+		Errs:    []error{nil, errors.New("rate limited")},
+	}
+	svc := NewService(llm, st, store.OMConfig{ObserveThreshold: 1, ReflectThreshold: 50, MaxRetries: 1, Enabled: true})
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Arin guards the gate.", 10)
+	result, err := svc.Process(context.Background(), "t1")
+	if err == nil {
+		t.Fatal("Expected a reflection error")
+	}
+	if result == nil || !result.Observed || result.Reflected || result.Attempts != 2 {
+		t.Errorf("Expected the observation kept and reflection reported, got %+v", result)
+	}
+	rec, _ := st.GetOMRecord("t1")
+	if rec.LastObservedAt != 10 || rec.GenerationNum != 0 || !strings.Contains(rec.Observations, "Arin") {
+		t.Errorf("Expected the observation saved without a generation, got %+v", rec)
+	}
+	if gens, _ := st.GetOMGenerations("t1"); len(gens) != 0 {
+		t.Errorf("Expected no generation logged, got %d", len(gens))
+	}
+}
+
+func TestProcess_Disabled(t *testing.T) {
+	st := testutil.NewStore(t, "t1")
+	llm := &testutil.Scripted{}
+	svc := NewService(llm, st, store.OMConfig{ObserveThreshold: 1})
+
+	testutil.AddMessage(t, st, "t1", "m1", "user", "Hello.", 10)
+	if result, err := svc.Process(context.Background(), "t1"); err != nil || result.Observed || len(llm.Prompts) != 0 {
+		t.Errorf("Expected disabled OM to do nothing, got %+v, %v", result, err)
+	}
+}
+
+func TestContextBlock(t *testing.T) {
+	st := testutil.NewStore(t, "t1")
+	svc := NewService(&testutil.Scripted{}, st, DefaultConfig())
+
+	if block, err := svc.ContextBlock("t1"); err != nil || block != "" {
+		t.Errorf("Expected an empty block without a record, got %q, %v", block, err)
+	}
+
+	st.UpsertOMRecord(&store.OMRecord{ThreadID: "t1", Observations: "- Lyra likes tea.", CurrentTask: "Editing chapter 2", CreatedAt: 1, UpdatedAt: 1})
+	block, err := svc.ContextBlock("t1")
+	if err != nil {
+		t.Fatalf("ContextBlock failed: %v", err)
+	}
+	if !strings.Contains(block, "<observations>\n- Lyra likes tea.\n</observations>") ||
+		!strings.Contains(block, "<current-task>Editing chapter 2</current-task>") {
+		t.Errorf("Unexpected context block: %q", block)
+	}
+}
+
+func TestParseReply_Untagged(t *testing.T) {
+	obs, task := parseReply("  - plain notes  ")
+	if obs != "- plain notes" || task != "" {
+		t.Errorf("Expected untagged reply as observations, got %q / %q", obs, task)
+	}
+}
@@ -0,0 +1,98 @@
+package ontology
+
+// Kind groups used by the default relations. Scanner kinds (PLACE) and LLM
+// kinds (LOCATION, NPC) are both listed so either vocabulary checks cleanly.
+var (
+	agents    = []string{"CHARACTER", "NPC", "FACTION", "ORGANIZATION", "CREATURE"}
+	people    = []string{"CHARACTER", "NPC", "CREATURE"}
+	groups    = []string{"FACTION", "ORGANIZATION"}
+	places    = []string{"LOCATION", "PLACE"}
+	ownable   = []string{"ITEM", "LOCATION", "PLACE", "CREATURE"}
+	governed  = []string{"LOCATION", "PLACE", "FACTION", "ORGANIZATION"}
+	occasions = []string{"EVENT"}
+)
+
+// defaultRelations covers the scanner's verb relations and the relation
+// types the extraction prompt offers the LLM
+var defaultRelations = []Relation{
+	// Conflict
+	{Name: "KILLS", Domain: agents, Range: people, Inverse: "KILLED_BY", Synonyms: []string{"KILLED", "SLAYS", "SLEW", "MURDERS", "MURDERED"}},
+	{Name: "ATTACKS", Domain: agents, Inverse: "ATTACKED_BY", Synonyms: []string{"ASSAULTS", "AMBUSHES"}},
+	{Name: "DEFEATS", Domain: agents, Range: agents, Inverse: "DEFEATED_BY", Synonyms: []string{"DEFEATED", "CONQUERS"}},
+	{Name: "FIGHTS", Domain: agents, Range: agents, Symmetric: true, Synonyms: []string{"BATTLES", "FIGHTS_WITH", "DUELS"}},
+	{Name: "CAPTURES", Domain: agents, Range: people, Inverse: "CAPTIVE_OF", Synonyms: []string{"IMPRISONS"}},
+	{Name: "ENEMY_OF", Domain: agents, Range: agents, Symmetric: true, Synonyms: []string{"ENEMIES", "ENEMIES_WITH"}},
+	{Name: "RIVAL_OF", Domain: agents, Range: agents, Symmetric: true, Synonyms: []string{"RIVALS"}},
+	{Name: "BETRAYS", Domain: agents, Range: agents, Inverse: "BETRAYED_BY", Synonyms: []string{"BETRAYED"}},
+	{Name: "THREATENS", Domain: agents},
+	{Name: "ACCUSES", Domain: agents},
+
+	// Alliance and affection
+	{Name: "ALLIES", Domain: agents, Range: agents, Symmetric: true, Synonyms: []string{"ALLIED_WITH", "ALLY_OF", "ALLIES_WITH"}},
+	{Name: "FRIEND_OF", Domain: people, Range: people, Symmetric: true, Synonyms: []string{"FRIENDS", "FRIENDS_WITH"}},
+	{Name: "LOVES", Domain: people, Inverse: "LOVED_BY"},
+	{Name: "HATES", Domain: people, Inverse: "HATED_BY"},
+	{Name: "SAVES", Domain: agents, Inverse: "SAVED_BY", Synonyms: []string{"RESCUES", "SAVED"}},
+
+	// Family
+	{Name: "PARENT_OF", Domain: people, Range: people, Inverse: "CHILD_OF", Synonyms: []string{"FATHER_OF", "MOTHER_OF"}},
+	{Name: "SIBLING_OF", Domain: people, Range: people, Symmetric: true, Synonyms: []string{"BROTHER_OF", "SISTER_OF"}},
+	{Name: "MARRIED_TO", Domain: people, Range: people, Symmetric: true, Synonyms: []string{"SPOUSE_OF", "WED"}},
+
+	// Hierarchy and membership
+	{Name: "RULES", Domain: agents, Range: governed, Inverse: "RULED_BY", Synonyms: []string{"GOVERNS", "REIGNS_OVER"}},
+	{Name: "LEADS", Domain: people, Range: groups, Inverse: "LED_BY"},
+	{Name: "COMMANDS", Domain: people, Range: agents, Inverse: "COMMANDED_BY"},
+	{Name: "MEMBER_OF", Domain: people, Range: groups, Inverse: "HAS_MEMBER", Synonyms: []string{"BELONGS_TO"}},
+	{Name: "REPORTS_TO", Domain: people, Range: people},
+	{Name: "SERVES", Domain: agents, Range: agents, Inverse: "SERVED_BY"},
+
+	// Possession and creation
+	{Name: "OWNS", Domain: agents, Range: ownable, Inverse: "OWNED_BY", Synonyms: []string{"POSSESSES"}},
+	{Name: "GIVES", Domain: agents},
+	{Name: "TAKES", Domain: agents},
+	{Name: "STEALS", Domain: agents, Synonyms: []string{"STOLE", "ROBS"}},
+	{Name: "USES", Domain: agents, Range: []string{"ITEM"}, Inverse: "USED_BY", Synonyms: []string{"WIELDS"}},
+	{Name: "CREATES", Domain: agents, Inverse: "CREATED_BY", Synonyms: []string{"CREATED", "FORGES", "BUILDS"}},
+	{Name: "DESTROYS", Domain: agents, Inverse: "DESTROYED_BY", Synonyms: []string{"DESTROYED"}},
+
+	// Place
+	{Name: "LOCATED_IN", Range: places, Synonyms: []string{"LIVES_IN", "RESIDES_IN"}},
+	{Name: "TRAVELS", Domain: agents, Range: places, Synonyms: []string{"TRAVELED_TO", "TRAVELS_TO", "JOURNEYS_TO"}},
+	{Name: "ARRIVES", Domain: agents, Range: places, Synonyms: []string{"ARRIVES_AT", "ARRIVED_AT"}},
+	{Name: "DEPARTS", Domain: agents, Range: places, Synonyms: []string{"LEAVES", "DEPARTED_FROM"}},
+	{Name: "ORIGINATES_FROM", Range: places, Synonyms: []string{"COMES_FROM", "BORN_IN"}},
+
+	// Knowledge and speech
+	{Name: "KNOWS", Domain: people, Symmetric: true, Synonyms: []string{"ACQUAINTED_WITH"}},
+	{Name: "TEACHES", Domain: people, Range: people, Inverse: "LEARNED_FROM", Synonyms: []string{"MENTORS", "TRAINS"}},
+	{Name: "SPEAKS_TO", Domain: people, Synonyms: []string{"TALKS_TO", "TELLS"}},
+	{Name: "MENTIONS", Domain: people},
+	{Name: "REVEALS", Domain: agents},
+	{Name: "CONCEALS", Domain: agents, Synonyms: []string{"HIDES"}},
+	{Name: "DECEIVES", Domain: agents, Synonyms: []string{"LIES_TO", "TRICKS"}},
+	{Name: "DISCOVERS", Domain: agents, Synonyms: []string{"FINDS_OUT"}},
+	{Name: "FINDS", Domain: agents},
+	{Name: "OBSERVES", Domain: agents, Synonyms: []string{"WATCHES", "SEES"}},
+	{Name: "PROMISES", Domain: agents},
+
+	// Events and change
+	{Name: "PARTICIPATES_IN", Domain: agents, Range: occasions, Synonyms: []string{"TAKES_PART_IN"}},
+	{Name: "WITNESSES", Domain: people, Range: occasions},
+	{Name: "CAUSES", Inverse: "CAUSED_BY"},
+	{Name: "ENABLES"},
+	{Name: "PREVENTS"},
+	{Name: "BECOMES", Synonyms: []string{"TRANSFORMS_INTO", "TURNS_INTO"}},
+	{Name: "INHERITS_FROM"},
+	{Name: "INTERACTS"},
+	{Name: "IS"},
+}
+
+// Default returns the built-in ontology
+func Default() *Ontology {
+	o, err := New(defaultRelations)
+	if err != nil {
+		panic(err) // The built-in table is static; a conflict is a programming error
+	}
+	return o
+}
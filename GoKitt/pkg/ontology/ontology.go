@@ -0,0 +1,191 @@
+// Package ontology defines canonical relation types: which entity kinds they
+// connect, which are symmetric or inverses of each other, and which labels
+// (LLM output, verb forms) are synonyms. The merger uses it to normalise
+// edges onto one canonical form; the validator uses it to flag type violations.
+package ontology
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Relation describes a canonical relation type
+type Relation struct {
+	Name      string   `json:"name"`
+	Domain    []string `json:"domain,omitempty"`    // Allowed subject kinds; empty = any
+	Range     []string `json:"range,omitempty"`     // Allowed object kinds; empty = any
+	Symmetric bool     `json:"symmetric,omitempty"` // A-R-B implies B-R-A
+	Inverse   string   `json:"inverse,omitempty"`   // B-Inverse-A is stored as A-Name-B
+	Synonyms  []string `json:"synonyms,omitempty"`  // Alternative labels for Name
+}
+
+// Ontology indexes relations by every label that resolves to them
+type Ontology struct {
+	relations map[string]*Relation
+	labels    map[string]label
+}
+
+// label is a resolvable name: the relation it maps to and whether the
+// subject and object swap (inverse labels)
+type label struct {
+	rel     *Relation
+	flipped bool
+}
+
+// New builds an ontology. Labels must be unambiguous: a name, synonym or
+// inverse may only resolve to one relation.
+func New(relations []Relation) (*Ontology, error) {
+	o := &Ontology{
+		relations: make(map[string]*Relation, len(relations)),
+		labels:    make(map[string]label),
+	}
+	for i := range relations {
+		rel := relations[i]
+		rel.Name = Key(rel.Name)
+		if rel.Name == "" {
+			return nil, fmt.Errorf("ontology: relation %d has no name", i)
+		}
+		if rel.Symmetric && rel.Inverse != "" {
+			return nil, fmt.Errorf("ontology: %s cannot be both symmetric and have an inverse", rel.Name)
+		}
+		rel.Inverse = Key(rel.Inverse)
+		rel.Domain = upperAll(rel.Domain)
+		rel.Range = upperAll(rel.Range)
+		o.relations[rel.Name] = &rel
+	}
+
+	for _, rel := range o.relations {
+		if err := o.addLabel(rel.Name, rel, false); err != nil {
+			return nil, err
+		}
+		for _, syn := range rel.Synonyms {
+			if err := o.addLabel(syn, rel, false); err != nil {
+				return nil, err
+			}
+		}
+		if rel.Inverse != "" {
+			if err := o.addLabel(rel.Inverse, rel, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return o, nil
+}
+
+func (o *Ontology) addLabel(name string, rel *Relation, flipped bool) error {
+	key := Key(name)
+	if existing, ok := o.labels[key]; ok && (existing.rel != rel || existing.flipped != flipped) {
+		return fmt.Errorf("ontology: label %s maps to both %s and %s", key, existing.rel.Name, rel.Name)
+	}
+	o.labels[key] = label{rel: rel, flipped: flipped}
+	return nil
+}
+
+// Parse builds an ontology from a JSON array of relations
+func Parse(data []byte) (*Ontology, error) {
+	var relations []Relation
+	if err := json.Unmarshal(data, &relations); err != nil {
+		return nil, fmt.Errorf("ontology: parse: %w", err)
+	}
+	return New(relations)
+}
+
+// Key normalises a relation label: "owned by" / "owned-by" -> OWNED_BY
+func Key(name string) string {
+	fields := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_' || r == '\t'
+	})
+	return strings.Join(fields, "_")
+}
+
+// Resolve maps a label to its canonical relation. flipped reports that the
+// label is the relation's inverse, so subject and object must swap.
+// Returns nil for labels the ontology does not know.
+func (o *Ontology) Resolve(name string) (rel *Relation, flipped bool) {
+	l, ok := o.labels[Key(name)]
+	if !ok {
+		return nil, false
+	}
+	return l.rel, l.flipped
+}
+
+// Normalize rewrites an edge onto its canonical form: synonyms become the
+// relation name, inverse labels swap the endpoints, and symmetric relations
+// order the endpoints so both directions share one edge. Unknown labels are
+// only normalised with Key.
+func (o *Ontology) Normalize(sourceID, relation, targetID string) (string, string, string) {
+	rel, flipped := o.Resolve(relation)
+	if rel == nil {
+		return sourceID, Key(relation), targetID
+	}
+	if flipped || (rel.Symmetric && targetID < sourceID) {
+		sourceID, targetID = targetID, sourceID
+	}
+	return sourceID, rel.Name, targetID
+}
+
+// Check returns the domain/range violations of a relation between entities
+// of the given kinds. Unknown relations and unknown kinds (empty, CONCEPT,
+// OTHER, UNKNOWN) never violate.
+func (o *Ontology) Check(relation, subjectKind, objectKind string) []string {
+	rel, flipped := o.Resolve(relation)
+	if rel == nil {
+		return nil
+	}
+	if flipped {
+		subjectKind, objectKind = objectKind, subjectKind
+	}
+
+	var violations []string
+	if !allows(rel.Domain, subjectKind) {
+		violations = append(violations, fmt.Sprintf("%s subject must be %s, got %s",
+			rel.Name, strings.Join(rel.Domain, "|"), strings.ToUpper(subjectKind)))
+	}
+	if !allows(rel.Range, objectKind) {
+		violations = append(violations, fmt.Sprintf("%s object must be %s, got %s",
+			rel.Name, strings.Join(rel.Range, "|"), strings.ToUpper(objectKind)))
+	}
+	return violations
+}
+
+// Relation returns a canonical relation by name, or nil
+func (o *Ontology) Relation(name string) *Relation {
+	return o.relations[Key(name)]
+}
+
+// Relations lists the canonical relations sorted by name
+func (o *Ontology) Relations() []Relation {
+	out := make([]Relation, 0, len(o.relations))
+	for _, rel := range o.relations {
+		out = append(out, *rel)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func allows(kinds []string, kind string) bool {
+	kind = strings.ToUpper(kind)
+	switch kind {
+	case "", "CONCEPT", "OTHER", "UNKNOWN":
+		return true
+	}
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func upperAll(list []string) []string {
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i] = strings.ToUpper(s)
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	o := Default()
+
+	tests := []struct {
+		src, rel, tgt string
+		want          [3]string
+	}{
+		{"Arin", "OWNS", "Sword", [3]string{"Arin", "OWNS", "Sword"}},
+		{"Sword", "owned by", "Arin", [3]string{"Arin", "OWNS", "Sword"}}, // Inverse flips
+		{"Troll", "KILLED_BY", "Arin", [3]string{"Arin", "KILLS", "Troll"}},
+		{"Arin", "slew", "Troll", [3]string{"Arin", "KILLS", "Troll"}},       // Synonym
+		{"Lyra", "ALLIED_WITH", "Arin", [3]string{"Arin", "ALLIES", "Lyra"}}, // Symmetric orders endpoints
+		{"Arin", "ALLIES", "Lyra", [3]string{"Arin", "ALLIES", "Lyra"}},
+		{"Arin", "hums at", "Lyra", [3]string{"Arin", "HUMS_AT", "Lyra"}}, // Unknown: key only
+	}
+	for _, tt := range tests {
+		s, r, g := o.Normalize(tt.src, tt.rel, tt.tgt)
+		if got := [3]string{s, r, g}; got != tt.want {
+			t.Errorf("Normalize(%s, %s, %s) = %v, want %v", tt.src, tt.rel, tt.tgt, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	o := Default()
+
+	if v := o.Check("OWNS", "CHARACTER", "ITEM"); len(v) != 0 {
+		t.Errorf("Expected no violations, got %v", v)
+	}
+	// Inverse labels check the flipped kinds
+	if v := o.Check("OWNED_BY", "ITEM", "CHARACTER"); len(v) != 0 {
+		t.Errorf("Expected OWNED_BY(ITEM, CHARACTER) to be valid, got %v", v)
+	}
+	v := o.Check("OWNS", "ITEM", "CHARACTER")
+	if len(v) != 2 || !strings.Contains(v[0], "subject") || !strings.Contains(v[1], "object") {
+		t.Errorf("Expected subject and object violations, got %v", v)
+	}
+	// Unknown kinds and relations never violate
+	if v := o.Check("OWNS", "", "CONCEPT"); len(v) != 0 {
+		t.Errorf("Expected unknown kinds to pass, got %v", v)
+	}
+	if v := o.Check("HUMS_AT", "ITEM", "ITEM"); len(v) != 0 {
+		t.Errorf("Expected unknown relation to pass, got %v", v)
+	}
+}
+
+func TestParse(t *testing.T) {
+	o, err := Parse([]byte(`[
+		{"name": "sworn to", "domain": ["character"], "range": ["faction"], "inverse": "has sworn", "synonyms": ["pledged to"]},
+		{"name": "twin_of", "symmetric": true}
+	]`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if s, r, g := o.Normalize("Guild", "HAS_SWORN", "Arin"); s != "Arin" || r != "SWORN_TO" || g != "Guild" {
+		t.Errorf("Unexpected normalization %s %s %s", s, r, g)
+	}
+	if rel, flipped := o.Resolve("Pledged-To"); rel == nil || rel.Name != "SWORN_TO" || flipped {
+		t.Errorf("Expected synonym to resolve, got %+v", rel)
+	}
+	if v := o.Check("SWORN_TO", "Character", "place"); len(v) != 1 {
+		t.Errorf("Expected a range violation, got %v", v)
+	}
+	if names := o.Relations(); len(names) != 2 || names[0].Name != "SWORN_TO" {
+		t.Errorf("Unexpected relations %v", names)
+	}
+
+	// Ambiguous and contradictory definitions are rejected
+	if _, err := New([]Relation{{Name: "OWNS", Synonyms: []string{"HOLDS"}}, {Name: "HOLDS"}}); err == nil {
+		t.Error("Expected an error for a synonym shadowing a relation")
+	}
+	if _, err := New([]Relation{{Name: "TWIN_OF", Symmetric: true, Inverse: "TWINNED"}}); err == nil {
+		t.Error("Expected an error for a symmetric relation with an inverse")
+	}
+}
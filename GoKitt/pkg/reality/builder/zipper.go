@@ -9,6 +9,7 @@ import (
 	rsyntax "github.com/kittclouds/gokitt/pkg/reality/syntax"
 	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
 	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
 )
 
 // span represents a potential node in the tree
@@ -20,11 +21,12 @@ type span struct {
 }
 
 const (
-	prioPara  = 90
-	prioSent  = 80
-	prioChunk = 50
-	prioSpan  = 40 // Entity, Link
-	prioToken = 10
+	prioPara   = 90
+	prioSent   = 80
+	prioTriple = 60 // Above chunks: a triple is one unit, not prose
+	prioChunk  = 50
+	prioSpan   = 40 // Entity, Link
+	prioToken  = 10
 )
 
 // Zip constructs a CST from the source text and scan results
@@ -136,7 +138,16 @@ func collectSpans(text string, scan conductor.ScanResult) []span {
 
 	// 3. Syntax Semantic Spans (Entities/Links)
 	for _, m := range scan.Syntax {
-		spans = append(spans, span{rsyntax.KindEntitySpan, m.Start, m.End, prioSpan})
+		switch m.Kind {
+		case syntax.KindTriple:
+			spans = append(spans, span{rsyntax.KindTriple, m.Start, m.End, prioTriple})
+		case syntax.KindWikilink:
+			spans = append(spans, span{rsyntax.KindWikilink, m.Start, m.End, prioSpan})
+		case syntax.KindBacklink:
+			spans = append(spans, span{rsyntax.KindBacklink, m.Start, m.End, prioSpan})
+		default:
+			spans = append(spans, span{rsyntax.KindEntitySpan, m.Start, m.End, prioSpan})
+		}
 	}
 
 	// 4. Tokens (Leaves) - Reuse from Scanner
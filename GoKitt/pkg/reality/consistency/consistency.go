@@ -0,0 +1,267 @@
+// Package consistency detects continuity errors in the merged world graph:
+// victims acting after their death, mutually exclusive relations, and
+// relations held by too many entities at once.
+package consistency
+
+import (
+	"sort"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+)
+
+// Severity grades a contradiction
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // Cannot both be true
+	SeverityWarning Severity = "warning" // Suspicious, may be intended
+)
+
+// Contradiction is one continuity problem with the edges that conflict
+type Contradiction struct {
+	Rule     string         `json:"rule"`
+	Severity Severity       `json:"severity"`
+	Message  string         `json:"message"`
+	Edges    []ConflictEdge `json:"edges"`
+}
+
+// ConflictEdge locates a conflicting edge: its endpoints, the notes and
+// sentence spans it was read from, and when it holds in the story
+type ConflictEdge struct {
+	Key         string            `json:"key"`
+	SourceID    string            `json:"sourceId"`
+	RelType     string            `json:"relType"`
+	TargetID    string            `json:"targetId"`
+	SourceNotes []string          `json:"sourceNotes,omitempty"`
+	Evidence    []merger.Evidence `json:"evidence,omitempty"`
+	StoryTime   *float64          `json:"storyTime,omitempty"`
+}
+
+// NoteOrder maps note IDs to their position in the narrative (note Order)
+type NoteOrder map[string]float64
+
+// Rule detects one kind of contradiction
+type Rule interface {
+	Name() string
+	Check(w *World) []Contradiction
+}
+
+// World is the graph a rule checks, indexed for lookups
+type World struct {
+	Graph *merger.MergedGraph
+	Order NoteOrder
+
+	edges    []*merger.MergedEdge            // Story edges, sorted by key
+	keys     map[*merger.MergedEdge]string   // Edge -> merged-graph key
+	byRel    map[string][]*merger.MergedEdge // Relation -> edges
+	bySource map[string][]*merger.MergedEdge // Subject ID -> edges
+}
+
+// NewWorld indexes the story edges of a merged graph (hierarchy edges and
+// edges touching World nodes are left out)
+func NewWorld(g *merger.MergedGraph, order NoteOrder) *World {
+	w := &World{
+		Graph:    g,
+		Order:    order,
+		keys:     make(map[*merger.MergedEdge]string),
+		byRel:    make(map[string][]*merger.MergedEdge),
+		bySource: make(map[string][]*merger.MergedEdge),
+	}
+
+	keys := make([]string, 0, len(g.Edges))
+	for key := range g.Edges {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		e := g.Edges[key]
+		if graph.IsHierarchy(e.RelType) || w.isWorld(e.SourceID) || w.isWorld(e.TargetID) {
+			continue
+		}
+		w.edges = append(w.edges, e)
+		w.keys[e] = key
+		w.byRel[e.RelType] = append(w.byRel[e.RelType], e)
+		w.bySource[e.SourceID] = append(w.bySource[e.SourceID], e)
+	}
+	return w
+}
+
+func (w *World) isWorld(id string) bool {
+	node, ok := w.Graph.Nodes[id]
+	return ok && node.Kind == graph.KindWorld
+}
+
+// Edges returns the story edges, sorted by key
+func (w *World) Edges() []*merger.MergedEdge { return w.edges }
+
+// EdgesByRelation returns the story edges with a relation type
+func (w *World) EdgesByRelation(rel string) []*merger.MergedEdge { return w.byRel[rel] }
+
+// EdgesFrom returns the story edges whose subject is id
+func (w *World) EdgesFrom(id string) []*merger.MergedEdge { return w.bySource[id] }
+
+// StoryTime returns when an edge starts holding: its validity start, else
+// the earliest position of the notes supporting it
+func (w *World) StoryTime(e *merger.MergedEdge) (float64, bool) {
+	if e.Valid != nil && e.Valid.From != nil {
+		return *e.Valid.From, true
+	}
+	best, found := 0.0, false
+	for _, note := range e.SourceNotes {
+		if t, ok := w.Order[note]; ok && (!found || t < best) {
+			best, found = t, true
+		}
+	}
+	return best, found
+}
+
+// Window returns the story interval an edge holds over: its validity, or
+// from its story time onwards
+func (w *World) Window(e *merger.MergedEdge) *graph.Interval {
+	if e.Valid != nil {
+		return e.Valid
+	}
+	if t, ok := w.StoryTime(e); ok {
+		return graph.NewInterval(graph.StoryPoint(t), nil)
+	}
+	return nil
+}
+
+// LastStoryTime returns the latest point an edge is attested at: for each
+// piece of evidence its validity start, else its note's position. Notes
+// without evidence count too unless the edge has an explicit validity.
+// Falls back to StoryTime.
+func (w *World) LastStoryTime(e *merger.MergedEdge) (float64, bool) {
+	best, found := 0.0, false
+	see := func(t float64) {
+		if !found || t > best {
+			best, found = t, true
+		}
+	}
+	covered := make(map[string]bool, len(e.Evidence))
+	for _, ev := range e.Evidence {
+		covered[ev.NoteID] = true
+		if ev.Valid != nil && ev.Valid.From != nil {
+			see(*ev.Valid.From)
+		} else if t, ok := w.Order[ev.NoteID]; ok {
+			see(t)
+		}
+	}
+	if e.Valid == nil {
+		for _, note := range e.SourceNotes {
+			if t, ok := w.Order[note]; ok && !covered[note] {
+				see(t)
+			}
+		}
+	}
+	if !found {
+		return w.StoryTime(e)
+	}
+	return best, true
+}
+
+// WindowAgainst returns e's window with an open end closed at the story
+// time of the first rival edge that starts after it: a later rival takes
+// over from e, as in a handover or a change of allegiance, rather than
+// overlapping it
+func (w *World) WindowAgainst(e *merger.MergedEdge, rivals []*merger.MergedEdge) *graph.Interval {
+	window := w.Window(e)
+	start, ok := w.StoryTime(e)
+	if window == nil || window.Until != nil || !ok {
+		return window
+	}
+	var end *float64
+	for _, r := range rivals {
+		if r == e {
+			continue
+		}
+		if t, ok := w.StoryTime(r); ok && t > start && (end == nil || t < *end) {
+			end = graph.StoryPoint(t)
+		}
+	}
+	if end == nil {
+		return window
+	}
+	return graph.NewInterval(window.From, end)
+}
+
+// Conflict describes an edge for a contradiction report
+func (w *World) Conflict(e *merger.MergedEdge) ConflictEdge {
+	c := ConflictEdge{
+		Key:         w.keys[e],
+		SourceID:    e.SourceID,
+		RelType:     e.RelType,
+		TargetID:    e.TargetID,
+		SourceNotes: e.SourceNotes,
+		Evidence:    e.Evidence,
+	}
+	if t, ok := w.StoryTime(e); ok {
+		c.StoryTime = graph.StoryPoint(t)
+	}
+	return c
+}
+
+// Factual reports whether an edge states something that happened
+// (not negated, not hedged by a modal)
+func Factual(e *merger.MergedEdge) bool {
+	if e.Attributes == nil {
+		return true
+	}
+	if negated, _ := e.Attributes["negated"].(bool); negated {
+		return false
+	}
+	modality, _ := e.Attributes["modality"].(string)
+	return modality == ""
+}
+
+// Label returns a node's label, falling back to its ID
+func (w *World) Label(id string) string {
+	if node, ok := w.Graph.Nodes[id]; ok && node.Label != "" {
+		return node.Label
+	}
+	return id
+}
+
+// Checker runs a set of rules over a world graph
+type Checker struct {
+	rules []Rule
+}
+
+// NewChecker creates a checker; with no rules it uses DefaultRules
+func NewChecker(rules ...Rule) *Checker {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	return &Checker{rules: rules}
+}
+
+// Check runs every rule. Contradictions are ordered by severity, rule and
+// first edge so reports are stable.
+func (c *Checker) Check(g *merger.MergedGraph, order NoteOrder) []Contradiction {
+	w := NewWorld(g, order)
+
+	found := make([]Contradiction, 0)
+	for _, rule := range c.rules {
+		found = append(found, rule.Check(w)...)
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.Severity != b.Severity {
+			return a.Severity == SeverityError
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return firstKey(a) < firstKey(b)
+	})
+	return found
+}
+
+func firstKey(c Contradiction) string {
+	if len(c.Edges) == 0 {
+		return ""
+	}
+	return c.Edges[0].Key
+}
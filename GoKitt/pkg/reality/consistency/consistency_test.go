@@ -0,0 +1,181 @@
+package consistency
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+)
+
+// world builds a merged graph from (source, relation, target, note) rows
+func world(rows ...[4]string) *merger.MergedGraph {
+	g := &merger.MergedGraph{
+		Nodes: make(map[string]*graph.ConceptNode),
+		Edges: make(map[string]*merger.MergedEdge),
+	}
+	for _, r := range rows {
+		for _, id := range []string{r[0], r[2]} {
+			g.Nodes[id] = &graph.ConceptNode{ID: id, Label: id, Kind: graph.KindConcept}
+		}
+		g.Edges[merger.EdgeKey(r[0], r[2], r[1])] = &merger.MergedEdge{
+			SourceID:    r[0],
+			RelType:     r[1],
+			TargetID:    r[2],
+			Confidence:  1,
+			SourceNotes: []string{r[3]},
+			Evidence:    []merger.Evidence{{NoteID: r[3], Sentence: [2]int{0, 10}, Weight: 1}},
+		}
+	}
+	return g
+}
+
+func rules(found []Contradiction) map[string]int {
+	count := make(map[string]int)
+	for _, c := range found {
+		count[c.Rule]++
+	}
+	return count
+}
+
+func TestPosthumousAction(t *testing.T) {
+	order := NoteOrder{"ch1": 1, "ch2": 2, "ch3": 3}
+	g := world(
+		[4]string{"lyra", "TRAVELS", "eldoria", "ch1"}, // Before the death: fine
+		[4]string{"arin", "KILLS", "lyra", "ch2"},
+		[4]string{"lyra", "SPEAKS_TO", "bram", "ch3"},  // After: contradiction
+		[4]string{"lyra", "MEMBER_OF", "guild", "ch3"}, // Exempt state
+	)
+
+	found := NewChecker().Check(g, order)
+	if len(found) != 1 || found[0].Rule != "posthumous-action" || found[0].Severity != SeverityError {
+		t.Fatalf("Expected one posthumous action, got %+v", found)
+	}
+	edges := found[0].Edges
+	if len(edges) != 2 || edges[0].RelType != "KILLS" || edges[1].RelType != "SPEAKS_TO" {
+		t.Fatalf("Expected the kill and the later act, got %+v", edges)
+	}
+	if edges[1].StoryTime == nil || *edges[1].StoryTime != 3 || edges[1].SourceNotes[0] != "ch3" {
+		t.Errorf("Expected the act located at ch3, got %+v", edges[1])
+	}
+	if len(edges[1].Evidence) != 1 || edges[1].Evidence[0].Sentence != [2]int{0, 10} {
+		t.Errorf("Expected the evidence span, got %+v", edges[1].Evidence)
+	}
+
+	// Hedged acts and unknown note positions are not contradictions
+	g.Edges[merger.EdgeKey("lyra", "bram", "SPEAKS_TO")].Attributes = map[string]any{"modality": "might"}
+	if found := NewChecker().Check(g, order); len(found) != 0 {
+		t.Errorf("Expected modal act to pass, got %+v", found)
+	}
+	if found := NewChecker().Check(world([4]string{"arin", "KILLS", "lyra", "ch2"}, [4]string{"lyra", "SPEAKS_TO", "bram", "x"}), order); len(found) != 0 {
+		t.Errorf("Expected unordered note to pass, got %+v", found)
+	}
+}
+
+func TestPosthumousAction_EvidenceAroundTheDeath(t *testing.T) {
+	// X attacks Y in notes 1 and 5; X is killed in note 3
+	order := NoteOrder{"n1": 1, "n3": 3, "n5": 5}
+	g := world(
+		[4]string{"x", "ATTACKS", "y", "n1"},
+		[4]string{"z", "KILLS", "x", "n3"},
+	)
+	attack := g.Edges[merger.EdgeKey("x", "y", "ATTACKS")]
+	attack.SourceNotes = append(attack.SourceNotes, "n5")
+	attack.Evidence = append(attack.Evidence, merger.Evidence{NoteID: "n5", Sentence: [2]int{0, 10}, Weight: 1})
+
+	found := NewChecker().Check(g, order)
+	if len(found) != 1 || found[0].Rule != "posthumous-action" {
+		t.Fatalf("Expected the attack after the death flagged, got %+v", found)
+	}
+}
+
+func TestMutuallyExclusive(t *testing.T) {
+	order := NoteOrder{"ch1": 1, "ch2": 2}
+	g := world(
+		[4]string{"arin", "ALLIES", "bram", "ch2"},
+		[4]string{"bram", "ENEMY_OF", "arin", "ch2"},
+	)
+	if got := rules(NewChecker().Check(g, order)); got["mutually-exclusive"] != 1 {
+		t.Errorf("Expected allies and enemies at once to conflict, got %v", got)
+	}
+
+	// An alliance that explicitly lasts past the enmity's start conflicts
+	g.Edges[merger.EdgeKey("arin", "bram", "ALLIES")].Valid = graph.NewInterval(graph.StoryPoint(1), graph.StoryPoint(3))
+	if got := rules(NewChecker().Check(g, order)); got["mutually-exclusive"] != 1 {
+		t.Errorf("Expected overlapping windows to conflict, got %v", got)
+	}
+
+	// An alliance that ended before the enmity began is fine
+	g.Edges[merger.EdgeKey("arin", "bram", "ALLIES")].Valid = graph.NewInterval(graph.StoryPoint(1), graph.StoryPoint(2))
+	if found := NewChecker().Check(g, order); len(found) != 0 {
+		t.Errorf("Expected disjoint windows to pass, got %+v", found)
+	}
+}
+
+func TestMutuallyExclusive_AlliesThenEnemies(t *testing.T) {
+	// Allies in chapter 1, enemies in chapter 5: the alliance ended
+	order := NoteOrder{"ch1": 1, "ch5": 5}
+	g := world(
+		[4]string{"arin", "ALLIES", "bram", "ch1"},
+		[4]string{"arin", "ENEMY_OF", "bram", "ch5"},
+	)
+	if found := NewChecker().Check(g, order); len(found) != 0 {
+		t.Errorf("Expected a change of allegiance to pass, got %+v", found)
+	}
+}
+
+func TestCardinality(t *testing.T) {
+	order := NoteOrder{"ch1": 1, "ch2": 2, "ch3": 3}
+	g := world(
+		[4]string{"arin", "PARENT_OF", "kai", "ch1"},
+		[4]string{"lyra", "PARENT_OF", "kai", "ch1"},
+		[4]string{"bram", "PARENT_OF", "kai", "ch2"},
+		[4]string{"arin", "OWNS", "sword", "ch1"},
+		[4]string{"bram", "OWNS", "sword", "ch3"},
+	)
+	g.Edges[merger.EdgeKey("arin", "sword", "OWNS")].Valid = graph.NewInterval(graph.StoryPoint(1), graph.StoryPoint(3))
+
+	found := NewChecker().Check(g, order)
+	if len(found) != 1 || found[0].Severity != SeverityWarning || len(found[0].Edges) != 3 {
+		t.Fatalf("Expected three parents flagged (sword changed hands), got %+v", found)
+	}
+
+	// Overlapping owners are flagged
+	g.Edges[merger.EdgeKey("arin", "sword", "OWNS")].Valid = graph.NewInterval(graph.StoryPoint(1), graph.StoryPoint(5))
+	if got := rules(NewChecker().Check(g, order)); got["cardinality"] != 2 {
+		t.Errorf("Expected parents and owners flagged, got %v", got)
+	}
+}
+
+func TestCardinality_Handover(t *testing.T) {
+	// Arin owns the sword in note 1, Bram in note 5: it changed hands
+	order := NoteOrder{"n1": 1, "n5": 5}
+	g := world(
+		[4]string{"arin", "OWNS", "sword", "n1"},
+		[4]string{"bram", "OWNS", "sword", "n5"},
+		[4]string{"arin", "RULES", "eldoria", "n1"},
+		[4]string{"bram", "RULES", "eldoria", "n5"},
+	)
+	if found := NewChecker().Check(g, order); len(found) != 0 {
+		t.Fatalf("Expected handovers accepted, got %+v", found)
+	}
+
+	// A third owner in the same note as Bram overlaps him
+	g.Edges[merger.EdgeKey("cael", "sword", "OWNS")] = &merger.MergedEdge{
+		SourceID: "cael", RelType: "OWNS", TargetID: "sword", Confidence: 1, SourceNotes: []string{"n5"},
+	}
+	found := NewChecker().Check(g, order)
+	if len(found) != 1 || len(found[0].Edges) != 2 || found[0].Edges[0].SourceID != "bram" {
+		t.Errorf("Expected only the simultaneous owners flagged, got %+v", found)
+	}
+}
+
+func TestAntisymmetric(t *testing.T) {
+	g := world(
+		[4]string{"arin", "PARENT_OF", "kai", "ch1"},
+		[4]string{"kai", "PARENT_OF", "arin", "ch2"},
+	)
+	found := NewChecker(&Antisymmetric{Relation: "PARENT_OF"}).Check(g, nil)
+	if len(found) != 1 || found[0].Rule != "antisymmetric" {
+		t.Errorf("Expected a mutual parent, got %+v", found)
+	}
+}
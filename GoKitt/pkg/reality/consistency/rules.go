@@ -0,0 +1,210 @@
+package consistency
+
+import (
+	"fmt"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+)
+
+// DefaultRules returns the built-in continuity rules
+func DefaultRules() []Rule {
+	return []Rule{
+		&PosthumousAction{
+			Killing: []string{"KILLS"},
+			// States and relations that outlive their subject
+			Exempt: []string{"IS", "BECOMES", "LOCATED_IN", "ORIGINATES_FROM", "MEMBER_OF",
+				"PARENT_OF", "SIBLING_OF", "MARRIED_TO", "OWNS", "KNOWS", "INHERITS_FROM"},
+		},
+		&MutuallyExclusive{A: "ALLIES", B: "ENEMY_OF"},
+		&MutuallyExclusive{A: "FRIEND_OF", B: "ENEMY_OF"},
+		&Antisymmetric{Relation: "PARENT_OF"},
+		&Cardinality{Relation: "PARENT_OF", Max: 2},
+		&Cardinality{Relation: "OWNS", Max: 1, Simultaneous: true},
+		&Cardinality{Relation: "RULES", Max: 1, Simultaneous: true},
+	}
+}
+
+// PosthumousAction flags a victim acting later in the story than the
+// relation that killed them
+type PosthumousAction struct {
+	Killing []string // Relations that end their target
+	Exempt  []string // Relations the dead may still hold
+}
+
+func (r *PosthumousAction) Name() string { return "posthumous-action" }
+
+func (r *PosthumousAction) Check(w *World) []Contradiction {
+	exempt := toSet(r.Exempt)
+	var found []Contradiction
+	for _, rel := range r.Killing {
+		for _, kill := range w.EdgesByRelation(rel) {
+			died, ok := w.StoryTime(kill)
+			if !ok || !Factual(kill) {
+				continue
+			}
+			for _, act := range w.EdgesFrom(kill.TargetID) {
+				if exempt[act.RelType] || !Factual(act) {
+					continue
+				}
+				// Any attestation after the death counts, not just the first
+				if t, ok := w.LastStoryTime(act); ok && t > died {
+					found = append(found, Contradiction{
+						Rule:     r.Name(),
+						Severity: SeverityError,
+						Message: fmt.Sprintf("%s %s after being killed by %s",
+							w.Label(act.SourceID), act.RelType, w.Label(kill.SourceID)),
+						Edges: []ConflictEdge{w.Conflict(kill), w.Conflict(act)},
+					})
+				}
+			}
+		}
+	}
+	return found
+}
+
+// MutuallyExclusive flags two relations holding between the same pair (in
+// either direction) at overlapping story times. An open-ended relation ends
+// when the other one begins (allies who later become enemies).
+type MutuallyExclusive struct {
+	A, B string
+}
+
+func (r *MutuallyExclusive) Name() string { return "mutually-exclusive" }
+
+func (r *MutuallyExclusive) Check(w *World) []Contradiction {
+	var found []Contradiction
+	for _, a := range w.EdgesByRelation(r.A) {
+		for _, b := range w.EdgesByRelation(r.B) {
+			if !samePair(a, b) || !Factual(a) || !Factual(b) {
+				continue
+			}
+			if !w.WindowAgainst(a, []*merger.MergedEdge{b}).Overlaps(w.WindowAgainst(b, []*merger.MergedEdge{a})) {
+				continue
+			}
+			found = append(found, Contradiction{
+				Rule:     r.Name(),
+				Severity: SeverityError,
+				Message: fmt.Sprintf("%s and %s are both %s and %s",
+					w.Label(a.SourceID), w.Label(a.TargetID), r.A, r.B),
+				Edges: []ConflictEdge{w.Conflict(a), w.Conflict(b)},
+			})
+		}
+	}
+	return found
+}
+
+// Antisymmetric flags a relation holding in both directions
+// (A PARENT_OF B and B PARENT_OF A)
+type Antisymmetric struct {
+	Relation string
+}
+
+func (r *Antisymmetric) Name() string { return "antisymmetric" }
+
+func (r *Antisymmetric) Check(w *World) []Contradiction {
+	edges := w.EdgesByRelation(r.Relation)
+	var found []Contradiction
+	for i, a := range edges {
+		for _, b := range edges[i+1:] {
+			if a.SourceID == b.TargetID && a.TargetID == b.SourceID && a.SourceID != a.TargetID {
+				found = append(found, Contradiction{
+					Rule:     r.Name(),
+					Severity: SeverityError,
+					Message: fmt.Sprintf("%s and %s are each %s the other",
+						w.Label(a.SourceID), w.Label(a.TargetID), r.Relation),
+					Edges: []ConflictEdge{w.Conflict(a), w.Conflict(b)},
+				})
+			}
+		}
+	}
+	return found
+}
+
+// Cardinality flags a target held by more than Max subjects through one
+// relation (three parents; an item with two owners). With Simultaneous,
+// only subjects whose edges overlap in story time count together, and an
+// open-ended edge ends when another subject's edge begins (X changes hands).
+type Cardinality struct {
+	Relation     string
+	Max          int
+	Simultaneous bool
+}
+
+func (r *Cardinality) Name() string { return "cardinality" }
+
+func (r *Cardinality) Check(w *World) []Contradiction {
+	byTarget := make(map[string][]*merger.MergedEdge)
+	var targets []string
+	for _, e := range w.EdgesByRelation(r.Relation) {
+		if !Factual(e) {
+			continue
+		}
+		if _, ok := byTarget[e.TargetID]; !ok {
+			targets = append(targets, e.TargetID)
+		}
+		byTarget[e.TargetID] = append(byTarget[e.TargetID], e)
+	}
+
+	var found []Contradiction
+	for _, target := range targets {
+		edges := byTarget[target]
+		if len(edges) <= r.Max {
+			continue
+		}
+		if r.Simultaneous {
+			edges = r.largestOverlap(w, edges)
+			if len(edges) <= r.Max {
+				continue
+			}
+		}
+
+		c := Contradiction{
+			Rule:     r.Name(),
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("%s has %d %s subjects (at most %d expected)",
+				w.Label(target), len(edges), r.Relation, r.Max),
+		}
+		for _, e := range edges {
+			c.Edges = append(c.Edges, w.Conflict(e))
+		}
+		found = append(found, c)
+	}
+	return found
+}
+
+// largestOverlap returns the biggest group of edges that all overlap
+// the same edge's window
+func (r *Cardinality) largestOverlap(w *World, edges []*merger.MergedEdge) []*merger.MergedEdge {
+	windows := make(map[*merger.MergedEdge]*graph.Interval, len(edges))
+	for _, e := range edges {
+		windows[e] = w.WindowAgainst(e, edges)
+	}
+
+	var best []*merger.MergedEdge
+	for _, anchor := range edges {
+		group := []*merger.MergedEdge{anchor}
+		for _, other := range edges {
+			if other != anchor && windows[anchor].Overlaps(windows[other]) {
+				group = append(group, other)
+			}
+		}
+		if len(group) > len(best) {
+			best = group
+		}
+	}
+	return best
+}
+
+func samePair(a, b *merger.MergedEdge) bool {
+	return (a.SourceID == b.SourceID && a.TargetID == b.TargetID) ||
+		(a.SourceID == b.TargetID && a.TargetID == b.SourceID)
+}
+
+func toSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, s := range list {
+		set[s] = true
+	}
+	return set
+}
@@ -0,0 +1,400 @@
+// Package grounding runs LLM extraction end to end: extract entities and
+// relations from a note, ground each relation in the note's CST, merge the
+// grounded relations into the knowledge graph and register the entities.
+package grounding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kittclouds/gokitt/internal/ids"
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/extraction"
+	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/ontology"
+	"github.com/kittclouds/gokitt/pkg/reality/builder"
+	"github.com/kittclouds/gokitt/pkg/reality/cst"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+	"github.com/kittclouds/gokitt/pkg/reality/validator"
+	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+)
+
+// Entity outcomes
+const (
+	StatusCreated  = "created"  // Registered as a new entity
+	StatusUpdated  = "updated"  // Matched an entity and added aliases to it
+	StatusExisting = "existing" // Matched an entity as-is
+	StatusRejected = "rejected"
+)
+
+// Config sets the acceptance thresholds; zero values pick the defaults.
+type Config struct {
+	MinEntityConfidence   float64 `json:"minEntityConfidence,omitempty"`   // LLM confidence (default 0.5)
+	MinRelationConfidence float64 `json:"minRelationConfidence,omitempty"` // After AdjustConfidence (default 0.6)
+}
+
+// DefaultConfig returns the default thresholds.
+func DefaultConfig() Config {
+	return Config{MinEntityConfidence: 0.5, MinRelationConfidence: 0.6}
+}
+
+// EntityOutcome reports what happened to one extracted entity.
+type EntityOutcome struct {
+	Label      string  `json:"label"`
+	Kind       string  `json:"kind"`
+	Confidence float64 `json:"confidence"`
+	Status     string  `json:"status"`
+	EntityID   string  `json:"entityId,omitempty"`
+	Reason     string  `json:"reason,omitempty"` // Why it was rejected
+}
+
+// RelationOutcome reports what happened to one extracted relation.
+type RelationOutcome struct {
+	Relation   extraction.ExtractedRelation `json:"relation"`
+	Accepted   bool                         `json:"accepted"`
+	Confidence float64                      `json:"confidence"` // After CST grounding
+	SourceID   string                       `json:"sourceId,omitempty"`
+	TargetID   string                       `json:"targetId,omitempty"`
+	Evidence   *merger.Evidence             `json:"evidence,omitempty"`
+	Reasons    []string                     `json:"reasons,omitempty"` // Why it was rejected
+}
+
+// Report summarises one pipeline run.
+type Report struct {
+	NoteID            string            `json:"noteId"`
+	Cached            bool              `json:"cached"` // Extraction served from the cache
+	Entities          []EntityOutcome   `json:"entities"`
+	Relations         []RelationOutcome `json:"relations"`
+	AcceptedEntities  int               `json:"acceptedEntities"`
+	AcceptedRelations int               `json:"acceptedRelations"`
+	EdgesAdded        int               `json:"edgesAdded"` // New edges in the merged graph
+}
+
+// Pipeline grounds LLM extraction results and merges them.
+type Pipeline struct {
+	extractor *extraction.Service
+	store     store.Storer
+	merger    *merger.Merger
+	ontology  *ontology.Ontology
+	cfg       Config
+}
+
+// New creates a pipeline that extracts with ex, registers entities in st
+// and adds grounded relations to m.
+func New(ex *extraction.Service, st store.Storer, m *merger.Merger, cfg Config) *Pipeline {
+	def := DefaultConfig()
+	if cfg.MinEntityConfidence <= 0 {
+		cfg.MinEntityConfidence = def.MinEntityConfidence
+	}
+	if cfg.MinRelationConfidence <= 0 {
+		cfg.MinRelationConfidence = def.MinRelationConfidence
+	}
+	return &Pipeline{extractor: ex, store: st, merger: m, cfg: cfg}
+}
+
+// SetOntology enables relation type checks during validation. Relations
+// violating the ontology's domain/range are rejected.
+func (p *Pipeline) SetOntology(o *ontology.Ontology) {
+	p.ontology = o
+}
+
+// Run extracts entities and relations from a note and processes them:
+//  1. extract (through the result cache when the service has one)
+//  2. match entities against the store; confident new ones are created
+//  3. scan the note with those entities in the dictionary and build its CST
+//  4. validate each relation against the CST and adjust its confidence
+//  5. add relations that are grounded, confident, type-correct and between
+//     known entities to the merger, with their sentence as evidence
+//
+// The note's previous LLM support is retracted first, so a re-run replaces
+// it rather than boosting the same relations again.
+func (p *Pipeline) Run(ctx context.Context, noteID, text string, knownEntities []string) (*Report, error) {
+	if p.extractor == nil || p.store == nil || p.merger == nil {
+		return nil, fmt.Errorf("grounding: pipeline not initialized")
+	}
+
+	result, cached, err := p.extractor.ExtractFromNoteCached(ctx, noteID, text, knownEntities)
+	if err != nil {
+		return nil, fmt.Errorf("grounding: %w", err)
+	}
+	report := &Report{
+		NoteID:    noteID,
+		Cached:    cached,
+		Entities:  make([]EntityOutcome, 0, len(result.Entities)),
+		Relations: make([]RelationOutcome, 0, len(result.Relations)),
+	}
+
+	idx, err := p.loadEntities()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.registerEntities(idx, result.Entities, noteID, report); err != nil {
+		return nil, err
+	}
+
+	root, err := scan(text, idx.all)
+	if err != nil {
+		return nil, err
+	}
+	v := validator.New(root, text)
+	v.SetOntology(p.ontology)
+
+	before := make(map[string]bool, len(p.merger.GetMergedGraph().Edges))
+	for key := range p.merger.GetMergedGraph().Edges {
+		before[key] = true
+	}
+	p.merger.RetractLLM(noteID)
+
+	for _, vr := range v.Validate(result.Relations) {
+		out := RelationOutcome{
+			Relation:   vr.Original,
+			Confidence: validator.AdjustConfidence(&vr),
+		}
+		out.Reasons = append(out.Reasons, vr.Issues...)
+		for _, violation := range vr.TypeViolations {
+			out.Reasons = append(out.Reasons, "Type violation: "+violation)
+		}
+		if out.Confidence < p.cfg.MinRelationConfidence {
+			out.Reasons = append(out.Reasons, fmt.Sprintf("Confidence %.2f below %.2f", out.Confidence, p.cfg.MinRelationConfidence))
+		}
+		src, dst := idx.lookup(vr.Original.Subject), idx.lookup(vr.Original.Object)
+		if src == nil {
+			out.Reasons = append(out.Reasons, "Subject is not a known entity: "+vr.Original.Subject)
+		}
+		if dst == nil {
+			out.Reasons = append(out.Reasons, "Object is not a known entity: "+vr.Original.Object)
+		}
+
+		if len(out.Reasons) == 0 {
+			out.Accepted = true
+			out.SourceID, out.TargetID = src.ID, dst.ID
+			out.Evidence = evidence(&vr, noteID, out.Confidence)
+			report.AcceptedRelations++
+
+			p.merger.EnsureNode(src.ID, src.Label, src.Kind)
+			p.merger.EnsureNode(dst.ID, dst.Label, dst.Kind)
+			p.merger.AddLLMEdges([]merger.LLMEdgeInput{{
+				SourceID:     src.ID,
+				TargetID:     dst.ID,
+				RelType:      vr.Original.RelationType,
+				Confidence:   out.Confidence,
+				Attributes:   attributes(vr.Original),
+				SourceNoteID: noteID,
+				Evidence:     []merger.Evidence{*out.Evidence},
+			}})
+		}
+		report.Relations = append(report.Relations, out)
+	}
+
+	// Edges the retraction removed and this run restored are not new
+	for key := range p.merger.GetMergedGraph().Edges {
+		if !before[key] {
+			report.EdgesAdded++
+		}
+	}
+	return report, nil
+}
+
+// registerEntities matches extracted entities against the index, creating
+// or extending store entities for the confident ones. New entities take the
+// note's narrative, or its world when the note has none.
+func (p *Pipeline) registerEntities(idx *entityIndex, extracted []extraction.ExtractedEntity, noteID string, report *Report) error {
+	note, err := p.store.GetNote(noteID)
+	if err != nil {
+		return fmt.Errorf("grounding: get note %s: %w", noteID, err)
+	}
+	narrativeID := ""
+	if note != nil {
+		narrativeID = note.NarrativeID
+		if narrativeID == "" {
+			narrativeID = note.WorldID
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	for _, e := range extracted {
+		out := EntityOutcome{Label: e.Label, Kind: string(e.Kind), Confidence: e.Confidence}
+		if e.Confidence < p.cfg.MinEntityConfidence {
+			out.Status = StatusRejected
+			out.Reason = fmt.Sprintf("Confidence %.2f below %.2f", e.Confidence, p.cfg.MinEntityConfidence)
+			report.Entities = append(report.Entities, out)
+			continue
+		}
+
+		names := append([]string{e.Label}, e.Aliases...)
+		entity := idx.match(names)
+		switch {
+		case entity == nil:
+			entity = &store.Entity{
+				ID:          ids.New(),
+				Label:       e.Label,
+				Kind:        string(e.Kind),
+				Aliases:     append([]string{}, e.Aliases...),
+				FirstNote:   noteID,
+				NarrativeID: narrativeID,
+				CreatedBy:   "extraction",
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+			out.Status = StatusCreated
+		case addAliases(entity, names):
+			entity.UpdatedAt = now
+			out.Status = StatusUpdated
+		default:
+			out.Status = StatusExisting
+		}
+
+		if out.Status != StatusExisting {
+			if err := p.store.UpsertEntity(entity); err != nil {
+				return fmt.Errorf("grounding: upsert entity %q: %w", entity.Label, err)
+			}
+			idx.add(entity)
+		}
+		out.EntityID = entity.ID
+		report.AcceptedEntities++
+		report.Entities = append(report.Entities, out)
+	}
+	return nil
+}
+
+// entityIndex finds entities by label or alias, case-insensitively.
+type entityIndex struct {
+	all    []*store.Entity
+	ids    map[string]bool
+	byName map[string]*store.Entity
+}
+
+func (p *Pipeline) loadEntities() (*entityIndex, error) {
+	entities, err := p.store.ListEntities("")
+	if err != nil {
+		return nil, fmt.Errorf("grounding: list entities: %w", err)
+	}
+	idx := &entityIndex{ids: make(map[string]bool), byName: make(map[string]*store.Entity)}
+	for _, e := range entities {
+		idx.add(e)
+	}
+	return idx, nil
+}
+
+// add indexes e; an entity already in the index is re-indexed in place.
+func (idx *entityIndex) add(e *store.Entity) {
+	if !idx.ids[e.ID] {
+		idx.ids[e.ID] = true
+		idx.all = append(idx.all, e)
+	}
+	for _, name := range append([]string{e.Label}, e.Aliases...) {
+		if key := normName(name); key != "" {
+			if _, taken := idx.byName[key]; !taken {
+				idx.byName[key] = e
+			}
+		}
+	}
+}
+
+func (idx *entityIndex) lookup(name string) *store.Entity {
+	return idx.byName[normName(name)]
+}
+
+// match returns the entity any of names refers to.
+func (idx *entityIndex) match(names []string) *store.Entity {
+	for _, name := range names {
+		if e := idx.lookup(name); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// addAliases records names e does not know yet; reports whether any were new.
+func addAliases(e *store.Entity, names []string) bool {
+	seen := map[string]bool{normName(e.Label): true}
+	for _, a := range e.Aliases {
+		seen[normName(a)] = true
+	}
+	added := false
+	for _, name := range names {
+		key := normName(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		e.Aliases = append(e.Aliases, strings.TrimSpace(name))
+		added = true
+	}
+	return added
+}
+
+// scan builds the note's CST with entities compiled into the implicit
+// dictionary, so their mentions become entity spans.
+func scan(text string, entities []*store.Entity) (*cst.Node, error) {
+	c, err := conductor.New()
+	if err != nil {
+		return nil, fmt.Errorf("grounding: scanner: %w", err)
+	}
+	defer c.Close()
+
+	if len(entities) > 0 {
+		registered := make([]implicitmatcher.RegisteredEntity, 0, len(entities))
+		for _, e := range entities {
+			registered = append(registered, implicitmatcher.RegisteredEntity{
+				ID:          e.ID,
+				Label:       e.Label,
+				Aliases:     e.Aliases,
+				Kind:        e.Kind,
+				NarrativeID: e.NarrativeID,
+			})
+		}
+		dict, err := implicitmatcher.Compile(registered)
+		if err != nil {
+			return nil, fmt.Errorf("grounding: dictionary: %w", err)
+		}
+		c.SetDictionary(dict)
+	}
+
+	return builder.Zip(text, c.Scan(text)), nil
+}
+
+// evidence locates a grounded relation: the sentence(s) holding its subject
+// and object, and the verb phrase when the validator found one.
+func evidence(vr *validator.ValidatedRelation, noteID string, weight float64) *merger.Evidence {
+	ev := &merger.Evidence{NoteID: noteID, Weight: weight, LLM: true}
+	lo, hi := vr.SubjectNode, vr.ObjectNode
+	if s := validator.Sentence(lo); s != nil {
+		lo = s
+	}
+	if s := validator.Sentence(hi); s != nil {
+		hi = s
+	}
+	ev.Sentence = [2]int{min(lo.Range.Start, hi.Range.Start), max(lo.Range.End, hi.Range.End)}
+	if vr.VerbNode != nil {
+		ev.Verb = [2]int{vr.VerbNode.Range.Start, vr.VerbNode.Range.End}
+	}
+	return ev
+}
+
+// attributes carries a relation's verb and modifiers onto the merged edge.
+func attributes(r extraction.ExtractedRelation) map[string]any {
+	attrs := make(map[string]any)
+	for k, v := range map[string]string{
+		"verb":      r.Verb,
+		"manner":    r.Manner,
+		"location":  r.Location,
+		"time":      r.Time,
+		"recipient": r.Recipient,
+	} {
+		if v != "" {
+			attrs[k] = v
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	return attrs
+}
+
+// normName folds a name for comparison: trimmed, lowercased, single-spaced.
+func normName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,211 @@
+package grounding
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/batch"
+	"github.com/kittclouds/gokitt/pkg/extraction"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+)
+
+const noteText = "Arin betrayed Lyra at dawn. Lyra traveled to Eldoria."
+
+const extractionReply = `{
+	"entities": [
+		{"label": "Arin", "kind": "CHARACTER", "confidence": 0.9},
+		{"label": "Lyra", "kind": "CHARACTER", "confidence": 0.9},
+		{"label": "Eldoria", "kind": "LOCATION", "confidence": 0.85},
+		{"label": "Ghost", "kind": "NPC", "confidence": 0.3}
+	],
+	"relations": [
+		{"subject": "Arin", "object": "Lyra", "verb": "betrayed", "relationType": "ENEMY_OF", "time": "at dawn", "confidence": 0.8},
+		{"subject": "Lyra", "object": "Eldoria", "verb": "traveled to", "relationType": "TRAVELED_TO", "confidence": 0.6},
+		{"subject": "Arin", "object": "Mordor", "verb": "went to", "relationType": "TRAVELED_TO", "confidence": 0.9}
+	]
+}`
+
+func newPipeline(t *testing.T) (*Pipeline, store.Storer, *merger.Merger, *int) {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		calls++
+		out, _ := json.Marshal(map[string]interface{}{
+			"choices": []interface{}{map[string]interface{}{"message": map[string]string{"role": "assistant", "content": extractionReply}}},
+		})
+		w.Write(out)
+	}))
+	t.Cleanup(srv.Close)
+
+	b := batch.NewServiceWithTransport(batch.Config{
+		Provider:      batch.ProviderOpenAI,
+		OpenAIBaseURL: srv.URL,
+		OpenAIModel:   "test-model",
+	}, batch.NewHTTPTransport(srv.Client()))
+
+	st, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	ex := extraction.NewService(b)
+	ex.SetCache(st)
+	m := merger.New()
+	return New(ex, st, m, Config{}), st, m, &calls
+}
+
+func TestRun_GroundsAndMerges(t *testing.T) {
+	p, st, m, _ := newPipeline(t)
+	st.UpsertNote(&store.Note{ID: "note-1", WorldID: "w", NarrativeID: "saga", Content: noteText})
+
+	report, err := p.Run(context.Background(), "note-1", noteText, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// Entities: three created, the low-confidence one rejected
+	if report.AcceptedEntities != 3 || len(report.Entities) != 4 {
+		t.Fatalf("Expected 3 of 4 entities accepted, got %+v", report.Entities)
+	}
+	if ghost := report.Entities[3]; ghost.Status != StatusRejected || ghost.Reason == "" {
+		t.Errorf("Expected Ghost rejected with a reason, got %+v", ghost)
+	}
+	arin, err := st.GetEntityByLabel("Arin")
+	if err != nil || arin == nil {
+		t.Fatalf("Expected Arin in the store, got %v (%v)", arin, err)
+	}
+	if arin.CreatedBy != "extraction" || arin.FirstNote != "note-1" || arin.NarrativeID != "saga" {
+		t.Errorf("Unexpected stored entity: %+v", arin)
+	}
+
+	// Relations: two grounded, the one about Mordor rejected
+	if report.AcceptedRelations != 2 || report.EdgesAdded != 2 {
+		t.Fatalf("Expected 2 accepted relations and edges, got %d/%d: %+v",
+			report.AcceptedRelations, report.EdgesAdded, report.Relations)
+	}
+	betrayal := report.Relations[0]
+	if !betrayal.Accepted || betrayal.SourceID != arin.ID || betrayal.Confidence <= 0.8 {
+		t.Errorf("Expected the betrayal accepted with boosted confidence, got %+v", betrayal)
+	}
+	if ev := betrayal.Evidence; ev == nil || noteText[ev.Sentence[0]:ev.Sentence[1]] != "Arin betrayed Lyra at dawn." {
+		t.Errorf("Expected the first sentence as evidence, got %+v", betrayal.Evidence)
+	} else if !strings.Contains(noteText[ev.Verb[0]:ev.Verb[1]], "betrayed") {
+		t.Errorf("Expected the verb span, got %q", noteText[ev.Verb[0]:ev.Verb[1]])
+	}
+
+	mordor := report.Relations[2]
+	if mordor.Accepted || len(mordor.Reasons) == 0 {
+		t.Errorf("Expected the Mordor relation rejected with reasons, got %+v", mordor)
+	}
+
+	// The merger holds the grounded edges with LLM provenance and evidence
+	key := merger.EdgeKey(betrayal.SourceID, betrayal.TargetID, "ENEMY_OF")
+	edge := m.GetMergedGraph().Edges[key]
+	if edge == nil {
+		t.Fatalf("Expected edge %s in the merged graph", key)
+	}
+	if len(edge.Provenances) != 1 || edge.Provenances[0] != merger.ProvenanceLLM {
+		t.Errorf("Expected LLM provenance only, got %v", edge.Provenances)
+	}
+	if len(edge.Evidence) != 1 || !edge.Evidence[0].LLM {
+		t.Errorf("Expected one LLM evidence span, got %+v", edge.Evidence)
+	}
+	if edge.Attributes["time"] != "at dawn" {
+		t.Errorf("Expected the time modifier as an attribute, got %v", edge.Attributes)
+	}
+	if node := m.GetMergedGraph().Nodes[arin.ID]; node == nil || node.Label != "Arin" {
+		t.Errorf("Expected Arin as a merged node, got %+v", node)
+	}
+
+	// A rescan keeps the LLM-grounded evidence
+	if removed := m.RetractNote("note-1"); len(removed) != 0 || len(edge.Evidence) != 1 {
+		t.Errorf("Expected LLM evidence to survive RetractNote, removed %v", removed)
+	}
+}
+
+func TestRun_RerunIsCachedAndIdempotent(t *testing.T) {
+	p, st, m, calls := newPipeline(t)
+	ctx := context.Background()
+
+	if _, err := p.Run(ctx, "note-1", noteText, nil); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	first := make(map[string]float64)
+	for key, e := range m.GetMergedGraph().Edges {
+		first[key] = e.Confidence
+	}
+	report, err := p.Run(ctx, "note-1", noteText, nil)
+	if err != nil {
+		t.Fatalf("Rerun failed: %v", err)
+	}
+
+	if !report.Cached || *calls != 1 {
+		t.Errorf("Expected the rerun served from the cache, cached=%v calls=%d", report.Cached, *calls)
+	}
+	for _, e := range report.Entities[:3] {
+		if e.Status != StatusExisting {
+			t.Errorf("Expected %s to match the stored entity, got %s", e.Label, e.Status)
+		}
+	}
+	if report.EdgesAdded != 0 {
+		t.Errorf("Expected no new edges, got %d", report.EdgesAdded)
+	}
+	entities, _ := st.ListEntities("")
+	if len(entities) != 3 {
+		t.Errorf("Expected 3 stored entities, got %d", len(entities))
+	}
+
+	// The rerun replaces the note's LLM support instead of boosting it
+	uncapped := false
+	for key, e := range m.GetMergedGraph().Edges {
+		if e.Confidence != first[key] {
+			t.Errorf("Expected %s to keep confidence %.3f, got %.3f", key, first[key], e.Confidence)
+		}
+		uncapped = uncapped || e.Confidence < 1
+	}
+	if !uncapped || len(first) != 2 {
+		t.Errorf("Expected two edges, one below the confidence cap, got %v", first)
+	}
+}
+
+func TestRetractLLM_WithdrawsNoteSupport(t *testing.T) {
+	p, _, m, _ := newPipeline(t)
+	if _, err := p.Run(context.Background(), "note-1", noteText, nil); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	m.AddLLMEdges([]merger.LLMEdgeInput{{SourceID: "x", TargetID: "y", RelType: "KNOWS", Confidence: 0.5, SourceNoteID: "note-2"}})
+
+	if removed := m.RetractLLM("note-1"); len(removed) != 2 {
+		t.Errorf("Expected the note's two edges removed, got %v", removed)
+	}
+	edges := m.GetMergedGraph().Edges
+	if len(edges) != 1 || edges[merger.EdgeKey("x", "y", "KNOWS")] == nil {
+		t.Errorf("Expected only the other note's edge left, got %d edges", len(edges))
+	}
+}
+
+func TestRun_AliasesExtendExistingEntities(t *testing.T) {
+	p, st, _, _ := newPipeline(t)
+	st.UpsertEntity(&store.Entity{ID: "e-lyra", Label: "Lyra Vance", Kind: "CHARACTER", Aliases: []string{"Lyra"}})
+	st.UpsertEntity(&store.Entity{ID: "e-arin", Label: "Arin", Kind: "CHARACTER", Aliases: []string{}})
+
+	report, err := p.Run(context.Background(), "note-1", noteText, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if lyra := report.Entities[1]; lyra.Status != StatusExisting || lyra.EntityID != "e-lyra" {
+		t.Errorf("Expected Lyra matched through the alias, got %+v", lyra)
+	}
+	if rel := report.Relations[0]; !rel.Accepted || rel.SourceID != "e-arin" || rel.TargetID != "e-lyra" {
+		t.Errorf("Expected the relation between the stored entities, got %+v", rel)
+	}
+}
@@ -2,10 +2,12 @@ package merger
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/kittclouds/gokitt/pkg/graph"
-	"github.com/kittclouds/gokitt/pkg/reality/pcst"
+	"github.com/kittclouds/gokitt/pkg/ontology"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
 )
 
 // Provenance indicates where an edge came from
@@ -17,15 +19,61 @@ const (
 	ProvenanceManual  Provenance = "manual"  // User-created
 )
 
+// FactualityPolicy controls how negated or modal scanner edges are merged
+type FactualityPolicy string
+
+const (
+	FactualityKeep       FactualityPolicy = "keep"       // Merge as-is
+	FactualityDownweight FactualityPolicy = "downweight" // Scale by modality, drop negated
+	FactualityFilter     FactualityPolicy = "filter"     // Drop all non-factual edges
+)
+
 // MergedEdge represents an edge with combined metadata from multiple sources
 type MergedEdge struct {
-	SourceID    string         `json:"sourceId"`
-	TargetID    string         `json:"targetId"`
-	RelType     string         `json:"relType"`
-	Confidence  float64        `json:"confidence"`
-	Provenances []Provenance   `json:"provenances"` // Can have multiple sources
-	Attributes  map[string]any `json:"attributes,omitempty"`
-	SourceNotes []string       `json:"sourceNotes,omitempty"` // Which notes this edge came from
+	SourceID    string          `json:"sourceId"`
+	TargetID    string          `json:"targetId"`
+	RelType     string          `json:"relType"`
+	Confidence  float64         `json:"confidence"`
+	Provenances []Provenance    `json:"provenances"` // Can have multiple sources
+	Attributes  map[string]any  `json:"attributes,omitempty"`
+	SourceNotes []string        `json:"sourceNotes,omitempty"` // Which notes this edge came from
+	Evidence    []Evidence      `json:"evidence,omitempty"`    // Sentences that justified the edge
+	Valid       *graph.Interval `json:"valid,omitempty"`       // Story-time validity, from evidence
+	Support     Support         `json:"support"`               // LLM and manual support; Confidence combines it with Evidence
+}
+
+// Support is the LLM and manual backing of an edge; scanner support is its
+// non-LLM evidence
+type Support struct {
+	LLM    map[string]float64 `json:"llm,omitempty"`    // LLM confidence per source note
+	Manual bool               `json:"manual,omitempty"` // Added via AddManualEdges
+}
+
+// Evidence locates a sentence that justified an edge
+type Evidence struct {
+	NoteID   string          `json:"noteId"`
+	Sentence [2]int          `json:"sentence"` // Byte range [start, end)
+	Verb     [2]int          `json:"verb"`     // Byte range [start, end)
+	Weight   float64         `json:"weight"`
+	Authored bool            `json:"authored,omitempty"` // Explicit triple/wikilink
+	LLM      bool            `json:"llm,omitempty"`      // Grounded LLM relation; weight is informational
+	Valid    *graph.Interval `json:"valid,omitempty"`    // When the sentence says it held
+
+	// Factuality frame of the sentence (empty Modality means factual)
+	Negated  bool   `json:"negated,omitempty"`
+	Modality string `json:"modality,omitempty"`
+	Tense    string `json:"tense,omitempty"`
+}
+
+// isFactual reports whether the sentence asserts the relation as holding
+func (ev Evidence) isFactual() bool {
+	return narrative.Frame{Negated: ev.Negated, Modality: narrative.ParseModality(ev.Modality)}.IsFactual()
+}
+
+// sameSpan reports whether two pieces of evidence point at the same text
+func (ev Evidence) sameSpan(o Evidence) bool {
+	return ev.NoteID == o.NoteID && ev.Sentence == o.Sentence && ev.Verb == o.Verb &&
+		ev.Authored == o.Authored && ev.LLM == o.LLM
 }
 
 // MergedGraph is the combined graph from all sources
@@ -45,7 +93,10 @@ type MergeResult struct {
 
 // Merger combines edges from multiple sources
 type Merger struct {
-	merged *MergedGraph
+	merged     *MergedGraph
+	factuality FactualityPolicy
+	ontology   *ontology.Ontology
+	aliases    map[string]string // Normalised label or alias -> node ID
 }
 
 // New creates a new Merger
@@ -55,9 +106,48 @@ func New() *Merger {
 			Nodes: make(map[string]*graph.ConceptNode),
 			Edges: make(map[string]*MergedEdge),
 		},
+		factuality: FactualityDownweight,
+		aliases:    make(map[string]string),
 	}
 }
 
+// SetFactualityPolicy changes how non-factual scanner edges are merged
+func (m *Merger) SetFactualityPolicy(p FactualityPolicy) {
+	switch p {
+	case FactualityKeep, FactualityDownweight, FactualityFilter:
+		m.factuality = p
+	}
+}
+
+// SetOntology normalises incoming edges onto canonical relations:
+// synonyms are renamed, inverses flipped, symmetric endpoints ordered.
+// nil disables normalisation.
+func (m *Merger) SetOntology(o *ontology.Ontology) {
+	m.ontology = o
+}
+
+// normalize applies the ontology to a non-hierarchy edge
+func (m *Merger) normalize(sourceID, relType, targetID string) (string, string, string) {
+	if m.ontology == nil || graph.IsHierarchy(relType) {
+		return sourceID, relType, targetID
+	}
+	return m.ontology.Normalize(sourceID, relType, targetID)
+}
+
+// scannerWeight applies the factuality policy to a scanner edge.
+// Returns ok=false when the edge should not be merged at all.
+func (m *Merger) scannerWeight(e *graph.ConceptEdge) (float64, bool) {
+	frame := narrative.Frame{Negated: e.Negated, Modality: narrative.ParseModality(e.Modality)}
+	if frame.IsFactual() || m.factuality == FactualityKeep {
+		return e.Weight, true
+	}
+	if m.factuality == FactualityFilter {
+		return 0, false
+	}
+	w := e.Weight * frame.Weight()
+	return w, w > 0
+}
+
 // edgeKey generates a unique key for deduplication
 func edgeKey(sourceID, targetID, relType string) string {
 	// Normalize: always use smaller ID first for undirected comparison
@@ -65,50 +155,147 @@ func edgeKey(sourceID, targetID, relType string) string {
 	return fmt.Sprintf("%s-%s-%s", sourceID, strings.ToUpper(relType), targetID)
 }
 
-// AddScannerGraph adds edges from the Go CST scanner/projection
+// EdgeKey returns the merged-graph key of an edge
+func EdgeKey(sourceID, targetID, relType string) string {
+	return edgeKey(sourceID, targetID, relType)
+}
+
+// edge returns the merged edge for key, creating an empty one if needed
+func (m *Merger) edge(key, sourceID, targetID, relType string) (*MergedEdge, bool) {
+	if existing, exists := m.merged.Edges[key]; exists {
+		return existing, false
+	}
+	e := &MergedEdge{
+		SourceID:    sourceID,
+		TargetID:    targetID,
+		RelType:     relType,
+		Provenances: []Provenance{},
+		SourceNotes: []string{},
+	}
+	m.merged.Edges[key] = e
+	return e, true
+}
+
+// EnsureNode adds a node unless one with the same ID exists, and returns
+// the node in the merged graph. The label becomes an alias of the ID.
+func (m *Merger) EnsureNode(id, label, kind string) *graph.ConceptNode {
+	if label != "" && label != id {
+		m.Alias(label, id)
+	}
+	if existing, ok := m.merged.Nodes[id]; ok {
+		return existing
+	}
+	node := &graph.ConceptNode{
+		ID:       id,
+		Label:    label,
+		Kind:     kind,
+		Outbound: make([]*graph.ConceptEdge, 0),
+		Inbound:  make([]*graph.ConceptEdge, 0),
+	}
+	m.merged.Nodes[id] = node
+	return node
+}
+
+// AddScannerGraph adds edges from the Go CST scanner/projection.
+// Each edge is recorded as evidence (note, sentence, verb); adding the same
+// evidence twice does not inflate confidence. Use RetractNote first when a
+// note is rescanned.
 func (m *Merger) AddScannerGraph(g *graph.ConceptGraph, sourceNoteID string) int {
 	added := 0
 
-	// Add nodes
+	// Add nodes (a declared kind replaces a generic Concept); names of
+	// known nodes land on them
 	for _, node := range g.AllNodes() {
-		if _, exists := m.merged.Nodes[node.ID]; !exists {
+		if id := m.resolve(node.ID); id != node.ID {
+			m.EnsureNode(id, node.Label, node.Kind)
+			continue
+		}
+		existing, exists := m.merged.Nodes[node.ID]
+		if !exists || (existing.Kind == graph.KindConcept && node.Kind != graph.KindConcept) {
 			m.merged.Nodes[node.ID] = node
 		}
 	}
 
 	// Add edges
 	for _, edge := range g.AllEdges() {
-		key := edgeKey(edge.Source.ID, edge.Target.ID, string(edge.Edge.Relation))
-
-		if existing, exists := m.merged.Edges[key]; exists {
-			// Merge: add provenance, update confidence
-			existing.Provenances = appendUnique(existing.Provenances, ProvenanceScanner)
-			if sourceNoteID != "" {
-				existing.SourceNotes = appendUniqueStr(existing.SourceNotes, sourceNoteID)
-			}
-			// Boost confidence when multiple sources agree
-			existing.Confidence = boostConfidence(existing.Confidence, edge.Edge.Weight)
-		} else {
-			// New edge
-			notes := []string{}
-			if sourceNoteID != "" {
-				notes = append(notes, sourceNoteID)
-			}
-			m.merged.Edges[key] = &MergedEdge{
-				SourceID:    edge.Source.ID,
-				TargetID:    edge.Target.ID,
-				RelType:     string(edge.Edge.Relation),
-				Confidence:  edge.Edge.Weight,
-				Provenances: []Provenance{ProvenanceScanner},
-				SourceNotes: notes,
-			}
+		// Authored edges (triples, wikilinks) are user statements: certain
+		weight, ok := 1.0, true
+		if !edge.Edge.Authored {
+			weight, ok = m.scannerWeight(edge.Edge)
+		}
+		if !ok {
+			continue
+		}
+		sourceID, relType, targetID := m.normalize(m.resolve(edge.Source.ID), edge.Edge.Relation, m.resolve(edge.Target.ID))
+		key := edgeKey(sourceID, targetID, relType)
+		merged, created := m.edge(key, sourceID, targetID, relType)
+		if created {
 			added++
 		}
+
+		merged.Evidence = appendEvidence(merged.Evidence, edgeEvidence(edge.Edge, sourceNoteID, weight))
+		if sourceNoteID != "" {
+			merged.SourceNotes = appendUniqueStr(merged.SourceNotes, sourceNoteID)
+		}
+		merged.refresh()
 	}
 
 	return added
 }
 
+// RetractNote removes the scanner evidence a note contributed, e.g. before
+// the note is rescanned; spans grounding LLM edges stay with their LLM
+// support (see RetractLLM). Edges left without any provenance are deleted.
+// Returns the keys of the deleted edges.
+func (m *Merger) RetractNote(noteID string) []string {
+	return m.retract(func(e *MergedEdge) bool {
+		return e.dropEvidence(func(ev Evidence) bool { return ev.NoteID == noteID && !ev.LLM })
+	})
+}
+
+// RetractLLM removes the LLM support a note contributed, with the spans
+// grounding it, e.g. before the note is re-extracted. Edges left without
+// any provenance are deleted. Returns the keys of the deleted edges.
+func (m *Merger) RetractLLM(noteID string) []string {
+	return m.retract(func(e *MergedEdge) bool {
+		_, had := e.Support.LLM[noteID]
+		delete(e.Support.LLM, noteID)
+		dropped := e.dropEvidence(func(ev Evidence) bool { return ev.NoteID == noteID && ev.LLM })
+		return had || dropped
+	})
+}
+
+// retract applies drop to every edge, refreshing the ones it changed and
+// deleting those left without provenance
+func (m *Merger) retract(drop func(e *MergedEdge) bool) []string {
+	var removed []string
+	for key, e := range m.merged.Edges {
+		if !drop(e) {
+			continue
+		}
+		e.refreshNotes()
+		e.refresh()
+		if len(e.Provenances) == 0 {
+			delete(m.merged.Edges, key)
+			removed = append(removed, key)
+		}
+	}
+	return removed
+}
+
+// dropEvidence removes the evidence matching drop; reports whether any was
+func (e *MergedEdge) dropEvidence(drop func(Evidence) bool) bool {
+	kept := e.Evidence[:0]
+	for _, ev := range e.Evidence {
+		if !drop(ev) {
+			kept = append(kept, ev)
+		}
+	}
+	changed := len(kept) != len(e.Evidence)
+	e.Evidence = kept
+	return changed
+}
+
 // LLMEdgeInput is the structure for LLM-extracted edges
 type LLMEdgeInput struct {
 	SourceID     string         `json:"sourceId"`
@@ -117,47 +304,47 @@ type LLMEdgeInput struct {
 	Confidence   float64        `json:"confidence"`
 	Attributes   map[string]any `json:"attributes,omitempty"`
 	SourceNoteID string         `json:"sourceNoteId"`
+	Evidence     []Evidence     `json:"evidence,omitempty"` // Spans the edge was grounded in
 }
 
-// AddLLMEdges adds edges from LLM extraction
+// AddLLMEdges adds edges from LLM extraction. Support is kept per source
+// note: adding the same relation for a note again keeps the higher
+// confidence instead of boosting it. Use RetractLLM first when a note is
+// re-extracted.
 func (m *Merger) AddLLMEdges(edges []LLMEdgeInput) int {
 	added := 0
 
 	for _, e := range edges {
-		key := edgeKey(e.SourceID, e.TargetID, e.RelType)
-
-		if existing, exists := m.merged.Edges[key]; exists {
-			// Merge
-			existing.Provenances = appendUnique(existing.Provenances, ProvenanceLLM)
-			if e.SourceNoteID != "" {
-				existing.SourceNotes = appendUniqueStr(existing.SourceNotes, e.SourceNoteID)
-			}
-			existing.Confidence = boostConfidence(existing.Confidence, e.Confidence)
+		sourceID, relType, targetID := m.normalize(m.resolve(e.SourceID), e.RelType, m.resolve(e.TargetID))
+		key := edgeKey(sourceID, targetID, relType)
+		merged, created := m.edge(key, sourceID, targetID, relType)
+		if created {
+			added++
+			merged.Attributes = e.Attributes
+		} else if len(e.Attributes) > 0 {
 			// Merge attributes
-			if existing.Attributes == nil {
-				existing.Attributes = make(map[string]any)
+			if merged.Attributes == nil {
+				merged.Attributes = make(map[string]any)
 			}
 			for k, v := range e.Attributes {
-				if _, ok := existing.Attributes[k]; !ok {
-					existing.Attributes[k] = v
+				if _, ok := merged.Attributes[k]; !ok {
+					merged.Attributes[k] = v
 				}
 			}
-		} else {
-			notes := []string{}
-			if e.SourceNoteID != "" {
-				notes = append(notes, e.SourceNoteID)
-			}
-			m.merged.Edges[key] = &MergedEdge{
-				SourceID:    e.SourceID,
-				TargetID:    e.TargetID,
-				RelType:     e.RelType,
-				Confidence:  e.Confidence,
-				Provenances: []Provenance{ProvenanceLLM},
-				Attributes:  e.Attributes,
-				SourceNotes: notes,
-			}
-			added++
 		}
+
+		if merged.Support.LLM == nil {
+			merged.Support.LLM = make(map[string]float64)
+		}
+		merged.Support.LLM[e.SourceNoteID] = max(merged.Support.LLM[e.SourceNoteID], e.Confidence)
+		for _, ev := range e.Evidence {
+			ev.LLM = true
+			merged.Evidence = appendEvidence(merged.Evidence, ev)
+		}
+		if e.SourceNoteID != "" {
+			merged.SourceNotes = appendUniqueStr(merged.SourceNotes, e.SourceNoteID)
+		}
+		merged.refresh()
 	}
 
 	return added
@@ -176,39 +363,376 @@ func (m *Merger) AddManualEdges(edges []ManualEdgeInput) int {
 	added := 0
 
 	for _, e := range edges {
-		key := edgeKey(e.SourceID, e.TargetID, e.RelType)
-
-		if existing, exists := m.merged.Edges[key]; exists {
-			// Manual always wins for confidence
-			existing.Provenances = appendUnique(existing.Provenances, ProvenanceManual)
-			existing.Confidence = 1.0 // Manual = certain
-			if existing.Attributes == nil {
-				existing.Attributes = make(map[string]any)
+		sourceID, relType, targetID := m.normalize(m.resolve(e.SourceID), e.RelType, m.resolve(e.TargetID))
+		key := edgeKey(sourceID, targetID, relType)
+		merged, created := m.edge(key, sourceID, targetID, relType)
+		if created {
+			added++
+		}
+		if len(e.Attributes) > 0 {
+			// Manual always wins for attributes
+			if merged.Attributes == nil {
+				merged.Attributes = make(map[string]any)
 			}
 			for k, v := range e.Attributes {
-				existing.Attributes[k] = v
-			}
-		} else {
-			m.merged.Edges[key] = &MergedEdge{
-				SourceID:    e.SourceID,
-				TargetID:    e.TargetID,
-				RelType:     e.RelType,
-				Confidence:  1.0, // Manual = certain
-				Provenances: []Provenance{ProvenanceManual},
-				Attributes:  e.Attributes,
+				merged.Attributes[k] = v
 			}
-			added++
 		}
+
+		merged.Support.Manual = true // Manual = certain
+		merged.refresh()
 	}
 
 	return added
 }
 
+// Restore adds nodes and edges loaded from persistent storage. Node labels
+// become aliases, so a rescan keyed by name lands on the restored nodes.
+// Edges keep their persisted Support; edges stored without one (created by
+// the user, or before support was persisted) are manual when marked so, and
+// credit their stored confidence to LLM support when it is their only
+// non-scanner source.
+func (m *Merger) Restore(nodes []*graph.ConceptNode, edges []*MergedEdge) int {
+	for _, node := range nodes {
+		m.merged.Nodes[node.ID] = node
+	}
+	for _, node := range nodes {
+		if node.Label != "" && node.Label != node.ID {
+			m.Alias(node.Label, node.ID)
+		}
+	}
+
+	for _, e := range edges {
+		if len(e.Support.LLM) == 0 && !e.Support.Manual {
+			e.Support = legacySupport(e)
+		}
+		if e.SourceNotes == nil {
+			e.SourceNotes = []string{}
+		}
+		e.refresh()
+		key := edgeKey(e.SourceID, e.TargetID, e.RelType)
+		if existing, ok := m.merged.Edges[key]; ok {
+			existing.absorb(e)
+			continue
+		}
+		m.merged.Edges[key] = e
+	}
+	return len(edges)
+}
+
+// legacySupport derives the support of an edge stored without one
+func legacySupport(e *MergedEdge) Support {
+	authored := false
+	evidenceNotes := map[string]bool{}
+	for _, ev := range e.Evidence {
+		if !ev.LLM {
+			authored = authored || ev.Authored
+			evidenceNotes[ev.NoteID] = true
+		}
+	}
+	sup := Support{Manual: hasProvenance(e.Provenances, ProvenanceManual) && !authored}
+	if hasProvenance(e.Provenances, ProvenanceLLM) {
+		note := ""
+		for _, n := range e.SourceNotes {
+			if !evidenceNotes[n] {
+				note = n
+				break
+			}
+		}
+		sup.LLM = map[string]float64{note: e.Confidence}
+	}
+	return sup
+}
+
+// Alias makes name refer to the node id: edges added later under the name
+// (e.g. a rescan keyed by label) land on id, and a node already keyed by
+// the name is folded into id together with its edges. A name keeps the
+// first ID it was given.
+func (m *Merger) Alias(name, id string) {
+	key := aliasKey(name)
+	if key == "" || id == "" {
+		return
+	}
+	if existing, ok := m.aliases[key]; ok && existing != id {
+		return
+	}
+	m.aliases[key] = id
+	if _, ok := m.merged.Nodes[name]; ok && name != id {
+		m.rekey(name, id)
+	}
+}
+
+// resolve maps a name to the node ID it is an alias of
+func (m *Merger) resolve(id string) string {
+	if target, ok := m.aliases[aliasKey(id)]; ok {
+		return target
+	}
+	return id
+}
+
+// rekey moves node from and its edges onto node to, merging edges that
+// then denote the same relation
+func (m *Merger) rekey(from, to string) {
+	node := m.merged.Nodes[from]
+	delete(m.merged.Nodes, from)
+	if _, ok := m.merged.Nodes[to]; !ok && node != nil {
+		m.EnsureNode(to, node.Label, node.Kind)
+	}
+
+	for key, e := range m.merged.Edges {
+		if e.SourceID != from && e.TargetID != from {
+			continue
+		}
+		delete(m.merged.Edges, key)
+		sourceID, targetID := e.SourceID, e.TargetID
+		if sourceID == from {
+			sourceID = to
+		}
+		if targetID == from {
+			targetID = to
+		}
+		e.SourceID, e.RelType, e.TargetID = m.normalize(sourceID, e.RelType, targetID)
+		newKey := edgeKey(e.SourceID, e.TargetID, e.RelType)
+		if existing, ok := m.merged.Edges[newKey]; ok {
+			existing.absorb(e)
+		} else {
+			m.merged.Edges[newKey] = e
+		}
+	}
+}
+
+// Combine folds edges that denote the same relation (e.g. one keyed by a
+// label and one by the entity it names) into a new edge with their joint
+// evidence, support and notes. The first edge gives the endpoints.
+func Combine(edges ...*MergedEdge) *MergedEdge {
+	if len(edges) == 0 {
+		return nil
+	}
+	out := &MergedEdge{
+		SourceID:    edges[0].SourceID,
+		TargetID:    edges[0].TargetID,
+		RelType:     edges[0].RelType,
+		Provenances: []Provenance{},
+		SourceNotes: []string{},
+		Valid:       edges[0].Valid,
+	}
+	for _, e := range edges {
+		out.absorb(e)
+	}
+	return out
+}
+
+// absorb folds o's evidence, support, notes and attributes into e
+func (e *MergedEdge) absorb(o *MergedEdge) {
+	for _, ev := range o.Evidence {
+		e.Evidence = appendEvidence(e.Evidence, ev)
+	}
+	for note, c := range o.Support.LLM {
+		if e.Support.LLM == nil {
+			e.Support.LLM = make(map[string]float64)
+		}
+		e.Support.LLM[note] = max(e.Support.LLM[note], c)
+	}
+	e.Support.Manual = e.Support.Manual || o.Support.Manual
+	for _, n := range o.SourceNotes {
+		e.SourceNotes = appendUniqueStr(e.SourceNotes, n)
+	}
+	for k, v := range o.Attributes {
+		if e.Attributes == nil {
+			e.Attributes = make(map[string]any)
+		}
+		if _, ok := e.Attributes[k]; !ok {
+			e.Attributes[k] = v
+		}
+	}
+	e.Valid = e.Valid.Hull(o.Valid)
+	e.refresh()
+}
+
+func aliasKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// refresh recomputes provenances and confidence from per-source support
+func (e *MergedEdge) refresh() {
+	scanner, authored := false, false
+	for _, ev := range e.Evidence {
+		if ev.LLM {
+			continue
+		}
+		if ev.Authored {
+			authored = true
+		} else {
+			scanner = true
+		}
+	}
+
+	provs := make([]Provenance, 0, 3)
+	if scanner {
+		provs = append(provs, ProvenanceScanner)
+	}
+	if len(e.Support.LLM) > 0 {
+		provs = append(provs, ProvenanceLLM)
+	}
+	if e.Support.Manual || authored {
+		provs = append(provs, ProvenanceManual)
+	}
+	e.Provenances = provs
+	e.Confidence = e.combined()
+	e.Valid = e.validity()
+	e.refreshFactuality()
+}
+
+// refreshFactuality derives the negated/modality/tense attributes from the
+// scanner evidence: the edge is factual when any sentence asserts it, and
+// otherwise takes the frame of its first sentence. Manual edges and edges
+// without scanner evidence keep the attributes they were given.
+func (e *MergedEdge) refreshFactuality() {
+	if e.Support.Manual {
+		return
+	}
+	var frame *Evidence
+	for i := range e.Evidence {
+		ev := &e.Evidence[i]
+		if ev.LLM {
+			continue
+		}
+		if frame == nil || (!frame.isFactual() && ev.isFactual()) {
+			frame = ev
+		}
+	}
+	if frame == nil {
+		return
+	}
+
+	for _, k := range []string{"negated", "modality", "tense"} {
+		delete(e.Attributes, k)
+	}
+	set := func(k string, v any) {
+		if e.Attributes == nil {
+			e.Attributes = make(map[string]any)
+		}
+		e.Attributes[k] = v
+	}
+	if frame.Negated {
+		set("negated", true)
+	}
+	if narrative.ParseModality(frame.Modality) != narrative.ModalityFactual {
+		set("modality", frame.Modality)
+	}
+	if frame.Tense != "" {
+		set("tense", frame.Tense)
+	}
+	if len(e.Attributes) == 0 {
+		e.Attributes = nil
+	}
+}
+
+// validity covers every scanner evidence interval. LLM and manual support
+// carry no story time, so edges without such evidence keep the interval
+// they were given (nil, unless restored from the store).
+func (e *MergedEdge) validity() *graph.Interval {
+	var valid *graph.Interval
+	seen := false
+	for _, ev := range e.Evidence {
+		if ev.LLM {
+			continue
+		}
+		if !seen {
+			valid, seen = ev.Valid, true
+			continue
+		}
+		valid = valid.Hull(ev.Valid)
+	}
+	if !seen {
+		return e.Valid
+	}
+	return valid
+}
+
+// combined merges all support: 1 - prod(1 - c)
+func (e *MergedEdge) combined() float64 {
+	if e.Support.Manual {
+		return 1.0
+	}
+	conf := 0.0
+	for _, c := range e.Support.LLM {
+		conf = boostConfidence(conf, c)
+	}
+	for _, ev := range e.Evidence {
+		if ev.LLM {
+			continue // Already counted in llm
+		}
+		conf = boostConfidence(conf, ev.Weight)
+	}
+	return conf
+}
+
+// refreshNotes rebuilds SourceNotes from evidence and LLM notes
+func (e *MergedEdge) refreshNotes() {
+	notes := make([]string, 0, len(e.SourceNotes))
+	for _, ev := range e.Evidence {
+		if ev.NoteID != "" {
+			notes = appendUniqueStr(notes, ev.NoteID)
+		}
+	}
+	llmNotes := make([]string, 0, len(e.Support.LLM))
+	for n := range e.Support.LLM {
+		if n != "" {
+			llmNotes = append(llmNotes, n)
+		}
+	}
+	sort.Strings(llmNotes)
+	for _, n := range llmNotes {
+		notes = appendUniqueStr(notes, n)
+	}
+	e.SourceNotes = notes
+}
+
 // GetMergedGraph returns the combined graph
 func (m *Merger) GetMergedGraph() *MergedGraph {
 	return m.merged
 }
 
+// GraphAt returns the relationship state at a story point: the edges whose
+// validity contains t, and the nodes they connect
+func (m *Merger) GraphAt(t float64) *MergedGraph {
+	at := &MergedGraph{
+		Nodes: make(map[string]*graph.ConceptNode),
+		Edges: make(map[string]*MergedEdge),
+	}
+	for key, e := range m.merged.Edges {
+		if !e.Valid.Contains(t) {
+			continue
+		}
+		at.Edges[key] = e
+		for _, id := range []string{e.SourceID, e.TargetID} {
+			if node, ok := m.merged.Nodes[id]; ok {
+				at.Nodes[id] = node
+			}
+		}
+	}
+	return at
+}
+
+// Graph returns the merged graph as a ConceptGraph weighted by confidence,
+// so the graph package's algorithms (paths, centrality, communities) apply
+func (mg *MergedGraph) Graph() *graph.ConceptGraph {
+	g := graph.NewGraph()
+	for id, node := range mg.Nodes {
+		g.EnsureNode(id, node.Label, node.Kind)
+	}
+	for _, edge := range mg.Edges {
+		source := g.EnsureNode(edge.SourceID, edge.SourceID, graph.KindConcept)
+		target := g.EnsureNode(edge.TargetID, edge.TargetID, graph.KindConcept)
+		g.AddEdge(source, target, &graph.ConceptEdge{
+			Relation: edge.RelType,
+			Weight:   edge.Confidence,
+		})
+	}
+	return g
+}
+
 // GetStats returns merge statistics
 func (m *Merger) GetStats() MergeResult {
 	result := MergeResult{
@@ -234,43 +758,10 @@ func (m *Merger) GetStats() MergeResult {
 	return result
 }
 
-// ToConceptGraph converts merged graph to a ConceptGraph for PCST
+// ToConceptGraph converts merged graph to a ConceptGraph for PCST,
+// priced with ConfidenceCost
 func (m *Merger) ToConceptGraph() *graph.ConceptGraph {
-	g := graph.NewGraph()
-
-	// Add nodes
-	for id, node := range m.merged.Nodes {
-		g.EnsureNode(id, node.Label, node.Kind)
-	}
-
-	// Add edges
-	for _, edge := range m.merged.Edges {
-		source, _ := g.Nodes[edge.SourceID]
-		target, _ := g.Nodes[edge.TargetID]
-
-		if source == nil || target == nil {
-			// Create placeholder nodes if needed
-			if source == nil {
-				source = g.EnsureNode(edge.SourceID, edge.SourceID, graph.KindConcept)
-			}
-			if target == nil {
-				target = g.EnsureNode(edge.TargetID, edge.TargetID, graph.KindConcept)
-			}
-		}
-
-		// Weight = 1 - confidence (PCST uses cost, lower is better)
-		weight := 1.0 - edge.Confidence
-		if weight < 0.01 {
-			weight = 0.01 // Minimum cost
-		}
-
-		g.AddEdge(source, target, &graph.ConceptEdge{
-			Relation: edge.RelType,
-			Weight:   weight,
-		})
-	}
-
-	return g
+	return m.merged.CostGraph(ConfidenceCost)
 }
 
 // RunPCST runs the PCST algorithm on the merged graph
@@ -278,42 +769,7 @@ func (m *Merger) ToConceptGraph() *graph.ConceptGraph {
 // rootID: optional root node for the tree
 // Returns the filtered subgraph
 func (m *Merger) RunPCST(prizes map[string]float64, rootID string) (*MergedGraph, error) {
-	g := m.ToConceptGraph()
-
-	solver := pcst.NewIpcstSolver(pcst.DefaultConfig())
-	solution, err := solver.Solve(g, prizes, rootID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Build filtered graph from solution
-	filtered := &MergedGraph{
-		Nodes: make(map[string]*graph.ConceptNode),
-		Edges: make(map[string]*MergedEdge),
-	}
-
-	// Add solution nodes
-	nodeSet := make(map[string]bool)
-	for _, nodeID := range solution.Nodes {
-		nodeSet[nodeID] = true
-		if node, ok := m.merged.Nodes[nodeID]; ok {
-			filtered.Nodes[nodeID] = node
-		}
-	}
-
-	// Add solution edges
-	for _, edge := range solution.Edges {
-		// Find matching edge in merged graph
-		for key, mergedEdge := range m.merged.Edges {
-			if (mergedEdge.SourceID == edge.SourceID && mergedEdge.TargetID == edge.TargetID) ||
-				(mergedEdge.SourceID == edge.TargetID && mergedEdge.TargetID == edge.SourceID) {
-				filtered.Edges[key] = mergedEdge
-				break
-			}
-		}
-	}
-
-	return filtered, nil
+	return m.Summarize(prizes, rootID, nil)
 }
 
 // Helper functions
@@ -328,13 +784,40 @@ func boostConfidence(existing, new float64) float64 {
 	return combined
 }
 
-func appendUnique(slice []Provenance, p Provenance) []Provenance {
+func edgeEvidence(e *graph.ConceptEdge, sourceNoteID string, weight float64) Evidence {
+	noteID := e.SourceDoc
+	if noteID == "" {
+		noteID = sourceNoteID
+	}
+	return Evidence{
+		NoteID: noteID, Sentence: e.SourceSpan, Verb: e.VerbSpan, Weight: weight, Authored: e.Authored, Valid: e.Valid,
+		Negated: e.Negated, Modality: e.Modality, Tense: e.Tense,
+	}
+}
+
+// appendEvidence adds ev unless the same span is already recorded,
+// in which case the higher weight and the latest validity and frame are kept
+func appendEvidence(list []Evidence, ev Evidence) []Evidence {
+	for i, existing := range list {
+		if existing.sameSpan(ev) {
+			if ev.Weight > existing.Weight {
+				list[i].Weight = ev.Weight
+			}
+			list[i].Valid = ev.Valid
+			list[i].Negated, list[i].Modality, list[i].Tense = ev.Negated, ev.Modality, ev.Tense
+			return list
+		}
+	}
+	return append(list, ev)
+}
+
+func hasProvenance(slice []Provenance, p Provenance) bool {
 	for _, existing := range slice {
 		if existing == p {
-			return slice
+			return true
 		}
 	}
-	return append(slice, p)
+	return false
 }
 
 func appendUniqueStr(slice []string, s string) []string {
@@ -0,0 +1,183 @@
+package merger
+
+import (
+	"sort"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/reality/pcst"
+)
+
+// CostPolicy prices a merged edge for PCST; the tree keeps cheap edges first
+type CostPolicy func(e *MergedEdge) float64
+
+// ConfidenceCost prices an edge by inverse confidence, halved for manual
+// edges and cut by a quarter when more than one source agrees
+func ConfidenceCost(e *MergedEdge) float64 {
+	cost := pcst.InverseConfidence(e.Confidence)
+	switch {
+	case hasProvenance(e.Provenances, ProvenanceManual):
+		cost *= 0.5
+	case len(e.Provenances) > 1:
+		cost *= 0.75
+	}
+	return max(cost, pcst.MinCost)
+}
+
+// CostGraph converts the merged graph to a ConceptGraph whose edge
+// weights are PCST costs
+func (mg *MergedGraph) CostGraph(cost CostPolicy) *graph.ConceptGraph {
+	g := graph.NewGraph()
+	for id, node := range mg.Nodes {
+		g.EnsureNode(id, node.Label, node.Kind)
+	}
+	for _, edge := range mg.Edges {
+		source := g.EnsureNode(edge.SourceID, edge.SourceID, graph.KindConcept)
+		target := g.EnsureNode(edge.TargetID, edge.TargetID, graph.KindConcept)
+		g.AddEdge(source, target, &graph.ConceptEdge{
+			Relation: edge.RelType,
+			Weight:   cost(edge),
+		})
+	}
+	return g
+}
+
+// Mentions counts the sentences supporting each node's edges (a source
+// note stands in for edges without sentence evidence)
+func (mg *MergedGraph) Mentions() map[string]int {
+	counts := make(map[string]int)
+	for _, e := range mg.Edges {
+		n := max(len(e.Evidence), len(e.SourceNotes), 1)
+		counts[e.SourceID] += n
+		counts[e.TargetID] += n
+	}
+	return counts
+}
+
+// LastSeen returns each node's latest story position among the notes its
+// edges came from. Nodes only seen in unordered notes are left out.
+func (mg *MergedGraph) LastSeen(order map[string]float64) map[string]float64 {
+	seen := make(map[string]float64)
+	for _, e := range mg.Edges {
+		for _, note := range e.SourceNotes {
+			t, ok := order[note]
+			if !ok {
+				continue
+			}
+			for _, id := range []string{e.SourceID, e.TargetID} {
+				if prev, ok := seen[id]; !ok || t > prev {
+					seen[id] = t
+				}
+			}
+		}
+	}
+	return seen
+}
+
+// NoteRelevance spreads note search scores onto the nodes those notes
+// connect: a node scores its best-ranked note
+func (mg *MergedGraph) NoteRelevance(hits map[string]float64) pcst.Prizes {
+	out := make(pcst.Prizes)
+	for _, e := range mg.Edges {
+		for _, note := range e.SourceNotes {
+			score, ok := hits[note]
+			if !ok {
+				continue
+			}
+			for _, id := range []string{e.SourceID, e.TargetID} {
+				out[id] = max(out[id], score)
+			}
+		}
+	}
+	return out
+}
+
+// Summarize runs PCST over the merged graph and returns the chosen
+// subgraph. cost defaults to ConfidenceCost. Every merged edge between a
+// pair the tree connects is kept, so parallel relations are not lost.
+func (m *Merger) Summarize(prizes map[string]float64, rootID string, cost CostPolicy) (*MergedGraph, error) {
+	return m.SummarizeWith(pcst.DefaultConfig(), prizes, rootID, cost)
+}
+
+// SummarizeWith is Summarize with explicit solver settings (pruning
+// strategy, number of trees)
+func (m *Merger) SummarizeWith(cfg pcst.Config, prizes map[string]float64, rootID string, cost CostPolicy) (*MergedGraph, error) {
+	if cost == nil {
+		cost = ConfidenceCost
+	}
+
+	solver := pcst.NewIpcstSolver(cfg)
+	solution, err := solver.Solve(m.merged.CostGraph(cost), prizes, rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := &MergedGraph{
+		Nodes: make(map[string]*graph.ConceptNode),
+		Edges: make(map[string]*MergedEdge),
+	}
+	for _, id := range solution.Nodes {
+		if node, ok := m.merged.Nodes[id]; ok {
+			filtered.Nodes[id] = node
+		}
+	}
+	if node, ok := m.merged.Nodes[rootID]; ok {
+		filtered.Nodes[rootID] = node
+	}
+
+	pairs := make(map[[2]string]bool, len(solution.Edges))
+	for _, e := range solution.Edges {
+		pairs[pairKey(e.SourceID, e.TargetID)] = true
+	}
+	for key, e := range m.merged.Edges {
+		if pairs[pairKey(e.SourceID, e.TargetID)] {
+			filtered.Edges[key] = e
+		}
+	}
+	return filtered, nil
+}
+
+// QuerySubgraph returns the PCST summary around a search query. Nodes whose
+// labels match the query, and nodes from notes the search ranked (noteHits:
+// note ID -> score), carry large prizes; centrality adds small prizes so
+// the tree can route through hubs. The tree is rooted at the best match.
+// Returns an empty graph when nothing matches.
+func (m *Merger) QuerySubgraph(query string, noteHits map[string]float64) (*MergedGraph, error) {
+	g := m.merged.Graph()
+	relevance := pcst.Combine(
+		pcst.Weighted{Prizes: pcst.LabelMatch(g, query), Weight: 2},
+		pcst.Weighted{Prizes: m.merged.NoteRelevance(noteHits), Weight: 1},
+	)
+	if len(relevance) == 0 {
+		return &MergedGraph{
+			Nodes: make(map[string]*graph.ConceptNode),
+			Edges: make(map[string]*MergedEdge),
+		}, nil
+	}
+
+	ids := make([]string, 0, len(relevance))
+	for id := range relevance {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if relevance[ids[i]] != relevance[ids[j]] {
+			return relevance[ids[i]] > relevance[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	prizes := pcst.Combine(
+		pcst.Weighted{Prizes: relevance, Weight: 3},
+		pcst.Weighted{Prizes: pcst.Centrality(g), Weight: 0.5},
+	)
+	// Strong pruning keeps only the profitable tree around the root
+	cfg := pcst.DefaultConfig()
+	cfg.Pruning = pcst.PruneStrong
+	return m.SummarizeWith(cfg, prizes, ids[0], nil)
+}
+
+func pairKey(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
@@ -23,16 +23,29 @@ type Edge struct {
 	TargetID string
 }
 
+// Pruning selects how the GW forest is cleaned up
+type Pruning string
+
+const (
+	PruneNone   Pruning = "none"   // Keep every edge GW made tight
+	PruneSimple Pruning = "simple" // Strip leaves that carry no prize
+	PruneGW     Pruning = "gw"     // Cut dead clusters hanging by one edge (classic GW)
+	PruneStrong Pruning = "strong" // GW, then keep each tree's most profitable subtree
+)
+
 // Config for IPCST
 type Config struct {
 	Beta     float64
 	MaxDepth int
+	Pruning  Pruning // Empty = PruneGW
+	NumTrees int     // Unrooted only: keep the N most profitable trees (0 = all)
 }
 
 func DefaultConfig() Config {
 	return Config{
 		Beta:     2.0,
 		MaxDepth: 10,
+		Pruning:  PruneGW,
 	}
 }
 
@@ -63,7 +76,7 @@ type IpcstSolver struct {
 func NewIpcstSolver(cfg Config) *IpcstSolver {
 	return &IpcstSolver{
 		config: cfg,
-		gw:     &gwSolver{epsilon: 1e-10},
+		gw:     &gwSolver{epsilon: 1e-10, pruning: cfg.Pruning},
 	}
 }
 
@@ -74,6 +87,15 @@ func (s *IpcstSolver) Solve(g *graph.ConceptGraph, prizes map[string]float64, ro
 	// Run recursive solver
 	sol := s.solveRecursive(inst, 0)
 
+	// Post-process the best forest
+	if s.config.Pruning == PruneStrong {
+		sol = strongPrune(inst, sol)
+	}
+	if inst.root == -1 && s.config.NumTrees > 0 {
+		sol = limitTrees(inst, sol, s.config.NumTrees)
+	}
+	sol.cost = s.calculateCost(inst, sol)
+
 	// Convert back to external solution
 	return s.convertSolution(inst, sol), nil
 }
@@ -139,40 +161,11 @@ func (s *IpcstSolver) solveRecursive(inst *pcstInstance, depth int) *pcsfSolutio
 }
 
 func (s *IpcstSolver) calculateCost(inst *pcstInstance, sol *pcsfSolution) Cost {
-	edgeCost := 0.0
-	// For each edge in solution, find cost in instance
-	// This is a bit inefficient (O(M)), but fine for now.
-	// We can optimize by storing edge costs in solution or map.
-	// Since expected edge count in solution is small:
-
-	// Build adjacency of instance for fast lookup? Or just iterate edges if we kept indices?
-	// Let's rely on internalEdge having cost.
-	// But sol.edges only has u,v.
-	// We need to look up the cost.
-
-	// Optimization: create map for instance edges
-	edgeMap := make(map[[2]int]Cost)
-	for _, e := range inst.edges {
-		u, v := e.u, e.v
-		if u > v {
-			u, v = v, u
-		}
-		// Use min cost if multigraph (shouldn't be for this use case but safe)
-		if c, ok := edgeMap[[2]int{u, v}]; ok {
-			if e.cost < c {
-				edgeMap[[2]int{u, v}] = e.cost
-			}
-		} else {
-			edgeMap[[2]int{u, v}] = e.cost
-		}
-	}
+	edgeMap := inst.edgeCosts()
 
+	edgeCost := 0.0
 	for _, e := range sol.edges {
-		u, v := e.u, e.v
-		if u > v {
-			u, v = v, u
-		}
-		edgeCost += edgeMap[[2]int{u, v}]
+		edgeCost += edgeMap[orderedPair(e.u, e.v)]
 	}
 
 	penaltyCost := 0.0
@@ -190,6 +183,25 @@ func (s *IpcstSolver) calculateCost(inst *pcstInstance, sol *pcsfSolution) Cost
 	return edgeCost + penaltyCost
 }
 
+// edgeCosts maps each (u < v) pair to its cheapest edge cost
+func (inst *pcstInstance) edgeCosts() map[[2]int]Cost {
+	edgeMap := make(map[[2]int]Cost, len(inst.edges))
+	for _, e := range inst.edges {
+		p := orderedPair(e.u, e.v)
+		if c, ok := edgeMap[p]; !ok || e.cost < c {
+			edgeMap[p] = e.cost
+		}
+	}
+	return edgeMap
+}
+
+func orderedPair(u, v int) [2]int {
+	if u > v {
+		u, v = v, u
+	}
+	return [2]int{u, v}
+}
+
 func (s *IpcstSolver) buildInstance(g *graph.ConceptGraph, prizes map[string]float64, rootID string) *pcstInstance {
 	nodes := g.AllNodes()
 	count := len(nodes)
@@ -335,6 +347,7 @@ func (inst *pcstInstance) withZeroedPenalties(zeros map[int]bool) *pcstInstance
 
 type gwSolver struct {
 	epsilon float64
+	pruning Pruning
 }
 
 type gwResult struct {
@@ -499,7 +512,15 @@ func (gw *gwSolver) solve(inst *pcstInstance) *gwResult {
 	}
 
 	// Pruning
-	finalEdges := prune(selectedEdges, deadSets)
+	var finalEdges [][2]int
+	switch gw.pruning {
+	case PruneNone:
+		finalEdges = selectedEdges
+	case PruneSimple:
+		finalEdges = simplePrune(inst, selectedEdges)
+	default:
+		finalEdges = prune(selectedEdges, deadSets)
+	}
 
 	// Build result
 	solEdges := make([]struct{ u, v int }, 0)
@@ -0,0 +1,147 @@
+package pcst
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+)
+
+// Prizes maps node IDs to prizes. A node joins the tree when its prize
+// outweighs the cost of the edges connecting it.
+type Prizes map[string]float64
+
+// Weighted is one prize source in a Combine
+type Weighted struct {
+	Prizes Prizes
+	Weight float64
+}
+
+// Normalize scales prizes so the largest is 1. Non-positive prizes are dropped.
+func Normalize(p Prizes) Prizes {
+	top := 0.0
+	for _, v := range p {
+		top = max(top, v)
+	}
+	out := make(Prizes, len(p))
+	if top <= 0 {
+		return out
+	}
+	for id, v := range p {
+		if v > 0 {
+			out[id] = v / top
+		}
+	}
+	return out
+}
+
+// Combine sums several prize sources, each normalised first so weights
+// compare sources rather than their scales
+func Combine(sources ...Weighted) Prizes {
+	out := make(Prizes)
+	for _, s := range sources {
+		for id, v := range Normalize(s.Prizes) {
+			out[id] += s.Weight * v
+		}
+	}
+	return out
+}
+
+// Uniform gives every node of g the same prize
+func Uniform(g *graph.ConceptGraph, prize float64) Prizes {
+	out := make(Prizes, len(g.Nodes))
+	for id := range g.Nodes {
+		out[id] = prize
+	}
+	return out
+}
+
+// Mentions turns mention counts into prizes, damped with log(1+n) so a
+// protagonist mentioned 500 times does not drown out everyone else
+func Mentions(counts map[string]int) Prizes {
+	out := make(Prizes, len(counts))
+	for id, n := range counts {
+		if n > 0 {
+			out[id] = math.Log1p(float64(n))
+		}
+	}
+	return out
+}
+
+// Centrality prizes nodes by PageRank over g's story edges. Edge weights
+// are read as strengths (confidence), not PCST costs.
+func Centrality(g *graph.ConceptGraph) Prizes {
+	story := g.Filter(func(e *graph.ConceptEdge) bool { return !graph.IsHierarchy(e.Relation) })
+	return Prizes(story.PageRank(0.85, 50))
+}
+
+// Recency prizes nodes by when they were last seen: a node last seen at
+// now scores 1, halving every halfLife story units before that
+func Recency(lastSeen map[string]float64, now, halfLife float64) Prizes {
+	out := make(Prizes, len(lastSeen))
+	for id, t := range lastSeen {
+		age := max(now-t, 0)
+		if halfLife <= 0 {
+			if age == 0 {
+				out[id] = 1
+			}
+			continue
+		}
+		out[id] = math.Pow(0.5, age/halfLife)
+	}
+	return out
+}
+
+// LabelMatch prizes nodes whose labels share words with a query: the
+// fraction of query words found in the label
+func LabelMatch(g *graph.ConceptGraph, query string) Prizes {
+	words := tokens(query)
+	out := make(Prizes)
+	if len(words) == 0 {
+		return out
+	}
+	for id, node := range g.Nodes {
+		label := make(map[string]bool)
+		for _, w := range tokens(node.Label) {
+			label[w] = true
+		}
+		hits := 0
+		for _, w := range words {
+			if label[w] {
+				hits++
+			}
+		}
+		if hits > 0 {
+			out[id] = float64(hits) / float64(len(words))
+		}
+	}
+	return out
+}
+
+func tokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// InverseConfidence prices an edge of confidence c by the odds against it
+// (1/c - 1): certain edges are nearly free, doubtful ones expensive
+func InverseConfidence(c float64) Cost {
+	return max(1/max(c, 0.05)-1, MinCost)
+}
+
+// MinCost is the cheapest an edge can be, so certain edges still break ties
+const MinCost = 0.01
+
+// CostGraph copies a confidence-weighted graph's story edges with weights
+// turned into PCST costs by InverseConfidence
+func CostGraph(g *graph.ConceptGraph) *graph.ConceptGraph {
+	out := g.Filter(func(e *graph.ConceptEdge) bool { return !graph.IsHierarchy(e.Relation) })
+	for _, node := range out.Nodes {
+		for _, e := range node.Outbound {
+			e.Weight = InverseConfidence(e.Weight)
+		}
+	}
+	return out
+}
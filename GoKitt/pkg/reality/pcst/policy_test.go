@@ -0,0 +1,66 @@
+package pcst_test
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/reality/pcst"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombinePrizes(t *testing.T) {
+	prizes := pcst.Combine(
+		pcst.Weighted{Prizes: pcst.Prizes{"a": 10, "b": 5}, Weight: 2},
+		pcst.Weighted{Prizes: pcst.Prizes{"b": 0.2, "c": 0.1, "d": 0}, Weight: 1},
+	)
+
+	// Sources are normalised before weighting
+	assert.InDelta(t, 2.0, prizes["a"], 1e-9)
+	assert.InDelta(t, 2.0, prizes["b"], 1e-9)
+	assert.InDelta(t, 0.5, prizes["c"], 1e-9)
+	assert.NotContains(t, prizes, "d")
+}
+
+func TestPrizeSources(t *testing.T) {
+	mentions := pcst.Mentions(map[string]int{"hero": 500, "cook": 3, "ghost": 0})
+	assert.Greater(t, mentions["hero"], mentions["cook"])
+	assert.Less(t, mentions["hero"], 5*mentions["cook"]) // Damped
+	assert.NotContains(t, mentions, "ghost")
+
+	recency := pcst.Recency(map[string]float64{"now": 10, "old": 6, "older": 2}, 10, 4)
+	assert.InDelta(t, 1.0, recency["now"], 1e-9)
+	assert.InDelta(t, 0.5, recency["old"], 1e-9)
+	assert.InDelta(t, 0.25, recency["older"], 1e-9)
+
+	g := graph.NewGraph()
+	g.AddEdgeWithNodes("arin", "Arin Vale", "CHARACTER", "tower", "Black Tower", "PLACE", "LOCATED_IN", 1)
+	g.AddEdgeWithNodes("lyra", "Lyra", "CHARACTER", "tower", "Black Tower", "PLACE", "LOCATED_IN", 1)
+
+	match := pcst.LabelMatch(g, "the black tower")
+	assert.InDelta(t, 2.0/3.0, match["tower"], 1e-9)
+	assert.NotContains(t, match, "arin")
+
+	central := pcst.Centrality(g)
+	assert.Greater(t, central["tower"], central["arin"])
+}
+
+func TestPrizesSteerTree(t *testing.T) {
+	// a - b - c with a costly spur b - d: only the prized end is worth the spur
+	g := graph.NewGraph()
+	g.AddEdgeWithNodes("a", "a", "test", "b", "b", "test", "rel", 1)
+	g.AddEdgeWithNodes("b", "b", "test", "c", "c", "test", "rel", 1)
+	g.AddEdgeWithNodes("b", "b", "test", "d", "d", "test", "rel", 3)
+
+	solver := pcst.NewIpcstSolver(pcst.DefaultConfig())
+
+	uniform, err := solver.Solve(g, pcst.Uniform(g, 2), "a")
+	assert.NoError(t, err)
+	assert.NotContains(t, uniform.Nodes, "d")
+
+	boosted := pcst.Uniform(g, 2)
+	boosted["d"] = 10
+	focused, err := solver.Solve(g, boosted, "a")
+	assert.NoError(t, err)
+	assert.Contains(t, focused.Nodes, "d")
+}
@@ -0,0 +1,253 @@
+package pcst
+
+import "sort"
+
+// -----------------------------------------------------------------------------
+// Pruning strategies
+// -----------------------------------------------------------------------------
+
+// simplePrune repeatedly strips non-root leaves whose prize is zero: they
+// only add edge cost
+func simplePrune(inst *pcstInstance, edges [][2]int) [][2]int {
+	current := make(map[[2]int]bool, len(edges))
+	degree := make(map[int]int)
+	for _, e := range edges {
+		current[orderedPair(e[0], e[1])] = true
+		degree[e[0]]++
+		degree[e[1]]++
+	}
+
+	changed := true
+	for changed {
+		changed = false
+		for e := range current {
+			for _, leaf := range e {
+				if degree[leaf] == 1 && leaf != inst.root && inst.penalties[leaf] <= 0 {
+					delete(current, e)
+					degree[e[0]]--
+					degree[e[1]]--
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	res := make([][2]int, 0, len(current))
+	for e := range current {
+		res = append(res, e)
+	}
+	return res
+}
+
+// tree is one connected component of a solution forest
+type tree struct {
+	nodes []int // Sorted
+	adj   map[int][]int
+}
+
+// components splits a solution into its trees, ordered by smallest node
+func components(sol *pcsfSolution) []tree {
+	adj := make(map[int][]int)
+	for _, n := range sol.nodes {
+		if _, ok := adj[n]; !ok {
+			adj[n] = nil
+		}
+	}
+	for _, e := range sol.edges {
+		adj[e.u] = append(adj[e.u], e.v)
+		adj[e.v] = append(adj[e.v], e.u)
+	}
+
+	ids := make([]int, 0, len(adj))
+	for n := range adj {
+		ids = append(ids, n)
+	}
+	sort.Ints(ids)
+
+	seen := make(map[int]bool)
+	var trees []tree
+	for _, start := range ids {
+		if seen[start] {
+			continue
+		}
+		t := tree{adj: make(map[int][]int)}
+		stack := []int{start}
+		seen[start] = true
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			t.nodes = append(t.nodes, n)
+			t.adj[n] = adj[n]
+			for _, next := range adj[n] {
+				if !seen[next] {
+					seen[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+		sort.Ints(t.nodes)
+		trees = append(trees, t)
+	}
+	return trees
+}
+
+// strongPrune keeps the most profitable subtree of each tree (Johnson,
+// Minkoff and Phillips): a subtree hanging off an edge is cut when its
+// prizes do not pay for it plus the edge. Rooted solutions keep only the
+// root's tree; unrooted trees are re-rooted at their best node and dropped
+// when no subtree is profitable.
+func strongPrune(inst *pcstInstance, sol *pcsfSolution) *pcsfSolution {
+	costs := inst.edgeCosts()
+	out := &pcsfSolution{}
+
+	keep := func(t tree, top int, parent map[int]int, worth map[int]float64) {
+		stack := []int{top}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			out.nodes = append(out.nodes, n)
+			for _, c := range t.adj[n] {
+				if parent[c] == n && worth[c]-costs[orderedPair(n, c)] > 0 {
+					out.edges = append(out.edges, struct{ u, v int }{n, c})
+					stack = append(stack, c)
+				}
+			}
+		}
+	}
+
+	rootFound := false
+	for _, t := range components(sol) {
+		start := t.nodes[0]
+		if inst.root != -1 {
+			if _, ok := t.adj[inst.root]; !ok {
+				continue
+			}
+			start = inst.root
+			rootFound = true
+		}
+
+		// Post-order worth: prize plus every child subtree that pays its edge
+		parent := map[int]int{start: -1}
+		order := []int{start}
+		for i := 0; i < len(order); i++ {
+			n := order[i]
+			for _, c := range t.adj[n] {
+				if _, ok := parent[c]; !ok {
+					parent[c] = n
+					order = append(order, c)
+				}
+			}
+		}
+		worth := make(map[int]float64, len(order))
+		for i := len(order) - 1; i >= 0; i-- {
+			n := order[i]
+			worth[n] += inst.penalties[n]
+			if p := parent[n]; p != -1 {
+				worth[p] += max(worth[n]-costs[orderedPair(p, n)], 0)
+			}
+		}
+
+		top := start
+		if inst.root == -1 {
+			// Any subtree has a unique highest node, so the best subtree
+			// hangs from the node of greatest worth
+			for _, n := range t.nodes {
+				if worth[n] > worth[top] {
+					top = n
+				}
+			}
+			if worth[top] <= 0 {
+				continue
+			}
+		}
+		keep(t, top, parent, worth)
+	}
+
+	if inst.root != -1 && !rootFound {
+		out.nodes = append(out.nodes, inst.root)
+	}
+	if inst.root == -1 {
+		out = withBestSingleton(inst, out)
+	}
+	return out
+}
+
+// withBestSingleton replaces an empty solution by the best single node: GW
+// never selects an isolated node, but one with a prize beats nothing
+func withBestSingleton(inst *pcstInstance, sol *pcsfSolution) *pcsfSolution {
+	if len(sol.nodes) > 0 {
+		return sol
+	}
+	best := -1
+	for i, p := range inst.penalties {
+		if p > 0 && (best == -1 || p > inst.penalties[best]) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return sol
+	}
+	return &pcsfSolution{nodes: []int{best}}
+}
+
+// limitTrees keeps the n most profitable trees of an unrooted forest. Single
+// prized nodes outside the forest compete as one-node trees, so asking for
+// one tree returns the best of the forest's trees and the best lone node.
+func limitTrees(inst *pcstInstance, sol *pcsfSolution, n int) *pcsfSolution {
+	costs := inst.edgeCosts()
+
+	type candidate struct {
+		nodes []int
+		worth float64
+	}
+	trees := components(sol)
+	var candidates []candidate
+	covered := make(map[int]bool)
+	for _, t := range trees {
+		c := candidate{nodes: t.nodes}
+		for _, v := range t.nodes {
+			covered[v] = true
+			c.worth += inst.penalties[v]
+			for _, u := range t.adj[v] {
+				if u > v {
+					c.worth -= costs[orderedPair(u, v)]
+				}
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	for v, p := range inst.penalties {
+		if !covered[v] && p > 0 {
+			candidates = append(candidates, candidate{nodes: []int{v}, worth: p})
+		}
+	}
+	if len(candidates) <= n && len(candidates) == len(trees) {
+		return sol
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].worth != candidates[j].worth {
+			return candidates[i].worth > candidates[j].worth
+		}
+		return candidates[i].nodes[0] < candidates[j].nodes[0]
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+
+	kept := make(map[int]bool)
+	out := &pcsfSolution{}
+	for _, c := range candidates {
+		for _, v := range c.nodes {
+			kept[v] = true
+			out.nodes = append(out.nodes, v)
+		}
+	}
+	for _, e := range sol.edges {
+		if kept[e.u] {
+			out.edges = append(out.edges, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,256 @@
+package pcst_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/reality/pcst"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// instance is a small PCST problem with its costs kept for brute force
+type instance struct {
+	g      *graph.ConceptGraph
+	ids    []string
+	prizes map[string]float64
+	costs  map[[2]string]float64
+}
+
+func randomInstance(rng *rand.Rand, n int) *instance {
+	inst := &instance{
+		g:      graph.NewGraph(),
+		prizes: make(map[string]float64),
+		costs:  make(map[[2]string]float64),
+	}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("n%d", i)
+		inst.ids = append(inst.ids, id)
+		inst.g.EnsureNode(id, id, "test")
+		if rng.Float64() < 0.7 {
+			inst.prizes[id] = math.Round(rng.Float64()*60) / 10
+		}
+	}
+	for i := 1; i < n; i++ {
+		// A random spanning tree plus a few chords keeps the graph connected
+		inst.addEdge(rng, inst.ids[rng.Intn(i)], inst.ids[i])
+	}
+	for k := 0; k < n/2; k++ {
+		inst.addEdge(rng, inst.ids[rng.Intn(n)], inst.ids[rng.Intn(n)])
+	}
+	return inst
+}
+
+func (inst *instance) addEdge(rng *rand.Rand, a, b string) {
+	if a == b {
+		return
+	}
+	key := pair(a, b)
+	if _, ok := inst.costs[key]; ok {
+		return
+	}
+	cost := 0.5 + math.Round(rng.Float64()*40)/10
+	inst.costs[key] = cost
+	inst.g.AddEdgeWithNodes(a, a, "test", b, b, "test", "rel", cost)
+}
+
+func pair(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// cost prices a solution: its edges plus the prizes of nodes it leaves out
+func (inst *instance) cost(sol *pcst.Solution) float64 {
+	in := make(map[string]bool)
+	for _, id := range sol.Nodes {
+		in[id] = true
+	}
+	total := 0.0
+	for _, e := range sol.Edges {
+		total += inst.costs[pair(e.SourceID, e.TargetID)]
+	}
+	for id, p := range inst.prizes {
+		if !in[id] {
+			total += p
+		}
+	}
+	return total
+}
+
+// optimum finds the cheapest single tree by trying every node subset: a
+// subset costs its minimum spanning tree plus the prizes left out. The
+// empty tree is allowed unless rooted.
+func (inst *instance) optimum(root string) float64 {
+	n := len(inst.ids)
+	best := math.Inf(1)
+	for mask := 0; mask < 1<<n; mask++ {
+		in := make(map[string]bool)
+		for i, id := range inst.ids {
+			if mask&(1<<i) != 0 {
+				in[id] = true
+			}
+		}
+		if root != "" && !in[root] {
+			continue
+		}
+		mst, ok := inst.mst(in)
+		if !ok {
+			continue
+		}
+		total := mst
+		for id, p := range inst.prizes {
+			if !in[id] {
+				total += p
+			}
+		}
+		best = math.Min(best, total)
+	}
+	return best
+}
+
+// mst returns the spanning tree cost of the induced subgraph, or false
+// when it is disconnected
+func (inst *instance) mst(in map[string]bool) (float64, bool) {
+	type edge struct {
+		a, b string
+		cost float64
+	}
+	var edges []edge
+	for k, c := range inst.costs {
+		if in[k[0]] && in[k[1]] {
+			edges = append(edges, edge{k[0], k[1], c})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].cost < edges[j].cost })
+
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(x string) string {
+		if p, ok := parent[x]; ok && p != x {
+			parent[x] = find(p)
+			return parent[x]
+		}
+		return x
+	}
+	total, joined := 0.0, 0
+	for _, e := range edges {
+		ra, rb := find(e.a), find(e.b)
+		if ra != rb {
+			parent[ra] = rb
+			total += e.cost
+			joined++
+		}
+	}
+	return total, len(in) == 0 || joined == len(in)-1
+}
+
+// assertForest checks that the solution's edges join listed nodes without
+// cycles and returns its number of trees
+func assertForest(t *testing.T, inst *instance, sol *pcst.Solution) int {
+	t.Helper()
+	in := make(map[string]bool)
+	for _, id := range sol.Nodes {
+		assert.False(t, in[id], "node %s listed twice", id)
+		in[id] = true
+	}
+	for _, e := range sol.Edges {
+		assert.True(t, in[e.SourceID] && in[e.TargetID], "edge %v joins unlisted nodes", e)
+		_, ok := inst.costs[pair(e.SourceID, e.TargetID)]
+		assert.True(t, ok, "edge %v not in graph", e)
+	}
+	assert.GreaterOrEqual(t, len(sol.Nodes)-len(sol.Edges), 0, "solution has a cycle")
+	if len(sol.Nodes) == 0 {
+		return 0
+	}
+	return len(sol.Nodes) - len(sol.Edges)
+}
+
+func TestPruningAgainstBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
+	for round := 0; round < 60; round++ {
+		inst := randomInstance(rng, 4+rng.Intn(5))
+		root := ""
+		if round%2 == 1 {
+			root = inst.ids[rng.Intn(len(inst.ids))]
+		}
+		opt := inst.optimum(root)
+
+		costs := make(map[pcst.Pruning]float64)
+		for _, pruning := range []pcst.Pruning{pcst.PruneNone, pcst.PruneSimple, pcst.PruneGW, pcst.PruneStrong} {
+			cfg := pcst.DefaultConfig()
+			cfg.Pruning = pruning
+			cfg.NumTrees = 1
+
+			sol, err := pcst.NewIpcstSolver(cfg).Solve(inst.g, inst.prizes, root)
+			require.NoError(t, err)
+
+			trees := assertForest(t, inst, sol)
+			cost := inst.cost(sol)
+			costs[pruning] = cost
+			assert.InDelta(t, cost, sol.TotalCost, 1e-9, "round %d %s: TotalCost", round, pruning)
+			assert.GreaterOrEqual(t, cost, opt-1e-9, "round %d %s: beat the optimum", round, pruning)
+			if root == "" {
+				assert.LessOrEqual(t, trees, 1, "round %d %s: expected one tree", round, pruning)
+			} else if pruning == pcst.PruneStrong {
+				assert.Contains(t, sol.Nodes, root, "round %d: root dropped", round)
+			}
+		}
+
+		// GW's guarantee, and strong pruning only ever improves on GW
+		assert.LessOrEqual(t, costs[pcst.PruneGW], 2*opt+1e-9, "round %d: GW outside 2-approximation", round)
+		assert.LessOrEqual(t, costs[pcst.PruneStrong], costs[pcst.PruneGW]+1e-9, "round %d: strong worse than GW", round)
+	}
+}
+
+func TestNumTrees(t *testing.T) {
+	// Two profitable clusters joined by an expensive bridge
+	g := graph.NewGraph()
+	g.AddEdgeWithNodes("a1", "a1", "test", "a2", "a2", "test", "rel", 1)
+	g.AddEdgeWithNodes("b1", "b1", "test", "b2", "b2", "test", "rel", 1)
+	g.AddEdgeWithNodes("a2", "a2", "test", "b1", "b1", "test", "rel", 50)
+	prizes := map[string]float64{"a1": 10, "a2": 10, "b1": 5, "b2": 5}
+
+	cfg := pcst.DefaultConfig()
+	cfg.Pruning = pcst.PruneStrong
+
+	forest, err := pcst.NewIpcstSolver(cfg).Solve(g, prizes, "")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a1", "a2", "b1", "b2"}, forest.Nodes)
+	assert.Len(t, forest.Edges, 2)
+
+	cfg.NumTrees = 1
+	single, err := pcst.NewIpcstSolver(cfg).Solve(g, prizes, "")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a1", "a2"}, single.Nodes)
+	assert.InDelta(t, 1+10, single.TotalCost, 1e-9)
+}
+
+func TestStrongPruningCutsUnprofitableBranch(t *testing.T) {
+	// a - b is worth it; b - c costs more than c's prize
+	g := graph.NewGraph()
+	g.AddEdgeWithNodes("a", "a", "test", "b", "b", "test", "rel", 1)
+	g.AddEdgeWithNodes("b", "b", "test", "c", "c", "test", "rel", 3)
+	prizes := map[string]float64{"a": 5, "b": 5, "c": 2}
+
+	cfg := pcst.DefaultConfig()
+	cfg.Pruning = pcst.PruneStrong
+	sol, err := pcst.NewIpcstSolver(cfg).Solve(g, prizes, "a")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, sol.Nodes)
+
+	// A lone prized node is better than an empty solution
+	lone := graph.NewGraph()
+	lone.EnsureNode("n0", "n0", "test")
+	sol, err = pcst.NewIpcstSolver(cfg).Solve(lone, map[string]float64{"n0": 3}, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"n0"}, sol.Nodes)
+	assert.Zero(t, sol.TotalCost)
+}
@@ -0,0 +1,376 @@
+// Package persist reconciles the merged knowledge graph with the store's
+// entities and edges tables, and rebuilds a Merger from them on startup.
+package persist
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kittclouds/gokitt/internal/ids"
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+)
+
+// SyncResult reports what a Sync changed
+type SyncResult struct {
+	EntitiesCreated int `json:"entitiesCreated"`
+	EdgesCreated    int `json:"edgesCreated"`
+	EdgesUpdated    int `json:"edgesUpdated"`
+	EdgesRetracted  int `json:"edgesRetracted"`
+}
+
+// Service syncs merged graphs into a store.
+type Service struct {
+	store store.Storer
+}
+
+// NewService creates a persistence service backed by the given store.
+func NewService(s store.Storer) *Service {
+	return &Service{store: s}
+}
+
+// Sync reconciles the merged graph into the store:
+//   - typed nodes without a stored entity become entities
+//   - merged edges are created or updated (confidence, provenances, notes, evidence)
+//   - stored scanner-only edges missing from the graph are retracted
+//
+// The graph is expected to contain everything previously synced (see Load);
+// World nodes and their hierarchy edges are not persisted.
+func (s *Service) Sync(g *merger.MergedGraph) (*SyncResult, error) {
+	result := &SyncResult{}
+
+	ids, err := s.syncEntities(g, result)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.ListEdges()
+	if err != nil {
+		return nil, fmt.Errorf("persist: list edges: %w", err)
+	}
+	stored := make(map[string]*store.Edge, len(existing))
+	for _, e := range existing {
+		stored[e.ID] = e
+	}
+
+	now := time.Now().UnixMilli()
+	seen := make(map[string]bool, len(g.Edges))
+	for _, me := range storeEdges(g, ids) {
+		edge := ToStoreEdge(me, mapID(ids, me.SourceID), mapID(ids, me.TargetID))
+		seen[edge.ID] = true
+
+		old, ok := stored[edge.ID]
+		if ok {
+			edge.CreatedAt = old.CreatedAt
+			edge.Bidirectional = old.Bidirectional
+			if sameEdge(old, edge) {
+				continue
+			}
+			result.EdgesUpdated++
+		} else {
+			edge.CreatedAt = now
+			result.EdgesCreated++
+		}
+		if err := s.store.UpsertEdge(edge); err != nil {
+			return nil, fmt.Errorf("persist: upsert edge %s: %w", edge.ID, err)
+		}
+	}
+
+	// Retract scanner edges whose evidence is gone. Edges with LLM or manual
+	// provenance (or none, i.e. created directly by the user) are kept.
+	for id, e := range stored {
+		if seen[id] || !scannerOnly(e.Provenances) {
+			continue
+		}
+		if err := s.store.DeleteEdge(id); err != nil {
+			return nil, fmt.Errorf("persist: retract edge %s: %w", id, err)
+		}
+		result.EdgesRetracted++
+	}
+
+	return result, nil
+}
+
+// storeEdges returns the graph's non-world edges, one per store edge ID.
+// Edges that map onto the same store edge (e.g. one keyed by a label and
+// one by the entity it names) are combined, in key order, rather than
+// overwriting each other.
+func storeEdges(g *merger.MergedGraph, ids map[string]string) []*merger.MergedEdge {
+	keys := make([]string, 0, len(g.Edges))
+	for key, me := range g.Edges {
+		if !isWorld(g, me.SourceID) && !isWorld(g, me.TargetID) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	groups := make(map[string][]*merger.MergedEdge, len(keys))
+	order := make([]string, 0, len(keys))
+	for _, key := range keys {
+		me := g.Edges[key]
+		id := merger.EdgeKey(mapID(ids, me.SourceID), mapID(ids, me.TargetID), me.RelType)
+		if _, ok := groups[id]; !ok {
+			order = append(order, id)
+		}
+		groups[id] = append(groups[id], me)
+	}
+
+	edges := make([]*merger.MergedEdge, 0, len(order))
+	for _, id := range order {
+		if group := groups[id]; len(group) == 1 {
+			edges = append(edges, group[0])
+		} else {
+			edges = append(edges, merger.Combine(group...))
+		}
+	}
+	return edges
+}
+
+// syncEntities maps graph node IDs to entity IDs, creating entities for
+// typed nodes that are not stored yet
+func (s *Service) syncEntities(g *merger.MergedGraph, result *SyncResult) (map[string]string, error) {
+	mapped := make(map[string]string)
+	for id, node := range g.Nodes {
+		if node.Kind == graph.KindWorld {
+			continue
+		}
+
+		entity, err := s.store.GetEntity(id)
+		if err != nil {
+			return nil, fmt.Errorf("persist: get entity %s: %w", id, err)
+		}
+		if entity == nil && node.Label != "" {
+			if entity, err = s.store.GetEntityByLabel(node.Label); err != nil {
+				return nil, fmt.Errorf("persist: lookup entity %q: %w", node.Label, err)
+			}
+		}
+		if entity != nil {
+			mapped[id] = entity.ID
+			continue
+		}
+
+		// Untyped prose concepts ("the troll") stay graph-only
+		if node.Kind == "" || node.Kind == graph.KindConcept {
+			continue
+		}
+		now := time.Now().UnixMilli()
+		entity = &store.Entity{
+			ID:        ids.New(),
+			Label:     node.Label,
+			Kind:      strings.ToUpper(node.Kind),
+			Aliases:   []string{},
+			CreatedBy: "extraction",
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := s.store.UpsertEntity(entity); err != nil {
+			return nil, fmt.Errorf("persist: create entity %q: %w", node.Label, err)
+		}
+		mapped[id] = entity.ID
+		result.EntitiesCreated++
+	}
+	return mapped, nil
+}
+
+// Load rebuilds a merger from the stored entities and edges. Entity labels
+// and aliases become merger aliases, so later rescans keyed by name land on
+// the restored entities. Returns the number of edges restored.
+func (s *Service) Load(m *merger.Merger) (int, error) {
+	entities, err := s.store.ListEntities("")
+	if err != nil {
+		return 0, fmt.Errorf("persist: list entities: %w", err)
+	}
+	edges, err := s.store.ListEdges()
+	if err != nil {
+		return 0, fmt.Errorf("persist: list edges: %w", err)
+	}
+
+	nodes := make([]*graph.ConceptNode, 0, len(entities))
+	for _, e := range entities {
+		nodes = append(nodes, &graph.ConceptNode{ID: e.ID, Label: e.Label, Kind: e.Kind})
+	}
+	merged := make([]*merger.MergedEdge, 0, len(edges))
+	for _, e := range edges {
+		merged = append(merged, ToMergedEdge(e))
+	}
+
+	n := m.Restore(nodes, merged)
+	for _, e := range entities {
+		for _, alias := range e.Aliases {
+			m.Alias(alias, e.ID)
+		}
+	}
+	return n, nil
+}
+
+// MergeLLMEdge folds one LLM-asserted edge into the stored edge for the
+// same relation, keeping that edge's evidence and other support, and saves
+// it. The input's IDs and relation are used as given: normalise them first.
+func (s *Service) MergeLLMEdge(in merger.LLMEdgeInput) (*store.Edge, error) {
+	return s.mergeEdge(in.SourceID, in.TargetID, in.RelType, func(m *merger.Merger) {
+		m.AddLLMEdges([]merger.LLMEdgeInput{in})
+	})
+}
+
+// MergeManualEdge is MergeLLMEdge for an edge the user asserted.
+func (s *Service) MergeManualEdge(in merger.ManualEdgeInput) (*store.Edge, error) {
+	return s.mergeEdge(in.SourceID, in.TargetID, in.RelType, func(m *merger.Merger) {
+		m.AddManualEdges([]merger.ManualEdgeInput{in})
+	})
+}
+
+// mergeEdge restores the stored edge (if any) into a scratch merger, lets
+// add merge the new support into it, and saves the result.
+func (s *Service) mergeEdge(sourceID, targetID, relType string, add func(*merger.Merger)) (*store.Edge, error) {
+	id := merger.EdgeKey(sourceID, targetID, relType)
+	old, err := s.store.GetEdge(id)
+	if err != nil {
+		return nil, fmt.Errorf("persist: get edge %s: %w", id, err)
+	}
+
+	m := merger.New()
+	if old != nil {
+		m.Restore(nil, []*merger.MergedEdge{ToMergedEdge(old)})
+	}
+	add(m)
+	me, ok := m.GetMergedGraph().Edges[id]
+	if !ok {
+		return nil, fmt.Errorf("persist: edge %s was not merged", id)
+	}
+
+	edge := ToStoreEdge(me, sourceID, targetID)
+	edge.CreatedAt = time.Now().UnixMilli()
+	if old != nil {
+		edge.CreatedAt, edge.Bidirectional = old.CreatedAt, old.Bidirectional
+	}
+	if err := s.store.UpsertEdge(edge); err != nil {
+		return nil, fmt.Errorf("persist: upsert edge %s: %w", id, err)
+	}
+	return edge, nil
+}
+
+// ToStoreEdge converts a merged edge into a store edge between the given IDs
+func ToStoreEdge(me *merger.MergedEdge, sourceID, targetID string) *store.Edge {
+	edge := &store.Edge{
+		ID:          merger.EdgeKey(sourceID, targetID, me.RelType),
+		SourceID:    sourceID,
+		TargetID:    targetID,
+		RelType:     strings.ToUpper(me.RelType),
+		Confidence:  me.Confidence,
+		SourceNotes: me.SourceNotes,
+		Attributes:  me.Attributes,
+	}
+	if me.Valid != nil {
+		edge.StoryFrom, edge.StoryUntil = me.Valid.From, me.Valid.Until
+	}
+	if len(me.SourceNotes) > 0 {
+		edge.SourceNote = me.SourceNotes[0]
+	}
+	for _, p := range me.Provenances {
+		edge.Provenances = append(edge.Provenances, string(p))
+	}
+	if len(me.Support.LLM) > 0 || me.Support.Manual {
+		edge.Support = &store.EdgeSupport{LLM: me.Support.LLM, Manual: me.Support.Manual}
+	}
+	for _, ev := range me.Evidence {
+		se := store.EdgeEvidence{
+			NoteID:   ev.NoteID,
+			Sentence: ev.Sentence,
+			Verb:     ev.Verb,
+			Weight:   ev.Weight,
+			Authored: ev.Authored,
+			LLM:      ev.LLM,
+			Negated:  ev.Negated,
+			Modality: ev.Modality,
+			Tense:    ev.Tense,
+		}
+		if ev.Valid != nil {
+			se.From, se.Until = ev.Valid.From, ev.Valid.Until
+		}
+		edge.Evidence = append(edge.Evidence, se)
+	}
+	return edge
+}
+
+// ToMergedEdge converts a stored edge back into a merged edge.
+// Edges without provenance were created directly by the user: manual.
+func ToMergedEdge(e *store.Edge) *merger.MergedEdge {
+	me := &merger.MergedEdge{
+		SourceID:    e.SourceID,
+		TargetID:    e.TargetID,
+		RelType:     e.RelType,
+		Confidence:  e.Confidence,
+		Attributes:  e.Attributes,
+		SourceNotes: e.SourceNotes,
+		Valid:       graph.NewInterval(e.StoryFrom, e.StoryUntil),
+	}
+	if len(me.SourceNotes) == 0 && e.SourceNote != "" {
+		me.SourceNotes = []string{e.SourceNote}
+	}
+	for _, p := range e.Provenances {
+		me.Provenances = append(me.Provenances, merger.Provenance(p))
+	}
+	if len(me.Provenances) == 0 {
+		me.Provenances = []merger.Provenance{merger.ProvenanceManual}
+	}
+	if e.Support != nil {
+		me.Support = merger.Support{LLM: e.Support.LLM, Manual: e.Support.Manual}
+	}
+	for _, ev := range e.Evidence {
+		me.Evidence = append(me.Evidence, merger.Evidence{
+			NoteID:   ev.NoteID,
+			Sentence: ev.Sentence,
+			Verb:     ev.Verb,
+			Weight:   ev.Weight,
+			Authored: ev.Authored,
+			LLM:      ev.LLM,
+			Valid:    graph.NewInterval(ev.From, ev.Until),
+			Negated:  ev.Negated,
+			Modality: ev.Modality,
+			Tense:    ev.Tense,
+		})
+	}
+	return me
+}
+
+func isWorld(g *merger.MergedGraph, id string) bool {
+	if node, ok := g.Nodes[id]; ok && node.Kind == graph.KindWorld {
+		return true
+	}
+	return strings.HasPrefix(id, "world:")
+}
+
+func mapID(ids map[string]string, id string) string {
+	if mapped, ok := ids[id]; ok {
+		return mapped
+	}
+	return id
+}
+
+func scannerOnly(provs []string) bool {
+	if len(provs) == 0 {
+		return false
+	}
+	for _, p := range provs {
+		if p != string(merger.ProvenanceScanner) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameEdge reports whether a stored edge already matches the synced one
+func sameEdge(a, b *store.Edge) bool {
+	return a.SourceID == b.SourceID && a.TargetID == b.TargetID && a.RelType == b.RelType &&
+		a.Confidence == b.Confidence && a.SourceNote == b.SourceNote &&
+		graph.NewInterval(a.StoryFrom, a.StoryUntil).Equal(graph.NewInterval(b.StoryFrom, b.StoryUntil)) &&
+		reflect.DeepEqual(a.Provenances, b.Provenances) &&
+		reflect.DeepEqual(a.SourceNotes, b.SourceNotes) &&
+		reflect.DeepEqual(a.Attributes, b.Attributes) &&
+		reflect.DeepEqual(a.Evidence, b.Evidence) &&
+		reflect.DeepEqual(a.Support, b.Support)
+}
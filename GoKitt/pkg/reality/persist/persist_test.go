@@ -0,0 +1,326 @@
+package persist
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+)
+
+func newTestService(t *testing.T) (*Service, *store.SQLiteStore) {
+	s, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return NewService(s), s
+}
+
+// noteGraph is the projection of note-1: "Arin killed the troll."
+func noteGraph() *graph.ConceptGraph {
+	g := graph.NewGraph()
+	world := g.EnsureNode("world:note-1", "Note 1", graph.KindWorld)
+	arin := g.EnsureNode("Arin", "Arin", "CHARACTER")
+	troll := g.EnsureNode("the troll", "the troll", graph.KindConcept)
+	g.AddEdge(arin, troll, &graph.ConceptEdge{
+		Relation:   "KILLS",
+		Weight:     1.0,
+		SourceDoc:  "note-1",
+		SourceSpan: [2]int{0, 22},
+		VerbSpan:   [2]int{5, 11},
+	})
+	g.AddEdge(world, arin, &graph.ConceptEdge{Relation: graph.RelWorldContains, Weight: 1.0})
+	return g
+}
+
+func TestSyncAndLoad(t *testing.T) {
+	svc, s := newTestService(t)
+
+	m := merger.New()
+	m.AddScannerGraph(noteGraph(), "note-1")
+	m.AddLLMEdges([]merger.LLMEdgeInput{
+		{SourceID: "Arin", TargetID: "Lyra", RelType: "LOVES", Confidence: 0.7, SourceNoteID: "note-2"},
+	})
+
+	result, err := svc.Sync(m.GetMergedGraph())
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if result.EntitiesCreated != 1 || result.EdgesCreated != 2 {
+		t.Errorf("Expected 1 entity and 2 edges created, got %+v", result)
+	}
+
+	arin, _ := s.GetEntityByLabel("Arin")
+	if arin == nil || arin.Kind != "CHARACTER" {
+		t.Fatalf("Expected typed node to become an entity, got %+v", arin)
+	}
+	if troll, _ := s.GetEntityByLabel("the troll"); troll != nil {
+		t.Error("Expected untyped concept to stay graph-only")
+	}
+
+	kills, _ := s.GetEdge(merger.EdgeKey(arin.ID, "the troll", "KILLS"))
+	if kills == nil {
+		t.Fatal("Expected KILLS edge between the entity and the concept")
+	}
+	if len(kills.Provenances) != 1 || kills.Provenances[0] != "scanner" ||
+		len(kills.Evidence) != 1 || kills.Evidence[0].Sentence != [2]int{0, 22} {
+		t.Errorf("Expected scanner provenance and evidence, got %+v", kills)
+	}
+
+	// Syncing again changes nothing
+	if result, _ := svc.Sync(m.GetMergedGraph()); *result != (SyncResult{}) {
+		t.Errorf("Expected idempotent sync, got %+v", result)
+	}
+
+	// Startup: rebuild a merger from the store
+	m2 := merger.New()
+	if n, err := svc.Load(m2); err != nil || n != 2 {
+		t.Fatalf("Expected 2 edges loaded, got %d (%v)", n, err)
+	}
+	loves := m2.GetMergedGraph().Edges[merger.EdgeKey(arin.ID, "Lyra", "LOVES")]
+	if loves == nil || loves.Confidence != 0.7 || loves.Provenances[0] != merger.ProvenanceLLM {
+		t.Errorf("Expected LLM edge restored, got %+v", loves)
+	}
+
+	// Rescan note-1 without the sentence: its scanner edge is retracted
+	m2.RetractNote("note-1")
+	m2.AddScannerGraph(graph.NewGraph(), "note-1")
+	result, err = svc.Sync(m2.GetMergedGraph())
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if result.EdgesRetracted != 1 {
+		t.Errorf("Expected 1 retracted edge, got %+v", result)
+	}
+	if e, _ := s.GetEdge(kills.ID); e != nil {
+		t.Error("Expected KILLS edge to be deleted")
+	}
+	if n, _ := s.CountEdges(); n != 1 {
+		t.Errorf("Expected the LLM edge to remain, got %d edges", n)
+	}
+}
+
+func TestRetractKeepsOtherSources(t *testing.T) {
+	m := merger.New()
+	m.AddScannerGraph(noteGraph(), "note-1")
+	m.AddLLMEdges([]merger.LLMEdgeInput{
+		{SourceID: "Arin", TargetID: "the troll", RelType: "KILLS", Confidence: 0.5, SourceNoteID: "note-3"},
+	})
+
+	key := merger.EdgeKey("Arin", "the troll", "KILLS")
+	if e := m.GetMergedGraph().Edges[key]; e.Confidence != 1.0 || len(e.Provenances) != 2 {
+		t.Fatalf("Expected combined scanner+llm edge, got %+v", e)
+	}
+
+	// Only the note's WORLD_CONTAINS edge loses all support
+	if removed := m.RetractNote("note-1"); len(removed) != 1 || removed[0] == key {
+		t.Errorf("Expected KILLS to survive retraction, got %v", removed)
+	}
+	e := m.GetMergedGraph().Edges[key]
+	if e.Confidence != 0.5 || len(e.Provenances) != 1 || e.Provenances[0] != merger.ProvenanceLLM {
+		t.Errorf("Expected only LLM support to remain, got %+v", e)
+	}
+	if len(e.SourceNotes) != 1 || e.SourceNotes[0] != "note-3" {
+		t.Errorf("Expected note-1 removed from source notes, got %v", e.SourceNotes)
+	}
+}
+
+func TestSyncStoryTime(t *testing.T) {
+	svc, s := newTestService(t)
+
+	// note-1 (chapter 1): "Arin ruled Eldoria until the war (chapter 5)."
+	g := graph.NewGraph()
+	arin := g.EnsureNode("Arin", "Arin", "CHARACTER")
+	eldoria := g.EnsureNode("Eldoria", "Eldoria", "PLACE")
+	g.AddEdge(arin, eldoria, &graph.ConceptEdge{
+		Relation:  "RULES",
+		Weight:    1.0,
+		SourceDoc: "note-1",
+		Valid:     graph.NewInterval(graph.StoryPoint(1), graph.StoryPoint(5)),
+	})
+
+	m := merger.New()
+	m.AddScannerGraph(g, "note-1")
+	if _, err := svc.Sync(m.GetMergedGraph()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	if edges, _ := s.ListEdgesAt(3); len(edges) != 1 || edges[0].RelType != "RULES" {
+		t.Errorf("Expected RULES stored as valid at 3, got %v", edges)
+	}
+	if edges, _ := s.ListEdgesAt(5); len(edges) != 0 {
+		t.Errorf("Expected RULES to end at 5, got %v", edges)
+	}
+
+	m2 := merger.New()
+	if _, err := svc.Load(m2); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(m2.GraphAt(3).Edges) != 1 || len(m2.GraphAt(6).Edges) != 0 {
+		t.Error("Expected story-time validity to survive a reload")
+	}
+}
+
+func TestSyncFactuality(t *testing.T) {
+	svc, s := newTestService(t)
+
+	// note-1: "Arin might kill the king." note-2: "Arin killed the king."
+	sentence := func(noteID, modality string) *graph.ConceptGraph {
+		g := graph.NewGraph()
+		arin := g.EnsureNode("Arin", "Arin", "CHARACTER")
+		king := g.EnsureNode("the king", "the king", "CHARACTER")
+		g.AddEdge(arin, king, &graph.ConceptEdge{
+			Relation: "KILLS", Weight: 1.0, SourceDoc: noteID, Modality: modality, Tense: "PAST",
+		})
+		return g
+	}
+
+	m := merger.New()
+	m.AddScannerGraph(sentence("note-1", "POSSIBLE"), "note-1")
+	if _, err := svc.Sync(m.GetMergedGraph()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	edges, _ := s.ListEdges()
+	if len(edges) != 1 || edges[0].Attributes["modality"] != "POSSIBLE" {
+		t.Fatalf("Expected a hedged edge, got %+v", edges)
+	}
+
+	// Reload, then a later note asserts it: the edge becomes factual
+	m2 := merger.New()
+	if _, err := svc.Load(m2); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	m2.AddScannerGraph(sentence("note-2", ""), "note-2")
+	if _, err := svc.Sync(m2.GetMergedGraph()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	edges, _ = s.ListEdges()
+	if len(edges) != 1 || edges[0].Attributes["modality"] != nil || edges[0].Attributes["tense"] != "PAST" {
+		t.Errorf("Expected the asserted edge to be factual, got %+v", edges[0].Attributes)
+	}
+
+	// Retracting the assertion leaves the hedge again
+	m2.RetractNote("note-2")
+	if e := m2.GetMergedGraph().Edges[edges[0].ID]; e == nil || e.Attributes["modality"] != "POSSIBLE" {
+		t.Errorf("Expected the hedge restored after retraction, got %+v", e)
+	}
+}
+
+func TestLoadThenRescanKeepsOneEdge(t *testing.T) {
+	svc, s := newTestService(t)
+
+	m := merger.New()
+	m.AddScannerGraph(noteGraph(), "note-1")
+	g2 := graph.NewGraph()
+	g2.AddEdge(g2.EnsureNode("Arin", "Arin", "CHARACTER"), g2.EnsureNode("the troll", "the troll", graph.KindConcept),
+		&graph.ConceptEdge{Relation: "KILLS", Weight: 0.5, SourceDoc: "note-2", SourceSpan: [2]int{0, 30}})
+	m.AddScannerGraph(g2, "note-2")
+	m.AddLLMEdges([]merger.LLMEdgeInput{
+		{SourceID: "Arin", TargetID: "the troll", RelType: "KILLS", Confidence: 0.4, SourceNoteID: "note-3"},
+	})
+	if _, err := svc.Sync(m.GetMergedGraph()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	arin, _ := s.GetEntityByLabel("Arin")
+	id := merger.EdgeKey(arin.ID, "the troll", "KILLS")
+	stored, _ := s.GetEdge(id)
+	if stored.Support == nil || stored.Support.LLM["note-3"] != 0.4 {
+		t.Fatalf("Expected per-note LLM support persisted, got %+v", stored.Support)
+	}
+
+	// Startup, then note-1 is rescanned under its label keys
+	m2 := merger.New()
+	if _, err := svc.Load(m2); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	restored := m2.GetMergedGraph().Edges[id]
+	if restored == nil || restored.Confidence != stored.Confidence || restored.Support.LLM["note-3"] != 0.4 {
+		t.Fatalf("Expected support restored as stored, got %+v", restored)
+	}
+	m2.RetractNote("note-1")
+	m2.AddScannerGraph(noteGraph(), "note-1")
+
+	for key, e := range m2.GetMergedGraph().Edges {
+		if e.RelType == "KILLS" && key != id {
+			t.Errorf("Expected the rescan to land on the restored edge, got %s", key)
+		}
+	}
+	if _, err := svc.Sync(m2.GetMergedGraph()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	edge, _ := s.GetEdge(id)
+	notes := map[string]bool{}
+	for _, n := range edge.SourceNotes {
+		notes[n] = true
+	}
+	if len(notes) != 3 || edge.Confidence != stored.Confidence {
+		t.Errorf("Expected notes 1-3 and unchanged confidence, got %v at %.3f (was %.3f)",
+			edge.SourceNotes, edge.Confidence, stored.Confidence)
+	}
+}
+
+func TestSyncCombinesCollidingEdges(t *testing.T) {
+	svc, s := newTestService(t)
+	s.UpsertEntity(&store.Entity{ID: "e-arin", Label: "Arin", Kind: "CHARACTER", Aliases: []string{}})
+
+	// One copy keyed by the entity ID, one by its label
+	mg := &merger.MergedGraph{Nodes: map[string]*graph.ConceptNode{
+		"e-arin": {ID: "e-arin", Label: "Arin", Kind: "CHARACTER"},
+		"Arin":   {ID: "Arin", Label: "Arin", Kind: "CHARACTER"},
+	}, Edges: map[string]*merger.MergedEdge{}}
+	for _, src := range []string{"e-arin", "Arin"} {
+		note := map[string]string{"e-arin": "note-1", "Arin": "note-2"}[src]
+		mg.Edges[merger.EdgeKey(src, "Lyra", "LOVES")] = &merger.MergedEdge{
+			SourceID: src, TargetID: "Lyra", RelType: "LOVES", Confidence: 0.5,
+			Provenances: []merger.Provenance{merger.ProvenanceLLM},
+			SourceNotes: []string{note},
+			Support:     merger.Support{LLM: map[string]float64{note: 0.5}},
+		}
+	}
+	if _, err := svc.Sync(mg); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	edge, _ := s.GetEdge(merger.EdgeKey("e-arin", "Lyra", "LOVES"))
+	if edge == nil || len(edge.SourceNotes) != 2 || edge.Confidence != 0.75 {
+		t.Errorf("Expected both copies combined, got %+v", edge)
+	}
+}
+
+func TestMergeEdgeKeepsStoredSupport(t *testing.T) {
+	svc, s := newTestService(t)
+
+	m := merger.New()
+	m.AddScannerGraph(noteGraph(), "note-1")
+	if _, err := svc.Sync(m.GetMergedGraph()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	arin, _ := s.GetEntityByLabel("Arin")
+	id := merger.EdgeKey(arin.ID, "the troll", "KILLS")
+	scanned, _ := s.GetEdge(id)
+
+	edge, err := svc.MergeLLMEdge(merger.LLMEdgeInput{SourceID: arin.ID, TargetID: "the troll", RelType: "KILLS", Confidence: 0.6})
+	if err != nil {
+		t.Fatalf("MergeLLMEdge: %v", err)
+	}
+	if edge.ID != id || len(edge.Provenances) != 2 || len(edge.Evidence) != 1 || edge.CreatedAt != scanned.CreatedAt {
+		t.Errorf("Expected the LLM support merged into the scanned edge, got %+v", edge)
+	}
+
+	edge, err = svc.MergeManualEdge(merger.ManualEdgeInput{SourceID: arin.ID, TargetID: "the troll", RelType: "KILLS"})
+	if err != nil {
+		t.Fatalf("MergeManualEdge: %v", err)
+	}
+	stored, _ := s.GetEdge(id)
+	if stored == nil || stored.Support == nil || !stored.Support.Manual || len(stored.Support.LLM) != 1 || len(stored.Evidence) != 1 {
+		t.Errorf("Expected manual, LLM and scanner support kept, got %+v", stored)
+	}
+	if n, _ := s.CountEdges(); n != 1 {
+		t.Errorf("Expected one edge, got %d", n)
+	}
+
+	// A new relation is created
+	if edge, err = svc.MergeManualEdge(merger.ManualEdgeInput{SourceID: arin.ID, TargetID: "Lyra", RelType: "LOVES"}); err != nil || edge.Confidence != 1 {
+		t.Errorf("Expected a new manual edge, got %+v (%v)", edge, err)
+	}
+}
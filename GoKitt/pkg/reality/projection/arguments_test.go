@@ -0,0 +1,66 @@
+package projection
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/reality/builder"
+	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+)
+
+// argumentCorpus covers passive voice, coordination and relative clauses.
+// Each expected edge is "Source RELATION Target".
+var argumentCorpus = []struct {
+	text  string
+	edges []string
+}{
+	// Active baseline
+	{"Arin killed the king.", []string{"Arin KILLS the king"}},
+
+	// Passive voice
+	{"The king was killed by Arin.", []string{"Arin KILLS The king"}},
+	{"The king had been killed by Arin.", []string{"Arin KILLS The king"}},
+	{"The king was slain by Arin and Lyra.", []string{"Arin KILLS The king", "Lyra KILLS The king"}},
+	{"The king was killed.", nil}, // No agent, no edge
+
+	// Coordination
+	{"Arin and Lyra fought the troll.", []string{"Arin FIGHTS the troll", "Lyra FIGHTS the troll"}},
+	{"Arin, Lyra and Bram fought the troll.", []string{"Arin FIGHTS the troll", "Bram FIGHTS the troll", "Lyra FIGHTS the troll"}},
+	{"Arin fought the troll and the ogre.", []string{"Arin FIGHTS the ogre", "Arin FIGHTS the troll"}},
+	{"Arin attacked the troll, Lyra fled.", []string{"Arin ATTACKS the troll"}},
+
+	// Relative clauses
+	{"The knight who killed the dragon fled.", []string{"The knight KILLS dragon"}},
+	{"The sword that Arin stole was cursed.", []string{"Arin STEALS The sword"}},
+}
+
+func TestProjectArgumentCorpus(t *testing.T) {
+	for _, tt := range argumentCorpus {
+		c, err := conductor.New()
+		if err != nil {
+			t.Fatalf("Failed to create conductor: %v", err)
+		}
+
+		scan := c.Scan(tt.text)
+		root := builder.Zip(tt.text, scan)
+		g := Project(root, c.GetMatcher(), nil, tt.text, nil)
+		c.Close()
+
+		var got []string
+		for _, e := range g.AllEdges() {
+			got = append(got, e.Source.ID+" "+e.Edge.Relation+" "+e.Target.ID)
+		}
+		sort.Strings(got)
+
+		if len(got) != len(tt.edges) {
+			t.Errorf("%q: expected edges %v, got %v", tt.text, tt.edges, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.edges[i] {
+				t.Errorf("%q: expected edges %v, got %v", tt.text, tt.edges, got)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package projection
+
+import (
+	"strings"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/hierarchy"
+	"github.com/kittclouds/gokitt/pkg/reality/cst"
+	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
+)
+
+// tripleScanner re-parses triple spans for their components (stateless)
+var tripleScanner = syntax.New()
+
+// processTriple projects an explicit [Kind:Subject] -[PREDICATE]-> [Kind:Object]
+// as an authored edge between nodes typed with their declared kinds
+func processTriple(n *cst.Node, g *graph.ConceptGraph, entities EntityMap, source string, worldNode *graph.ConceptNode, noteID string, clock storyClock) {
+	tripleText := n.Text(source)
+	m := parseTriple(tripleText)
+	if m == nil {
+		return
+	}
+
+	// Participants keep the IDs the resolver gave their labels
+	subjOff, objOff := participantOffsets(tripleText, m)
+	subjID := participantID(entities, n.Range.Start, subjOff, m.Subject)
+	objID := participantID(entities, n.Range.Start, objOff, m.Object)
+
+	subj := ensureTypedNode(g, subjID, m.Subject, m.SubjectKind)
+	obj := ensureTypedNode(g, objID, m.Object, m.ObjectKind)
+
+	edge := &graph.ConceptEdge{
+		Relation: predicateRelation(m.Predicate),
+		Weight:   1.0,
+		Authored: true,
+	}
+	annotateSpans(edge, noteID, n.Range, predicateRange(n.Range.Start, tripleText))
+	edge.Valid = clock.validity("")
+	g.AddEdge(subj, obj, edge)
+
+	if worldNode != nil {
+		ensureWorldLink(g, worldNode, subj)
+		ensureWorldLink(g, worldNode, obj)
+	}
+}
+
+// processWikilink links the current note's world to the linked note's world
+func processWikilink(n *cst.Node, g *graph.ConceptGraph, source string, worldNode *graph.ConceptNode, prov *hierarchy.ProvenanceContext) {
+	if worldNode == nil {
+		return
+	}
+	target, label := parseLink(n.Text(source))
+	if target == "" {
+		return
+	}
+
+	noteID := target
+	if prov.ResolveLink != nil {
+		if id := prov.ResolveLink(target); id != "" {
+			noteID = id
+		}
+	}
+	if noteID == prov.WorldID {
+		return // Self-link
+	}
+
+	linked := g.EnsureNode("world:"+noteID, label, graph.KindWorld)
+	for _, edge := range worldNode.Outbound {
+		if edge.Target == linked && edge.Relation == graph.RelLinksTo {
+			return
+		}
+	}
+	edge := &graph.ConceptEdge{
+		Relation: graph.RelLinksTo,
+		Weight:   1.0,
+		Authored: true,
+	}
+	annotateSpans(edge, prov.WorldID, n.Range, n.Range)
+	g.AddEdge(worldNode, linked, edge)
+}
+
+// parseTriple returns the triple match for a triple span, or nil
+func parseTriple(text string) *syntax.SyntaxMatch {
+	for _, m := range tripleScanner.Scan(text) {
+		if m.Kind == syntax.KindTriple {
+			return &m
+		}
+	}
+	return nil
+}
+
+// parseLink splits [[Target|Label]] or <<Target|Label>> into target and label
+func parseLink(text string) (target, label string) {
+	inner := text
+	if strings.HasPrefix(inner, "[[") && strings.HasSuffix(inner, "]]") {
+		inner = inner[2 : len(inner)-2]
+	} else if strings.HasPrefix(inner, "<<") && strings.HasSuffix(inner, ">>") {
+		inner = inner[2 : len(inner)-2]
+	} else {
+		return "", ""
+	}
+
+	parts := strings.SplitN(inner, "|", 2)
+	target = strings.TrimSpace(parts[0])
+	label = target
+	if len(parts) > 1 && strings.TrimSpace(parts[1]) != "" {
+		label = strings.TrimSpace(parts[1])
+	}
+	return target, label
+}
+
+// participantOffsets locates the subject and object labels within the
+// triple text. Returns -1 for a label that cannot be found.
+func participantOffsets(text string, m *syntax.SyntaxMatch) (int, int) {
+	subjOff := -1
+	if end := strings.IndexByte(text, ']'); end >= 0 {
+		subjOff = labelOffset(text[:end], m.Subject)
+	}
+
+	objOff := -1
+	if open := strings.LastIndexByte(text, '['); open >= 0 {
+		if i := labelOffset(text[open:], m.Object); i >= 0 {
+			objOff = open + i
+		}
+	}
+	return subjOff, objOff
+}
+
+// predicateRange locates the -[PREDICATE]-> arrow of a triple starting at base
+func predicateRange(base int, text string) cst.TextRange {
+	start := strings.Index(text, "-[")
+	end := strings.Index(text, "]->")
+	if start < 0 || end < start {
+		return cst.TextRange{Start: base, End: base + len(text)}
+	}
+	return cst.TextRange{Start: base + start, End: base + end + 3}
+}
+
+// labelOffset finds label after the Kind separator of a [Kind:Label] block
+func labelOffset(block, label string) int {
+	if sep := strings.IndexAny(block, "|:"); sep >= 0 {
+		if i := strings.Index(block[sep:], label); i >= 0 {
+			return sep + i
+		}
+	}
+	return -1
+}
+
+// participantID returns the entity resolved inside the label's span,
+// falling back to the label itself
+func participantID(entities EntityMap, base, off int, label string) string {
+	if off < 0 {
+		return label
+	}
+	start, end := base+off, base+off+len(label)
+
+	best, bestID := end, ""
+	for pos, id := range entities {
+		if pos >= start && pos < best {
+			best, bestID = pos, id
+		}
+	}
+	if bestID != "" {
+		return bestID
+	}
+	return label
+}
+
+// ensureTypedNode adds a node with its declared kind, upgrading a generic
+// Concept node created earlier from prose
+func ensureTypedNode(g *graph.ConceptGraph, id, label, kind string) *graph.ConceptNode {
+	kind = strings.ToUpper(strings.TrimLeft(kind, "#@!"))
+	if kind == "" {
+		kind = graph.KindConcept
+	}
+	node := g.EnsureNode(id, label, kind)
+	if node.Kind == graph.KindConcept {
+		node.Kind = kind
+	}
+	return node
+}
+
+// predicateRelation normalizes a triple predicate: "owes money to" -> OWES_MONEY_TO
+func predicateRelation(pred string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(pred), "_"))
+}
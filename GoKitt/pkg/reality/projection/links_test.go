@@ -0,0 +1,132 @@
+package projection
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/hierarchy"
+	"github.com/kittclouds/gokitt/pkg/reality/builder"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+)
+
+func projectText(t *testing.T, text string, prov *hierarchy.ProvenanceContext) *graph.ConceptGraph {
+	t.Helper()
+	c, err := conductor.New()
+	if err != nil {
+		t.Fatalf("conductor.New: %v", err)
+	}
+	defer c.Close()
+
+	result := c.Scan(text)
+	entities := make(EntityMap)
+	for _, ref := range result.ResolvedRefs {
+		entities[ref.Range.Start] = ref.EntityID
+	}
+	return Project(builder.Zip(text, result), c.GetMatcher(), entities, text, prov)
+}
+
+func TestProjectTriple(t *testing.T) {
+	text := "[CHARACTER:Arin] -[OWES MONEY TO]-> [FACTION:The Guild]. Arin killed the troll."
+	prov := &hierarchy.ProvenanceContext{WorldID: "note-1"}
+	g := projectText(t, text, prov)
+
+	arin := g.GetNode("Arin")
+	guild := g.GetNode("The Guild")
+	if arin == nil || guild == nil {
+		t.Fatalf("Expected triple participants as nodes, got %v", g.Nodes)
+	}
+	if arin.Kind != "CHARACTER" || guild.Kind != "FACTION" {
+		t.Errorf("Expected declared kinds, got %s / %s", arin.Kind, guild.Kind)
+	}
+
+	var triple *graph.ConceptEdge
+	for _, out := range g.OutgoingEdges("Arin") {
+		if out.Target == guild {
+			triple = out.Edge
+		}
+	}
+	if triple == nil {
+		t.Fatal("Expected Arin -> The Guild edge")
+	}
+	if triple.Relation != "OWES_MONEY_TO" || !triple.Authored || triple.Weight != 1.0 {
+		t.Errorf("Unexpected triple edge %+v", triple)
+	}
+
+	// The triple's predicate is not re-read as prose ("TO" is not a verb edge),
+	// while the following sentence still is
+	for _, e := range g.AllEdges() {
+		if e.Edge.Relation != "OWES_MONEY_TO" && e.Edge.Relation != graph.RelWorldContains && e.Edge.Relation != "KILLS" {
+			t.Errorf("Unexpected edge %s %s %s", e.Source.ID, e.Edge.Relation, e.Target.ID)
+		}
+	}
+
+	// Participants belong to the note's world
+	world := g.GetNode("world:note-1")
+	contains := 0
+	for _, e := range world.Outbound {
+		if e.Relation == graph.RelWorldContains && (e.Target == arin || e.Target == guild) {
+			contains++
+		}
+	}
+	if contains != 2 {
+		t.Errorf("Expected both participants linked to the world, got %d", contains)
+	}
+
+	// The merger treats authored edges as manual
+	m := merger.New()
+	m.AddScannerGraph(g, "note-1")
+	for _, e := range m.GetMergedGraph().Edges {
+		if e.RelType == "OWES_MONEY_TO" {
+			if e.Confidence != 1.0 || len(e.Provenances) != 1 || e.Provenances[0] != merger.ProvenanceManual {
+				t.Errorf("Expected manual provenance, got %+v", e)
+			}
+		}
+	}
+}
+
+func TestProjectWikilinks(t *testing.T) {
+	text := "Arin left for [[Eldoria]]. See also [[Old Notes|the archive]] and [[Eldoria]]."
+	prov := &hierarchy.ProvenanceContext{
+		WorldID: "note-1",
+		ResolveLink: func(target string) string {
+			if target == "Eldoria" {
+				return "note-2"
+			}
+			return ""
+		},
+	}
+	g := projectText(t, text, prov)
+
+	world := g.GetNode("world:note-1")
+	links := map[string]string{}
+	for _, e := range world.Outbound {
+		if e.Relation == graph.RelLinksTo {
+			if !e.Authored {
+				t.Errorf("Expected authored LINKS_TO edge to %s", e.Target.ID)
+			}
+			links[e.Target.ID] = e.Target.Label
+		}
+	}
+
+	if len(links) != 2 {
+		t.Fatalf("Expected 2 deduplicated links, got %v", links)
+	}
+	if _, ok := links["world:note-2"]; !ok {
+		t.Errorf("Expected resolved link to world:note-2, got %v", links)
+	}
+	if label := links["world:Old Notes"]; label != "the archive" {
+		t.Errorf("Expected unresolved link to keep its title and label, got %v", links)
+	}
+	if n := g.GetNode("world:note-2"); n == nil || n.Kind != graph.KindWorld {
+		t.Error("Expected linked note to be a World node")
+	}
+
+	// Without a world there is nothing to link from
+	g = projectText(t, text, nil)
+	for _, e := range g.AllEdges() {
+		if e.Edge.Relation == graph.RelLinksTo {
+			t.Error("Expected no LINKS_TO edges without provenance")
+		}
+	}
+}
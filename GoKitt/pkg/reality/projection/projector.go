@@ -2,6 +2,7 @@ package projection
 
 import (
 	"strings"
+	"unicode"
 
 	"github.com/kittclouds/gokitt/pkg/graph"
 	"github.com/kittclouds/gokitt/pkg/hierarchy"
@@ -19,7 +20,10 @@ func Project(root *cst.Node, matcher *narrative.NarrativeMatcher, entities Entit
 
 	// 0. Create World Node (if provenance provided)
 	var worldNode *graph.ConceptNode
+	noteID := ""
+	clock := newStoryClock(prov)
 	if prov != nil && prov.WorldID != "" {
+		noteID = prov.WorldID
 		worldID := "world:" + prov.WorldID
 		worldLabel := prov.ParentPath // Use path as label for now
 		if worldLabel == "" {
@@ -31,8 +35,14 @@ func Project(root *cst.Node, matcher *narrative.NarrativeMatcher, entities Entit
 	// Recursive walk looking for Sentences
 	var walk func(n *cst.Node)
 	walk = func(n *cst.Node) {
-		if n.Kind == rsyntax.KindSentence {
-			processSentence(n, g, matcher, entities, text, worldNode)
+		switch n.Kind {
+		case rsyntax.KindSentence:
+			processSentence(n, g, matcher, entities, text, worldNode, noteID, clock)
+		case rsyntax.KindTriple:
+			processTriple(n, g, entities, text, worldNode, noteID, clock)
+			return // Its words are syntax, not prose
+		case rsyntax.KindWikilink:
+			processWikilink(n, g, text, worldNode, prov)
 		}
 
 		for _, child := range n.Children {
@@ -44,15 +54,18 @@ func Project(root *cst.Node, matcher *narrative.NarrativeMatcher, entities Entit
 	return g
 }
 
-func processSentence(sent *cst.Node, g *graph.ConceptGraph, matcher *narrative.NarrativeMatcher, entities EntityMap, source string, worldNode *graph.ConceptNode) {
+func processSentence(sent *cst.Node, g *graph.ConceptGraph, matcher *narrative.NarrativeMatcher, entities EntityMap, source string, worldNode *graph.ConceptNode, noteID string, clock storyClock) {
 	// 1. Flatten children into sequential list
 	var nodes []*cst.Node
 	var gather func(n *cst.Node)
 	gather = func(n *cst.Node) {
 		switch n.Kind {
-		case rsyntax.KindNounPhrase, rsyntax.KindVerbPhrase, rsyntax.KindEntitySpan, rsyntax.KindPrepPhrase, rsyntax.KindAdjPhrase, rsyntax.KindWord:
+		case rsyntax.KindNounPhrase, rsyntax.KindVerbPhrase, rsyntax.KindEntitySpan, rsyntax.KindPrepPhrase, rsyntax.KindAdjPhrase, rsyntax.KindWord, rsyntax.KindSubClause,
+			rsyntax.KindWikilink, rsyntax.KindBacklink:
 			nodes = append(nodes, n)
 			return
+		case rsyntax.KindTriple:
+			return // Projected by processTriple
 		}
 		for _, c := range n.Children {
 			gather(c)
@@ -60,116 +73,395 @@ func processSentence(sent *cst.Node, g *graph.ConceptGraph, matcher *narrative.N
 	}
 	gather(sent)
 
-	// 2. Iterate VPs to find relations
+	// emit adds one relation edge (plus world links) for a subject/target pair
+	emit := func(subjID, targetID, relType string, mods modifiers, recipientID string, vp *cst.Node) {
+		edge := g.AddQuadPlus(
+			subjID, subjID, "Concept",
+			targetID, targetID, "Concept",
+			relType,
+			1.0,
+			mods.manner, mods.location, mods.time, recipientID,
+		)
+		annotateFrame(edge, sent, vp, source)
+		annotateSpans(edge, noteID, sent.Range, vp.Range)
+		edge.Valid = clock.validity(mods.time)
+
+		// Link to World (if exists and hasn't been linked yet)
+		if worldNode != nil {
+			if subjNode := g.Nodes[subjID]; subjNode != nil {
+				ensureWorldLink(g, worldNode, subjNode)
+			}
+			if targetNode := g.Nodes[targetID]; targetNode != nil {
+				ensureWorldLink(g, worldNode, targetNode)
+			}
+		}
+	}
+
+	// 2. Iterate VPs (and relative clauses) to find relations
 	for i, n := range nodes {
-		if n.Kind == rsyntax.KindVerbPhrase {
-			verbText := n.Text(source)
-			match := matcher.Lookup(verbText)
-
-			if match != nil {
-				// Find Subject (Left)
-				subj := findNearestNP(nodes, i, -1, source)
-
-				// For communication verbs (SPEAKS_TO), look for "to [Name]" pattern first
-				var targetID, recipientID string
-				var objPP *cst.Node
-
-				relType := match.RelationType.String()
-				isCommunication := relType == "SPEAKS_TO" || relType == "MENTIONS" || relType == "REVEALS"
-
-				searchOffset := 1 // Start searching for object at verb + 1
-
-				if isCommunication {
-					// 1. Check for "that" (Attribution)
-					thatIdx := findToken(nodes, i+1, "that", source, 4) // Look ahead 4 nodes
-					if thatIdx != -1 {
-						relType = "MENTIONS"
-						searchOffset = (thatIdx - i) + 1 // Skip "that"
-					}
-
-					// 2. Look for "to [CapitalizedWord]" pattern (Recipient)
-					recipient := findRecipient(nodes, i, source)
-					if recipient != "" {
-						recipientID = recipient
-						// If NOT attribution, default target to recipient
-						if relType == "SPEAKS_TO" {
-							targetID = recipient
-						}
-					}
-				}
+		if n.Kind == rsyntax.KindSubClause {
+			processClause(nodes, i, matcher, entities, source, emit)
+			continue
+		}
+		if n.Kind != rsyntax.KindVerbPhrase {
+			continue
+		}
 
-				// If target not yet set (or we are in MENTIONS mode looking for content), scan for object
-				if targetID == "" {
-					obj, pp := findNearestNPWithContainer(nodes, i, searchOffset, source)
-					objPP = pp
-					if obj != nil {
-						objID := resolveID(obj, entities)
-						if objID == "" {
-							objID = obj.Text(source)
-						}
-						targetID = objID
-					}
+		verbText := n.Text(source)
+		match := lookupVerb(matcher, verbText)
+		if match == nil {
+			continue
+		}
+		relType := match.RelationType.String()
+
+		// Find Subject(s) (Left), including coordinated NPs: "Arin and Lyra fought"
+		subjIdx, _ := findNearestNPIndex(nodes, i, -1)
+		subjects, firstSubj := coordinatedSpan(nodes, subjIdx, -1, source)
+
+		// Passive voice: "The king was killed by Arin" -> Arin KILLS king
+		if narrative.IsPassive(splitWords(verbText)) {
+			agents := findAgents(nodes, i, source)
+			mods := collectModifiers(nodes, nil, source, true)
+			for _, agent := range agents {
+				for _, patient := range subjects {
+					emit(nodeID(agent, entities, source), nodeID(patient, entities, source), relType, mods, "", n)
 				}
+			}
+			continue
+		}
 
-				if subj != nil && targetID != "" {
-					subjID := resolveID(subj, entities)
-					if subjID == "" {
-						subjID = subj.Text(source)
-					}
-
-					// Extract Modifiers from unused PPs
-					var manner, location, time string
-
-					for _, node := range nodes {
-						if node.Kind == rsyntax.KindPrepPhrase {
-							// Skip if this PP contained the object
-							if node == objPP {
-								continue
-							}
-
-							// Heuristic classification
-							ppText := node.Text(source)
-							lower := strings.ToLower(ppText)
-
-							if strings.HasPrefix(lower, "with ") {
-								manner = strings.TrimPrefix(lower, "with ")
-							} else if strings.HasPrefix(lower, "in ") || strings.HasPrefix(lower, "at ") || strings.HasPrefix(lower, "on ") {
-								location = ppText
-							} else if strings.HasPrefix(lower, "during ") || strings.HasPrefix(lower, "after ") || strings.HasPrefix(lower, "before ") {
-								time = ppText
-							}
-							// Note: "to [X]" for communication is handled separately as recipient
-						}
-					}
-
-					// Add QuadPlus (with recipient)
-					g.AddQuadPlus(
-						subjID, subjID, "Concept",
-						targetID, targetID, "Concept",
-						relType,
-						1.0,
-						manner, location, time, recipientID,
-					)
-
-					// Link to World (if exists and hasn't been linked yet)
-					if worldNode != nil {
-						// Link Subject
-						subjNode, _ := g.Nodes[subjID] // Should exist after AddQuadPlus
-						if subjNode != nil {
-							ensureWorldLink(g, worldNode, subjNode)
-						}
-						// Link Target
-						targetNode, _ := g.Nodes[targetID]
-						if targetNode != nil {
-							ensureWorldLink(g, worldNode, targetNode)
-						}
-					}
+		// For communication verbs (SPEAKS_TO), look for "to [Name]" pattern first
+		var targetIDs []string
+		var recipientID string
+		var objPP *cst.Node
+
+		isCommunication := relType == "SPEAKS_TO" || relType == "MENTIONS" || relType == "REVEALS"
+
+		searchOffset := 1 // Start searching for object at verb + 1
+
+		if isCommunication {
+			// 1. Check for "that" (Attribution)
+			thatIdx := findToken(nodes, i+1, "that", source, 4) // Look ahead 4 nodes
+			if thatIdx != -1 {
+				relType = "MENTIONS"
+				searchOffset = (thatIdx - i) + 1 // Skip "that"
+			}
+
+			// 2. Look for "to [CapitalizedWord]" pattern (Recipient)
+			recipient := findRecipient(nodes, i, source)
+			if recipient != "" {
+				recipientID = recipient
+				// If NOT attribution, default target to recipient
+				if relType == "SPEAKS_TO" {
+					targetIDs = []string{recipient}
 				}
 			}
 		}
+
+		// Object relative: "The sword that Arin stole" -> Arin STEALS sword
+		if len(targetIDs) == 0 {
+			if ante := narrative.ObjectRelativeAntecedent(nodeSeq{nodes, source}, i, firstSubj); ante >= 0 {
+				targetIDs = []string{nodeID(nodes[ante], entities, source)}
+			}
+		}
+
+		// If target not yet set (or we are in MENTIONS mode looking for content), scan for object(s)
+		if len(targetIDs) == 0 {
+			objIdx, pp := findNearestNPIndex(nodes, i, searchOffset)
+			objPP = pp
+			for _, obj := range coordinated(nodes, objIdx, 1, source) {
+				targetIDs = append(targetIDs, nodeID(obj, entities, source))
+			}
+		}
+
+		if len(subjects) == 0 || len(targetIDs) == 0 {
+			continue
+		}
+
+		// Extract Modifiers from unused PPs
+		mods := collectModifiers(nodes, objPP, source, false)
+		for _, subj := range subjects {
+			subjID := nodeID(subj, entities, source)
+			for _, targetID := range targetIDs {
+				emit(subjID, targetID, relType, mods, recipientID, n)
+			}
+		}
+	}
+}
+
+// modifiers are the QuadPlus PP modifiers of a relation
+type modifiers struct {
+	manner, location, time string
+}
+
+// collectModifiers classifies the sentence's PPs into manner/location/time.
+// objPP (the PP that held the object) is skipped; so are agent "by" PPs when skipAgent is set.
+func collectModifiers(nodes []*cst.Node, objPP *cst.Node, source string, skipAgent bool) modifiers {
+	var mods modifiers
+	for i, node := range nodes {
+		// "until/since" are tagged as conjunctions: "ruled Eldoria until the war"
+		if node.Kind == rsyntax.KindWord && i+1 < len(nodes) && isArgumentKind(nodes[i+1].Kind) {
+			if w := strings.ToLower(node.Text(source)); w == "until" || w == "till" || w == "since" {
+				mods.time = w + " " + nodes[i+1].Text(source)
+				continue
+			}
+		}
+		if node.Kind != rsyntax.KindPrepPhrase || node == objPP {
+			continue
+		}
+
+		// Heuristic classification
+		ppText := node.Text(source)
+		lower := strings.ToLower(ppText)
+
+		if skipAgent && strings.HasPrefix(lower, "by ") {
+			continue
+		}
+		if strings.HasPrefix(lower, "with ") {
+			mods.manner = strings.TrimPrefix(lower, "with ")
+		} else if strings.HasPrefix(lower, "in ") || strings.HasPrefix(lower, "at ") || strings.HasPrefix(lower, "on ") {
+			mods.location = ppText
+		} else if strings.HasPrefix(lower, "during ") || strings.HasPrefix(lower, "after ") || strings.HasPrefix(lower, "before ") {
+			mods.time = ppText
+		}
+		// Note: "to [X]" for communication is handled separately as recipient
+	}
+	return mods
+}
+
+// processClause handles relative clauses: "the knight who slew the dragon",
+// "the king who was killed by Arin". The antecedent NP fills the subject
+// (or the patient, for passive clauses).
+func processClause(nodes []*cst.Node, i int, matcher *narrative.NarrativeMatcher, entities EntityMap, source string,
+	emit func(subjID, targetID, relType string, mods modifiers, recipientID string, vp *cst.Node)) {
+	clause := nodes[i]
+
+	var words []*cst.Node
+	for _, child := range clause.Children {
+		if child.Kind == rsyntax.KindWord || child.Kind == rsyntax.KindEntitySpan {
+			words = append(words, child)
+		}
+	}
+	if len(words) < 2 {
+		return
+	}
+
+	// Verb: first matching word after the relative pronoun, skipping auxiliaries
+	// that are followed by another verb ("was killed")
+	verbIdx := -1
+	var match *narrative.VerbMatch
+	for j := 1; j < len(words); j++ {
+		m := matcher.Lookup(words[j].Text(source))
+		if m == nil {
+			continue
+		}
+		verbIdx, match = j, m
+		if isAuxWord(words[j].Text(source)) && j+1 < len(words) && matcher.Lookup(words[j+1].Text(source)) != nil {
+			continue
+		}
+		break
+	}
+	if match == nil {
+		return
+	}
+	relType := match.RelationType.String()
+
+	anteIdx, _ := findNearestNPIndex(nodes, i, -1)
+	antecedents := coordinated(nodes, anteIdx, -1, source)
+	if len(antecedents) == 0 {
+		return
+	}
+
+	verbWords := make([]string, 0, verbIdx+1)
+	for _, w := range words[1 : verbIdx+1] {
+		verbWords = append(verbWords, w.Text(source))
+	}
+
+	verbSpan := &cst.Node{Kind: rsyntax.KindVerbPhrase, Range: cst.TextRange{Start: words[1].Range.Start, End: words[verbIdx].Range.End}}
+	mods := collectModifiers(nodes, nil, source, true)
+
+	if narrative.IsPassive(verbWords) {
+		for _, agent := range findAgents(nodes, i, source) {
+			for _, patient := range antecedents {
+				emit(nodeID(agent, entities, source), nodeID(patient, entities, source), relType, mods, "", verbSpan)
+			}
+		}
+		return
+	}
+
+	// Object: the head (last word) after the verb inside the clause
+	if verbIdx+1 >= len(words) {
+		return
+	}
+	obj := words[len(words)-1]
+	for _, subj := range antecedents {
+		emit(nodeID(subj, entities, source), nodeID(obj, entities, source), relType, mods, "", verbSpan)
 	}
 }
 
+// nodeSeq adapts a run of CST nodes to narrative.Sequence
+type nodeSeq struct {
+	nodes  []*cst.Node
+	source string
+}
+
+func (s nodeSeq) Len() int              { return len(s.nodes) }
+func (s nodeSeq) IsArgument(i int) bool { return isArgumentKind(s.nodes[i].Kind) }
+
+func (s nodeSeq) IsConjunction(i int) bool {
+	return s.nodes[i].Kind == rsyntax.KindWord && isConjunctionWord(s.nodes[i].Text(s.source))
+}
+
+func (s nodeSeq) Preposition(i int) string {
+	if s.nodes[i].Kind != rsyntax.KindPrepPhrase {
+		return ""
+	}
+	if words := splitWords(s.nodes[i].Text(s.source)); len(words) > 0 {
+		return words[0]
+	}
+	return ""
+}
+
+func (s nodeSeq) Gap(i, j int) (string, bool) {
+	start, end := s.nodes[i].Range.End, s.nodes[j].Range.Start
+	if start > end {
+		return "", false
+	}
+	return s.source[start:end], true
+}
+
+// findAgents returns the "by X (and Y)" agents directly following a passive verb
+func findAgents(nodes []*cst.Node, verbIdx int, source string) []*cst.Node {
+	return arguments(nodes, narrative.Agents(nodeSeq{nodes, source}, verbIdx))
+}
+
+// coordinated returns the argument at idx plus arguments joined to it by
+// "and"/"or"/commas, walking in direction dir. Returns nil when idx < 0.
+func coordinated(nodes []*cst.Node, idx, dir int, source string) []*cst.Node {
+	args, _ := coordinatedSpan(nodes, idx, dir, source)
+	return args
+}
+
+// coordinatedSpan is coordinated that also returns the index of the outermost
+// argument reached in direction dir.
+func coordinatedSpan(nodes []*cst.Node, idx, dir int, source string) ([]*cst.Node, int) {
+	idxs := narrative.Coordinated(nodeSeq{nodes, source}, idx, dir)
+	args := arguments(nodes, idxs)
+	if len(args) == 0 {
+		return nil, idx
+	}
+	if dir < 0 {
+		return args, idxs[0]
+	}
+	return args, idxs[len(idxs)-1]
+}
+
+// arguments maps node indices to their argument nodes. Returns nil when the
+// first has none (a PP without an object).
+func arguments(nodes []*cst.Node, idxs []int) []*cst.Node {
+	if len(idxs) == 0 || argumentNode(nodes[idxs[0]]) == nil {
+		return nil
+	}
+	args := make([]*cst.Node, 0, len(idxs))
+	for _, i := range idxs {
+		args = append(args, argumentNode(nodes[i]))
+	}
+	return args
+}
+
+// argumentNode returns the NP for an argument slot (unwrapping PPs)
+func argumentNode(n *cst.Node) *cst.Node {
+	if n.Kind == rsyntax.KindPrepPhrase {
+		return findNPInPP(n)
+	}
+	return n
+}
+
+func isArgumentKind(k rsyntax.SyntaxKind) bool {
+	return k == rsyntax.KindNounPhrase || k == rsyntax.KindEntitySpan || isLinkKind(k)
+}
+
+func isLinkKind(k rsyntax.SyntaxKind) bool {
+	return k == rsyntax.KindWikilink || k == rsyntax.KindBacklink
+}
+
+func isConjunctionWord(w string) bool {
+	switch strings.ToLower(w) {
+	case "and", "or", "nor":
+		return true
+	}
+	return false
+}
+
+func isAuxWord(w string) bool {
+	switch strings.ToLower(w) {
+	case "is", "are", "was", "were", "be", "been", "being", "has", "have", "had":
+		return true
+	}
+	return false
+}
+
+// nodeID resolves a node to its entity ID, falling back to its text
+func nodeID(n *cst.Node, entities EntityMap, source string) string {
+	if id := resolveID(n, entities); id != "" {
+		return id
+	}
+	if isLinkKind(n.Kind) {
+		if target, _ := parseLink(n.Text(source)); target != "" {
+			return target
+		}
+	}
+	return n.Text(source)
+}
+
+// lookupVerb matches a verb phrase against the narrative matcher.
+// Multi-word phrases ("did not kill", "might betray") fall back to their
+// individual words, last first, so auxiliaries don't hide the head verb.
+func lookupVerb(matcher *narrative.NarrativeMatcher, verbText string) *narrative.VerbMatch {
+	if match := matcher.Lookup(verbText); match != nil {
+		return match
+	}
+	words := splitWords(verbText)
+	if len(words) < 2 {
+		return nil
+	}
+	for j := len(words) - 1; j >= 0; j-- {
+		if match := matcher.Lookup(words[j]); match != nil {
+			return match
+		}
+	}
+	return nil
+}
+
+// annotateFrame records negation, modality and tense of the verb phrase on the edge
+func annotateFrame(edge *graph.ConceptEdge, sent, vp *cst.Node, source string) {
+	var preceding []string
+	if sent.Range.Start < vp.Range.Start && vp.Range.Start <= len(source) {
+		preceding = splitWords(source[sent.Range.Start:vp.Range.Start])
+	}
+	frame := narrative.AnalyzeFrame(preceding, splitWords(vp.Text(source)))
+
+	edge.Negated = frame.Negated
+	if frame.Modality != narrative.ModalityFactual {
+		edge.Modality = frame.Modality.String()
+	}
+	edge.Tense = frame.Tense.String()
+}
+
+// annotateSpans records where the edge was read: note, sentence and verb ranges
+func annotateSpans(edge *graph.ConceptEdge, noteID string, sentence, verb cst.TextRange) {
+	edge.SourceDoc = noteID
+	edge.SourceSpan = [2]int{sentence.Start, sentence.End}
+	edge.VerbSpan = [2]int{verb.Start, verb.End}
+}
+
+// splitWords breaks text into word tokens, keeping contractions intact
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '-'
+	})
+}
+
 // findRecipient looks for "to [CapitalizedWord]" pattern after a verb
 // Returns the name if found, empty string otherwise
 func findRecipient(nodes []*cst.Node, verbIdx int, source string) string {
@@ -194,36 +486,32 @@ func findRecipient(nodes []*cst.Node, verbIdx int, source string) string {
 	return ""
 }
 
-// findNearestNPWithContainer looks for NP, checking PPs. Returns (NP, ContainerPP)
-func findNearestNPWithContainer(nodes []*cst.Node, startIdx int, direction int, source string) (*cst.Node, *cst.Node) {
+// findNearestNPIndex looks for an NP in the given direction, checking inside PPs.
+// Returns the node index (the PP's index when the NP sits inside a PP) and the
+// container PP, or -1 when none is found.
+func findNearestNPIndex(nodes []*cst.Node, startIdx int, direction int) (int, *cst.Node) {
 	curr := startIdx + direction
 	for curr >= 0 && curr < len(nodes) {
 		n := nodes[curr]
 		switch n.Kind {
-		case rsyntax.KindNounPhrase, rsyntax.KindEntitySpan, rsyntax.KindAdjPhrase, rsyntax.KindWord:
-			return n, nil
+		case rsyntax.KindNounPhrase, rsyntax.KindEntitySpan, rsyntax.KindAdjPhrase, rsyntax.KindWord,
+			rsyntax.KindWikilink, rsyntax.KindBacklink:
+			return curr, nil
 		case rsyntax.KindPrepPhrase:
-			np := findNPInPP(n)
-			if np != nil {
-				return np, n
+			if findNPInPP(n) != nil {
+				return curr, n
 			}
 		}
 		curr += direction
 	}
-	return nil, nil
-}
-
-// Wrapper for existing signature
-func findNearestNP(nodes []*cst.Node, startIdx int, direction int, source string) *cst.Node {
-	np, _ := findNearestNPWithContainer(nodes, startIdx, direction, source)
-	return np
+	return -1, nil
 }
 
 // findNPInPP searches children of a PrepPhrase for a NounPhrase or noun Word
 func findNPInPP(pp *cst.Node) *cst.Node {
 	// First, look for explicit NounPhrase or EntitySpan
 	for _, child := range pp.Children {
-		if child.Kind == rsyntax.KindNounPhrase || child.Kind == rsyntax.KindEntitySpan {
+		if isArgumentKind(child.Kind) {
 			return child
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/kittclouds/gokitt/pkg/graph"
 	"github.com/kittclouds/gokitt/pkg/hierarchy"
 	"github.com/kittclouds/gokitt/pkg/reality/cst"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
 	rsyntax "github.com/kittclouds/gokitt/pkg/reality/syntax"
 	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
 )
@@ -74,3 +75,83 @@ func TestProjectWithProvenance(t *testing.T) {
 		t.Error("World -> Frodo link missing")
 	}
 }
+
+func TestProjectModalFrame(t *testing.T) {
+	// "Arin might betray Lyra"
+	text := "Arin might betray Lyra"
+	root := &cst.Node{
+		Kind: rsyntax.KindDocument,
+		Children: []*cst.Node{
+			{
+				Kind:  rsyntax.KindSentence,
+				Range: cst.TextRange{Start: 0, End: 22},
+				Children: []*cst.Node{
+					{Kind: rsyntax.KindNounPhrase, Range: cst.TextRange{Start: 0, End: 4}},   // Arin
+					{Kind: rsyntax.KindVerbPhrase, Range: cst.TextRange{Start: 5, End: 17}},  // might betray
+					{Kind: rsyntax.KindNounPhrase, Range: cst.TextRange{Start: 18, End: 22}}, // Lyra
+				},
+			},
+		},
+	}
+
+	matcher, _ := narrative.New()
+	g := Project(root, matcher, nil, text, nil)
+
+	edges := g.OutgoingEdges("Arin")
+	if len(edges) != 1 {
+		t.Fatalf("Expected 1 edge from Arin, got %d", len(edges))
+	}
+	edge := edges[0].Edge
+	if edge.Relation != "BETRAYS" {
+		t.Errorf("Expected BETRAYS, got %s", edge.Relation)
+	}
+	if edge.Modality != "POSSIBLE" || edge.Negated {
+		t.Errorf("Expected possible, non-negated edge, got modality=%q negated=%v", edge.Modality, edge.Negated)
+	}
+}
+
+func TestProjectSourceSpans(t *testing.T) {
+	text := "Arin met Lyra. Arin killed the troll."
+	prov := &hierarchy.ProvenanceContext{WorldID: "note-7"}
+	g := projectText(t, text, prov)
+
+	var kills *graph.ConceptEdge
+	for _, out := range g.OutgoingEdges("Arin") {
+		if out.Edge.Relation == "KILLS" {
+			kills = out.Edge
+		}
+	}
+	if kills == nil {
+		t.Fatal("Expected Arin KILLS edge")
+	}
+	if kills.SourceDoc != "note-7" {
+		t.Errorf("Expected source note note-7, got %q", kills.SourceDoc)
+	}
+	if got := text[kills.SourceSpan[0]:kills.SourceSpan[1]]; got != "Arin killed the troll." {
+		t.Errorf("Expected sentence span, got %q", got)
+	}
+	if got := text[kills.VerbSpan[0]:kills.VerbSpan[1]]; got != "killed" {
+		t.Errorf("Expected verb span, got %q", got)
+	}
+
+	// Spans survive serialization and merging
+	g.ToSerializable()
+	for _, e := range g.Edges {
+		if e.Relation == "KILLS" && (e.SourceDoc != "note-7" || e.SourceSpan != kills.SourceSpan) {
+			t.Errorf("Expected spans on serialized edge, got %+v", e)
+		}
+	}
+
+	m := merger.New()
+	m.AddScannerGraph(g, "note-7")
+	m.AddScannerGraph(g, "note-7") // Rescan: evidence is not duplicated
+	for _, e := range m.GetMergedGraph().Edges {
+		if e.RelType != "KILLS" {
+			continue
+		}
+		want := merger.Evidence{NoteID: "note-7", Sentence: kills.SourceSpan, Verb: kills.VerbSpan, Weight: 1.0, Tense: kills.Tense}
+		if len(e.Evidence) != 1 || e.Evidence[0] != want {
+			t.Errorf("Expected one evidence span %+v, got %+v", want, e.Evidence)
+		}
+	}
+}
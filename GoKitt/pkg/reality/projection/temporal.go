@@ -0,0 +1,66 @@
+package projection
+
+import (
+	"strings"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/hierarchy"
+)
+
+// storyClock anchors a note's edges in story time
+type storyClock struct {
+	at      *float64                          // Note position; nil = unordered
+	resolve func(expr string) (float64, bool) // Time expression -> story point
+}
+
+func newStoryClock(prov *hierarchy.ProvenanceContext) storyClock {
+	if prov == nil {
+		return storyClock{}
+	}
+	return storyClock{at: prov.StoryTime, resolve: prov.ResolveTime}
+}
+
+// validity derives an edge's interval from the note position and its time
+// modifier: "until/till/before X" closes it at X, "since/after/during X"
+// opens it at X. An end at or before the note's position means the note
+// narrates the past, so the note no longer anchors the start.
+func (c storyClock) validity(timeExpr string) *graph.Interval {
+	var from, until *float64
+	if c.at != nil {
+		from = graph.StoryPoint(*c.at)
+	}
+
+	lower := strings.ToLower(strings.TrimSpace(timeExpr))
+	if marker, rest, ok := strings.Cut(lower, " "); ok {
+		switch marker {
+		case "until", "till", "before":
+			if t, ok := c.point(rest); ok {
+				until = graph.StoryPoint(t)
+				if from != nil && *from >= t {
+					from = nil
+				}
+			}
+		case "since", "after", "during":
+			if t, ok := c.point(rest); ok {
+				from = graph.StoryPoint(t)
+			}
+		}
+	}
+	return graph.NewInterval(from, until)
+}
+
+// point resolves a time expression, retrying without a leading article
+func (c storyClock) point(expr string) (float64, bool) {
+	if c.resolve == nil {
+		return 0, false
+	}
+	if t, ok := c.resolve(expr); ok {
+		return t, true
+	}
+	for _, article := range []string{"the ", "a ", "an "} {
+		if rest, ok := strings.CutPrefix(expr, article); ok {
+			return c.resolve(rest)
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,88 @@
+package projection
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/hierarchy"
+	"github.com/kittclouds/gokitt/pkg/reality/merger"
+)
+
+// chapter builds the provenance of a note at a story position; "the war"
+// resolves to the note titled "War" at position 5
+func chapter(noteID string, order float64) *hierarchy.ProvenanceContext {
+	return &hierarchy.ProvenanceContext{
+		WorldID:   noteID,
+		StoryTime: graph.StoryPoint(order),
+		ResolveTime: func(expr string) (float64, bool) {
+			if expr == "war" {
+				return 5, true
+			}
+			return 0, false
+		},
+	}
+}
+
+func findEdge(g *graph.ConceptGraph, rel string) *graph.ConceptEdge {
+	for _, e := range g.AllEdges() {
+		if e.Edge.Relation == rel {
+			return e.Edge
+		}
+	}
+	return nil
+}
+
+func TestProjectStoryTime(t *testing.T) {
+	g := projectText(t, "Arin ruled Eldoria until the war. Arin loved Lyra after the war. Bram killed the troll.", chapter("ch1", 1))
+
+	rules := findEdge(g, "RULES")
+	if rules == nil || !rules.Valid.Equal(graph.NewInterval(graph.StoryPoint(1), graph.StoryPoint(5))) {
+		t.Errorf("Expected RULES valid over [1, 5), got %+v", rules)
+	}
+	if loves := findEdge(g, "LOVES"); loves == nil || !loves.Valid.Equal(graph.NewInterval(graph.StoryPoint(5), nil)) {
+		t.Errorf("Expected LOVES valid from 5, got %+v", loves)
+	}
+	if kills := findEdge(g, "KILLS"); kills == nil || !kills.Valid.Equal(graph.NewInterval(graph.StoryPoint(1), nil)) {
+		t.Errorf("Expected KILLS anchored at the note, got %+v", kills)
+	}
+
+	// A later chapter recalling the past does not anchor the start
+	g = projectText(t, "Arin ruled Eldoria until the war.", chapter("ch7", 7))
+	if rules := findEdge(g, "RULES"); rules == nil || rules.Valid.From != nil || *rules.Valid.Until != 5 {
+		t.Errorf("Expected retrospective RULES valid until 5, got %+v", rules.Valid)
+	}
+
+	// Without an order the edges are unanchored
+	if kills := findEdge(projectText(t, "Bram killed the troll.", nil), "KILLS"); kills.Valid != nil {
+		t.Errorf("Expected no validity without provenance, got %+v", kills.Valid)
+	}
+}
+
+func TestGraphAt(t *testing.T) {
+	m := merger.New()
+	m.AddScannerGraph(projectText(t, "Arin ruled Eldoria until the war.", chapter("ch1", 1)), "ch1")
+	m.AddScannerGraph(projectText(t, "Bram ruled Eldoria after the war.", chapter("ch6", 6)), "ch6")
+	m.AddLLMEdges([]merger.LLMEdgeInput{{SourceID: "Arin", TargetID: "Bram", RelType: "KNOWS", Confidence: 0.6}})
+
+	relations := func(at float64) map[string]bool {
+		out := map[string]bool{}
+		for _, e := range m.GraphAt(at).Edges {
+			if e.RelType != graph.RelWorldContains {
+				out[e.SourceID+" "+e.RelType] = true
+			}
+		}
+		return out
+	}
+
+	before := relations(3)
+	if !before["Arin RULES"] || before["Bram RULES"] || !before["Arin KNOWS"] {
+		t.Errorf("Expected Arin ruling before the war, got %v", before)
+	}
+	after := relations(6)
+	if after["Arin RULES"] || !after["Bram RULES"] || !after["Arin KNOWS"] {
+		t.Errorf("Expected Bram ruling after the war, got %v", after)
+	}
+	if len(relations(0)) != 1 {
+		t.Errorf("Expected only the timeless LLM edge before the story, got %v", relations(0))
+	}
+}
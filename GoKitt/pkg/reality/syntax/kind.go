@@ -69,6 +69,12 @@ func (k SyntaxKind) String() string {
 		return "PrepPhrase"
 	case KindEntitySpan:
 		return "EntitySpan"
+	case KindWikilink:
+		return "Wikilink"
+	case KindBacklink:
+		return "Backlink"
+	case KindTriple:
+		return "Triple"
 	default:
 		return "Unknown"
 	}
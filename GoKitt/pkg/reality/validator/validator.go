@@ -3,34 +3,34 @@ package validator
 import (
 	"strings"
 
+	"github.com/kittclouds/gokitt/pkg/extraction"
+	"github.com/kittclouds/gokitt/pkg/ontology"
 	"github.com/kittclouds/gokitt/pkg/reality/cst"
 	"github.com/kittclouds/gokitt/pkg/reality/syntax"
 )
 
-// LLMRelation mirrors the JSON structure from the TS service
-type LLMRelation struct {
-	Subject        string  `json:"subject"`
-	Object         string  `json:"object"`
-	Verb           string  `json:"verb"`
-	RelationType   string  `json:"relationType"`
-	Confidence     float64 `json:"confidence"`
-	SourceSentence string  `json:"sourceSentence"`
-}
+// LLMRelation is a relation as extracted by the LLM (see pkg/extraction)
+type LLMRelation = extraction.ExtractedRelation
 
 // ValidatedRelation is a relation grounded in the CST
 type ValidatedRelation struct {
 	Original    LLMRelation
 	SubjectNode *cst.Node // The actual EntitySpan in CST
 	ObjectNode  *cst.Node // The actual EntitySpan in CST
-	VerbNode    *cst.Node // The VerbPhrase node (optional)
+	VerbNode    *cst.Node // The VerbPhrase node matching the verb (optional)
 	IsValid     bool
 	Issues      []string
+
+	// Ontology checks (only with SetOntology)
+	CanonicalType  string   // Relation type after synonym/inverse resolution
+	TypeViolations []string // Domain/range mismatches of the entity kinds
 }
 
 // Validator validates LLM relations against the CST
 type Validator struct {
-	root *cst.Node
-	text string
+	root     *cst.Node
+	text     string
+	ontology *ontology.Ontology
 }
 
 // New creates a new validator
@@ -41,6 +41,12 @@ func New(root *cst.Node, text string) *Validator {
 	}
 }
 
+// SetOntology enables relation type checks: relation types are resolved to
+// their canonical names and entity kinds are checked against domain/range
+func (v *Validator) SetOntology(o *ontology.Ontology) {
+	v.ontology = o
+}
+
 // Validate cross-references LLM relations with the CST
 func (v *Validator) Validate(relations []LLMRelation) []ValidatedRelation {
 	var validated []ValidatedRelation
@@ -98,6 +104,16 @@ func (v *Validator) Validate(relations []LLMRelation) []ValidatedRelation {
 				vr.IsValid = false
 				vr.Issues = append(vr.Issues, "Subject and Object too far apart (>500 chars)")
 			}
+
+			vr.VerbNode = findVerb(verbIndex, rel.Verb, s, o)
+		}
+
+		// 5. Check relation type constraints
+		if v.ontology != nil {
+			if canon, _ := v.ontology.Resolve(rel.RelationType); canon != nil {
+				vr.CanonicalType = canon.Name
+			}
+			vr.TypeViolations = v.ontology.Check(rel.RelationType, rel.SubjectKind, rel.ObjectKind)
 		}
 
 		validated = append(validated, vr)
@@ -154,6 +170,46 @@ func findClosestPair(subjs, objs []*cst.Node) (*cst.Node, *cst.Node) {
 	return bestS, bestO
 }
 
+// findVerb picks the verb phrase matching verb that lies closest to the
+// subject/object pair
+func findVerb(index map[string][]*cst.Node, verb string, s, o *cst.Node) *cst.Node {
+	lower := strings.ToLower(strings.TrimSpace(verb))
+	if lower == "" {
+		return nil
+	}
+	lo, hi := min(s.Range.Start, o.Range.Start), max(s.Range.End, o.Range.End)
+
+	var best *cst.Node
+	bestDist := 0
+	for k, nodes := range index {
+		if !strings.Contains(k, lower) && !strings.Contains(lower, k) {
+			continue
+		}
+		for _, n := range nodes {
+			dist := 0 // Between the pair
+			if n.Range.End <= lo {
+				dist = lo - n.Range.End
+			} else if n.Range.Start >= hi {
+				dist = n.Range.Start - hi
+			}
+			if best == nil || dist < bestDist || (dist == bestDist && n.Range.Start < best.Range.Start) {
+				best, bestDist = n, dist
+			}
+		}
+	}
+	return best
+}
+
+// Sentence returns the sentence node containing n, or nil
+func Sentence(n *cst.Node) *cst.Node {
+	for ; n != nil; n = n.Parent {
+		if n.Kind == syntax.KindSentence {
+			return n
+		}
+	}
+	return nil
+}
+
 func abs(x int) int {
 	if x < 0 {
 		return -x
@@ -188,8 +244,12 @@ func ValidCount(validated []ValidatedRelation) int {
 // - Close proximity: +0.05 boost
 // - Same sentence: +0.1 boost
 // - Not found: -0.3 penalty
+// - Type violation: halved
 func AdjustConfidence(vr *ValidatedRelation) float64 {
 	conf := vr.Original.Confidence
+	if len(vr.TypeViolations) > 0 {
+		conf *= 0.5
+	}
 
 	if !vr.IsValid {
 		// Heavy penalty for ungrounded relations
@@ -230,6 +290,22 @@ func (vr *ValidatedRelation) ToJSON(text string) map[string]interface{} {
 		"isValid":        vr.IsValid,
 		"issues":         vr.Issues,
 	}
+	for k, v := range map[string]string{
+		"manner":    vr.Original.Manner,
+		"location":  vr.Original.Location,
+		"time":      vr.Original.Time,
+		"recipient": vr.Original.Recipient,
+	} {
+		if v != "" {
+			result[k] = v
+		}
+	}
+	if vr.CanonicalType != "" {
+		result["canonicalType"] = vr.CanonicalType
+	}
+	if len(vr.TypeViolations) > 0 {
+		result["typeViolations"] = vr.TypeViolations
+	}
 
 	// Add CST position info if grounded
 	if vr.SubjectNode != nil {
@@ -242,6 +318,10 @@ func (vr *ValidatedRelation) ToJSON(text string) map[string]interface{} {
 		result["objectEnd"] = vr.ObjectNode.Range.End
 		result["objectText"] = vr.ObjectNode.Text(text)
 	}
+	if vr.VerbNode != nil {
+		result["verbStart"] = vr.VerbNode.Range.Start
+		result["verbEnd"] = vr.VerbNode.Range.End
+	}
 
 	return result
 }
@@ -61,6 +61,23 @@ func (s *Scorer) IndexDocument(docID string, meta DocumentMetadata, tokens map[s
 	s.CorpusStats.TotalDocuments++
 }
 
+// RemoveDocument drops a document and its postings from the mutable index.
+// Postings already compacted into the frozen index are left in place.
+func (s *Scorer) RemoveDocument(docID string) {
+	if _, ok := s.DocumentIndex[docID]; !ok {
+		return
+	}
+	delete(s.DocumentIndex, docID)
+	for term, postings := range s.TokenIndex {
+		delete(postings, docID)
+		if len(postings) == 0 {
+			delete(s.TokenIndex, term)
+		}
+	}
+	s.CorpusStats.TotalDocuments--
+	clear(s.IDFCache)
+}
+
 // Search executes a query (Hybrid)
 func (s *Scorer) Search(query []string, queryVector []float32, limit int) []SearchResult {
 	return s.SearchScoped(query, queryVector, limit, nil)
@@ -0,0 +1,357 @@
+// Package review turns discovery candidates into a persisted triage queue.
+// Candidates found by the scanner are recorded per world with counts,
+// first/last-seen notes and example contexts; users accept them (creating
+// an Entity), reject them (adding them to the world's stop list) or snooze them.
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kittclouds/gokitt/internal/ids"
+	"github.com/kittclouds/gokitt/internal/store"
+	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/scanner/discovery"
+)
+
+// Defaults for example contexts
+const (
+	DefaultMinCount      = 2 // Occurrences before a candidate is worth reviewing
+	DefaultMaxContexts   = 3
+	DefaultContextRadius = 60 // Bytes of text kept on each side of a mention
+)
+
+// Service manages the discovery review queue.
+type Service struct {
+	store         store.Storer
+	MinCount      int
+	MaxContexts   int
+	ContextRadius int
+}
+
+// NewService creates a review service backed by the given store.
+func NewService(s store.Storer) *Service {
+	return &Service{
+		store:         s,
+		MinCount:      DefaultMinCount,
+		MaxContexts:   DefaultMaxContexts,
+		ContextRadius: DefaultContextRadius,
+	}
+}
+
+// Record merges the candidates mentioned in one note into the queue.
+// Per-note counts are replaced rather than added, so rescanning a note is
+// idempotent. Stop-listed tokens are skipped. Returns the updated candidates.
+func (s *Service) Record(worldID, noteID, text string, candidates []discovery.Candidate) ([]*store.DiscoveryCandidate, error) {
+	stop, err := s.stopSet(worldID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	var updated []*store.DiscoveryCandidate
+
+	for _, cand := range candidates {
+		if cand.Status == int(discovery.StatusIgnored) {
+			continue
+		}
+		key, display, valid := discovery.Canonicalize(cand.Token)
+		if !valid || stop[string(key)] {
+			continue
+		}
+		mentions := findMentions(text, display)
+		if len(mentions) == 0 {
+			continue
+		}
+
+		c, err := s.store.GetCandidate(worldID, string(key))
+		if err != nil {
+			return nil, fmt.Errorf("review: get candidate %s: %w", key, err)
+		}
+		if c == nil {
+			c = &store.DiscoveryCandidate{
+				WorldID:     worldID,
+				Token:       string(key),
+				Display:     display,
+				NoteCounts:  map[string]int{},
+				Contexts:    []string{},
+				FirstNoteID: noteID,
+				Status:      store.CandidatePending,
+				CreatedAt:   now,
+			}
+		}
+
+		c.NoteCounts[noteID] = len(mentions)
+		c.Count = 0
+		for _, n := range c.NoteCounts {
+			c.Count += n
+		}
+		c.LastNoteID = noteID
+		if cand.Kind != "" && cand.Kind != "UNKNOWN" {
+			c.InferredKind = cand.Kind
+		}
+		if len(c.Contexts) < s.MaxContexts {
+			snippet := s.context(text, mentions[0], len(display))
+			if !containsString(c.Contexts, snippet) {
+				c.Contexts = append(c.Contexts, snippet)
+			}
+		}
+		c.UpdatedAt = now
+
+		if err := s.store.UpsertCandidate(c); err != nil {
+			return nil, fmt.Errorf("review: save candidate %s: %w", key, err)
+		}
+		updated = append(updated, c)
+	}
+
+	return updated, nil
+}
+
+// Queue returns the candidates awaiting review: pending ones plus snoozed
+// ones whose snooze has expired, seen at least MinCount times, most
+// frequent first.
+func (s *Service) Queue(worldID string) ([]*store.DiscoveryCandidate, error) {
+	all, err := s.store.ListCandidates(worldID, "")
+	if err != nil {
+		return nil, fmt.Errorf("review: list candidates: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	queue := make([]*store.DiscoveryCandidate, 0, len(all))
+	for _, c := range all {
+		if c.Count < s.MinCount {
+			continue
+		}
+		switch c.Status {
+		case store.CandidatePending:
+			queue = append(queue, c)
+		case store.CandidateSnoozed:
+			if c.SnoozedUntil <= now {
+				queue = append(queue, c)
+			}
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].Count > queue[j].Count })
+	return queue, nil
+}
+
+// Accept promotes a candidate to an Entity. kind overrides the inferred
+// kind; one of them is required. If an entity with the same label already
+// exists the candidate is linked to it instead of creating a duplicate.
+// New entities are scoped to the world's narrative.
+func (s *Service) Accept(worldID, token, kind string) (*store.Entity, error) {
+	c, err := s.get(worldID, token)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		kind = c.InferredKind
+	}
+	if kind == "" {
+		return nil, fmt.Errorf("review: candidate %q has no kind", c.Display)
+	}
+
+	entity, err := s.store.GetEntityByLabel(c.Display)
+	if err != nil {
+		return nil, fmt.Errorf("review: lookup entity %q: %w", c.Display, err)
+	}
+	if entity == nil {
+		now := time.Now().UnixMilli()
+		entity = &store.Entity{
+			ID:            ids.New(),
+			Label:         c.Display,
+			Kind:          strings.ToUpper(kind),
+			Aliases:       []string{},
+			FirstNote:     c.FirstNoteID,
+			TotalMentions: c.Count,
+			NarrativeID:   worldID,
+			CreatedBy:     "auto",
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := s.store.UpsertEntity(entity); err != nil {
+			return nil, fmt.Errorf("review: create entity %q: %w", c.Display, err)
+		}
+	}
+
+	c.Status = store.CandidateAccepted
+	c.EntityID = entity.ID
+	c.SnoozedUntil = 0
+	if err := s.save(c); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Reject marks a candidate as not an entity and adds it to the world's
+// stop list so it is never proposed again.
+func (s *Service) Reject(worldID, token string) error {
+	c, err := s.get(worldID, token)
+	if err != nil {
+		return err
+	}
+	if err := s.store.AddStopWord(worldID, c.Token); err != nil {
+		return fmt.Errorf("review: add stop word %q: %w", c.Token, err)
+	}
+	c.Status = store.CandidateRejected
+	c.SnoozedUntil = 0
+	return s.save(c)
+}
+
+// Snooze hides a candidate from the queue for the given duration.
+func (s *Service) Snooze(worldID, token string, d time.Duration) error {
+	c, err := s.get(worldID, token)
+	if err != nil {
+		return err
+	}
+	c.Status = store.CandidateSnoozed
+	c.SnoozedUntil = time.Now().Add(d).UnixMilli()
+	return s.save(c)
+}
+
+// Hydrate restores a scanner's in-memory registry from the persisted queue
+// and stop list, so discovery state survives reloads.
+func (s *Service) Hydrate(worldID string, reg *discovery.CandidateRegistry) error {
+	stop, err := s.store.ListStopWords(worldID)
+	if err != nil {
+		return fmt.Errorf("review: list stop words: %w", err)
+	}
+	for _, w := range stop {
+		reg.Ignore(w)
+	}
+
+	candidates, err := s.store.ListCandidates(worldID, "")
+	if err != nil {
+		return fmt.Errorf("review: list candidates: %w", err)
+	}
+	for _, c := range candidates {
+		var kind *implicitmatcher.EntityKind
+		if c.InferredKind != "" {
+			k := implicitmatcher.ParseKind(c.InferredKind)
+			kind = &k
+		}
+		switch c.Status {
+		case store.CandidateRejected:
+			reg.Ignore(c.Display)
+		case store.CandidateAccepted:
+			reg.Restore(c.Display, c.Count, discovery.StatusPromoted, kind)
+		default:
+			reg.Restore(c.Display, c.Count, discovery.StatusWatching, kind)
+		}
+	}
+	return nil
+}
+
+// get loads a candidate by display text or canonical token
+func (s *Service) get(worldID, token string) (*store.DiscoveryCandidate, error) {
+	key, _, valid := discovery.Canonicalize(token)
+	if !valid {
+		return nil, fmt.Errorf("review: invalid token %q", token)
+	}
+	c, err := s.store.GetCandidate(worldID, string(key))
+	if err != nil {
+		return nil, fmt.Errorf("review: get candidate %s: %w", key, err)
+	}
+	if c == nil {
+		return nil, fmt.Errorf("review: candidate %q not found", token)
+	}
+	return c, nil
+}
+
+func (s *Service) save(c *store.DiscoveryCandidate) error {
+	c.UpdatedAt = time.Now().UnixMilli()
+	if err := s.store.UpsertCandidate(c); err != nil {
+		return fmt.Errorf("review: save candidate %s: %w", c.Token, err)
+	}
+	return nil
+}
+
+func (s *Service) stopSet(worldID string) (map[string]bool, error) {
+	words, err := s.store.ListStopWords(worldID)
+	if err != nil {
+		return nil, fmt.Errorf("review: list stop words: %w", err)
+	}
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set, nil
+}
+
+// context returns the text around a mention, trimmed to whole words
+func (s *Service) context(text string, start, length int) string {
+	from := start - s.ContextRadius
+	to := start + length + s.ContextRadius
+	if from < 0 {
+		from = 0
+	}
+	if to > len(text) {
+		to = len(text)
+	}
+	// Don't split UTF-8 sequences
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to++
+	}
+
+	snippet := text[from:to]
+	lead := start - from // Mention offsets within the snippet
+	tail := lead + length
+	if from > 0 {
+		if i := strings.IndexFunc(snippet[:lead], unicode.IsSpace); i >= 0 {
+			snippet, tail = snippet[i+1:], tail-i-1
+		}
+	}
+	if to < len(text) {
+		if i := strings.LastIndexFunc(snippet[tail:], unicode.IsSpace); i >= 0 {
+			snippet = snippet[:tail+i]
+		}
+	}
+	return strings.Join(strings.Fields(snippet), " ")
+}
+
+// findMentions returns the byte offsets of whole-word occurrences of word
+func findMentions(text, word string) []int {
+	var out []int
+	for i := 0; i+len(word) <= len(text); {
+		j := strings.Index(text[i:], word)
+		if j < 0 {
+			break
+		}
+		start := i + j
+		end := start + len(word)
+		if isBoundary(text, start-1, true) && isBoundary(text, end, false) {
+			out = append(out, start)
+		}
+		i = end
+	}
+	return out
+}
+
+func isBoundary(text string, i int, before bool) bool {
+	if i < 0 || i >= len(text) {
+		return true
+	}
+	var r rune
+	if before {
+		r, _ = utf8.DecodeLastRuneInString(text[:i+1])
+	} else {
+		r, _ = utf8.DecodeRuneInString(text[i:])
+	}
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
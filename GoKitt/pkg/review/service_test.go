@@ -0,0 +1,146 @@
+package review
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kittclouds/gokitt/internal/store"
+	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/scanner/discovery"
+)
+
+func newTestService(t *testing.T) (*Service, *store.SQLiteStore) {
+	s, err := store.NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return NewService(s), s
+}
+
+var scanned = []discovery.Candidate{
+	{Token: "Eldoria", Count: 3, Kind: "PLACE"},
+	{Token: "Bram", Count: 2, Kind: "UNKNOWN"},
+	{Token: "Vesk", Count: 2, Kind: "UNKNOWN"},
+}
+
+func TestRecordIsIdempotentPerNote(t *testing.T) {
+	svc, s := newTestService(t)
+
+	text := "Bram rode to Eldoria past Vesk. Eldoria was silent when Bram arrived."
+	if _, err := svc.Record("w", "note-1", text, scanned); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	// Rescanning the same note must not inflate counts
+	if _, err := svc.Record("w", "note-1", text, scanned); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if _, err := svc.Record("w", "note-2", "Far from Eldoria, the sea rose.", scanned); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	c, _ := s.GetCandidate("w", "eldoria")
+	if c == nil {
+		t.Fatal("Expected Eldoria to be recorded")
+	}
+	if c.Count != 3 {
+		t.Errorf("Expected 3 mentions (2 + 1), got %d", c.Count)
+	}
+	if c.FirstNoteID != "note-1" || c.LastNoteID != "note-2" {
+		t.Errorf("Expected first/last notes note-1/note-2, got %s/%s", c.FirstNoteID, c.LastNoteID)
+	}
+	if c.InferredKind != "PLACE" {
+		t.Errorf("Expected inferred kind PLACE, got %q", c.InferredKind)
+	}
+	if len(c.Contexts) != 2 || !strings.Contains(c.Contexts[1], "Far from Eldoria") {
+		t.Errorf("Expected one context per note, got %q", c.Contexts)
+	}
+
+	// "Bramble" must not count as a mention of "Bram"
+	if got := findMentions("Bramble and Bram", "Bram"); len(got) != 1 || got[0] != 12 {
+		t.Errorf("Expected a single whole-word mention, got %v", got)
+	}
+}
+
+func TestTriage(t *testing.T) {
+	svc, s := newTestService(t)
+
+	text := "Bram rode to Eldoria past Vesk. Eldoria was silent. Bram waited near Vesk."
+	if _, err := svc.Record("w", "note-1", text, scanned); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	queue, err := svc.Queue("w")
+	if err != nil || len(queue) != 3 {
+		t.Fatalf("Expected 3 queued candidates, got %d (%v)", len(queue), err)
+	}
+
+	// Accept creates an entity with the inferred kind
+	entity, err := svc.Accept("w", "Eldoria", "")
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if entity.Label != "Eldoria" || entity.Kind != "PLACE" || entity.TotalMentions != 2 || entity.NarrativeID != "w" {
+		t.Errorf("Unexpected entity %+v", entity)
+	}
+	if stored, _ := s.GetEntityByLabel("Eldoria"); stored == nil || stored.ID != entity.ID {
+		t.Error("Expected the entity to be stored")
+	}
+
+	// A candidate without any kind needs one
+	if _, err := svc.Accept("w", "Bram", ""); err == nil {
+		t.Error("Expected an error accepting a candidate with no kind")
+	}
+
+	// Reject adds to the stop list; later scans skip it
+	if err := svc.Reject("w", "Vesk"); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if words, _ := s.ListStopWords("w"); len(words) != 1 || words[0] != "vesk" {
+		t.Errorf("Expected vesk on the stop list, got %v", words)
+	}
+	before, _ := s.GetCandidate("w", "vesk")
+	svc.Record("w", "note-3", "Vesk again.", scanned)
+	after, _ := s.GetCandidate("w", "vesk")
+	if after.Count != before.Count {
+		t.Error("Expected stop-listed candidate not to be recorded")
+	}
+
+	// Snooze hides until expiry
+	if err := svc.Snooze("w", "Bram", time.Hour); err != nil {
+		t.Fatalf("Snooze: %v", err)
+	}
+	if queue, _ := svc.Queue("w"); len(queue) != 0 {
+		t.Errorf("Expected empty queue, got %d", len(queue))
+	}
+	svc.Snooze("w", "Bram", -time.Minute)
+	if queue, _ := svc.Queue("w"); len(queue) != 1 || queue[0].Token != "bram" {
+		t.Errorf("Expected expired snooze to return to the queue, got %v", queue)
+	}
+}
+
+func TestHydrate(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	text := "Bram rode to Eldoria past Vesk. Eldoria was silent. Bram waited near Vesk."
+	svc.Record("w", "note-1", text, scanned)
+	svc.Reject("w", "Vesk")
+	svc.Accept("w", "Eldoria", "")
+
+	reg := discovery.NewRegistry(5)
+	if err := svc.Hydrate("w", reg); err != nil {
+		t.Fatalf("Hydrate: %v", err)
+	}
+
+	if stats := reg.GetStats("Eldoria"); stats == nil || stats.Status != discovery.StatusPromoted ||
+		stats.InferredKind == nil || *stats.InferredKind != implicitmatcher.KindPlace {
+		t.Errorf("Expected accepted Eldoria to be promoted as a place, got %+v", stats)
+	}
+	if stats := reg.GetStats("Bram"); stats == nil || stats.Count != 2 || stats.Status != discovery.StatusWatching {
+		t.Errorf("Expected pending Bram to be restored as watching, got %+v", stats)
+	}
+	if reg.AddToken("Vesk") {
+		t.Error("Expected rejected token to be ignored")
+	}
+}
@@ -323,20 +323,19 @@ func (c *Chunker) tryNounPhrase(tokens []Token, start int) (Chunk, int) {
 	return Chunk{}, 0
 }
 
-// tryVerbPhrase: Aux? Adv* Verb Adv*
+// tryVerbPhrase: (Aux|Modal|Adv)* Verb Adv*
+// Auxiliary chains are kept together: "might have been killed", "was not killed".
 func (c *Chunker) tryVerbPhrase(tokens []Token, start int) (Chunk, int) {
 	i := start
 	var modifiers []TextRange
 	headIdx := -1
 
-	// Optional auxiliary/modal
-	if i < len(tokens) && (tokens[i].POS == Auxiliary || tokens[i].POS == Modal) {
-		modifiers = append(modifiers, tokens[i].Range)
-		i++
-	}
-
-	// Pre-verb adverbs
-	for i < len(tokens) && tokens[i].POS == Adverb {
+	// Optional auxiliaries/modals, interleaved with pre-verb adverbs
+	for i < len(tokens) {
+		pos := tokens[i].POS
+		if pos != Auxiliary && pos != Modal && pos != Adverb {
+			break
+		}
 		modifiers = append(modifiers, tokens[i].Range)
 		i++
 	}
@@ -345,13 +344,10 @@ func (c *Chunker) tryVerbPhrase(tokens []Token, start int) (Chunk, int) {
 	if i < len(tokens) && tokens[i].POS == Verb {
 		headIdx = i
 		i++
-	} else if headIdx == -1 && len(modifiers) > 0 {
-		// If we saw Aux/Modal but no main Verb, treat the last Aux as the head (Copula-like behavior)
+	} else if aux := lastAuxiliary(tokens, start, i); aux >= 0 {
+		// No main verb: the last Aux/Modal is the head (Copula-like behavior)
 		// e.g. "is" in "is dangerous"
-		headIdx = i - 1 // The last modifier was the Aux
-		// Re-classify modifiers (remove the head from modifiers list)
-		// This is a bit hacky, but simpler than rewriting the loop.
-		// Actually, let's just accept it.
+		headIdx = aux
 	} else {
 		// Special Case: "is" appearing alone (Auxiliary)
 		// If we haven't consumed anything distinctively verb-like, fail.
@@ -369,6 +365,16 @@ func (c *Chunker) tryVerbPhrase(tokens []Token, start int) (Chunk, int) {
 	return Chunk{Kind: VerbPhrase, Range: rng, Head: head, Modifiers: modifiers}, i - start
 }
 
+// lastAuxiliary returns the index of the last Aux/Modal in tokens[start:end], or -1
+func lastAuxiliary(tokens []Token, start, end int) int {
+	for j := end - 1; j >= start; j-- {
+		if tokens[j].POS == Auxiliary || tokens[j].POS == Modal {
+			return j
+		}
+	}
+	return -1
+}
+
 // tryPrepPhrase: Prep NP
 func (c *Chunker) tryPrepPhrase(tokens []Token, start int) (Chunk, int) {
 	if start >= len(tokens) || tokens[start].POS != Preposition {
@@ -415,6 +421,7 @@ func (c *Chunker) tryAdjPhrase(tokens []Token, start int) (Chunk, int) {
 }
 
 // tryClause: RelPronoun VP (NP)?
+// Modifiers[0] is the relative pronoun; Modifiers[1], when present, is the object head.
 func (c *Chunker) tryClause(tokens []Token, start int) (Chunk, int) {
 	if start >= len(tokens) || tokens[start].POS != RelativePronoun {
 		return Chunk{}, 0
@@ -431,12 +438,14 @@ func (c *Chunker) tryClause(tokens []Token, start int) (Chunk, int) {
 	end := vp.Range.End
 
 	// Optional NP after VP
+	modifiers := []TextRange{rel.Range}
 	np, npConsumed := c.tryNounPhrase(tokens, i)
 	if npConsumed > 0 {
 		end = np.Range.End
 		i += npConsumed
+		modifiers = append(modifiers, np.Head)
 	}
 
 	rng := NewRange(rel.Range.Start, end)
-	return Chunk{Kind: Clause, Range: rng, Head: vp.Head, Modifiers: []TextRange{rel.Range}}, i - start
+	return Chunk{Kind: Clause, Range: rng, Head: vp.Head, Modifiers: modifiers}, i - start
 }
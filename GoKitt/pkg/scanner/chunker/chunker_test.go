@@ -101,6 +101,43 @@ func TestVerbPhraseWithAuxiliary(t *testing.T) {
 	}
 }
 
+func TestVerbPhraseAuxiliaryChain(t *testing.T) {
+	c := New()
+	text := "might not have been killed"
+	result := c.Chunk(text)
+
+	vps := filterByKind(result.Chunks, VerbPhrase)
+	if len(vps) != 1 {
+		t.Errorf("Expected 1 VP, got %d", len(vps))
+		return
+	}
+
+	vp := vps[0]
+	if vp.Text(text) != text {
+		t.Errorf("VP should be '%s', got '%s'", text, vp.Text(text))
+	}
+	if vp.HeadText(text) != "killed" {
+		t.Errorf("Head should be 'killed', got '%s'", vp.HeadText(text))
+	}
+}
+
+func TestTaggerAdverbKeepsVerb(t *testing.T) {
+	tagger := NewTagger()
+
+	// An adverb before a verb does not make it a noun
+	if tags := tagger.Tag([]string{"Arin", "did", "not", "kill", "Lyra"}); tags[3] != Verb {
+		t.Errorf("Expected 'kill' after 'not' to be a verb, got %v", tags[3])
+	}
+	if tags := tagger.Tag([]string{"Arin", "suddenly", "attacked", "the", "troll"}); !tags[2].IsVerbal() {
+		t.Errorf("Expected 'attacked' after 'suddenly' to be a verb, got %v", tags[2])
+	}
+
+	// An adjective still does: "a mighty [attack]"
+	if tags := tagger.Tag([]string{"a", "mighty", "attack"}); tags[2] != Noun {
+		t.Errorf("Expected 'attack' after an adjective to be a noun, got %v", tags[2])
+	}
+}
+
 func TestPrepPhrase(t *testing.T) {
 	c := New()
 	text := "in the forest"
@@ -3,6 +3,7 @@ package chunker
 import (
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Tagger performs Part-of-Speech tagging with context awareness (Dynamic Reinforcement)
@@ -45,7 +46,8 @@ func (t *Tagger) Tag(words []string) []POS {
 		// Rule 1: Determiner/Adjective force Noun
 		// "The [run]", "A fast [attack]"
 		// If current is Verb-like but preceded by Modifier/Det, it's likely a Noun
-		if (prevTag == Determiner || prevTag.IsModifier()) && currentTag.IsVerbal() {
+		// Adverbs are excluded: "did not [kill]", "suddenly [attacked]" keep the verb.
+		if (prevTag == Determiner || prevTag == Adjective) && currentTag.IsVerbal() {
 			// Special check: Don't convert "is/was" etc? No, lexicon handles those firmly.
 			// This works best for ambiguous words like "run", "attack", "play"
 			tags[i] = Noun
@@ -60,8 +62,8 @@ func (t *Tagger) Tag(words []string) []POS {
 		}
 
 		// Rule 3: "To" forces Verb (Infinitive marker)
-		// "want to [run]"
-		if i > 0 && isTo(words[i-1]) && currentTag.IsNominal() {
+		// "want to [run]" (but not "spoke to [Lyra]")
+		if i > 0 && isTo(words[i-1]) && currentTag.IsNominal() && currentTag != ProperNoun {
 			tags[i] = Verb
 			continue
 		}
@@ -78,7 +80,7 @@ func (t *Tagger) Tag(words []string) []POS {
 		// (Simplistic implementation: relies on inferPOS logic which checks caps)
 
 		// Fix punctuations that slipped through?
-		if len(currentWord) == 1 && unicode.IsPunct(rune(currentWord[0])) {
+		if isPunctWord(currentWord) {
 			tags[i] = Punctuation
 		}
 	}
@@ -101,12 +103,9 @@ func (t *Tagger) lookupBaseline(word string) POS {
 func (t *Tagger) inferPOS(word string) POS {
 	lower := fastLower(word)
 
-	// Single punctuation
-	if len(word) == 1 {
-		ch := rune(word[0])
-		if unicode.IsPunct(ch) {
-			return Punctuation
-		}
+	// Single punctuation (including multi-byte marks like curly quotes)
+	if isPunctWord(word) {
+		return Punctuation
 	}
 
 	// Proper noun: starts with uppercase
@@ -148,6 +147,12 @@ func fastLower(s string) string {
 	return s
 }
 
+// isPunctWord reports whether word is a single punctuation rune
+func isPunctWord(word string) bool {
+	r, size := utf8.DecodeRuneInString(word)
+	return size > 0 && size == len(word) && unicode.IsPunct(r)
+}
+
 func isTo(s string) bool {
 	return len(s) == 2 && (s[0] == 't' || s[0] == 'T') && (s[1] == 'o' || s[1] == 'O')
 }
@@ -174,12 +179,15 @@ func (t *Tagger) loadDefaultLexicon() {
 
 	// Auxiliaries
 	for _, w := range []string{"is", "are", "was", "were", "be", "been", "being", "am",
-		"have", "has", "had", "having", "do", "does", "did", "doing"} {
+		"have", "has", "had", "having", "do", "does", "did", "doing",
+		"isn't", "aren't", "wasn't", "weren't", "hasn't", "haven't", "hadn't",
+		"don't", "doesn't", "didn't"} {
 		t.lexicon[w] = Auxiliary
 	}
 
-	// Modals
-	for _, w := range []string{"can", "could", "will", "would", "shall", "should", "may", "might", "must"} {
+	// Modals (including negated contractions so "didn't kill" stays one VP)
+	for _, w := range []string{"can", "could", "will", "would", "shall", "should", "may", "might", "must",
+		"cannot", "can't", "couldn't", "won't", "wouldn't", "shan't", "shouldn't", "mightn't", "mustn't"} {
 		t.lexicon[w] = Modal
 	}
 
@@ -209,7 +217,7 @@ func (t *Tagger) loadDefaultLexicon() {
 	}
 
 	// Common adverbs
-	for _, w := range []string{"very", "quite", "rather", "really", "too", "just", "only",
+	for _, w := range []string{"not", "very", "quite", "rather", "really", "too", "just", "only",
 		"now", "then", "here", "there", "always", "never", "often", "sometimes", "slowly",
 		"quickly", "suddenly", "finally", "already", "still", "even"} {
 		t.lexicon[w] = Adverb
@@ -222,7 +230,7 @@ func (t *Tagger) loadDefaultLexicon() {
 		"walk", "walked", "walking", "run", "ran", "running", "live", "lived", "living",
 		"speak", "spoke", "spoken", "speaking", "fight", "fought", "fighting", "kill", "killed",
 		"killing", "love", "loved", "loving", "hate", "hated", "hating", "rule", "ruled", "ruling",
-		"serve", "served", "serving", "attack"} { // Explicitly added "attack" as base verb
+		"serve", "served", "serving", "slay", "slew", "slain", "steal", "stole", "stolen", "attack"} { // Explicitly added "attack" as base verb
 		t.lexicon[w] = Verb
 	}
 
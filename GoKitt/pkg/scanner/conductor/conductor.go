@@ -9,6 +9,7 @@ import (
 	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
 	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
 	"github.com/kittclouds/gokitt/pkg/scanner/conductor/helpers"
+	"github.com/kittclouds/gokitt/pkg/scanner/dialogue"
 	"github.com/kittclouds/gokitt/pkg/scanner/discovery"
 	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
 	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
@@ -24,6 +25,7 @@ type ScanResult struct {
 	Chunks       []chunker.Chunk
 	Narrative    []NarrativeEvent
 	ResolvedRefs []ResolvedReference
+	Dialogue     []dialogue.Quote
 }
 
 // NarrativeEvent is a high-level derived event from the scan
@@ -33,13 +35,28 @@ type NarrativeEvent struct {
 	Subject  string // EntityID or "Unknown"
 	Object   string // EntityID or "Unknown"
 	Range    chunker.TextRange
+	Negated  bool               // "did not kill", "never betrayed"
+	Modality narrative.Modality // might/must/would, "if ...", "tried to ..."
+	Tense    narrative.Tense
+	Passive  bool // Subject/Object already swapped to agent/patient
+}
+
+// Frame returns the event's factuality frame
+func (e NarrativeEvent) Frame() narrative.Frame {
+	return narrative.Frame{Negated: e.Negated, Modality: e.Modality, Tense: e.Tense}
+}
+
+// IsFactual reports whether the event is asserted rather than negated or hypothetical
+func (e NarrativeEvent) IsFactual() bool {
+	return e.Frame().IsFactual()
 }
 
 // ResolvedReference maps a text span to an EntityID
 type ResolvedReference struct {
-	Text     string
-	EntityID string
-	Range    chunker.TextRange
+	Text       string
+	EntityID   string
+	Range      chunker.TextRange
+	Confidence float64 // 1.0 exact name, lower for aliases, pronouns and descriptions
 }
 
 // Conductor manages the scanning pipeline
@@ -50,6 +67,7 @@ type Conductor struct {
 	narrativeMatcher *narrative.NarrativeMatcher
 	resolver         *resolver.Resolver
 	discoveryEngine  *discovery.DiscoveryEngine
+	dialogue         *dialogue.Segmenter
 }
 
 // New creates a new Conductor with all sub-components initialized
@@ -69,6 +87,7 @@ func New() (*Conductor, error) {
 		narrativeMatcher: nm,
 		resolver:         resolver.New(),
 		discoveryEngine:  discEngine,
+		dialogue:         dialogue.New(nm),
 	}, nil
 }
 
@@ -77,6 +96,17 @@ func (c *Conductor) SetDictionary(dict *implicitmatcher.RuntimeDictionary) {
 	c.implicitScanner = dict
 }
 
+// SetRegistry switches the resolver to a shared, world-level entity registry
+// (see resolver.Registry). Recency and dialogue state start fresh.
+func (c *Conductor) SetRegistry(reg *resolver.Registry) {
+	c.resolver = resolver.NewWithRegistry(reg)
+}
+
+// GetResolver returns the coreference resolver
+func (c *Conductor) GetResolver() *resolver.Resolver {
+	return c.resolver
+}
+
 // GetDictionary returns the Aho-Corasick implicit scanner
 func (c *Conductor) GetDictionary() *implicitmatcher.RuntimeDictionary {
 	return c.implicitScanner
@@ -150,33 +180,52 @@ func (c *Conductor) Scan(text string) ScanResult {
 		}
 	}
 
-	// 5. Narrative Pass (Verbs -> Events) & Discovery "Virus"
-	var narrativeEvents []NarrativeEvent
+	// 4b. Dialogue Pass (Quotes -> Speaker/Addressee)
+	quotes := c.dialogue.Analyze(text, chunkResult.Tokens)
+	c.resolveSpeakers(quotes)
+	narrativeEvents := dialogueEvents(quotes, synMatches, chunkResult.Tokens, c.resolver)
 
+	// 5. Narrative Pass (Verbs -> Events) & Discovery "Virus"
 	for i, chunk := range chunkResult.Chunks {
-		if chunk.Kind == chunker.VerbPhrase {
-			// Check verb against Narrative FST
-			headVerb := chunk.HeadText(text)
-			match := c.narrativeMatcher.Lookup(headVerb)
-
-			if match != nil {
-				// We found a narrative event!
-				// Attempt to find Subject (prev NP) and Object (next NP)
-				subjChunk := helpers.FindPrevNP(chunkResult.Chunks, i)
-				objChunk := helpers.FindNextNP(chunkResult.Chunks, i)
-
-				subjText := "Unknown"
-				objText := "Unknown"
-
-				if subjChunk != nil {
-					subjText = subjChunk.HeadText(text)
-				}
-				if objChunk != nil {
-					objText = objChunk.HeadText(text)
-				}
+		if chunk.Kind != chunker.VerbPhrase && chunk.Kind != chunker.Clause {
+			continue
+		}
+		// Dialogue tags ("said Arin") were already emitted by the dialogue pass
+		if isDialogueTag(quotes, chunk.Head) {
+			continue
+		}
+		// First/second person inside quotes resolve to speaker/addressee
+		c.enterQuote(quotes, chunk.Range)
+
+		// Check verb against Narrative FST
+		headVerb := chunk.HeadText(text)
+		match := c.narrativeMatcher.Lookup(headVerb)
+		if match == nil {
+			continue
+		}
+
+		// We found a narrative event!
+		// Assign Subjects/Objects (passive, coordination and relative clauses aware)
+		args := helpers.FindArguments(chunkResult.Chunks, chunkResult.Tokens, i, text)
+
+		// Factuality frame from the surrounding sentence tokens
+		verbRange := chunk.Range
+		if chunk.Kind == chunker.Clause {
+			verbRange = chunker.NewRange(chunk.Range.Start, chunk.Head.End)
+		}
+		preceding, phrase := helpers.FrameWindow(chunkResult.Tokens, verbRange)
+		frame := narrative.AnalyzeFrame(preceding, phrase)
+
+		subjects := argumentTexts(args.Subjects, text)
+		objects := argumentTexts(args.Objects, text)
 
+		// One event per subject/object pair
+		for si, subjText := range subjects {
+			subjRanges := argumentRange(args.Subjects, si)
+			for oi, objText := range objects {
+				objRanges := argumentRange(args.Objects, oi)
 				// Run Discovery Logic (Virus)
-				if subjChunk != nil && objChunk != nil {
+				if subjText != "Unknown" && objText != "Unknown" {
 					subjKind := c.resolveKind(subjText)
 					// Only propagate from known kinds for now, or assume Character if Proper
 					if subjKind != implicitmatcher.KindOther {
@@ -185,15 +234,8 @@ func (c *Conductor) Scan(text string) ScanResult {
 				}
 
 				// Resolve Entity IDs for final output
-				subjID := c.resolver.Resolve(subjText, nil)
-				if subjID == "" {
-					subjID = subjText
-				}
-
-				objID := c.resolver.Resolve(objText, nil)
-				if objID == "" {
-					objID = objText
-				}
+				subjID := c.resolveArgument(chunkResult, subjRanges, subjText, text)
+				objID := c.resolveArgument(chunkResult, objRanges, objText, text)
 
 				narrativeEvents = append(narrativeEvents, NarrativeEvent{
 					Event:    match.EventClass,
@@ -201,26 +243,51 @@ func (c *Conductor) Scan(text string) ScanResult {
 					Subject:  subjID,
 					Object:   objID,
 					Range:    chunk.Range,
+					Negated:  frame.Negated,
+					Modality: frame.Modality,
+					Tense:    frame.Tense,
+					Passive:  args.Passive,
 				})
 			}
 		}
 	}
+	c.resolver.ExitDialogue()
 
-	// 6. Resolver Pass (Pronouns) - Second pass for remaining tokens
+	// 6. Resolver Pass (Pronouns, Names, Definite Descriptions) - Second pass for remaining tokens
 	var resolvedRefs []ResolvedReference
 	for _, token := range chunkResult.Tokens {
-		if token.POS == chunker.Pronoun || token.POS == chunker.ProperNoun {
-			word := token.Text
-			if id := c.resolver.Resolve(word, nil); id != "" {
+		word := token.Text
+		if token.POS == chunker.Noun {
+			// "the old wizard" -> known entity via descriptors
+			if desc, ok := helpers.DefiniteDescription(chunkResult.Chunks, chunkResult.Tokens, token.Range); ok {
+				phrase := desc.Slice(text)
+				if id, conf := c.resolver.ResolveWithConfidence(phrase, nil); id != "" {
+					resolvedRefs = append(resolvedRefs, ResolvedReference{
+						Text:       phrase,
+						EntityID:   id,
+						Range:      desc,
+						Confidence: conf,
+					})
+					c.resolver.ObserveMention(id)
+				}
+			}
+			continue
+		}
+		if token.POS == chunker.Pronoun || token.POS == chunker.ProperNoun || resolver.IsDialoguePronoun(word) {
+			c.enterQuote(quotes, token.Range)
+			if id, conf := c.resolver.ResolveWithConfidence(word, nil); id != "" {
 				resolvedRefs = append(resolvedRefs, ResolvedReference{
-					Text:     word,
-					EntityID: id,
-					Range:    token.Range,
+					Text:       word,
+					EntityID:   id,
+					Range:      token.Range,
+					Confidence: conf,
 				})
 			}
 		}
 	}
 
+	c.resolver.ExitDialogue()
+
 	return ScanResult{
 		Text:         text,
 		CleanText:    text,
@@ -229,6 +296,7 @@ func (c *Conductor) Scan(text string) ScanResult {
 		Chunks:       chunkResult.Chunks,
 		Narrative:    narrativeEvents,
 		ResolvedRefs: resolvedRefs,
+		Dialogue:     quotes,
 	}
 }
 
@@ -239,31 +307,95 @@ func (c *Conductor) Close() error {
 
 // Helpers
 
+// argumentTexts maps argument head ranges to text, defaulting to "Unknown"
+func argumentTexts(ranges []chunker.TextRange, text string) []string {
+	if len(ranges) == 0 {
+		return []string{"Unknown"}
+	}
+	out := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		out = append(out, r.Slice(text))
+	}
+	return out
+}
+
+// resolveArgument resolves a verb argument to an entity ID, trying the full
+// definite description ("the old wizard") before the head word, and falls back
+// to the head text.
+func (c *Conductor) resolveArgument(res chunker.ChunkResult, r *chunker.TextRange, head, text string) string {
+	if r != nil {
+		if desc, ok := helpers.DefiniteDescription(res.Chunks, res.Tokens, *r); ok {
+			if id := c.resolver.Resolve(desc.Slice(text), nil); id != "" {
+				return id
+			}
+		}
+	}
+	if id := c.resolver.Resolve(head, nil); id != "" {
+		return id
+	}
+	return head
+}
+
+// argumentRange returns the i-th argument range, or nil for "Unknown"
+func argumentRange(ranges []chunker.TextRange, i int) *chunker.TextRange {
+	if i < len(ranges) {
+		return &ranges[i]
+	}
+	return nil
+}
+
 func (c *Conductor) registerExplicitEntities(matches []syntax.SyntaxMatch) {
 	for _, m := range matches {
-		if m.Kind == syntax.KindEntity {
-			gender := resolver.GenderUnknown
-			k := strings.ToUpper(m.EntityKind)
-			if k == "LOCATION" || k == "OBJECT" || k == "ITEM" || k == "MONSTER" {
-				gender = resolver.GenderNeutral
-			}
+		switch m.Kind {
+		case syntax.KindEntity:
+			c.registerExplicit(m.Label, m.EntityKind)
+		case syntax.KindTriple:
+			// [Kind:Subject] -[PREDICATE]-> [Kind:Object]
+			c.registerExplicit(m.Subject, m.SubjectKind)
+			c.registerExplicit(m.Object, m.ObjectKind)
+		}
+	}
+}
 
-			c.resolver.RegisterEntity(resolver.EntityMetadata{
-				ID:      m.Label,
-				Name:    m.Label,
-				Kind:    m.EntityKind,
-				Aliases: []string{},
-				Gender:  gender,
-			})
-			c.resolver.ObserveMention(m.Label)
-
-			// Also tell Discovery about it (as PROMOTED + Known Kind)
-			c.discoveryEngine.ObserveToken(m.Label)
-			// Force set kind in registry
-			kind := implicitmatcher.ParseKind(m.EntityKind)
-			c.discoveryEngine.Registry.ProposeInference(m.Label, kind)
+// registerExplicit registers a user-tagged entity with the resolver and Discovery
+func (c *Conductor) registerExplicit(label, entityKind string) {
+	// Entities seeded from the world registry keep their stored
+	// ID, gender and aliases; the tag only counts as a mention.
+	if id := c.seededID(label); id != "" {
+		c.resolver.ObserveMention(id)
+	} else {
+		gender := resolver.GenderUnknown
+		k := strings.ToUpper(entityKind)
+		if k == "LOCATION" || k == "OBJECT" || k == "ITEM" || k == "MONSTER" {
+			gender = resolver.GenderNeutral
 		}
+
+		c.resolver.RegisterEntity(resolver.EntityMetadata{
+			ID:      label,
+			Name:    label,
+			Kind:    entityKind,
+			Aliases: []string{},
+			Gender:  gender,
+		})
+		c.resolver.ObserveMention(label)
 	}
+
+	// Also tell Discovery about it (as PROMOTED + Known Kind)
+	c.discoveryEngine.ObserveToken(label)
+	// Force set kind in registry
+	kind := implicitmatcher.ParseKind(entityKind)
+	c.discoveryEngine.Registry.ProposeInference(label, kind)
+}
+
+// seededID returns the ID of a registered entity named label whose ID is
+// not the label itself, i.e. one that came from the store rather than a tag
+func (c *Conductor) seededID(label string) string {
+	for _, id := range c.resolver.Registry().Lookup(label) {
+		if id != label {
+			return id
+		}
+	}
+	return ""
 }
 
 func (c *Conductor) resolveKind(text string) implicitmatcher.EntityKind {
@@ -290,6 +422,11 @@ func (c *Conductor) GetCandidates() interface{} {
 	return c.discoveryEngine.Registry.GetCandidates()
 }
 
+// DiscoveryRegistry returns the discovery candidate registry
+func (c *Conductor) DiscoveryRegistry() *discovery.CandidateRegistry {
+	return c.discoveryEngine.Registry
+}
+
 // ScanDiscovery runs the full discovery pipeline (Harvester + Virus)
 func (c *Conductor) ScanDiscovery(text string) {
 	// Phase 1: Harvester - Observe ALL capitalized words
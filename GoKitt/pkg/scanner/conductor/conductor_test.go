@@ -3,6 +3,9 @@ package conductor
 import (
 	"strings"
 	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
 )
 
 func TestConductorFullPipeline(t *testing.T) {
@@ -60,3 +63,185 @@ func TestConductorFullPipeline(t *testing.T) {
 		t.Error("Did not resolve 'He' to 'Gandalf'")
 	}
 }
+
+func TestConductorEventFrames(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	tests := []struct {
+		text     string
+		negated  bool
+		modality narrative.Modality
+		tense    narrative.Tense
+	}{
+		{"Arin killed the king.", false, narrative.ModalityFactual, narrative.TensePast},
+		{"Arin did not kill the king.", true, narrative.ModalityFactual, narrative.TensePast},
+		{"Arin might betray Lyra.", false, narrative.ModalityPossible, narrative.TenseUnknown},
+		{"If Arin killed the king, Lyra would flee.", false, narrative.ModalityHypothetical, narrative.TensePast},
+		{"Arin will attack the castle.", false, narrative.ModalityFactual, narrative.TenseFuture},
+	}
+
+	for _, tt := range tests {
+		result := c.Scan(tt.text)
+		if len(result.Narrative) == 0 {
+			t.Errorf("%q: expected a narrative event", tt.text)
+			continue
+		}
+		ev := result.Narrative[0]
+		if ev.Negated != tt.negated {
+			t.Errorf("%q: expected negated=%v, got %v", tt.text, tt.negated, ev.Negated)
+		}
+		if ev.Modality != tt.modality {
+			t.Errorf("%q: expected modality %s, got %s", tt.text, tt.modality, ev.Modality)
+		}
+		if ev.Tense != tt.tense {
+			t.Errorf("%q: expected tense %q, got %q", tt.text, tt.tense, ev.Tense)
+		}
+		if ev.IsFactual() != (!tt.negated && tt.modality == narrative.ModalityFactual) {
+			t.Errorf("%q: IsFactual mismatch", tt.text)
+		}
+	}
+
+	// Subject should survive the negation ("not" must not become the subject NP)
+	result := c.Scan("Arin did not kill the king.")
+	if len(result.Narrative) > 0 && result.Narrative[0].Subject != "Arin" {
+		t.Errorf("Expected subject Arin, got %q", result.Narrative[0].Subject)
+	}
+}
+
+func TestConductorArgumentStructure(t *testing.T) {
+	tests := []struct {
+		text    string
+		pairs   []string // "Subject>Object"
+		passive bool
+	}{
+		{"The king was killed by Arin.", []string{"Arin>king"}, true},
+		{"The king was slain by Arin and Lyra.", []string{"Arin>king", "Lyra>king"}, true},
+		{"Arin and Lyra fought the troll.", []string{"Arin>troll", "Lyra>troll"}, false},
+		{"Arin, Lyra and Bram fought the troll.", []string{"Arin>troll", "Lyra>troll", "Bram>troll"}, false},
+		{"Arin fought the troll and the ogre.", []string{"Arin>troll", "Arin>ogre"}, false},
+		{"The knight who killed the dragon fled.", []string{"knight>dragon"}, false},
+		{"The sword that Arin stole was cursed.", []string{"Arin>sword"}, false},
+	}
+
+	for _, tt := range tests {
+		c, err := New()
+		if err != nil {
+			t.Fatalf("Failed to create conductor: %v", err)
+		}
+		result := c.Scan(tt.text)
+		c.Close()
+
+		var got []string
+		for _, ev := range result.Narrative {
+			if ev.Relation == narrative.RelIs {
+				continue // copula events ("was cursed") are not under test
+			}
+			got = append(got, ev.Subject+">"+ev.Object)
+			if ev.Passive != tt.passive {
+				t.Errorf("%q: expected passive=%v", tt.text, tt.passive)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.pairs, ",") {
+			t.Errorf("%q: expected %v, got %v", tt.text, tt.pairs, got)
+		}
+	}
+}
+
+func TestConductorDialogue(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	text := "[CHARACTER:Arin] met [CHARACTER:Lyra] at the gate. \"We must warn [CHARACTER:Bram],\" said Arin.\n\n" +
+		"\"I know,\" Lyra replied.\n\n\"Then you should ride tonight.\""
+	result := c.Scan(text)
+
+	if len(result.Dialogue) != 3 {
+		t.Fatalf("Expected 3 quotes, got %d", len(result.Dialogue))
+	}
+
+	has := func(subj string, rel narrative.RelationType, obj string) bool {
+		for _, ev := range result.Narrative {
+			if ev.Subject == subj && ev.Relation == rel && ev.Object == obj {
+				return true
+			}
+		}
+		return false
+	}
+	if !has("Arin", narrative.RelMentions, "Bram") {
+		t.Error("Expected Arin MENTIONS Bram")
+	}
+	if !has("Lyra", narrative.RelSpeaksTo, "Arin") {
+		t.Error("Expected Lyra SPEAKS_TO Arin")
+	}
+	if !has("Arin", narrative.RelSpeaksTo, "Lyra") {
+		t.Error("Expected Arin SPEAKS_TO Lyra (turn-taking)")
+	}
+
+	// Dialogue tags must not produce their own (garbled) SPEAKS_TO events
+	for _, ev := range result.Narrative {
+		if ev.Relation == narrative.RelSpeaksTo && ev.Subject == "Bram" {
+			t.Errorf("Unexpected tag event: %s SPEAKS_TO %s", ev.Subject, ev.Object)
+		}
+	}
+
+	// First/second person resolve to speaker/addressee
+	want := map[string]string{"I": "Lyra", "you": "Lyra"}
+	for _, ref := range result.ResolvedRefs {
+		if id, ok := want[ref.Text]; ok && ref.EntityID != id {
+			t.Errorf("Expected %s -> %s, got %s", ref.Text, id, ref.EntityID)
+		}
+	}
+}
+
+func TestConductorWorldRegistry(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	reg := resolver.NewRegistry()
+	reg.Register(resolver.EntityMetadata{
+		ID: "ent-gandalf", Name: "Gandalf", Gender: resolver.GenderMale,
+		Kind: "CHARACTER", Subtype: "wizard", Descriptors: []string{"old", "grey"},
+	})
+	c.SetRegistry(reg)
+
+	text := "[CHARACTER:Gandalf] entered the hall. The old wizard killed the troll."
+	result := c.Scan(text)
+
+	found := false
+	for _, ev := range result.Narrative {
+		if ev.Relation == narrative.RelKills && ev.Subject == "ent-gandalf" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected 'the old wizard' to resolve to ent-gandalf, got %+v", result.Narrative)
+	}
+
+	var desc *ResolvedReference
+	for i, ref := range result.ResolvedRefs {
+		if ref.Text == "The old wizard" {
+			desc = &result.ResolvedRefs[i]
+		}
+	}
+	if desc == nil || desc.EntityID != "ent-gandalf" {
+		t.Fatalf("Expected a resolved reference for the description, got %+v", result.ResolvedRefs)
+	}
+	if desc.Confidence <= 0 || desc.Confidence >= 1 {
+		t.Errorf("Expected a partial confidence for a description, got %.2f", desc.Confidence)
+	}
+
+	// The tag must not re-register the seeded entity under its label
+	if _, ok := reg.Get("Gandalf"); ok {
+		t.Error("Expected seeded entity to keep its stored ID")
+	}
+}
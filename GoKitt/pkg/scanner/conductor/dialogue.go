@@ -0,0 +1,104 @@
+package conductor
+
+import (
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/dialogue"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
+	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
+)
+
+// resolveSpeakers maps each quote's speaker/addressee text to an entity ID
+// (falling back to the surface text) and records the speaker as a mention.
+func (c *Conductor) resolveSpeakers(quotes []dialogue.Quote) {
+	for i := range quotes {
+		q := &quotes[i]
+		if q.Speaker != "" {
+			q.SpeakerID = c.resolveOrText(q.Speaker)
+			if c.resolver.Resolve(q.Speaker, nil) != "" {
+				c.resolver.ObserveMention(q.SpeakerID)
+			}
+		}
+		if q.Addressee != "" {
+			q.AddresseeID = c.resolveOrText(q.Addressee)
+		}
+	}
+}
+
+func (c *Conductor) resolveOrText(text string) string {
+	if id := c.resolver.Resolve(text, nil); id != "" {
+		return id
+	}
+	return text
+}
+
+// enterQuote sets the resolver's dialogue state for the quote covering r (if any)
+func (c *Conductor) enterQuote(quotes []dialogue.Quote, r chunker.TextRange) {
+	for i := range quotes {
+		if quotes[i].Contains(r) {
+			c.resolver.EnterDialogue(quotes[i].SpeakerID, quotes[i].AddresseeID)
+			return
+		}
+	}
+	c.resolver.ExitDialogue()
+}
+
+// isDialogueTag reports whether head is the tag verb of an attributed quote
+func isDialogueTag(quotes []dialogue.Quote, head chunker.TextRange) bool {
+	for _, q := range quotes {
+		if q.Attribution == dialogue.AttributionTag && q.VerbRange == head {
+			return true
+		}
+	}
+	return false
+}
+
+// dialogueEvents emits SPEAKS_TO (speaker -> addressee) and MENTIONS
+// (speaker -> entity named inside the quote) events for attributed quotes.
+func dialogueEvents(quotes []dialogue.Quote, synMatches []syntax.SyntaxMatch, tokens []chunker.Token, res *resolver.Resolver) []NarrativeEvent {
+	var events []NarrativeEvent
+	for _, q := range quotes {
+		if q.SpeakerID == "" {
+			continue
+		}
+
+		if q.AddresseeID != "" && q.AddresseeID != q.SpeakerID {
+			events = append(events, NarrativeEvent{
+				Event:    narrative.EventDialogue,
+				Relation: narrative.RelSpeaksTo,
+				Subject:  q.SpeakerID,
+				Object:   q.AddresseeID,
+				Range:    q.Range,
+			})
+		}
+
+		seen := map[string]bool{q.SpeakerID: true, q.AddresseeID: true}
+		mention := func(id string) {
+			if id == "" || seen[id] {
+				return
+			}
+			seen[id] = true
+			events = append(events, NarrativeEvent{
+				Event:    narrative.EventDialogue,
+				Relation: narrative.RelMentions,
+				Subject:  q.SpeakerID,
+				Object:   id,
+				Range:    q.Range,
+			})
+		}
+
+		// Explicit and implicit entity spans inside the quote
+		for _, m := range synMatches {
+			if m.Kind == syntax.KindEntity && q.Contains(chunker.NewRange(m.Start, m.End)) {
+				mention(m.Label)
+			}
+		}
+		// Proper nouns that resolve to registered entities
+		for _, tok := range tokens {
+			if tok.POS == chunker.ProperNoun && q.Contains(tok.Range) {
+				mention(res.Resolve(tok.Text, nil))
+			}
+		}
+	}
+	return events
+}
@@ -1,7 +1,10 @@
 package helpers
 
 import (
+	"strings"
+
 	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
 )
 
 // FindPrevNP searches backward for the nearest NounPhrase
@@ -23,3 +26,220 @@ func FindNextNP(chunks []chunker.Chunk, currentIdx int) *chunker.Chunk {
 	}
 	return nil
 }
+
+// FrameWindow splits the tokens of the sentence containing r into the words
+// preceding the phrase and the words inside it, for factuality analysis.
+// The preceding window starts after the last sentence terminator.
+func FrameWindow(tokens []chunker.Token, r chunker.TextRange) (preceding, phrase []string) {
+	for _, tok := range tokens {
+		if tok.Range.Start >= r.End {
+			break
+		}
+		if r.Contains(tok.Range) {
+			phrase = append(phrase, tok.Text)
+			continue
+		}
+		if tok.POS == chunker.Punctuation && isSentenceEnd(tok.Text) {
+			preceding = preceding[:0]
+			continue
+		}
+		if tok.POS != chunker.Punctuation {
+			preceding = append(preceding, tok.Text)
+		}
+	}
+	return preceding, phrase
+}
+
+func isSentenceEnd(s string) bool {
+	return s == "." || s == "!" || s == "?" || s == ";"
+}
+
+// DefiniteDescription returns the span from a definite determiner to the
+// head r ("the old wizard") when the chunk containing r opens one.
+func DefiniteDescription(chunks []chunker.Chunk, tokens []chunker.Token, r chunker.TextRange) (chunker.TextRange, bool) {
+	for _, c := range chunks {
+		if c.Kind != chunker.NounPhrase && c.Kind != chunker.PrepPhrase {
+			continue
+		}
+		if !c.Range.Contains(r) {
+			continue
+		}
+		for _, tok := range tokens {
+			if tok.Range.Start >= r.Start {
+				break
+			}
+			if c.Range.Contains(tok.Range) && isDefiniteDeterminer(tok.Text) {
+				return chunker.NewRange(tok.Range.Start, r.End), true
+			}
+		}
+		return chunker.TextRange{}, false
+	}
+	return chunker.TextRange{}, false
+}
+
+func isDefiniteDeterminer(w string) bool {
+	switch strings.ToLower(w) {
+	case "the", "this", "that":
+		return true
+	}
+	return false
+}
+
+// Arguments are the subject and object heads assigned to a verb
+type Arguments struct {
+	Subjects []chunker.TextRange
+	Objects  []chunker.TextRange
+	Passive  bool
+}
+
+// FindArguments assigns subjects and objects to the VerbPhrase or Clause chunk at idx.
+//
+// Handles:
+//   - Passive voice: "The king was killed by Arin" -> Arin KILLS king
+//   - Coordination: "Arin and Lyra fought the troll" -> two subjects
+//   - Subject relatives: "the knight who slew the dragon" -> knight SLAYS dragon
+//   - Object relatives: "the sword that Arin stole" -> Arin STEALS sword
+func FindArguments(chunks []chunker.Chunk, tokens []chunker.Token, idx int, text string) Arguments {
+	chunk := chunks[idx]
+	var args Arguments
+
+	if chunk.Kind == chunker.Clause {
+		verbRange := chunker.NewRange(chunk.Range.Start, chunk.Head.End)
+		args.Passive = narrative.IsPassive(phraseWords(tokens, verbRange))
+
+		var antecedents []chunker.TextRange
+		if ante := prevIndex(chunks, idx, chunker.NounPhrase); ante >= 0 {
+			antecedents, _ = coordinatedBackward(chunks, ante, text)
+		}
+		if args.Passive {
+			args.Objects = antecedents
+			args.Subjects = findAgents(chunks, idx, text)
+		} else {
+			args.Subjects = antecedents
+			if len(chunk.Modifiers) > 1 {
+				args.Objects = []chunker.TextRange{chunk.Modifiers[1]}
+			}
+		}
+		return args
+	}
+
+	args.Passive = narrative.IsPassive(phraseWords(tokens, chunk.Range))
+
+	var left []chunker.TextRange
+	first := -1
+	if prev := prevIndex(chunks, idx, chunker.NounPhrase); prev >= 0 {
+		left, first = coordinatedBackward(chunks, prev, text)
+	}
+
+	if args.Passive {
+		args.Objects = left
+		args.Subjects = findAgents(chunks, idx, text)
+		return args
+	}
+
+	args.Subjects = left
+	if ante := narrative.ObjectRelativeAntecedent(chunkSeq{chunks, text}, idx, first); ante >= 0 {
+		args.Objects = []chunker.TextRange{chunks[ante].Head}
+	} else if next := nextIndex(chunks, idx, chunker.NounPhrase); next >= 0 {
+		args.Objects = coordinatedForward(chunks, next, text)
+	}
+	return args
+}
+
+// chunkSeq adapts chunks to narrative.Sequence; conjunctions and relative
+// pronouns are left in the gaps between chunks
+type chunkSeq struct {
+	chunks []chunker.Chunk
+	text   string
+}
+
+func (s chunkSeq) Len() int                 { return len(s.chunks) }
+func (s chunkSeq) IsArgument(i int) bool    { return s.chunks[i].Kind == chunker.NounPhrase }
+func (s chunkSeq) IsConjunction(i int) bool { return false }
+
+func (s chunkSeq) Preposition(i int) string {
+	if c := &s.chunks[i]; c.Kind == chunker.PrepPhrase && len(c.Modifiers) > 0 {
+		return c.HeadText(s.text)
+	}
+	return ""
+}
+
+func (s chunkSeq) Gap(i, j int) (string, bool) {
+	start, end := s.chunks[i].Range.End, s.chunks[j].Range.Start
+	if start > end {
+		return "", false
+	}
+	return gapText(s.text, start, end), true
+}
+
+// findAgents returns the "by X (and Y)" agents directly following a passive verb
+func findAgents(chunks []chunker.Chunk, idx int, text string) []chunker.TextRange {
+	return heads(chunks, narrative.Agents(chunkSeq{chunks, text}, idx))
+}
+
+// coordinatedBackward collects the NP at idx plus NPs coordinated before it.
+// Returns heads in text order and the index of the leftmost NP.
+func coordinatedBackward(chunks []chunker.Chunk, idx int, text string) ([]chunker.TextRange, int) {
+	idxs := narrative.Coordinated(chunkSeq{chunks, text}, idx, -1)
+	return heads(chunks, idxs), idxs[0]
+}
+
+// coordinatedForward collects the NP (or PP object) at idx plus NPs coordinated
+// after it.
+func coordinatedForward(chunks []chunker.Chunk, idx int, text string) []chunker.TextRange {
+	return heads(chunks, narrative.Coordinated(chunkSeq{chunks, text}, idx, 1))
+}
+
+// heads maps chunk indices to their argument heads
+func heads(chunks []chunker.Chunk, idxs []int) []chunker.TextRange {
+	if len(idxs) == 0 {
+		return nil
+	}
+	out := make([]chunker.TextRange, 0, len(idxs))
+	for _, i := range idxs {
+		out = append(out, argumentHead(&chunks[i]))
+	}
+	return out
+}
+
+func argumentHead(c *chunker.Chunk) chunker.TextRange {
+	if c.Kind == chunker.PrepPhrase && len(c.Modifiers) > 0 {
+		return c.Modifiers[0] // NP head inside the PP
+	}
+	return c.Head
+}
+
+func prevIndex(chunks []chunker.Chunk, idx int, kind chunker.ChunkKind) int {
+	for i := idx - 1; i >= 0; i-- {
+		if chunks[i].Kind == kind {
+			return i
+		}
+	}
+	return -1
+}
+
+func nextIndex(chunks []chunker.Chunk, idx int, kind chunker.ChunkKind) int {
+	for i := idx + 1; i < len(chunks); i++ {
+		if chunks[i].Kind == kind {
+			return i
+		}
+	}
+	return -1
+}
+
+func phraseWords(tokens []chunker.Token, r chunker.TextRange) []string {
+	var words []string
+	for _, tok := range tokens {
+		if r.Contains(tok.Range) {
+			words = append(words, tok.Text)
+		}
+	}
+	return words
+}
+
+func gapText(text string, start, end int) string {
+	if start < 0 || end > len(text) || start > end {
+		return ""
+	}
+	return text[start:end]
+}
@@ -0,0 +1,374 @@
+// Package dialogue segments quoted speech and attributes each quote to a speaker.
+// Attribution uses dialogue tags ("said Arin", "Lyra asked", "Arin said to Lyra"),
+// vocatives inside the quote ("Lyra, run!") and turn-taking between alternating
+// speakers for untagged lines.
+package dialogue
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+)
+
+// Attribution describes how a quote's speaker was determined
+type Attribution uint8
+
+const (
+	AttributionNone  Attribution = 0 // Speaker unknown
+	AttributionTag   Attribution = 1 // Dialogue tag: "said Arin", "Arin asked"
+	AttributionTurn  Attribution = 2 // Turn-taking / same-paragraph continuation
+	AttributionReply Attribution = 3 // The previous quote's addressee answers
+)
+
+// String returns a readable name
+func (a Attribution) String() string {
+	switch a {
+	case AttributionTag:
+		return "TAG"
+	case AttributionTurn:
+		return "TURN"
+	case AttributionReply:
+		return "REPLY"
+	default:
+		return "NONE"
+	}
+}
+
+// Quote is a span of quoted speech with its attribution
+type Quote struct {
+	Range       chunker.TextRange // Including quote marks
+	Content     chunker.TextRange // Inside quote marks
+	Speaker     string            // Surface text of the speaker ("Arin", "he", "wizard")
+	Addressee   string            // Surface text of the addressee, if known
+	SpeakerID   string            // Resolved speaker entity (filled by the conductor)
+	AddresseeID string            // Resolved addressee entity (filled by the conductor)
+	VerbRange   chunker.TextRange // The dialogue tag verb ("said"), empty if none
+	Attribution Attribution
+}
+
+// Contains reports whether the quote content covers r
+func (q *Quote) Contains(r chunker.TextRange) bool {
+	return q.Content.Contains(r)
+}
+
+// Segmenter finds and attributes quotes
+type Segmenter struct {
+	matcher *narrative.NarrativeMatcher
+}
+
+// New creates a Segmenter that recognises speech verbs via the narrative matcher
+func New(matcher *narrative.NarrativeMatcher) *Segmenter {
+	return &Segmenter{matcher: matcher}
+}
+
+// Analyze segments quotes in text and attributes speakers and addressees.
+// tokens must be the chunker tokens for the same text.
+func (s *Segmenter) Analyze(text string, tokens []chunker.Token) []Quote {
+	quotes := Segment(text)
+	if len(quotes) == 0 {
+		return quotes
+	}
+
+	// 1. Dialogue tags and vocatives
+	for i := range quotes {
+		q := &quotes[i]
+		s.attributeTag(text, tokens, quotes, i)
+		if q.Addressee == "" {
+			q.Addressee = vocative(tokens, q.Content)
+		}
+	}
+
+	// 2. Turn-taking for untagged quotes
+	var recent []string // Distinct speakers, most recent first
+	for i := range quotes {
+		q := &quotes[i]
+		if q.Speaker == "" && i > 0 {
+			prev := &quotes[i-1]
+			switch {
+			case prev.Speaker != "" && samePara(text, prev.Range.End, q.Range.Start):
+				// Same paragraph continuation: "Run," he said. "Now!"
+				q.Speaker = prev.Speaker
+				q.Attribution = AttributionTurn
+			case prev.Addressee != "" && !strings.EqualFold(prev.Addressee, prev.Speaker):
+				// The person addressed answers
+				q.Speaker = prev.Addressee
+				q.Attribution = AttributionReply
+			case len(recent) >= 2:
+				// Alternate between the last two speakers
+				q.Speaker = recent[1]
+				q.Attribution = AttributionTurn
+			}
+		}
+
+		// Addressee defaults to the other party of the conversation
+		if q.Addressee == "" && q.Speaker != "" {
+			for _, sp := range recent {
+				if !strings.EqualFold(sp, q.Speaker) {
+					q.Addressee = sp
+					break
+				}
+			}
+		}
+
+		if q.Speaker != "" {
+			recent = pushRecent(recent, q.Speaker)
+		}
+	}
+
+	return quotes
+}
+
+// Segment finds quoted spans. Straight double quotes toggle; curly quotes
+// open/close explicitly. An unclosed quote ends at the paragraph break.
+func Segment(text string) []Quote {
+	var quotes []Quote
+	open := -1
+	openLen := 0
+
+	closeAt := func(end, closeLen int) {
+		quotes = append(quotes, Quote{
+			Range:   chunker.NewRange(open, end+closeLen),
+			Content: chunker.NewRange(open+openLen, end),
+		})
+		open = -1
+	}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '"':
+			if open == -1 {
+				open, openLen = i, size
+			} else {
+				closeAt(i, size)
+			}
+		case r == '\u201c': // “
+			if open == -1 {
+				open, openLen = i, size
+			}
+		case r == '\u201d': // ”
+			if open != -1 {
+				closeAt(i, size)
+			}
+		case r == '\n' && open != -1 && strings.HasPrefix(text[i:], "\n\n"):
+			closeAt(i, 0)
+		}
+		i += size
+	}
+	if open != -1 {
+		closeAt(len(text), 0)
+	}
+	return quotes
+}
+
+// attributeTag looks for a dialogue tag after the quote (up to the sentence
+// end or next quote), then before it (from the sentence start).
+func (s *Segmenter) attributeTag(text string, tokens []chunker.Token, quotes []Quote, i int) {
+	q := &quotes[i]
+
+	// After: "...," said Arin. / "...," Arin said to Lyra.
+	limit := len(text)
+	if i+1 < len(quotes) {
+		limit = quotes[i+1].Range.Start
+	}
+	after := window(tokens, q.Range.End, limit, true)
+	if s.matchTag(after, q) {
+		return
+	}
+
+	// Before: Arin said, "..." / Arin asked Lyra: "..."
+	start := 0
+	if i > 0 {
+		start = quotes[i-1].Range.End
+	}
+	before := window(tokens, start, q.Range.Start, false)
+	s.matchTag(before, q)
+}
+
+// window returns the tokens between start and end that form one sentence
+// adjacent to the quote: forward stops at a terminator, backward starts after one.
+func window(tokens []chunker.Token, start, end int, forward bool) []chunker.Token {
+	var out []chunker.Token
+	for _, tok := range tokens {
+		if tok.Range.Start < start || tok.Range.End > end {
+			continue
+		}
+		if isTerminator(tok.Text) {
+			if forward {
+				break
+			}
+			out = out[:0]
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// matchTag finds "SPEAKER VERB" or "VERB SPEAKER" in the window, plus "to ADDRESSEE"
+func (s *Segmenter) matchTag(win []chunker.Token, q *Quote) bool {
+	for v, tok := range win {
+		if !s.isSpeechVerb(tok) {
+			continue
+		}
+
+		// Speaker before the verb ("Arin said"), else after ("said Arin")
+		speaker := nominalBefore(win, v)
+		rest := v + 1
+		if speaker == "" {
+			var n int
+			speaker, n = nominalAfter(win, v+1)
+			rest = v + 1 + n
+		}
+		if speaker == "" {
+			continue
+		}
+
+		q.Speaker = speaker
+		q.VerbRange = tok.Range
+		q.Attribution = AttributionTag
+
+		// Addressee: "said to Lyra" / "asked Lyra"
+		if rest < len(win) && strings.EqualFold(win[rest].Text, "to") {
+			rest++
+		}
+		if addressee, _ := nominalAfter(win, rest); addressee != "" {
+			q.Addressee = addressee
+		}
+		return true
+	}
+	return false
+}
+
+func (s *Segmenter) isSpeechVerb(tok chunker.Token) bool {
+	if tok.POS.IsNominal() && tok.POS != chunker.Noun {
+		return false // Proper nouns/pronouns are never tags
+	}
+	if s.matcher == nil {
+		return false
+	}
+	m := s.matcher.Lookup(tok.Text)
+	return m != nil && m.EventClass == narrative.EventDialogue
+}
+
+// nominalBefore returns the speaker head immediately before index v
+func nominalBefore(win []chunker.Token, v int) string {
+	end := v
+	start := end
+	for start > 0 && isNominalToken(win[start-1]) {
+		start--
+	}
+	if start == end {
+		return ""
+	}
+	return headOf(win[start:end])
+}
+
+// nominalAfter returns the speaker head starting at index i and the tokens consumed
+func nominalAfter(win []chunker.Token, i int) (string, int) {
+	end := i
+	for end < len(win) && isNominalToken(win[end]) {
+		end++
+	}
+	if end == i {
+		return "", 0
+	}
+	return headOf(win[i:end]), end - i
+}
+
+// headOf picks the speaker text from a nominal group: a run of proper nouns
+// ("Lady Lyra"), otherwise the last noun or pronoun ("the old wizard" -> "wizard").
+func headOf(group []chunker.Token) string {
+	var proper []string
+	for _, tok := range group {
+		if tok.POS == chunker.ProperNoun {
+			proper = append(proper, tok.Text)
+		} else if len(proper) > 0 {
+			break
+		}
+	}
+	if len(proper) > 0 {
+		return strings.Join(proper, " ")
+	}
+	for j := len(group) - 1; j >= 0; j-- {
+		if group[j].POS.IsNominal() {
+			return group[j].Text
+		}
+	}
+	return ""
+}
+
+func isNominalToken(tok chunker.Token) bool {
+	switch tok.POS {
+	case chunker.Noun, chunker.ProperNoun, chunker.Pronoun, chunker.Determiner, chunker.Adjective:
+		return true
+	}
+	return false
+}
+
+// vocative detects a direct address inside the quote: "Lyra, run!" / "Run, Lyra!"
+func vocative(tokens []chunker.Token, content chunker.TextRange) string {
+	var inside []chunker.Token
+	for _, tok := range tokens {
+		if content.Contains(tok.Range) {
+			inside = append(inside, tok)
+		}
+	}
+	// Leading: "Lyra, run!" - the first word of a quote is always capitalized,
+	// so only accept names also seen capitalized mid-sentence elsewhere.
+	if len(inside) >= 2 && inside[0].POS == chunker.ProperNoun && inside[1].Text == "," &&
+		seenMidSentence(tokens, inside[0].Text) {
+		return inside[0].Text
+	}
+	// Trailing: ", Lyra!" (skip closing punctuation)
+	j := len(inside) - 1
+	for j >= 0 && inside[j].POS == chunker.Punctuation {
+		j--
+	}
+	if j >= 1 && inside[j].POS == chunker.ProperNoun && inside[j-1].Text == "," {
+		return inside[j].Text
+	}
+	return ""
+}
+
+// seenMidSentence reports whether word occurs capitalized somewhere other than
+// the start of a sentence or quote, i.e. it behaves like a name.
+func seenMidSentence(tokens []chunker.Token, word string) bool {
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].Text != word {
+			continue
+		}
+		prev := tokens[i-1].Text
+		if !isTerminator(prev) && !isQuoteMark(prev) {
+			return true
+		}
+	}
+	return false
+}
+
+func isQuoteMark(s string) bool {
+	return s == "\"" || s == "\u201c" || s == "\u201d"
+}
+
+func isTerminator(s string) bool {
+	return s == "." || s == "!" || s == "?" || s == "\n"
+}
+
+func samePara(text string, start, end int) bool {
+	if start < 0 || end > len(text) || start > end {
+		return false
+	}
+	return !strings.Contains(text[start:end], "\n")
+}
+
+func pushRecent(recent []string, speaker string) []string {
+	for i, sp := range recent {
+		if strings.EqualFold(sp, speaker) {
+			recent = append(recent[:i], recent[i+1:]...)
+			break
+		}
+	}
+	return append([]string{speaker}, recent...)
+}
@@ -0,0 +1,103 @@
+package dialogue
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+)
+
+func analyze(t *testing.T, text string) []Quote {
+	t.Helper()
+	matcher, err := narrative.New()
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+	defer matcher.Close()
+
+	tokens := chunker.New().Chunk(text).Tokens
+	return New(matcher).Analyze(text, tokens)
+}
+
+func TestSegment(t *testing.T) {
+	text := "\"Hello,\" she said. “Goodbye.” \"Unclosed\n\nNext para"
+	quotes := Segment(text)
+	if len(quotes) != 3 {
+		t.Fatalf("Expected 3 quotes, got %d", len(quotes))
+	}
+	if got := quotes[0].Content.Slice(text); got != "Hello," {
+		t.Errorf("Expected 'Hello,', got %q", got)
+	}
+	if got := quotes[1].Content.Slice(text); got != "Goodbye." {
+		t.Errorf("Expected 'Goodbye.', got %q", got)
+	}
+	if got := quotes[2].Content.Slice(text); got != "Unclosed" {
+		t.Errorf("Unclosed quote should end at paragraph break, got %q", got)
+	}
+}
+
+func TestTagAttribution(t *testing.T) {
+	tests := []struct {
+		text      string
+		speaker   string
+		addressee string
+	}{
+		{"\"Run,\" said Arin.", "Arin", ""},
+		{"\"Run,\" Arin shouted.", "Arin", ""},
+		{"Arin said to Lyra, \"Run.\"", "Arin", "Lyra"},
+		{"\"Who goes there?\" asked the old wizard.", "wizard", ""},
+		{"\"Run, Lyra!\" shouted Arin.", "Arin", "Lyra"},
+		{"Arin saw Lyra. \"Lyra, run!\" he shouted.", "he", "Lyra"},
+		{"\"Tonight, we ride,\" said Arin.", "Arin", ""},
+	}
+
+	for _, tt := range tests {
+		quotes := analyze(t, tt.text)
+		if len(quotes) != 1 {
+			t.Errorf("%q: expected 1 quote, got %d", tt.text, len(quotes))
+			continue
+		}
+		q := quotes[0]
+		if q.Speaker != tt.speaker {
+			t.Errorf("%q: expected speaker %q, got %q", tt.text, tt.speaker, q.Speaker)
+		}
+		if q.Addressee != tt.addressee {
+			t.Errorf("%q: expected addressee %q, got %q", tt.text, tt.addressee, q.Addressee)
+		}
+		if q.Attribution != AttributionTag {
+			t.Errorf("%q: expected TAG attribution, got %s", tt.text, q.Attribution)
+		}
+	}
+}
+
+func TestTurnTaking(t *testing.T) {
+	text := "\"We must go,\" said Arin.\n\n\"I know,\" Lyra replied.\n\n\"Tonight, then.\"\n\n\"Agreed.\""
+	quotes := analyze(t, text)
+	if len(quotes) != 4 {
+		t.Fatalf("Expected 4 quotes, got %d", len(quotes))
+	}
+
+	want := []string{"Arin", "Lyra", "Arin", "Lyra"}
+	for i, q := range quotes {
+		if q.Speaker != want[i] {
+			t.Errorf("Quote %d: expected speaker %s, got %q (%s)", i, want[i], q.Speaker, q.Attribution)
+		}
+	}
+	if quotes[2].Attribution == AttributionTag || quotes[2].Attribution == AttributionNone {
+		t.Errorf("Quote 2 should be attributed by turn-taking, got %s", quotes[2].Attribution)
+	}
+	if quotes[2].Addressee != "Lyra" {
+		t.Errorf("Quote 2 should be addressed to Lyra, got %q", quotes[2].Addressee)
+	}
+}
+
+func TestSameParagraphContinuation(t *testing.T) {
+	text := "\"Run,\" shouted Arin. \"Now!\""
+	quotes := analyze(t, text)
+	if len(quotes) != 2 {
+		t.Fatalf("Expected 2 quotes, got %d", len(quotes))
+	}
+	if quotes[1].Speaker != "Arin" || quotes[1].Attribution != AttributionTurn {
+		t.Errorf("Expected continuation by Arin, got %q (%s)", quotes[1].Speaker, quotes[1].Attribution)
+	}
+}
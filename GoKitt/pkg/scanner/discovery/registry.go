@@ -113,6 +113,44 @@ func (r *CandidateRegistry) AddStopWord(word string) {
 	r.StopWords[strings.ToLower(word)] = true
 }
 
+// Ignore stops tracking a token: it joins the stop words and any existing
+// stats are marked ignored (e.g. after the user rejects the candidate).
+func (r *CandidateRegistry) Ignore(raw string) {
+	key, _, valid := Canonicalize(raw)
+	if !valid {
+		return
+	}
+	r.StopWords[string(key)] = true
+	if stats, ok := r.Stats[key]; ok {
+		stats.Status = StatusIgnored
+	}
+}
+
+// Restore re-creates a candidate from persisted state, e.g. after a reload.
+// Counts never decrease; a known kind is kept unless none was inferred yet.
+func (r *CandidateRegistry) Restore(raw string, count int, status CandidateStatus, kind *implicitmatcher.EntityKind) {
+	key, display, valid := Canonicalize(raw)
+	if !valid {
+		return
+	}
+	stats, exists := r.Stats[key]
+	if !exists {
+		stats = &CandidateStats{Display: display}
+		r.Stats[key] = stats
+	}
+	if count > stats.Count {
+		stats.Count = count
+	}
+	stats.Status = status
+	if status == StatusWatching && stats.Count >= r.PromotionThreshold {
+		stats.Status = StatusPromoted
+	}
+	if kind != nil && stats.InferredKind == nil {
+		k := *kind
+		stats.InferredKind = &k
+	}
+}
+
 // AddToken processes a token. Returns true if promoted this time.
 func (r *CandidateRegistry) AddToken(raw string) bool {
 	key, display, valid := Canonicalize(raw)
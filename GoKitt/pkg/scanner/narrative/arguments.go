@@ -0,0 +1,107 @@
+package narrative
+
+import "strings"
+
+// Sequence is a sentence's phrases in text order, as seen by the argument
+// rules below. The scanner's chunks and the reality layer's CST nodes both
+// implement it, so the two pipelines assign arguments the same way.
+type Sequence interface {
+	Len() int
+	// IsArgument reports whether item i is a noun-phrase argument
+	IsArgument(i int) bool
+	// IsConjunction reports whether item i is a standalone "and"/"or" word
+	IsConjunction(i int) bool
+	// Preposition returns the preposition heading item i when it is a
+	// prepositional phrase, or ""
+	Preposition(i int) string
+	// Gap returns the text between items i and j (i < j); false when they
+	// overlap
+	Gap(i, j int) (string, bool)
+}
+
+// Coordinated returns the index of the argument at idx plus the arguments
+// joined to it by "and"/"or"/commas, walking in direction dir (-1 or 1).
+// Indices are in text order. A bare comma only joins list items closed by
+// a conjunction ("A, B and C"), so "Arin attacked the troll, Lyra fled"
+// keeps the troll alone. The item at idx itself is not checked.
+func Coordinated(seq Sequence, idx, dir int) []int {
+	if idx < 0 || idx >= seq.Len() {
+		return nil
+	}
+	chain := []int{idx}
+	keep, sawConj := 1, false
+	for curr := idx; ; {
+		next := curr + dir
+		if next >= 0 && next < seq.Len() && seq.IsConjunction(next) {
+			next += dir
+		}
+		if next < 0 || next >= seq.Len() || !seq.IsArgument(next) {
+			break
+		}
+
+		a, b := curr, next
+		if dir < 0 {
+			a, b = b, a
+		}
+		gap, ok := seq.Gap(a, b)
+		if !ok {
+			break
+		}
+		bareComma := strings.TrimSpace(gap) == ","
+		if !IsCoordination(gap) || (dir < 0 && bareComma && !sawConj) {
+			break
+		}
+		chain = append(chain, next)
+		if !bareComma {
+			sawConj = true
+		}
+		if dir < 0 || !bareComma {
+			keep = len(chain)
+		}
+		curr = next
+	}
+
+	chain = chain[:keep]
+	if dir < 0 {
+		for l, r := 0, len(chain)-1; l < r; l, r = l+1, r-1 {
+			chain[l], chain[r] = chain[r], chain[l]
+		}
+	}
+	return chain
+}
+
+// Agents returns the indices of the "by X (and Y)" agents directly following
+// the passive verb at verbIdx. The first index is the "by" phrase itself.
+func Agents(seq Sequence, verbIdx int) []int {
+	next := verbIdx + 1
+	if next >= seq.Len() || !IsAgentMarker(seq.Preposition(next)) {
+		return nil
+	}
+	return Coordinated(seq, next, 1)
+}
+
+// ObjectRelativeAntecedent detects "NP that/which SUBJ VERB" and returns the
+// index of the antecedent, or -1. first is the index of the leftmost subject.
+// Only applies when the verb has no direct object of its own ("the sword
+// that Arin stole was cursed").
+func ObjectRelativeAntecedent(seq Sequence, verbIdx, first int) int {
+	if first <= 0 {
+		return -1
+	}
+	if verbIdx+1 < seq.Len() && seq.IsArgument(verbIdx+1) {
+		return -1
+	}
+	// The pronoun may be an item of its own or just text between the two
+	ante := first - 1
+	if !seq.IsArgument(ante) {
+		ante--
+	}
+	if ante < 0 || !seq.IsArgument(ante) {
+		return -1
+	}
+	gap, ok := seq.Gap(ante, first)
+	if words := strings.Fields(gap); !ok || len(words) != 1 || !IsRelativePronoun(words[0]) {
+		return -1
+	}
+	return ante
+}
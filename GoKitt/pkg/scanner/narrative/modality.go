@@ -0,0 +1,286 @@
+package narrative
+
+import "strings"
+
+// Modality describes how strongly a narrative event is asserted
+type Modality uint8
+
+const (
+	ModalityFactual      Modality = 0 // "Arin killed the king"
+	ModalityPossible     Modality = 1 // "Arin might kill the king"
+	ModalityNecessary    Modality = 2 // "Arin must kill the king"
+	ModalityHypothetical Modality = 3 // "If Arin killed the king..." / "Arin would kill"
+	ModalityIntended     Modality = 4 // "Arin tried to kill the king"
+)
+
+// String returns a readable name
+func (m Modality) String() string {
+	switch m {
+	case ModalityFactual:
+		return "FACTUAL"
+	case ModalityPossible:
+		return "POSSIBLE"
+	case ModalityNecessary:
+		return "NECESSARY"
+	case ModalityHypothetical:
+		return "HYPOTHETICAL"
+	case ModalityIntended:
+		return "INTENDED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Weight returns the confidence multiplier for edges carrying this modality
+func (m Modality) Weight() float64 {
+	switch m {
+	case ModalityFactual:
+		return 1.0
+	case ModalityNecessary:
+		return 0.6
+	case ModalityPossible:
+		return 0.5
+	case ModalityIntended:
+		return 0.4
+	case ModalityHypothetical:
+		return 0.3
+	default:
+		return 1.0
+	}
+}
+
+// ParseModality converts a modality name back into a Modality.
+// Empty or unknown names are treated as factual.
+func ParseModality(s string) Modality {
+	switch strings.ToUpper(s) {
+	case "POSSIBLE":
+		return ModalityPossible
+	case "NECESSARY":
+		return ModalityNecessary
+	case "HYPOTHETICAL":
+		return ModalityHypothetical
+	case "INTENDED":
+		return ModalityIntended
+	default:
+		return ModalityFactual
+	}
+}
+
+// Tense is the grammatical time of a narrative event
+type Tense uint8
+
+const (
+	TenseUnknown Tense = 0
+	TensePast    Tense = 1
+	TensePresent Tense = 2
+	TenseFuture  Tense = 3
+)
+
+// String returns a readable name (empty for unknown)
+func (t Tense) String() string {
+	switch t {
+	case TensePast:
+		return "PAST"
+	case TensePresent:
+		return "PRESENT"
+	case TenseFuture:
+		return "FUTURE"
+	default:
+		return ""
+	}
+}
+
+// Frame is the factuality frame of an event: polarity, modality and tense
+type Frame struct {
+	Negated  bool
+	Modality Modality
+	Tense    Tense
+}
+
+// IsFactual reports whether the event is asserted as having happened (or happening)
+func (f Frame) IsFactual() bool {
+	return !f.Negated && f.Modality == ModalityFactual
+}
+
+// Weight returns the confidence multiplier for the frame.
+// Negated events assert the absence of a relation, so they weigh nothing.
+func (f Frame) Weight() float64 {
+	if f.Negated {
+		return 0
+	}
+	return f.Modality.Weight()
+}
+
+var negators = map[string]bool{
+	"not": true, "never": true, "no": true, "cannot": true, "nor": true, "neither": true,
+}
+
+var modalKinds = map[string]Modality{
+	"might": ModalityPossible, "may": ModalityPossible, "could": ModalityPossible, "can": ModalityPossible,
+	"must": ModalityNecessary, "should": ModalityNecessary, "ought": ModalityNecessary,
+	"would": ModalityHypothetical,
+}
+
+var conditionalMarkers = map[string]bool{
+	"if": true, "unless": true, "suppose": true, "supposing": true, "imagine": true,
+	"whether": true, "lest": true, "perhaps": true, "maybe": true,
+}
+
+var intentVerbs = map[string]bool{
+	"tried": true, "try": true, "tries": true, "trying": true,
+	"wanted": true, "want": true, "wants": true,
+	"planned": true, "plan": true, "plans": true,
+	"intended": true, "intend": true, "intends": true,
+	"attempted": true, "attempt": true, "attempts": true,
+	"hoped": true, "hope": true, "hopes": true,
+	"meant": true, "vowed": true, "vow": true, "vows": true,
+	"plotted": true, "plot": true, "plots": true,
+	"sought": true, "seek": true, "seeks": true,
+	"wished": true, "wish": true, "wishes": true,
+	"threatened": true, "threaten": true, "threatens": true,
+}
+
+var pastAux = map[string]bool{"did": true, "was": true, "were": true, "had": true}
+
+var presentAux = map[string]bool{
+	"do": true, "does": true, "is": true, "are": true, "am": true, "has": true, "have": true,
+}
+
+var futureAux = map[string]bool{"will": true, "shall": true}
+
+// irregularPast lists common strong past forms the suffix heuristic misses
+var irregularPast = map[string]bool{
+	"went": true, "came": true, "saw": true, "took": true, "got": true, "made": true,
+	"ran": true, "spoke": true, "fought": true, "slew": true, "knew": true, "gave": true,
+	"stole": true, "found": true, "hid": true, "became": true, "left": true, "met": true,
+	"heard": true, "told": true, "said": true, "rose": true, "fell": true, "built": true,
+	"bore": true, "broke": true, "brought": true, "bought": true, "caught": true, "chose": true,
+	"drew": true, "drove": true, "ate": true, "flew": true, "forgot": true, "froze": true,
+	"held": true, "kept": true, "led": true, "lost": true, "rode": true, "sang": true,
+	"sent": true, "shot": true, "struck": true, "swore": true, "taught": true, "thought": true,
+	"threw": true, "won": true, "wrote": true, "stood": true, "sat": true, "slept": true,
+	"understood": true, "began": true, "betrayed": true,
+}
+
+// AnalyzeFrame classifies the factuality frame of an event.
+//
+// preceding holds the words of the sentence before the verb phrase (used for
+// conditional markers like "if" and intent constructions like "tried to");
+// phrase holds the words of the verb phrase itself (auxiliaries, modals,
+// adverbs and the head verb). Words are matched case-insensitively.
+func AnalyzeFrame(preceding, phrase []string) Frame {
+	var f Frame
+
+	// 1. Polarity: negators inside the phrase or immediately before it
+	for _, w := range phrase {
+		if isNegator(w) {
+			f.Negated = true
+			break
+		}
+	}
+	if !f.Negated {
+		for i := len(preceding) - 1; i >= 0 && i >= len(preceding)-2; i-- {
+			if isNegator(preceding[i]) {
+				f.Negated = true
+				break
+			}
+		}
+	}
+
+	// 2. Modality: modals in the phrase
+	hasModal := false
+	perfect := false
+	for _, w := range phrase {
+		lw := stripNegation(strings.ToLower(w))
+		if m, ok := modalKinds[lw]; ok {
+			hasModal = true
+			if m > f.Modality {
+				f.Modality = m
+			}
+		}
+		if lw == "have" && hasModal {
+			perfect = true
+		}
+	}
+
+	// Intent constructions: "tried to kill", "wanted to betray"
+	if n := len(preceding); n >= 2 && strings.EqualFold(preceding[n-1], "to") &&
+		intentVerbs[strings.ToLower(preceding[n-2])] {
+		f.Modality = ModalityIntended
+	}
+
+	// Conditional framing anywhere earlier in the sentence wins
+	for _, w := range preceding {
+		if conditionalMarkers[strings.ToLower(w)] {
+			f.Modality = ModalityHypothetical
+			break
+		}
+	}
+
+	// 3. Tense
+	f.Tense = detectTense(preceding, phrase, hasModal, perfect)
+
+	return f
+}
+
+func detectTense(preceding, phrase []string, hasModal, perfect bool) Tense {
+	// "going to kill"
+	if n := len(preceding); n >= 2 && strings.EqualFold(preceding[n-1], "to") &&
+		strings.EqualFold(preceding[n-2], "going") {
+		return TenseFuture
+	}
+
+	for _, w := range phrase {
+		lw := strings.ToLower(w)
+		if futureAux[stripNegation(lw)] || lw == "won't" || strings.HasSuffix(lw, "'ll") {
+			return TenseFuture
+		}
+	}
+	if perfect {
+		return TensePast // "might have killed"
+	}
+	for _, w := range phrase {
+		if pastAux[stripNegation(strings.ToLower(w))] {
+			return TensePast
+		}
+	}
+	for _, w := range phrase {
+		if presentAux[stripNegation(strings.ToLower(w))] {
+			return TensePresent
+		}
+	}
+	if hasModal {
+		return TenseUnknown
+	}
+
+	// Morphology of the head (last word that isn't an adverb)
+	for i := len(phrase) - 1; i >= 0; i-- {
+		lw := strings.ToLower(phrase[i])
+		if isNegator(lw) || strings.HasSuffix(lw, "ly") {
+			continue
+		}
+		if irregularPast[lw] || (len(lw) > 3 && strings.HasSuffix(lw, "ed")) {
+			return TensePast
+		}
+		return TensePresent
+	}
+	return TenseUnknown
+}
+
+func isNegator(w string) bool {
+	lw := strings.ToLower(w)
+	return negators[lw] || strings.HasSuffix(lw, "n't")
+}
+
+// stripNegation maps contracted negatives back to their auxiliary: "didn't" -> "did"
+func stripNegation(w string) string {
+	switch w {
+	case "won't":
+		return "will"
+	case "can't", "cannot":
+		return "can"
+	case "shan't":
+		return "shall"
+	}
+	return strings.TrimSuffix(w, "n't")
+}
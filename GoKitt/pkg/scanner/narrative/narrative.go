@@ -41,7 +41,9 @@ var verbEntries = []verbEntry{
 	{"fight", EventBattle, RelFights, Transitive},  // fight X
 	{"fought", EventBattle, RelFights, Transitive}, // Irregular past of 'fight'
 	{"kill", EventDeath, RelKills, Transitive},
+	{"slain", EventDeath, RelKills, Transitive}, // Irregular participle of 'slay'
 	{"slay", EventDeath, RelKills, Transitive},
+	{"slew", EventDeath, RelKills, Transitive}, // Irregular past of 'slay'
 	{"wound", EventBattle, RelAttacks, Transitive},
 
 	// Travel/Movement
@@ -94,6 +96,8 @@ var verbEntries = []verbEntry{
 	{"give", EventAcquire, RelGives, Ditransitive},
 	{"own", EventAcquire, RelOwns, Transitive},
 	{"steal", EventTheft, RelSteals, Transitive},
+	{"stole", EventTheft, RelSteals, Transitive},  // Irregular past of 'steal'
+	{"stolen", EventTheft, RelSteals, Transitive}, // Irregular participle of 'steal'
 	{"take", EventAcquire, RelTakes, Transitive},
 
 	// Causality
@@ -1,6 +1,9 @@
 package narrative
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestNarrativeMatcherBasic(t *testing.T) {
 	matcher, err := New()
@@ -111,3 +114,102 @@ func TestDictionarySize(t *testing.T) {
 		t.Errorf("Expected at least 30 entries, got %d", size)
 	}
 }
+
+func TestAnalyzeFrame(t *testing.T) {
+	tests := []struct {
+		sentence string // words before the verb phrase, then "|", then the phrase
+		negated  bool
+		modality Modality
+		tense    Tense
+	}{
+		{"Arin | killed", false, ModalityFactual, TensePast},
+		{"Arin | did not kill", true, ModalityFactual, TensePast},
+		{"Arin | didn't kill", true, ModalityFactual, TensePast},
+		{"Arin | never betrayed", true, ModalityFactual, TensePast},
+		{"Arin | might betray", false, ModalityPossible, TenseUnknown},
+		{"Arin | must kill", false, ModalityNecessary, TenseUnknown},
+		{"Arin | will attack", false, ModalityFactual, TenseFuture},
+		{"Arin | won't attack", true, ModalityFactual, TenseFuture},
+		{"If Arin | killed", false, ModalityHypothetical, TensePast},
+		{"Arin tried to | kill", false, ModalityIntended, TensePresent},
+		{"Arin | attacks", false, ModalityFactual, TensePresent},
+		{"Arin | could have saved", false, ModalityPossible, TensePast},
+	}
+
+	for _, tt := range tests {
+		parts := strings.SplitN(tt.sentence, "|", 2)
+		frame := AnalyzeFrame(strings.Fields(parts[0]), strings.Fields(parts[1]))
+		if frame.Negated != tt.negated {
+			t.Errorf("%q: expected negated=%v, got %v", tt.sentence, tt.negated, frame.Negated)
+		}
+		if frame.Modality != tt.modality {
+			t.Errorf("%q: expected modality %s, got %s", tt.sentence, tt.modality, frame.Modality)
+		}
+		if frame.Tense != tt.tense {
+			t.Errorf("%q: expected tense %q, got %q", tt.sentence, tt.tense, frame.Tense)
+		}
+	}
+}
+
+// phrases is a Sequence over items separated by the given gaps
+type phrases struct {
+	items []string // "NP:Arin", "VP:fought", "PP:by", "W:and"
+	gaps  []string // gaps[i] precedes items[i+1]
+}
+
+func (p phrases) Len() int { return len(p.items) }
+func (p phrases) kind(i int) string {
+	return p.items[i][:strings.Index(p.items[i], ":")]
+}
+func (p phrases) IsArgument(i int) bool    { return p.kind(i) == "NP" }
+func (p phrases) IsConjunction(i int) bool { return p.kind(i) == "W" && p.items[i] == "W:and" }
+func (p phrases) Preposition(i int) string {
+	if p.kind(i) == "PP" {
+		return strings.TrimPrefix(p.items[i], "PP:")
+	}
+	return ""
+}
+func (p phrases) Gap(i, j int) (string, bool) {
+	var sb strings.Builder
+	for k := i; k < j; k++ {
+		sb.WriteString(p.gaps[k])
+		if k+1 < j {
+			sb.WriteString(p.items[k+1][strings.Index(p.items[k+1], ":")+1:])
+		}
+	}
+	return sb.String(), true
+}
+
+func TestArgumentRules(t *testing.T) {
+	// "A, B and C fought the troll, Lyra fled"
+	s := phrases{
+		items: []string{"NP:A", "NP:B", "NP:C", "VP:fought", "NP:troll", "NP:Lyra"},
+		gaps:  []string{", ", " and ", " ", " ", ", "},
+	}
+	if got := Coordinated(s, 2, -1); len(got) != 3 || got[0] != 0 {
+		t.Errorf("Expected A, B and C as subjects, got %v", got)
+	}
+	if got := Coordinated(s, 4, 1); len(got) != 1 {
+		t.Errorf("Expected the troll alone after an unclosed comma, got %v", got)
+	}
+
+	// "the king was killed by Arin and Lyra", with a standalone conjunction
+	p := phrases{
+		items: []string{"NP:king", "VP:was killed", "PP:by", "W:and", "NP:Lyra"},
+		gaps:  []string{" ", " ", " ", " "},
+	}
+	if got := Agents(p, 1); len(got) != 2 || got[1] != 4 {
+		t.Errorf("Expected the by-phrase and Lyra as agents, got %v", got)
+	}
+
+	// "the sword that Arin stole", pronoun as an item or in the gap
+	for _, r := range []phrases{
+		{items: []string{"NP:sword", "W:that", "NP:Arin", "VP:stole"}, gaps: []string{" ", " ", " "}},
+		{items: []string{"NP:sword", "NP:Arin", "VP:stole"}, gaps: []string{" that ", " "}},
+	} {
+		first := r.Len() - 2
+		if got := ObjectRelativeAntecedent(r, first+1, first); got != 0 {
+			t.Errorf("Expected the sword as antecedent in %v, got %d", r.items, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package narrative
+
+import "strings"
+
+// beForms are the auxiliaries that mark a passive construction ("was killed")
+var beForms = map[string]bool{
+	"be": true, "been": true, "being": true, "is": true, "are": true, "am": true,
+	"was": true, "were": true, "isn't": true, "aren't": true, "wasn't": true, "weren't": true,
+	"get": true, "gets": true, "got": true, "gotten": true,
+}
+
+// irregularParticiples lists past participles the "-ed"/"-en" heuristic misses
+var irregularParticiples = map[string]bool{
+	"slain": true, "fought": true, "found": true, "made": true, "held": true, "kept": true,
+	"left": true, "lost": true, "told": true, "bought": true, "brought": true, "caught": true,
+	"struck": true, "sworn": true, "born": true, "borne": true, "led": true, "met": true,
+	"hid": true, "won": true, "sold": true, "sent": true, "built": true, "shot": true,
+	"taught": true, "thought": true, "heard": true, "hit": true, "hurt": true, "cut": true,
+	"bound": true, "betrayed": true, "slew": true, "stung": true, "spun": true, "torn": true,
+}
+
+// relativePronouns introduce relative clauses ("the knight who...", "the dragon that...")
+var relativePronouns = map[string]bool{
+	"who": true, "whom": true, "which": true, "that": true,
+}
+
+// IsPassive reports whether a verb phrase is in the passive voice:
+// a form of "be" (or "get") followed by a past participle head.
+func IsPassive(phrase []string) bool {
+	sawBe := false
+	for _, w := range phrase {
+		lw := strings.ToLower(w)
+		if beForms[lw] {
+			sawBe = true
+			continue
+		}
+		if !sawBe {
+			continue
+		}
+		if isNegator(lw) || strings.HasSuffix(lw, "ly") {
+			continue // "was not killed", "was brutally killed"
+		}
+		return isParticiple(lw)
+	}
+	return false
+}
+
+func isParticiple(w string) bool {
+	if irregularParticiples[w] {
+		return true
+	}
+	if len(w) > 3 && (strings.HasSuffix(w, "ed") || strings.HasSuffix(w, "en")) {
+		return true
+	}
+	return false
+}
+
+// IsAgentMarker reports whether a preposition introduces a passive agent ("by Arin")
+func IsAgentMarker(prep string) bool {
+	return strings.EqualFold(prep, "by")
+}
+
+// IsRelativePronoun reports whether a word opens a relative clause
+func IsRelativePronoun(w string) bool {
+	return relativePronouns[strings.ToLower(w)]
+}
+
+// IsCoordination reports whether the text between two noun phrases joins
+// them as coordinated arguments: "Arin and Lyra", "Arin, Lyra", "Arin or Lyra".
+func IsCoordination(gap string) bool {
+	switch strings.ToLower(strings.Join(strings.Fields(gap), " ")) {
+	case "and", "or", "nor", ",", ", and", ", or", ", nor":
+		return true
+	}
+	return false
+}
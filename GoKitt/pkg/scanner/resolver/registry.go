@@ -0,0 +1,391 @@
+package resolver
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/kittclouds/gokitt/pkg/resorank"
+)
+
+// Registry is the world-level set of known entities.
+// It indexes names and aliases for constant-time lookup, keeps descriptor
+// attributes for definite descriptions ("the old wizard") and records
+// user feedback (confirmed aliases, rejected resolutions).
+// A single Registry can be shared by every Resolver of a world; it is safe
+// for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	entities map[string]*EntityMetadata
+	aliases  map[string][]string        // lowercased name/alias -> entity IDs
+	rejected map[string]map[string]bool // lowercased text -> rejected entity IDs
+	scorer   *resorank.Scorer
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	cfg := resorank.DefaultConfig()
+	// Tune for alias matching
+	cfg.VectorAlpha = 0.5           // 50/50 mix
+	cfg.FieldWeights["name"] = 10.0 // Exact name match is high
+	cfg.FieldWeights["alias"] = 5.0 // Alias match is good
+	cfg.FieldWeights["kind"] = 1.0  // Weak signal
+	cfg.B = 0.5                     // Short text, lower length normalization penalty
+
+	return &Registry{
+		entities: make(map[string]*EntityMetadata),
+		aliases:  make(map[string][]string),
+		rejected: make(map[string]map[string]bool),
+		scorer:   resorank.NewScorer(cfg),
+	}
+}
+
+// Register adds or replaces an entity
+func (reg *Registry) Register(e EntityMetadata) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if old, ok := reg.entities[e.ID]; ok {
+		reg.unindex(old)
+	}
+	stored := e
+	stored.Aliases = append([]string(nil), e.Aliases...)
+	stored.Descriptors = append([]string(nil), e.Descriptors...)
+	reg.entities[e.ID] = &stored
+	reg.index(&stored)
+	reg.indexScorer(&stored)
+}
+
+// Remove deletes an entity from the registry
+func (reg *Registry) Remove(id string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if old, ok := reg.entities[id]; ok {
+		reg.unindex(old)
+		delete(reg.entities, id)
+	}
+}
+
+// Get returns a copy of the entity with the given ID
+func (reg *Registry) Get(id string) (EntityMetadata, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	e, ok := reg.entities[id]
+	if !ok {
+		return EntityMetadata{}, false
+	}
+	out := *e
+	out.Aliases = append([]string(nil), e.Aliases...)
+	out.Descriptors = append([]string(nil), e.Descriptors...)
+	return out, true
+}
+
+// Len returns the number of registered entities
+func (reg *Registry) Len() int {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return len(reg.entities)
+}
+
+// Lookup returns the IDs whose name or alias equals text (case-insensitive),
+// excluding resolutions the user rejected for that text.
+func (reg *Registry) Lookup(text string) []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return reg.lookupLocked(normalize(text))
+}
+
+func (reg *Registry) lookupLocked(key string) []string {
+	var out []string
+	for _, id := range reg.aliases[key] {
+		if !reg.rejected[key][id] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// IsRejected reports whether the user rejected resolving text to id
+func (reg *Registry) IsRejected(text, id string) bool {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return reg.rejected[normalize(text)][id]
+}
+
+// Confirm records that text refers to entity id. Names and descriptions not
+// yet known become aliases; a definite description also contributes its
+// words as descriptors. Returns true if the entity's aliases changed.
+// Pronouns are contextual and are never learned.
+func (reg *Registry) Confirm(text, id string) bool {
+	key := normalize(text)
+	if key == "" || isAnyPronoun(key) {
+		return false
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	e, ok := reg.entities[id]
+	if !ok {
+		return false
+	}
+	delete(reg.rejected[key], id)
+
+	if words, ok := descriptionWords(key); ok {
+		for _, w := range words {
+			if !containsFold(e.Descriptors, w) {
+				e.Descriptors = append(e.Descriptors, w)
+			}
+		}
+	}
+
+	if strings.EqualFold(e.Name, key) || containsFold(e.Aliases, key) {
+		return false
+	}
+	e.Aliases = append(e.Aliases, strings.Join(strings.Fields(text), " "))
+	reg.aliases[key] = appendUnique(reg.aliases[key], id)
+	return true
+}
+
+// Reject records that text does not refer to entity id, so later
+// resolutions skip it. If text was one of the entity's aliases it is
+// removed; the return value reports whether the aliases changed.
+func (reg *Registry) Reject(text, id string) bool {
+	key := normalize(text)
+	if key == "" || isAnyPronoun(key) {
+		return false
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.rejected[key] == nil {
+		reg.rejected[key] = make(map[string]bool)
+	}
+	reg.rejected[key][id] = true
+
+	e, ok := reg.entities[id]
+	if !ok {
+		return false
+	}
+	for i, alias := range e.Aliases {
+		if normalize(alias) == key {
+			e.Aliases = append(e.Aliases[:i], e.Aliases[i+1:]...)
+			reg.aliases[key] = removeID(reg.aliases[key], id)
+			return true
+		}
+	}
+	return false
+}
+
+// descriptionMatch is a candidate for a definite description
+type descriptionMatch struct {
+	id    string
+	score float64 // Fraction of description words the entity carries
+}
+
+// describe returns entities whose descriptors contain the head noun of a
+// definite description, scored by how many of its words they carry.
+func (reg *Registry) describe(key string) []descriptionMatch {
+	words, ok := descriptionWords(key)
+	if !ok {
+		return nil
+	}
+	head := words[len(words)-1]
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	var out []descriptionMatch
+	for id, e := range reg.entities {
+		if reg.rejected[key][id] {
+			continue
+		}
+		attrs := descriptorsOf(e)
+		if !attrs[head] {
+			continue
+		}
+		hits := 0
+		for _, w := range words {
+			if attrs[w] {
+				hits++
+			}
+		}
+		out = append(out, descriptionMatch{id: id, score: float64(hits) / float64(len(words))})
+	}
+	return out
+}
+
+// search runs the fuzzy scorer, skipping rejected candidates
+func (reg *Registry) search(text string, queryVector []float32) (string, float64) {
+	key := normalize(text)
+
+	// The scorer caches IDF/entropy values, so searching mutates it
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	for _, res := range reg.scorer.Search(strings.Fields(key), queryVector, 3) {
+		if _, ok := reg.entities[res.DocID]; !ok || reg.rejected[key][res.DocID] {
+			continue
+		}
+		return res.DocID, res.Score
+	}
+	return "", 0
+}
+
+// genderOf returns the gender of a registered entity
+func (reg *Registry) genderOf(id string) (Gender, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	e, ok := reg.entities[id]
+	if !ok {
+		return GenderUnknown, false
+	}
+	return e.Gender, true
+}
+
+func (reg *Registry) index(e *EntityMetadata) {
+	if key := normalize(e.Name); key != "" {
+		reg.aliases[key] = appendUnique(reg.aliases[key], e.ID)
+	}
+	for _, alias := range e.Aliases {
+		if key := normalize(alias); key != "" {
+			reg.aliases[key] = appendUnique(reg.aliases[key], e.ID)
+		}
+	}
+}
+
+func (reg *Registry) unindex(e *EntityMetadata) {
+	reg.scorer.RemoveDocument(e.ID)
+	for key, ids := range reg.aliases {
+		if ids = removeID(ids, e.ID); len(ids) == 0 {
+			delete(reg.aliases, key)
+		} else {
+			reg.aliases[key] = ids
+		}
+	}
+}
+
+// indexScorer indexes name, aliases and kind into the fuzzy scorer
+func (reg *Registry) indexScorer(e *EntityMetadata) {
+	meta := resorank.DocumentMetadata{
+		TotalTokenCount: 1 + len(e.Aliases), // heuristic
+		FieldLengths: map[string]int{
+			"name":  len(strings.Split(e.Name, " ")),
+			"alias": len(e.Aliases), // treats aliases as one bag for length? approximation
+			"kind":  1,
+		},
+		Embedding: e.Embedding,
+	}
+
+	tokens := make(map[string]resorank.TokenMetadata)
+
+	// Index Name
+	for _, word := range strings.Fields(strings.ToLower(e.Name)) {
+		tokens[word] = resorank.TokenMetadata{
+			CorpusDocFreq: 1,
+			FieldOccurrences: map[string]resorank.FieldOccurrence{
+				"name": {TF: 1, FieldLength: meta.FieldLengths["name"]},
+			},
+		}
+	}
+
+	// Index Aliases
+	for _, alias := range e.Aliases {
+		for _, word := range strings.Fields(strings.ToLower(alias)) {
+			// Merge if exists
+			if tm, ok := tokens[word]; ok {
+				if fo, ok := tm.FieldOccurrences["alias"]; ok {
+					fo.TF++
+					tm.FieldOccurrences["alias"] = fo
+				} else {
+					tm.FieldOccurrences["alias"] = resorank.FieldOccurrence{TF: 1, FieldLength: 10} // approx
+				}
+				tokens[word] = tm
+			} else {
+				tokens[word] = resorank.TokenMetadata{
+					CorpusDocFreq: 1,
+					FieldOccurrences: map[string]resorank.FieldOccurrence{
+						"alias": {TF: 1, FieldLength: 10},
+					},
+				}
+			}
+		}
+	}
+
+	reg.scorer.IndexDocument(e.ID, meta, tokens)
+}
+
+// descriptorsOf collects the attribute words of an entity: explicit
+// descriptors, kind and subtype, and the words of "the ..." aliases.
+func descriptorsOf(e *EntityMetadata) map[string]bool {
+	attrs := make(map[string]bool)
+	for _, d := range e.Descriptors {
+		for _, w := range strings.Fields(strings.ToLower(d)) {
+			attrs[w] = true
+		}
+	}
+	for _, k := range []string{e.Kind, e.Subtype} {
+		if k != "" {
+			attrs[strings.ToLower(k)] = true
+		}
+	}
+	for _, alias := range e.Aliases {
+		if words, ok := descriptionWords(normalize(alias)); ok {
+			for _, w := range words {
+				attrs[w] = true
+			}
+		}
+	}
+	return attrs
+}
+
+// descriptionWords splits a definite description ("the old wizard") into
+// its words after the determiner
+func descriptionWords(key string) ([]string, bool) {
+	words := strings.Fields(key)
+	if len(words) < 2 {
+		return nil, false
+	}
+	switch words[0] {
+	case "the", "that", "this":
+		return words[1:], true
+	}
+	return nil, false
+}
+
+func normalize(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+func isAnyPronoun(key string) bool {
+	return personOf(key) != 0 || pronounGender(key) != GenderUnknown
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(ids []string, id string) []string {
+	for _, v := range ids {
+		if v == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+func removeID(ids []string, id string) []string {
+	for i, v := range ids {
+		if v == id {
+			return append(ids[:i:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
@@ -3,6 +3,7 @@
 package resolver
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/kittclouds/gokitt/pkg/resorank"
@@ -21,39 +22,52 @@ const (
 
 // EntityMetadata represents a known entity in the context
 type EntityMetadata struct {
-	ID        string
-	Name      string
-	Gender    Gender
-	Aliases   []string
-	Kind      string
-	Embedding []float32
+	ID          string
+	Name        string
+	Gender      Gender
+	Aliases     []string
+	Kind        string
+	Subtype     string
+	Descriptors []string // Attribute words for definite descriptions ("old", "wizard")
+	Embedding   []float32
 }
 
 // NarrativeContext tracks the state of the narrative
 type NarrativeContext struct {
 	history    []string // Stack of entity IDs (most recent at front)
-	registry   map[string]EntityMetadata
+	registry   *Registry
 	maxHistory int
 
 	// Contextual fields
 	ScenarioID       string
 	ActiveCharacters []string
 	Speaker          string
+	Addressee        string
 	InDialogue       bool
 }
 
-// NewContext creates a new narrative context
+// NewContext creates a new narrative context with a private registry
 func NewContext() *NarrativeContext {
+	return NewContextWithRegistry(NewRegistry())
+}
+
+// NewContextWithRegistry creates a narrative context backed by a shared registry
+func NewContextWithRegistry(reg *Registry) *NarrativeContext {
 	return &NarrativeContext{
 		history:    make([]string, 0),
-		registry:   make(map[string]EntityMetadata),
+		registry:   reg,
 		maxHistory: 10,
 	}
 }
 
 // Register adds an entity to the known registry
 func (nc *NarrativeContext) Register(e EntityMetadata) {
-	nc.registry[e.ID] = e
+	nc.registry.Register(e)
+}
+
+// Registry returns the entity registry backing this context
+func (nc *NarrativeContext) Registry() *Registry {
+	return nc.registry
 }
 
 // PushMention records a mention, moving it to the front of history
@@ -78,14 +92,45 @@ func (nc *NarrativeContext) PushMention(entityID string) {
 
 // FindMostRecent finds the most recent entity matching the gender
 func (nc *NarrativeContext) FindMostRecent(gender Gender) string {
+	id, _ := nc.findMostRecent(gender)
+	return id
+}
+
+// findMostRecent also returns the confidence of the pick: an exact gender
+// agreement with no competing candidate is the strongest signal.
+func (nc *NarrativeContext) findMostRecent(gender Gender) (string, float64) {
+	best := ""
+	confidence := 0.0
 	for _, id := range nc.history {
-		if meta, ok := nc.registry[id]; ok {
-			if gendersCompatible(meta.Gender, gender) {
+		entityGender, ok := nc.registry.genderOf(id)
+		if !ok || !gendersCompatible(entityGender, gender) {
+			continue
+		}
+		if best != "" {
+			return best, confidence * 0.8 // Another compatible antecedent competes
+		}
+		best = id
+		confidence = 0.6
+		if entityGender == gender && gender != GenderUnknown {
+			confidence = 0.85
+		}
+	}
+	return best, confidence
+}
+
+// rankByRecency returns the candidate mentioned most recently (or the first)
+func (nc *NarrativeContext) rankByRecency(ids []string) string {
+	for _, h := range nc.history {
+		for _, id := range ids {
+			if id == h {
 				return id
 			}
 		}
 	}
-	return ""
+	if len(ids) == 0 {
+		return ""
+	}
+	return ids[0]
 }
 
 func gendersCompatible(entityGender, pronounGender Gender) bool {
@@ -106,6 +151,52 @@ func gendersCompatible(entityGender, pronounGender Gender) bool {
 	return false
 }
 
+// ParseGender converts a stored gender or pronoun string ("male", "she/her",
+// "they") into a Gender
+func ParseGender(s string) Gender {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "/ "); i > 0 {
+		s = s[:i] // "she/her" -> "she"
+	}
+	switch s {
+	case "male", "m", "man":
+		return GenderMale
+	case "female", "f", "woman":
+		return GenderFemale
+	case "neutral", "neuter", "none":
+		return GenderNeutral
+	case "plural", "group":
+		return GenderPlural
+	}
+	return pronounGender(s)
+}
+
+// String returns the stored form of a gender ("" for unknown)
+func (g Gender) String() string {
+	switch g {
+	case GenderMale:
+		return "male"
+	case GenderFemale:
+		return "female"
+	case GenderNeutral:
+		return "neutral"
+	case GenderPlural:
+		return "plural"
+	default:
+		return ""
+	}
+}
+
+// Resolution confidences by evidence
+const (
+	confidenceName     = 1.0
+	confidenceAlias    = 0.9
+	confidenceDialogue = 0.8
+	confidenceDescribe = 0.75
+	confidenceFuzzy    = 0.4
+	ambiguityPenalty   = 0.6
+)
+
 // Resolver handles pronoun and alias resolution
 type Resolver struct {
 	Context *NarrativeContext
@@ -114,107 +205,143 @@ type Resolver struct {
 
 // New creating a new Resolver
 func New() *Resolver {
-	cfg := resorank.DefaultConfig()
-	// Tune for alias matching
-	cfg.VectorAlpha = 0.5           // 50/50 mix
-	cfg.FieldWeights["name"] = 10.0 // Exact name match is high
-	cfg.FieldWeights["alias"] = 5.0 // Alias match is good
-	cfg.FieldWeights["kind"] = 1.0  // Weak signal
-	cfg.B = 0.5                     // Short text, lower length normalization penalty
+	return NewWithRegistry(NewRegistry())
+}
 
+// NewWithRegistry creates a Resolver over a shared (world-level) registry.
+// Recency and dialogue state stay per-resolver.
+func NewWithRegistry(reg *Registry) *Resolver {
 	return &Resolver{
-		Context: NewContext(),
-		Scorer:  resorank.NewScorer(cfg),
+		Context: NewContextWithRegistry(reg),
+		Scorer:  reg.scorer,
 	}
 }
 
 // RegisterEntity registers an entity with both the context and the fuzzy scorer
 func (r *Resolver) RegisterEntity(e EntityMetadata) {
 	r.Context.Register(e)
+}
 
-	// Index into ResoRank
-	meta := resorank.DocumentMetadata{
-		TotalTokenCount: 1 + len(e.Aliases), // heuristic
-		FieldLengths: map[string]int{
-			"name":  len(strings.Split(e.Name, " ")),
-			"alias": len(e.Aliases), // treats aliases as one bag for length? approximation
-			"kind":  1,
-		},
-		Embedding: e.Embedding,
-	}
-
-	tokens := make(map[string]resorank.TokenMetadata)
-
-	// Index Name
-	for _, word := range strings.Fields(strings.ToLower(e.Name)) {
-		tokens[word] = resorank.TokenMetadata{
-			CorpusDocFreq: 1,
-			FieldOccurrences: map[string]resorank.FieldOccurrence{
-				"name": {TF: 1, FieldLength: meta.FieldLengths["name"]},
-			},
-		}
-	}
+// Registry returns the registry backing this resolver
+func (r *Resolver) Registry() *Registry {
+	return r.Context.registry
+}
 
-	// Index Aliases
-	for _, alias := range e.Aliases {
-		for _, word := range strings.Fields(strings.ToLower(alias)) {
-			// Merge if exists
-			if tm, ok := tokens[word]; ok {
-				if fo, ok := tm.FieldOccurrences["alias"]; ok {
-					fo.TF++
-					tm.FieldOccurrences["alias"] = fo
-				} else {
-					tm.FieldOccurrences["alias"] = resorank.FieldOccurrence{TF: 1, FieldLength: 10} // approx
-				}
-				tokens[word] = tm
-			} else {
-				tokens[word] = resorank.TokenMetadata{
-					CorpusDocFreq: 1,
-					FieldOccurrences: map[string]resorank.FieldOccurrence{
-						"alias": {TF: 1, FieldLength: 10},
-					},
-				}
-			}
-		}
-	}
+// Confirm records user feedback that text refers to entityID; see Registry.Confirm
+func (r *Resolver) Confirm(text, entityID string) bool {
+	return r.Context.registry.Confirm(text, entityID)
+}
+
+// Reject records user feedback that text does not refer to entityID; see Registry.Reject
+func (r *Resolver) Reject(text, entityID string) bool {
+	return r.Context.registry.Reject(text, entityID)
+}
+
+// EnterDialogue marks that following text is quoted speech by speaker,
+// addressed to addressee (either may be empty when unknown).
+func (r *Resolver) EnterDialogue(speaker, addressee string) {
+	r.Context.InDialogue = true
+	r.Context.Speaker = speaker
+	r.Context.Addressee = addressee
+}
 
-	r.Scorer.IndexDocument(e.ID, meta, tokens)
+// ExitDialogue clears the dialogue state
+func (r *Resolver) ExitDialogue() {
+	r.Context.InDialogue = false
+	r.Context.Speaker = ""
+	r.Context.Addressee = ""
 }
 
 // Resolve attempts to resolve text (pronoun or alias) to an EntityID
 func (r *Resolver) Resolve(text string, queryVector []float32) string {
+	id, _ := r.ResolveWithConfidence(text, queryVector)
+	return id
+}
+
+// ResolveWithConfidence resolves text (pronoun, name, alias or definite
+// description) to an EntityID with a confidence in [0, 1].
+// Returns "" and 0 when nothing matches.
+func (r *Resolver) ResolveWithConfidence(text string, queryVector []float32) (string, float64) {
+	// First/second person only resolve inside attributed dialogue
+	switch personOf(text) {
+	case 1:
+		if r.Context.InDialogue && r.Context.Speaker != "" {
+			return r.Context.Speaker, confidenceDialogue
+		}
+		return "", 0
+	case 2:
+		if r.Context.InDialogue && r.Context.Addressee != "" {
+			return r.Context.Addressee, confidenceDialogue
+		}
+		return "", 0
+	}
+
 	if r.isPronoun(text) {
 		gender := r.inferPronounGender(text)
-		return r.Context.FindMostRecent(gender)
+		return r.Context.findMostRecent(gender)
 	}
 
-	// 1. Direct Alias Match (Fastest)
-	lower := strings.ToLower(text)
-	for _, meta := range r.Context.registry {
-		if strings.ToLower(meta.Name) == lower {
-			return meta.ID
+	reg := r.Context.registry
+
+	// 1. Direct Name/Alias Match (Fastest)
+	if ids := reg.Lookup(text); len(ids) > 0 {
+		id := r.Context.rankByRecency(ids)
+		confidence := confidenceAlias
+		if e, ok := reg.Get(id); ok && strings.EqualFold(e.Name, strings.TrimSpace(text)) {
+			confidence = confidenceName
 		}
-		for _, alias := range meta.Aliases {
-			if strings.ToLower(alias) == lower {
-				return meta.ID
-			}
+		if len(ids) > 1 {
+			confidence *= ambiguityPenalty
 		}
+		return id, confidence
 	}
 
-	// 2. Fuzzy/Hybrid Match (ResoRank)
-	// Split query
-	queryTokens := strings.Fields(lower)
+	// 2. Definite Description ("the old wizard")
+	if matches := reg.describe(normalize(text)); len(matches) > 0 {
+		return r.pickDescription(matches)
+	}
+
+	// 3. Fuzzy/Hybrid Match (ResoRank)
+	// BM25 scores can be high; only accept clear hits
+	if id, score := reg.search(text, queryVector); id != "" && score > 1.0 {
+		return id, confidenceFuzzy
+	}
+
+	return "", 0
+}
 
-	results := r.Scorer.Search(queryTokens, queryVector, 1)
-	if len(results) > 0 {
-		// Threshold check?
-		// For now if scoring > 0.5 (arbitrary), take it
-		if results[0].Score > 1.0 { // BM25 scores can be high.
-			return results[0].DocID
+// pickDescription chooses among description candidates: best word overlap,
+// ties broken by recency. Unresolved ties lower the confidence.
+func (r *Resolver) pickDescription(matches []descriptionMatch) (string, float64) {
+	bestScore := 0.0
+	for _, m := range matches {
+		if m.score > bestScore {
+			bestScore = m.score
 		}
 	}
+	var tied []string
+	for _, m := range matches {
+		if m.score == bestScore {
+			tied = append(tied, m.id)
+		}
+	}
+	sort.Strings(tied) // Deterministic fallback when none is recent
 
-	return ""
+	id := r.Context.rankByRecency(tied)
+	confidence := confidenceDescribe * bestScore
+	if len(tied) > 1 && !r.inHistory(id) {
+		confidence *= ambiguityPenalty
+	}
+	return id, confidence
+}
+
+func (r *Resolver) inHistory(id string) bool {
+	for _, h := range r.Context.history {
+		if h == id {
+			return true
+		}
+	}
+	return false
 }
 
 // ObserveMention updates context with an explicit mention
@@ -222,6 +349,24 @@ func (r *Resolver) ObserveMention(entityID string) {
 	r.Context.PushMention(entityID)
 }
 
+// IsDialoguePronoun reports whether text is a singular first- or second-person
+// pronoun ("I", "my", "you"), which only resolves via the dialogue speaker context.
+func IsDialoguePronoun(text string) bool {
+	return personOf(text) != 0
+}
+
+// personOf returns 1 for first-person singular, 2 for second-person, 0 otherwise
+func personOf(text string) int {
+	switch strings.ToLower(text) {
+	case "i", "me", "my", "mine", "myself":
+		return 1
+	case "you", "your", "yours", "yourself":
+		return 2
+	default:
+		return 0
+	}
+}
+
 func (r *Resolver) isPronoun(text string) bool {
 	switch strings.ToLower(text) {
 	case "he", "him", "his", "she", "her", "hers", "it", "its", "they", "them", "their":
@@ -232,6 +377,11 @@ func (r *Resolver) isPronoun(text string) bool {
 }
 
 func (r *Resolver) inferPronounGender(text string) Gender {
+	return pronounGender(text)
+}
+
+// pronounGender maps a third-person pronoun to the gender it agrees with
+func pronounGender(text string) Gender {
 	switch strings.ToLower(text) {
 	case "he", "him", "his":
 		return GenderMale
@@ -86,3 +86,181 @@ func TestRecencyUpdate(t *testing.T) {
 		t.Errorf("Expected e4 (Frodo), got %s", res)
 	}
 }
+
+func TestDialoguePronouns(t *testing.T) {
+	r := setupResolver()
+
+	// Outside dialogue, first/second person has no referent
+	if res := r.Resolve("I", nil); res != "" {
+		t.Errorf("Expected no resolution outside dialogue, got %s", res)
+	}
+
+	r.EnterDialogue("e1", "e2") // Gandalf speaking to Galadriel
+	if !r.Context.InDialogue || r.Context.Speaker != "e1" {
+		t.Error("Expected dialogue context to be set")
+	}
+	if res := r.Resolve("I", nil); res != "e1" {
+		t.Errorf("Expected I -> e1, got %s", res)
+	}
+	if res := r.Resolve("my", nil); res != "e1" {
+		t.Errorf("Expected my -> e1, got %s", res)
+	}
+	if res := r.Resolve("You", nil); res != "e2" {
+		t.Errorf("Expected You -> e2, got %s", res)
+	}
+
+	r.ExitDialogue()
+	if r.Context.InDialogue || r.Context.Speaker != "" {
+		t.Error("Expected dialogue context to be cleared")
+	}
+}
+
+func TestResolveConfidence(t *testing.T) {
+	r := setupResolver()
+
+	if id, conf := r.ResolveWithConfidence("Gandalf", nil); id != "e1" || conf != 1.0 {
+		t.Errorf("Expected exact name e1 at 1.0, got %s %.2f", id, conf)
+	}
+	if id, conf := r.ResolveWithConfidence("Mithrandir", nil); id != "e1" || conf >= 1.0 || conf < 0.8 {
+		t.Errorf("Expected alias e1 below name confidence, got %s %.2f", id, conf)
+	}
+
+	// A lone compatible antecedent is stronger than a contested one
+	r.ObserveMention("e1")
+	_, single := r.ResolveWithConfidence("he", nil)
+	r.RegisterEntity(EntityMetadata{ID: "e4", Name: "Frodo", Gender: GenderMale})
+	r.ObserveMention("e4")
+	id, contested := r.ResolveWithConfidence("he", nil)
+	if id != "e4" || contested >= single {
+		t.Errorf("Expected contested pronoun e4 with lower confidence (%.2f < %.2f)", contested, single)
+	}
+
+	if id, conf := r.ResolveWithConfidence("Sauron", nil); id != "" || conf != 0 {
+		t.Errorf("Expected no resolution, got %s %.2f", id, conf)
+	}
+}
+
+func TestDefiniteDescription(t *testing.T) {
+	r := New()
+	r.RegisterEntity(EntityMetadata{
+		ID: "gandalf", Name: "Gandalf", Gender: GenderMale, Kind: "CHARACTER",
+		Subtype: "wizard", Descriptors: []string{"old", "grey"},
+	})
+	r.RegisterEntity(EntityMetadata{
+		ID: "saruman", Name: "Saruman", Gender: GenderMale, Kind: "CHARACTER",
+		Subtype: "wizard", Descriptors: []string{"white"},
+	})
+
+	if id, _ := r.ResolveWithConfidence("the old wizard", nil); id != "gandalf" {
+		t.Errorf("Expected 'the old wizard' -> gandalf, got %s", id)
+	}
+	if id, _ := r.ResolveWithConfidence("the white wizard", nil); id != "saruman" {
+		t.Errorf("Expected 'the white wizard' -> saruman, got %s", id)
+	}
+
+	// Ambiguous head: recency decides, otherwise confidence drops
+	_, unseen := r.ResolveWithConfidence("the wizard", nil)
+	r.ObserveMention("saruman")
+	id, seen := r.ResolveWithConfidence("the wizard", nil)
+	if id != "saruman" || seen <= unseen {
+		t.Errorf("Expected recent saruman with higher confidence (%.2f > %.2f), got %s", seen, unseen, id)
+	}
+
+	if id, _ := r.ResolveWithConfidence("the tall hobbit", nil); id != "" {
+		t.Errorf("Expected no match for unknown head noun, got %s", id)
+	}
+}
+
+func TestConfirmReject(t *testing.T) {
+	r := setupResolver()
+
+	// Confirming a new name teaches an alias
+	if !r.Confirm("Stormcrow", "e1") {
+		t.Fatal("Expected Confirm to add an alias")
+	}
+	if id, conf := r.ResolveWithConfidence("stormcrow", nil); id != "e1" || conf < 0.8 {
+		t.Errorf("Expected confirmed alias -> e1, got %s %.2f", id, conf)
+	}
+	if r.Confirm("Stormcrow", "e1") {
+		t.Error("Expected repeated Confirm to be a no-op")
+	}
+	if r.Confirm("he", "e1") {
+		t.Error("Expected pronouns to never become aliases")
+	}
+
+	// Confirming a description teaches descriptors
+	r.Confirm("the grey pilgrim", "e1")
+	if id, _ := r.ResolveWithConfidence("the pilgrim", nil); id != "e1" {
+		t.Errorf("Expected learned descriptor 'pilgrim' -> e1, got %s", id)
+	}
+
+	// Rejecting an alias removes it and blocks the resolution
+	if !r.Reject("Mithrandir", "e1") {
+		t.Error("Expected Reject to remove the alias")
+	}
+	if id, _ := r.ResolveWithConfidence("Mithrandir", nil); id == "e1" {
+		t.Error("Expected rejected resolution to be skipped")
+	}
+	e, _ := r.Registry().Get("e1")
+	for _, a := range e.Aliases {
+		if a == "Mithrandir" {
+			t.Error("Expected alias to be removed from the registry")
+		}
+	}
+
+	// Confirming again lifts the rejection
+	r.Confirm("Mithrandir", "e1")
+	if id, _ := r.ResolveWithConfidence("Mithrandir", nil); id != "e1" {
+		t.Errorf("Expected re-confirmed alias -> e1, got %s", id)
+	}
+}
+
+func TestSharedRegistry(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(EntityMetadata{ID: "lyra", Name: "Lyra", Gender: GenderFemale})
+
+	a := NewWithRegistry(reg)
+	b := NewWithRegistry(reg)
+
+	// Feedback through one resolver is visible to the other
+	a.Confirm("the ranger", "lyra")
+	if id := b.Resolve("the ranger", nil); id != "lyra" {
+		t.Errorf("Expected shared alias -> lyra, got %s", id)
+	}
+
+	// Recency stays per resolver
+	a.ObserveMention("lyra")
+	if id := b.Resolve("she", nil); id != "" {
+		t.Errorf("Expected no antecedent in the other resolver, got %s", id)
+	}
+}
+
+func TestRegistry_ReRegisterDropsOldScorerEntries(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(EntityMetadata{ID: "a", Name: "Strider", Aliases: []string{"the ranger"}})
+	reg.Register(EntityMetadata{ID: "a", Name: "Aragorn"})
+
+	if id, _ := reg.search("Strider", nil); id != "" {
+		t.Errorf("Expected the old name forgotten by the scorer, got %s", id)
+	}
+	if id, _ := reg.search("Aragorn", nil); id != "a" {
+		t.Errorf("Expected the new name indexed, got %s", id)
+	}
+
+	reg.Remove("a")
+	if n := len(reg.scorer.DocumentIndex); n != 0 || len(reg.scorer.TokenIndex) != 0 {
+		t.Errorf("Expected the scorer emptied, got %d documents", n)
+	}
+}
+
+func TestParseGender(t *testing.T) {
+	cases := map[string]Gender{
+		"male": GenderMale, "she/her": GenderFemale, "they": GenderPlural,
+		"it": GenderNeutral, "": GenderUnknown, "Female": GenderFemale,
+	}
+	for in, want := range cases {
+		if got := ParseGender(in); got != want {
+			t.Errorf("ParseGender(%q) = %v, want %v", in, got, want)
+		}
+	}
+}